@@ -13,8 +13,11 @@ import (
 	"crypto/x509"
 	"encoding/binary"
 	"fmt"
+	"net"
 	"os"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/awcullen/opcua/ua"
 	"github.com/djherbis/buffer"
@@ -42,6 +45,9 @@ func Dial(ctx context.Context, endpointURL string, opts ...Option) (c *Client, e
 		maxChunkCount:     defaultMaxChunkCount,
 		trace:             false,
 		forcedEndpoint:    false,
+		subscriptions:     make(map[uint32]uint32),
+		publishers:        make(map[uint32]*Subscription),
+		done:              make(chan struct{}),
 	}
 
 	// apply each option to the default
@@ -51,12 +57,14 @@ func Dial(ctx context.Context, endpointURL string, opts ...Option) (c *Client, e
 		}
 	}
 
+	cli.initCertificateValidator()
+
 	// get endpoints from discovery url
 	req := &ua.GetEndpointsRequest{
 		EndpointURL: endpointURL,
-		ProfileURIs: []string{ua.TransportProfileURIUaTcpTransport},
+		ProfileURIs: []string{transportProfileURI(endpointURL)},
 	}
-	res, err := GetEndpoints(ctx, req)
+	res, err := cli.getEndpoints(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -131,11 +139,7 @@ func Dial(ctx context.Context, endpointURL string, opts ...Option) (c *Client, e
 		cli.securityMode,
 		cli.serverCertificate,
 		cli.connectTimeout,
-		cli.trustedCertsPath,
-		cli.trustedCRLsPath,
-		cli.issuerCertsPath,
-		cli.issuerCRLsPath,
-		cli.rejectedCertsPath,
+		cli.certificateValidator,
 		cli.suppressHostNameInvalid,
 		cli.suppressCertificateExpired,
 		cli.suppressCertificateChainIncomplete,
@@ -147,12 +151,19 @@ func Dial(ctx context.Context, endpointURL string, opts ...Option) (c *Client, e
 		cli.maxMessageSize,
 		cli.maxChunkCount,
 		cli.trace)
+	cli.channel.dialer = cli.dialer()
 
 	// open session and read the namespace table
 	if err := cli.open(ctx); err != nil {
 		cli.Abort(ctx)
 		return nil, err
 	}
+	cli.state = ConnectionStateConnected
+
+	// watch the secure channel and reconnect if it fails
+	if cli.reconnectBackoff != nil {
+		go cli.monitor()
+	}
 
 	return cli, nil
 }
@@ -185,6 +196,8 @@ type Client struct {
 	issuerCertsPath                      string
 	issuerCRLsPath                       string
 	rejectedCertsPath                    string
+	certificateStore                     ua.CertificateStore
+	certificateValidator                 *ua.CertificateValidator
 	suppressHostNameInvalid              bool
 	suppressCertificateExpired           bool
 	suppressCertificateChainIncomplete   bool
@@ -195,6 +208,19 @@ type Client struct {
 	maxChunkCount                        uint32
 	trace                                bool
 	forcedEndpoint                       bool
+	reconnectBackoff                     Backoff
+	reverseListener                      *reverseListener
+	stateHandler                         func(ConnectionState, error)
+	stateLock                            sync.RWMutex
+	state                                ConnectionState
+	serverNonce                          []byte
+	subscriptionsLock                    sync.Mutex
+	subscriptions                        map[uint32]uint32
+	publishers                           map[uint32]*Subscription
+	republished                          []*ua.PublishResponse
+	deferredAcks                         []ua.SubscriptionAcknowledgement
+	done                                 chan struct{}
+	stopOnce                             sync.Once
 }
 
 // EndpointURL gets the EndpointURL of the server.
@@ -234,6 +260,9 @@ func (ch *Client) IsClosing() bool {
 
 // Request sends a service request to the server and returns the response.
 func (ch *Client) request(ctx context.Context, req ua.ServiceRequest) (ua.ServiceResponse, error) {
+	if ch.State() != ConnectionStateConnected {
+		return nil, ua.BadServerNotConnected
+	}
 	return ch.channel.Request(ctx, req)
 }
 
@@ -242,7 +271,14 @@ func (ch *Client) open(ctx context.Context) error {
 	if err := ch.channel.Open(ctx); err != nil {
 		return err
 	}
+	if err := ch.openSession(ctx); err != nil {
+		return err
+	}
+	return ch.readServerArrays(ctx)
+}
 
+// openSession creates and activates a new session on the current secure channel.
+func (ch *Client) openSession(ctx context.Context) error {
 	var localNonce, localCertificate, remoteNonce []byte
 	localNonce = getNextNonce(nonceLength)
 	localCertificate = ch.channel.localCertificate
@@ -305,6 +341,11 @@ func (ch *Client) open(ctx context.Context) error {
 		}
 	}
 
+	return ch.activate(ctx, remoteNonce)
+}
+
+// activate activates the session on the current secure channel, signing with the last nonce received from the server.
+func (ch *Client) activate(ctx context.Context, remoteNonce []byte) error {
 	// create client signature
 	var clientSignature ua.SignatureData
 	switch ch.securityPolicyURI {
@@ -718,9 +759,12 @@ func (ch *Client) open(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	_ = []byte(activateSessionResponse.ServerNonce)
+	ch.serverNonce = []byte(activateSessionResponse.ServerNonce)
+	return nil
+}
 
-	// fetch namespace array, etc.
+// readServerArrays reads the namespace array and server array of the server.
+func (ch *Client) readServerArrays(ctx context.Context) error {
 	var readRequest = &ua.ReadRequest{
 		NodesToRead: []ua.ReadValueID{
 			{
@@ -733,10 +777,11 @@ func (ch *Client) open(ctx context.Context) error {
 			},
 		},
 	}
-	readResponse, err := ch.Read(ctx, readRequest)
+	response, err := ch.channel.Request(ctx, readRequest)
 	if err != nil {
 		return err
 	}
+	readResponse := response.(*ua.ReadResponse)
 	if len(readResponse.Results) == 2 {
 		if readResponse.Results[0].StatusCode.IsGood() {
 			value := readResponse.Results[0].Value.([]string)
@@ -753,6 +798,7 @@ func (ch *Client) open(ctx context.Context) error {
 
 // Close closes the session and secure channel.
 func (ch *Client) Close(ctx context.Context) error {
+	ch.stop()
 	var request = &ua.CloseSessionRequest{
 		DeleteSubscriptions: true,
 	}
@@ -761,11 +807,13 @@ func (ch *Client) Close(ctx context.Context) error {
 		return err
 	}
 	ch.channel.Close(ctx)
+	ch.setState(ConnectionStateDisconnected, nil)
 	return nil
 }
 
 // Close closes the session and secure channel.
 func (ch *Client) CloseDeleteSubscriptions(ctx context.Context, deleteSubscriptions bool) error {
+	ch.stop()
 	var request = &ua.CloseSessionRequest{
 		DeleteSubscriptions: deleteSubscriptions,
 	}
@@ -774,15 +822,78 @@ func (ch *Client) CloseDeleteSubscriptions(ctx context.Context, deleteSubscripti
 		return err
 	}
 	ch.channel.Close(ctx)
+	ch.setState(ConnectionStateDisconnected, nil)
 	return nil
 }
 
 // Abort closes the client abruptly.
 func (ch *Client) Abort(ctx context.Context) error {
+	ch.stop()
 	ch.channel.Abort(ctx)
+	ch.setState(ConnectionStateDisconnected, nil)
 	return nil
 }
 
 func (ch *Client) GetNamespaceURIs() []string {
 	return ch.channel.NamespaceURIs()
 }
+
+// RegisterDataTypeDefinition reads the DataTypeDefinition attribute of the DataType, and registers the
+// definition with the encoders, so that values of the DataType are encoded and decoded as a ua.Structure.
+// Returns the definition, a ua.StructureDefinition or ua.EnumDefinition.
+func (ch *Client) RegisterDataTypeDefinition(ctx context.Context, dataTypeID ua.NodeID) (any, error) {
+	req := &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{
+			{
+				NodeID:      dataTypeID,
+				AttributeID: ua.AttributeIDDataTypeDefinition,
+			},
+		},
+	}
+	res, err := ch.Read(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Results) != 1 {
+		return nil, ua.BadUnexpectedError
+	}
+	if sc := res.Results[0].StatusCode; sc.IsBad() {
+		return nil, sc
+	}
+	switch def := res.Results[0].Value.(type) {
+	case ua.StructureDefinition:
+		ua.RegisterStructureDefinition(dataTypeID, def, ch.GetNamespaceURIs())
+		return def, nil
+	case ua.EnumDefinition:
+		ua.RegisterEnumDefinition(dataTypeID, def, ch.GetNamespaceURIs())
+		return def, nil
+	default:
+		return nil, ua.BadDataTypeIDUnknown
+	}
+}
+
+// initCertificateValidator sets the certificate validator, if not set by an option.
+func (ch *Client) initCertificateValidator() {
+	if ch.certificateValidator == nil {
+		if ch.certificateStore == nil {
+			ch.certificateStore = ua.NewDirectoryCertificateStore(ch.trustedCertsPath, ch.trustedCRLsPath, ch.issuerCertsPath, ch.issuerCRLsPath, ch.rejectedCertsPath)
+		}
+		ch.certificateValidator = ua.NewCertificateValidator(ch.certificateStore)
+	}
+}
+
+// dialer returns the function to connect to the server, if the server connects to the client.
+func (ch *Client) dialer() func(context.Context) (net.Conn, error) {
+	if ch.reverseListener == nil {
+		return nil
+	}
+	return ch.reverseListener.dial
+}
+
+// transportProfileURI returns the transport profile for the scheme of the endpoint url.
+func transportProfileURI(endpointURL string) string {
+	if strings.HasPrefix(endpointURL, "opc.wss:") {
+		return ua.TransportProfileURIWssTransport
+	}
+	return ua.TransportProfileURIUaTcpTransport
+}
@@ -12,6 +12,7 @@ import (
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/binary"
 	"encoding/json"
@@ -71,12 +72,9 @@ type clientSecureChannel struct {
 	maxResponseMessageSize               uint32
 	maxResponseChunkCount                uint32
 	conn                                 net.Conn
+	dialer                               func(context.Context) (net.Conn, error)
 	connectTimeout                       int64
-	trustedCertsPath                     string
-	trustedCRLsPath                      string
-	issuerCertsPath                      string
-	issuerCRLsPath                       string
-	rejectedCertsPath                    string
+	certificateValidator                 *ua.CertificateValidator
 	suppressHostNameInvalid              bool
 	suppressCertificateExpired           bool
 	suppressCertificateChainIncomplete   bool
@@ -137,11 +135,7 @@ func newClientSecureChannel(
 	securityMode ua.MessageSecurityMode,
 	remoteCertificate []byte,
 	connectTimeout int64,
-	trustedCertsPath string,
-	trustedCRLsPath string,
-	issuerCertsPath string,
-	issuerCRLsPath string,
-	rejectedCertsPath string,
+	certificateValidator *ua.CertificateValidator,
 	suppressHostNameInvalid bool,
 	suppressCertificateExpired bool,
 	suppressCertificateChainIncomplete bool,
@@ -166,11 +160,7 @@ func newClientSecureChannel(
 		namespaceURIs:                        []string{"http://opcfoundation.org/UA/"},
 		serverURIs:                           []string{},
 		connectTimeout:                       connectTimeout,
-		trustedCertsPath:                     trustedCertsPath,
-		trustedCRLsPath:                      trustedCRLsPath,
-		issuerCertsPath:                      issuerCertsPath,
-		issuerCRLsPath:                       issuerCRLsPath,
-		rejectedCertsPath:                    rejectedCertsPath,
+		certificateValidator:                 certificateValidator,
 		suppressHostNameInvalid:              suppressHostNameInvalid,
 		suppressCertificateExpired:           suppressCertificateExpired,
 		suppressCertificateChainIncomplete:   suppressCertificateChainIncomplete,
@@ -242,8 +232,13 @@ func (ch *clientSecureChannel) Request(ctx context.Context, req ua.ServiceReques
 	if header.TimeoutHint == 0 {
 		header.TimeoutHint = defaultTimeoutHint
 	}
+	var closed = ch.Closed()
 	var operation = ua.NewServiceOperation(req, make(chan ua.ServiceResponse, 1))
-	ch.pendingResponseCh <- operation
+	select {
+	case ch.pendingResponseCh <- operation:
+	case <-closed:
+		return nil, ua.BadSecureChannelClosed
+	}
 	ctx, cancel := context.WithDeadline(ctx, header.Timestamp.Add(time.Duration(header.TimeoutHint)*time.Millisecond))
 	err := ch.sendRequest(ctx, operation)
 	if err != nil {
@@ -260,7 +255,7 @@ func (ch *clientSecureChannel) Request(ctx context.Context, req ua.ServiceReques
 	case <-ctx.Done():
 		cancel()
 		return nil, ua.BadRequestTimeout
-	case <-ch.closed:
+	case <-closed:
 		cancel()
 		return nil, ua.BadSecureChannelClosed
 	}
@@ -271,6 +266,13 @@ func (ch *clientSecureChannel) Open(ctx context.Context) error {
 	ch.Lock()
 	defer ch.Unlock()
 
+	// close the connection of a previous open, when reconnecting.
+	if ch.conn != nil {
+		ch.conn.Close()
+		ch.conn = nil
+	}
+	ch.closing = false
+
 	remoteURL, err := url.Parse(ch.endpointURL)
 	if err != nil {
 		return err
@@ -281,15 +283,10 @@ func (ch *clientSecureChannel) Open(ctx context.Context) error {
 		if err != nil || len(certs) == 0 {
 			return ua.BadSecurityChecksFailed
 		}
-		err = ua.ValidateCertificate(
+		err = ch.certificateValidator.Validate(
 			certs,
 			[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 			remoteURL.Hostname(),
-			ch.trustedCertsPath,
-			ch.trustedCRLsPath,
-			ch.issuerCertsPath,
-			ch.issuerCRLsPath,
-			ch.rejectedCertsPath,
 			ch.suppressHostNameInvalid,
 			ch.suppressCertificateExpired,
 			ch.suppressCertificateChainIncomplete,
@@ -300,7 +297,18 @@ func (ch *clientSecureChannel) Open(ctx context.Context) error {
 		}
 	}
 
-	ch.conn, err = net.DialTimeout("tcp", remoteURL.Host, time.Duration(ch.connectTimeout)*time.Millisecond)
+	switch {
+	case ch.dialer != nil:
+		ch.conn, err = ch.dialer(ctx)
+	case remoteURL.Scheme == "opc.wss":
+		ch.conn, err = ua.DialWebSocket(
+			ch.endpointURL,
+			&tls.Config{InsecureSkipVerify: true, VerifyPeerCertificate: ch.verifyPeerCertificate},
+			time.Duration(ch.connectTimeout)*time.Millisecond,
+		)
+	default:
+		ch.conn, err = net.DialTimeout("tcp", remoteURL.Host, time.Duration(ch.connectTimeout)*time.Millisecond)
+	}
 	if err != nil {
 		return err
 	}
@@ -435,7 +443,9 @@ func (ch *clientSecureChannel) Open(ctx context.Context) error {
 
 	ch.pendingResponseCh = make(chan *ua.ServiceOperation, 32)
 	ch.pendingResponses = make(map[uint32]*ua.ServiceOperation)
+	ch.tokenLock.Lock()
 	ch.closed = make(chan struct{})
+	ch.tokenLock.Unlock()
 	ch.channelID = 0
 	ch.tokenID = 0
 	ch.sendingTokenID = 0
@@ -469,6 +479,49 @@ func (ch *clientSecureChannel) Open(ctx context.Context) error {
 	return nil
 }
 
+// verifyPeerCertificate verifies the TLS certificate of a WebSocket connection. The certificate
+// is accepted if it is the validated certificate of the endpoint, if it is trusted by the system roots,
+// or if it is validated by the certificate validator. Use WithInsecureSkipVerify to accept any certificate.
+func (ch *clientSecureChannel) verifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return ua.BadSecurityChecksFailed
+	}
+	if remoteCerts, err := x509.ParseCertificates(ch.remoteCertificate); err == nil && len(remoteCerts) > 0 && bytes.Equal(remoteCerts[0].Raw, rawCerts[0]) {
+		return nil
+	}
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return ua.BadSecurityChecksFailed
+		}
+		certs = append(certs, cert)
+	}
+	remoteURL, err := url.Parse(ch.endpointURL)
+	if err != nil {
+		return err
+	}
+	// a connection for discovery only has no certificate of the endpoint, so may use a certificate of a public authority.
+	if len(ch.remoteCertificate) == 0 {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		if _, err := certs[0].Verify(x509.VerifyOptions{DNSName: remoteURL.Hostname(), Intermediates: intermediates}); err == nil {
+			return nil
+		}
+	}
+	return ch.certificateValidator.Validate(
+		certs,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		remoteURL.Hostname(),
+		ch.suppressHostNameInvalid,
+		ch.suppressCertificateExpired,
+		ch.suppressCertificateChainIncomplete,
+		ch.suppressCertificateRevocationUnknown,
+	)
+}
+
 // Close closes the channel.
 func (ch *clientSecureChannel) Close(ctx context.Context) error {
 	ch.Lock()
@@ -495,6 +548,20 @@ func (ch *clientSecureChannel) Abort(ctx context.Context) error {
 	return nil
 }
 
+// Closed returns a channel that is closed when the connection is closed.
+func (ch *clientSecureChannel) Closed() <-chan struct{} {
+	ch.tokenLock.RLock()
+	defer ch.tokenLock.RUnlock()
+	return ch.closed
+}
+
+// StatusCode returns the reason the connection was closed, or Good if the channel was closed by the client.
+func (ch *clientSecureChannel) StatusCode() ua.StatusCode {
+	ch.tokenLock.RLock()
+	defer ch.tokenLock.RUnlock()
+	return ch.statusCode
+}
+
 // IsClosing returns true when the channel is closing.
 func (ch *clientSecureChannel) IsClosing() bool {
 	ch.Lock()
@@ -1348,16 +1415,18 @@ func (ch *clientSecureChannel) sendServiceRequest(ctx context.Context, request u
 
 // responseWorker starts a task to receive service responses from transport channel.
 func (ch *clientSecureChannel) responseWorker() {
+	closed := ch.closed
 	for {
 		res, status := ch.readResponse()
 		if status != ua.Good {
+			ch.tokenLock.Lock()
 			if ch.closing {
 				ch.statusCode = ua.Good
-				close(ch.closed)
-				return
+			} else {
+				ch.statusCode = status
 			}
-			ch.statusCode = status
-			close(ch.closed)
+			ch.tokenLock.Unlock()
+			close(closed)
 			return
 		}
 		ch.handleResponse(res)
@@ -29,39 +29,15 @@ func (ch *clientSecureChannel) GetEndpoints(ctx context.Context, request *ua.Get
 }
 
 // FindServers returns the Servers known to a Server or Discovery Server.
+// The options set the verification of the TLS certificate of an opc.wss server, as for Dial.
 // See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.4.2/
-func FindServers(ctx context.Context, request *ua.FindServersRequest) (*ua.FindServersResponse, error) {
-	ch := newClientSecureChannel(
-		ua.ApplicationDescription{
-			ApplicationName: ua.LocalizedText{Text: "DiscoveryClient"},
-			ApplicationType: ua.ApplicationTypeClient,
-		},
-		nil,
-		nil,
-		request.EndpointURL,
-		ua.SecurityPolicyURINone,
-		ua.MessageSecurityModeNone,
-		nil,
-		defaultConnectTimeout,
-		"",
-		"",
-		"",
-		"",
-		"",
-		false,
-		false,
-		false,
-		false,
-		defaultTimeoutHint,
-		defaultDiagnosticsHint,
-		defaultTokenRequestedLifetime,
-		defaultMaxBufferSize,
-		defaultMaxMessageSize,
-		defaultMaxChunkCount,
-		false,
-	)
-
-	err := ch.Open(ctx)
+func FindServers(ctx context.Context, request *ua.FindServersRequest, opts ...Option) (*ua.FindServersResponse, error) {
+	cli, err := newDiscoveryClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	ch := cli.newDiscoveryChannel(request.EndpointURL)
+	err = ch.Open(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -79,29 +55,69 @@ func FindServers(ctx context.Context, request *ua.FindServersRequest) (*ua.FindS
 }
 
 // GetEndpoints returns the endpoint descriptions supported by the server.
+// The options set the verification of the TLS certificate of an opc.wss server, as for Dial.
 // See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.4.4/
-func GetEndpoints(ctx context.Context, request *ua.GetEndpointsRequest) (*ua.GetEndpointsResponse, error) {
-	ch := newClientSecureChannel(
+func GetEndpoints(ctx context.Context, request *ua.GetEndpointsRequest, opts ...Option) (*ua.GetEndpointsResponse, error) {
+	cli, err := newDiscoveryClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return cli.getEndpoints(ctx, request)
+}
+
+// getEndpoints returns the endpoint descriptions supported by the server, connecting with the dialer of the client.
+func (ch *Client) getEndpoints(ctx context.Context, request *ua.GetEndpointsRequest) (*ua.GetEndpointsResponse, error) {
+	dc := ch.newDiscoveryChannel(request.EndpointURL)
+	err := dc.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := dc.GetEndpoints(ctx, request)
+	if err != nil {
+		dc.Abort(ctx)
+		return nil, err
+	}
+	err = dc.Close(ctx)
+	if err != nil {
+		dc.Abort(ctx)
+		return nil, err
+	}
+	return res, nil
+}
+
+// newDiscoveryClient returns a client with the options applied, for the certificate validator and
+// verification flags of the discovery services.
+func newDiscoveryClient(opts []Option) (*Client, error) {
+	cli := &Client{}
+	for _, opt := range opts {
+		if err := opt(cli); err != nil {
+			return nil, err
+		}
+	}
+	cli.initCertificateValidator()
+	return cli, nil
+}
+
+// newDiscoveryChannel returns a channel without security, for the discovery services. The TLS certificate
+// of an opc.wss server is verified with the certificate validator and verification flags of the client.
+func (ch *Client) newDiscoveryChannel(endpointURL string) *clientSecureChannel {
+	dc := newClientSecureChannel(
 		ua.ApplicationDescription{
 			ApplicationName: ua.LocalizedText{Text: "DiscoveryClient"},
 			ApplicationType: ua.ApplicationTypeClient,
 		},
 		nil,
 		nil,
-		request.EndpointURL,
+		endpointURL,
 		ua.SecurityPolicyURINone,
 		ua.MessageSecurityModeNone,
 		nil,
 		defaultConnectTimeout,
-		"",
-		"",
-		"",
-		"",
-		"",
-		false,
-		false,
-		false,
-		false,
+		ch.certificateValidator,
+		ch.suppressHostNameInvalid,
+		ch.suppressCertificateExpired,
+		ch.suppressCertificateChainIncomplete,
+		ch.suppressCertificateRevocationUnknown,
 		defaultTimeoutHint,
 		defaultDiagnosticsHint,
 		defaultTokenRequestedLifetime,
@@ -110,28 +126,45 @@ func GetEndpoints(ctx context.Context, request *ua.GetEndpointsRequest) (*ua.Get
 		defaultMaxChunkCount,
 		false,
 	)
+	dc.dialer = ch.dialer()
+	return dc
+}
 
-	err := ch.Open(ctx)
+// FindServersOnNetwork returns the Servers known to a Discovery Server.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.4.3/
+func (ch *Client) FindServersOnNetwork(ctx context.Context, request *ua.FindServersOnNetworkRequest) (*ua.FindServersOnNetworkResponse, error) {
+	response, err := ch.request(ctx, request)
 	if err != nil {
 		return nil, err
 	}
-	res, err := ch.GetEndpoints(ctx, request)
+	return response.(*ua.FindServersOnNetworkResponse), nil
+}
+
+// RegisterServer registers a Server with a Discovery Server. The client certificate must be the certificate of the Server.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.4.5/
+func (ch *Client) RegisterServer(ctx context.Context, request *ua.RegisterServerRequest) (*ua.RegisterServerResponse, error) {
+	response, err := ch.request(ctx, request)
 	if err != nil {
-		ch.Abort(ctx)
 		return nil, err
 	}
-	err = ch.Close(ctx)
+	return response.(*ua.RegisterServerResponse), nil
+}
+
+// RegisterServer2 registers a Server with a Discovery Server, with the configuration for mDNS discovery.
+// The client certificate must be the certificate of the Server.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.4.6/
+func (ch *Client) RegisterServer2(ctx context.Context, request *ua.RegisterServer2Request) (*ua.RegisterServer2Response, error) {
+	response, err := ch.request(ctx, request)
 	if err != nil {
-		ch.Abort(ctx)
 		return nil, err
 	}
-	return res, nil
+	return response.(*ua.RegisterServer2Response), nil
 }
 
 // / Create a Session.
 // See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.6.2/
 func (ch *Client) createSession(ctx context.Context, request *ua.CreateSessionRequest) (*ua.CreateSessionResponse, error) {
-	response, err := ch.request(ctx, request)
+	response, err := ch.channel.Request(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -141,7 +174,7 @@ func (ch *Client) createSession(ctx context.Context, request *ua.CreateSessionRe
 // Activate a session.
 // See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.6.3/
 func (ch *Client) activateSession(ctx context.Context, request *ua.ActivateSessionRequest) (*ua.ActivateSessionResponse, error) {
-	response, err := ch.request(ctx, request)
+	response, err := ch.channel.Request(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +184,7 @@ func (ch *Client) activateSession(ctx context.Context, request *ua.ActivateSessi
 // Close a session.
 // See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.6.4/
 func (ch *Client) closeSession(ctx context.Context, request *ua.CloseSessionRequest) (*ua.CloseSessionResponse, error) {
-	response, err := ch.request(ctx, request)
+	response, err := ch.channel.Request(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -365,7 +398,9 @@ func (ch *Client) CreateSubscription(ctx context.Context, request *ua.CreateSubs
 	if err != nil {
 		return nil, err
 	}
-	return response.(*ua.CreateSubscriptionResponse), nil
+	res := response.(*ua.CreateSubscriptionResponse)
+	ch.trackSubscription(res.SubscriptionID)
+	return res, nil
 }
 
 // ModifySubscription modifies a Subscription.
@@ -391,11 +426,40 @@ func (ch *Client) SetPublishingMode(ctx context.Context, request *ua.SetPublishi
 // Publish requests the Server to return a NotificationMessage or a keep-alive Message.
 // See https://reference.opcfoundation.org/v104/Core/docs/Part4/5.13.5/
 func (ch *Client) Publish(ctx context.Context, request *ua.PublishRequest) (*ua.PublishResponse, error) {
-	response, err := ch.request(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-	return response.(*ua.PublishResponse), nil
+	// return notifications recovered after a reconnect first.
+	if res, ok := ch.dequeueRepublished(request); ok {
+		return res, nil
+	}
+	acks := ch.takeDeferredAcks()
+	sent := request
+	if len(acks) > 0 {
+		req := *request
+		req.SubscriptionAcknowledgements = append(acks, request.SubscriptionAcknowledgements...)
+		sent = &req
+	}
+	response, err := ch.request(ctx, sent)
+	if err != nil {
+		ch.subscriptionsLock.Lock()
+		ch.deferredAcks = append(acks, ch.deferredAcks...)
+		ch.subscriptionsLock.Unlock()
+		return nil, err
+	}
+	res := response.(*ua.PublishResponse)
+	if len(acks) > 0 {
+		// return the results of the caller's acknowledgements, found by the acknowledgement that was sent.
+		results := make(map[ua.SubscriptionAcknowledgement]ua.StatusCode, len(res.Results))
+		for i, ack := range sent.SubscriptionAcknowledgements {
+			if i < len(res.Results) {
+				results[ack] = res.Results[i]
+			}
+		}
+		res.Results = make([]ua.StatusCode, len(request.SubscriptionAcknowledgements))
+		for i, ack := range request.SubscriptionAcknowledgements {
+			res.Results[i] = results[ack]
+		}
+	}
+	ch.trackPublish(res)
+	return res, nil
 }
 
 // Republish requests the Server to republish a NotificationMessage from its retransmission queue.
@@ -415,7 +479,13 @@ func (ch *Client) TransferSubscriptions(ctx context.Context, request *ua.Transfe
 	if err != nil {
 		return nil, err
 	}
-	return response.(*ua.TransferSubscriptionsResponse), nil
+	res := response.(*ua.TransferSubscriptionsResponse)
+	for i, r := range res.Results {
+		if r.StatusCode.IsGood() && i < len(request.SubscriptionIDs) {
+			ch.trackSubscription(request.SubscriptionIDs[i])
+		}
+	}
+	return res, nil
 }
 
 // DeleteSubscriptions deletes one or more Subscriptions.
@@ -425,5 +495,11 @@ func (ch *Client) DeleteSubscriptions(ctx context.Context, request *ua.DeleteSub
 	if err != nil {
 		return nil, err
 	}
-	return response.(*ua.DeleteSubscriptionsResponse), nil
+	res := response.(*ua.DeleteSubscriptionsResponse)
+	for i, r := range res.Results {
+		if r.IsGood() && i < len(request.SubscriptionIDs) {
+			ch.untrackSubscription(request.SubscriptionIDs[i])
+		}
+	}
+	return res, nil
 }
@@ -10,13 +10,16 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/binary"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"math/big"
 	"net"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,7 +30,9 @@ import (
 )
 
 var (
-	endpointURL = fmt.Sprintf("opc.tcp://%s:%d", host, port) // our testserver
+	endpointURL      = fmt.Sprintf("opc.tcp://%s:%d", host, port)    // our testserver
+	wssEndpointURL   = fmt.Sprintf("opc.wss://%s:%d", host, port+4)  // our testserver, over WebSocket
+	reverseClientURL = fmt.Sprintf("opc.tcp://localhost:%d", port+5) // our testserver connects to the client here
 )
 
 // TestMain is run at the start of client testing. If an opcua server is not already running,
@@ -53,6 +58,13 @@ func TestMain(m *testing.M) {
 				os.Exit(3)
 			}
 		}()
+		// wait until the server is listening.
+		for i := 0; i < 50; i++ {
+			if _, err := client.FindServers(context.Background(), &ua.FindServersRequest{EndpointURL: endpointURL}); err == nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
 	}
 	// run the tests
 	res := m.Run()
@@ -145,6 +157,223 @@ func TestOpenClientWithSecurity(t *testing.T) {
 	}
 }
 
+// TestOpenClientOverWebSocket tests opening a connection with a server over the WebSocket transport using each security policy the server offers.
+func TestOpenClientOverWebSocket(t *testing.T) {
+	ctx := context.Background()
+	res, err := client.GetEndpoints(context.Background(), &ua.GetEndpointsRequest{EndpointURL: wssEndpointURL, ProfileURIs: []string{ua.TransportProfileURIWssTransport}}, client.WithInsecureSkipVerify())
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling GetEndpoints"))
+		return
+	}
+	if len(res.Endpoints) == 0 {
+		t.Error(errors.New("Error calling GetEndpoints. No endpoints for the WebSocket transport"))
+		return
+	}
+	for _, e := range res.Endpoints {
+		if e.EndpointURL != wssEndpointURL || e.TransportProfileURI != ua.TransportProfileURIWssTransport {
+			t.Error(errors.Errorf("Error calling GetEndpoints. Unexpected endpoint: %s, %s", e.EndpointURL, e.TransportProfileURI))
+			return
+		}
+		ch, err := client.Dial(
+			ctx,
+			wssEndpointURL,
+			client.WithSecurityPolicyURI(e.SecurityPolicyURI, e.SecurityMode),
+			client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+			client.WithInsecureSkipVerify(),
+			client.WithUserNameIdentity("root", "secret"),
+		)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error connecting to server"))
+			return
+		}
+		req := &ua.ReadRequest{
+			NodesToRead: []ua.ReadValueID{
+				{NodeID: ua.VariableIDServerServerStatus, AttributeID: ua.AttributeIDValue},
+			},
+		}
+		res, err := ch.Read(ctx, req)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error reading"))
+			ch.Abort(ctx)
+			return
+		}
+		if _, ok := res.Results[0].Value.(ua.ServerStatusDataType); !ok {
+			t.Error(errors.New("Error reading ServerStatus"))
+		}
+		t.Logf("Success connecting to server: %s", ch.EndpointURL())
+		t.Logf("  SecurityPolicyURI: %s", ch.SecurityPolicyURI())
+		t.Logf("  SecurityMode: %s", ch.SecurityMode())
+		err = ch.Close(ctx)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error closing client"))
+			ch.Abort(ctx)
+			return
+		}
+	}
+}
+
+// TestGetEndpointsOverWebSocket tests that the TLS certificate of the server is verified when getting the endpoints over the WebSocket transport.
+func TestGetEndpointsOverWebSocket(t *testing.T) {
+	req := &ua.GetEndpointsRequest{EndpointURL: wssEndpointURL, ProfileURIs: []string{ua.TransportProfileURIWssTransport}}
+	if _, err := client.GetEndpoints(context.Background(), req); err == nil {
+		t.Error(errors.New("Error calling GetEndpoints. Expected the untrusted certificate to be rejected"))
+		return
+	}
+	if _, err := client.GetEndpoints(context.Background(), req, client.WithTrustedCertificatesPaths("./pki/server.crt", "")); err != nil {
+		t.Error(errors.Wrap(err, "Error calling GetEndpoints with trusted certificate"))
+		return
+	}
+	if _, err := client.GetEndpoints(context.Background(), req, client.WithInsecureSkipVerify()); err != nil {
+		t.Error(errors.Wrap(err, "Error calling GetEndpoints with InsecureSkipVerify"))
+		return
+	}
+}
+
+// TestListenReverse tests opening a connection with a server that connects to the client.
+func TestListenReverse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ch, err := client.ListenReverse(
+		ctx,
+		fmt.Sprintf(":%d", port+5),
+		client.WithSecurityPolicyURI(ua.SecurityPolicyURIBasic256Sha256, ua.MessageSecurityModeSignAndEncrypt),
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error listening for server"))
+		return
+	}
+	if ch.EndpointURL() != endpointURL {
+		t.Error(errors.Errorf("Error listening for server. got: %s, want: %s", ch.EndpointURL(), endpointURL))
+	}
+	req := &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{
+			{NodeID: ua.VariableIDServerServerStatus, AttributeID: ua.AttributeIDValue},
+		},
+	}
+	res, err := ch.Read(ctx, req)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading"))
+		ch.Abort(ctx)
+		return
+	}
+	if _, ok := res.Results[0].Value.(ua.ServerStatusDataType); !ok {
+		t.Error(errors.New("Error reading ServerStatus"))
+	}
+	t.Logf("Success connecting to server: %s", ch.EndpointURL())
+	t.Logf("  SecurityPolicyURI: %s", ch.SecurityPolicyURI())
+	t.Logf("  SecurityMode: %s", ch.SecurityMode())
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+// TestCloseListenReverse tests closing a client while it waits for the server to connect again.
+func TestCloseListenReverse(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	addr := fmt.Sprintf("localhost:%d", port+13)
+	// connect to the client like a server would, relaying each connection to the testserver,
+	// until stopped.
+	var lock sync.Mutex
+	var conns []net.Conn
+	stopped := false
+	go func() {
+		for {
+			lock.Lock()
+			if stopped {
+				lock.Unlock()
+				return
+			}
+			lock.Unlock()
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			remote, err := net.Dial("tcp", net.JoinHostPort(host, fmt.Sprint(port)))
+			if err != nil {
+				conn.Close()
+				return
+			}
+			lock.Lock()
+			conns = append(conns, conn, remote)
+			lock.Unlock()
+			if _, err := conn.Write(reverseHello(endpointURL)); err != nil {
+				conn.Close()
+				remote.Close()
+				continue
+			}
+			go func() { io.Copy(remote, conn); remote.Close() }()
+			io.Copy(conn, remote)
+			conn.Close()
+		}
+	}()
+	states := make(chan client.ConnectionState, 8)
+	ch, err := client.ListenReverse(
+		ctx,
+		addr,
+		client.WithSecurityPolicyURI(ua.SecurityPolicyURIBasic256Sha256, ua.MessageSecurityModeSignAndEncrypt),
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+		client.WithConnectTimeout(30000),
+		client.WithReconnect(client.ExponentialBackoff(100*time.Millisecond, time.Second)),
+		client.WithConnectionStateHandler(func(state client.ConnectionState, err error) {
+			states <- state
+		}),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error listening for server"))
+		return
+	}
+
+	// stop connecting, and drop the connection.
+	lock.Lock()
+	stopped = true
+	for _, conn := range conns {
+		conn.Close()
+	}
+	lock.Unlock()
+	select {
+	case got := <-states:
+		if got != client.ConnectionStateReconnecting {
+			t.Errorf("Error in connection state. want: %s, got: %s", client.ConnectionStateReconnecting, got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("Error waiting for connection state %s", client.ConnectionStateReconnecting)
+	}
+	// wait for the client to wait for the ReverseHello, then close it.
+	time.Sleep(500 * time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		ch.Close(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("Error closing client while waiting for the server to connect")
+	}
+}
+
+// reverseHello returns a ReverseHello message from the testserver.
+func reverseHello(endpointURL string) []byte {
+	serverURI := fmt.Sprintf("urn:%s:testserver", host)
+	buf := make([]byte, 16, 16+len(serverURI)+len(endpointURL))
+	binary.LittleEndian.PutUint32(buf[0:4], ua.MessageTypeReverseHello)
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(16+len(serverURI)+len(endpointURL)))
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(len(serverURI)))
+	buf = append(buf[:12], serverURI...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(endpointURL)))
+	return append(buf, endpointURL...)
+}
+
 // TestOpenClientWithX509Identity tests opening a connection with a server using each security policy the server offers.
 func TestOpenClientWithX509Identity(t *testing.T) {
 	ctx := context.Background()
@@ -398,6 +627,84 @@ func TestWrite(t *testing.T) {
 	}
 }
 
+// TestRegisterDataTypeDefinition tests reading the definition of a server data type, then writing and reading a value of the data type.
+func TestRegisterDataTypeDefinition(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	dataTypeID := ua.NodeIDNumeric{NamespaceIndex: 2, ID: 15}
+	def, err := ch.RegisterDataTypeDefinition(ctx, dataTypeID)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error registering data type definition"))
+		ch.Abort(ctx)
+		return
+	}
+	if sd, ok := def.(ua.StructureDefinition); !ok || len(sd.Fields) != 2 {
+		t.Errorf("Error registering data type definition. Unexpected definition: %v", def)
+		ch.Abort(ctx)
+		return
+	}
+	if _, err := ch.RegisterDataTypeDefinition(ctx, ua.DataTypeIDDouble); err == nil {
+		t.Error(errors.New("Error registering data type definition. Expected error for a builtin data type"))
+	}
+	value := ua.Structure{
+		TypeID: ua.NewExpandedNodeID(dataTypeID),
+		Fields: []ua.StructureFieldValue{
+			{Name: "X", Value: float64(1.5)},
+			{Name: "Y", Value: float64(-2.5)},
+		},
+	}
+	nodeID := ua.NodeIDNumeric{NamespaceIndex: 2, ID: 17}
+	res, err := ch.Write(ctx, &ua.WriteRequest{
+		NodesToWrite: []ua.WriteValue{
+			{
+				NodeID:      nodeID,
+				AttributeID: ua.AttributeIDValue,
+				Value:       ua.NewDataValue(value, 0, time.Time{}, 0, time.Time{}, 0),
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error writing"))
+		ch.Abort(ctx)
+		return
+	}
+	if res.Results[0].IsBad() {
+		t.Error(errors.Wrap(res.Results[0], "Error writing"))
+		ch.Abort(ctx)
+		return
+	}
+	res2, err := ch.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{
+			{NodeID: nodeID, AttributeID: ua.AttributeIDValue},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading"))
+		ch.Abort(ctx)
+		return
+	}
+	s, ok := res2.Results[0].Value.(ua.Structure)
+	if !ok || len(s.Fields) != 2 || s.Fields[0].Value != float64(1.5) || s.Fields[1].Value != float64(-2.5) {
+		t.Errorf("Error reading. Unexpected value: %v", res2.Results[0].Value)
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
 // TestReadIndexRange tests reading the first three elements of a server array variable.
 func TestReadIndexRange(t *testing.T) {
 	ctx := context.Background()
@@ -634,6 +941,105 @@ func TestSubscribe(t *testing.T) {
 	}
 }
 
+// TestSubscription tests receiving data changes and events through a Subscription.
+func TestSubscription(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithInsecureSkipVerify(),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	sub, err := client.NewSubscription(
+		ctx,
+		ch,
+		&ua.CreateSubscriptionRequest{
+			RequestedPublishingInterval: 500.0,
+			RequestedMaxKeepAliveCount:  30,
+			RequestedLifetimeCount:      30 * 3,
+			PublishingEnabled:           true,
+		},
+		client.WithPublishRequestCount(3),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	values := make(chan ua.DataValue, 16)
+	_, err = sub.CreateDataChangeItem(
+		ctx,
+		ua.MonitoredItemCreateRequest{
+			ItemToMonitor: ua.ReadValueID{
+				AttributeID: ua.AttributeIDValue,
+				NodeID:      ua.VariableIDServerServerStatusCurrentTime,
+			},
+			MonitoringMode: ua.MonitoringModeReporting,
+			RequestedParameters: ua.MonitoringParameters{
+				QueueSize: 1, DiscardOldest: true, SamplingInterval: 500.0,
+			},
+		},
+		client.DataChangeChan(values),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating data change item"))
+		ch.Abort(ctx)
+		return
+	}
+	events := make(chan []ua.Variant, 16)
+	_, err = sub.CreateEventItem(
+		ctx,
+		ua.MonitoredItemCreateRequest{
+			ItemToMonitor: ua.ReadValueID{
+				AttributeID: ua.AttributeIDEventNotifier,
+				NodeID:      ua.ParseNodeID("ns=2;s=Area1"),
+			},
+			MonitoringMode: ua.MonitoringModeReporting,
+			RequestedParameters: ua.MonitoringParameters{
+				QueueSize: 100, DiscardOldest: true, Filter: ua.EventFilter{
+					SelectClauses: ua.BaseEventSelectClauses,
+				},
+			},
+		},
+		client.EventChan(events),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating event item"))
+		ch.Abort(ctx)
+		return
+	}
+	// wait for 3 data changes and 1 event.
+	numChanges, numEvents := 0, 0
+	timeout := time.After(20 * time.Second)
+	for numChanges < 3 || numEvents < 1 {
+		select {
+		case v := <-values:
+			t.Logf(" + CurrentTime: %s", v.Value)
+			numChanges++
+		case e := <-events:
+			t.Logf(" + Event: %s", e[3])
+			numEvents++
+		case <-timeout:
+			t.Errorf("Error waiting for notifications. changes: %d, events: %d", numChanges, numEvents)
+			ch.Abort(ctx)
+			return
+		}
+	}
+	err = sub.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing subscription"))
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
 // TestSubscribeEvents tests subscribing to receive events from Area1.
 func TestSubscribeEvents(t *testing.T) {
 	ctx := context.Background()
@@ -923,6 +1329,294 @@ func TestTranslate(t *testing.T) {
 	}
 }
 
+// TestReconnect tests recovering the session and subscription after the connection is lost.
+func TestReconnect(t *testing.T) {
+	ctx := context.Background()
+	proxy, err := newTestProxy(fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error starting proxy"))
+		return
+	}
+	defer proxy.Close()
+	states := make(chan client.ConnectionState, 8)
+	ch, err := client.Dial(
+		ctx,
+		fmt.Sprintf("opc.tcp://%s", proxy.Addr()),
+		client.WithForcedEndpoint(),
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+		client.WithReconnect(client.ExponentialBackoff(100*time.Millisecond, time.Second)),
+		client.WithConnectionStateHandler(func(state client.ConnectionState, err error) {
+			states <- state
+		}),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	if got := ch.State(); got != client.ConnectionStateConnected {
+		t.Errorf("Error in connection state. want: %s, got: %s", client.ConnectionStateConnected, got)
+	}
+	sessionID := ch.SessionID()
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 500.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	_, err = ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			{
+				ItemToMonitor: ua.ReadValueID{
+					AttributeID: ua.AttributeIDValue,
+					NodeID:      ua.VariableIDServerServerStatusCurrentTime,
+				},
+				MonitoringMode: ua.MonitoringModeReporting,
+				RequestedParameters: ua.MonitoringParameters{
+					ClientHandle: 42, QueueSize: 1, DiscardOldest: true, SamplingInterval: 500.0,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating item"))
+		ch.Abort(ctx)
+		return
+	}
+	// receive a data change before and after dropping the connection, acknowledging each message.
+	var acks []ua.SubscriptionAcknowledgement
+	for i := 0; i < 2; i++ {
+		if i == 1 {
+			proxy.Drop()
+			for _, want := range []client.ConnectionState{client.ConnectionStateReconnecting, client.ConnectionStateConnected} {
+				select {
+				case got := <-states:
+					if got != want {
+						t.Errorf("Error in connection state. want: %s, got: %s", want, got)
+					}
+				case <-time.After(10 * time.Second):
+					t.Errorf("Error waiting for connection state %s", want)
+					ch.Abort(ctx)
+					return
+				}
+			}
+			if ch.SessionID() != sessionID {
+				t.Errorf("Error reactivating session. want: %s, got: %s", sessionID, ch.SessionID())
+			}
+		}
+		for received := false; !received; {
+			res, err := ch.Publish(ctx, &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}, SubscriptionAcknowledgements: acks})
+			if err != nil {
+				t.Error(errors.Wrap(err, "Error publishing"))
+				ch.Abort(ctx)
+				return
+			}
+			if len(res.Results) != len(acks) {
+				t.Errorf("Error in acknowledgement results. want: %d, got: %d", len(acks), len(res.Results))
+			}
+			acks = nil
+			if received = len(res.NotificationMessage.NotificationData) > 0; received {
+				acks = append(acks, ua.SubscriptionAcknowledgement{SubscriptionID: res.SubscriptionID, SequenceNumber: res.NotificationMessage.SequenceNumber})
+			}
+		}
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+	if got := ch.State(); got != client.ConnectionStateDisconnected {
+		t.Errorf("Error in connection state. want: %s, got: %s", client.ConnectionStateDisconnected, got)
+	}
+}
+
+// TestReconnectNewSession tests that the client creates a new session and transfers the subscriptions,
+// when the session timed out while the connection was lost.
+func TestReconnectNewSession(t *testing.T) {
+	ctx := context.Background()
+	proxy, err := newTestProxy(fmt.Sprintf("%s:%d", host, port))
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error starting proxy"))
+		return
+	}
+	defer proxy.Close()
+	states := make(chan client.ConnectionState, 64)
+	ch, err := client.Dial(
+		ctx,
+		fmt.Sprintf("opc.tcp://%s", proxy.Addr()),
+		client.WithForcedEndpoint(),
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+		client.WithSessionTimeout(10000),
+		client.WithReconnect(client.ExponentialBackoff(100*time.Millisecond, time.Second)),
+		client.WithConnectionStateHandler(func(state client.ConnectionState, err error) {
+			states <- state
+		}),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	sessionID := ch.SessionID()
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 500.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	_, err = ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			{
+				ItemToMonitor: ua.ReadValueID{
+					AttributeID: ua.AttributeIDValue,
+					NodeID:      ua.VariableIDServerServerStatusCurrentTime,
+				},
+				MonitoringMode: ua.MonitoringModeReporting,
+				RequestedParameters: ua.MonitoringParameters{
+					ClientHandle: 42, QueueSize: 1, DiscardOldest: true, SamplingInterval: 500.0,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating item"))
+		ch.Abort(ctx)
+		return
+	}
+	// lose the connection for longer than the session timeout, so the session expires.
+	proxy.Block(true)
+	proxy.Drop()
+	select {
+	case got := <-states:
+		if got != client.ConnectionStateReconnecting {
+			t.Errorf("Error in connection state. want: %s, got: %s", client.ConnectionStateReconnecting, got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Errorf("Error waiting for connection state %s", client.ConnectionStateReconnecting)
+		ch.Abort(ctx)
+		return
+	}
+	time.Sleep(12 * time.Second)
+	proxy.Block(false)
+	for connected := false; !connected; {
+		select {
+		case got := <-states:
+			connected = got == client.ConnectionStateConnected
+		case <-time.After(10 * time.Second):
+			t.Errorf("Error waiting for connection state %s", client.ConnectionStateConnected)
+			ch.Abort(ctx)
+			return
+		}
+	}
+	if ch.SessionID() == sessionID {
+		t.Errorf("Error creating new session. got: %s", ch.SessionID())
+	}
+	// the subscription was transferred to the new session.
+	for received := false; !received; {
+		res, err := ch.Publish(ctx, &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}})
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error publishing"))
+			ch.Abort(ctx)
+			return
+		}
+		received = len(res.NotificationMessage.NotificationData) > 0
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+// testProxy forwards connections to the testserver, and can drop them to simulate a network failure.
+type testProxy struct {
+	sync.Mutex
+	ln      net.Listener
+	target  string
+	conns   []net.Conn
+	blocked bool
+}
+
+func newTestProxy(target string) (*testProxy, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &testProxy{ln: ln, target: target}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			p.Lock()
+			blocked := p.blocked
+			p.Unlock()
+			if blocked {
+				conn.Close()
+				continue
+			}
+			remote, err := net.Dial("tcp", target)
+			if err != nil {
+				conn.Close()
+				continue
+			}
+			p.Lock()
+			p.conns = append(p.conns, conn, remote)
+			p.Unlock()
+			go func() { io.Copy(remote, conn); remote.Close() }()
+			go func() { io.Copy(conn, remote); conn.Close() }()
+		}
+	}()
+	return p, nil
+}
+
+// Addr returns the address of the proxy.
+func (p *testProxy) Addr() string {
+	return p.ln.Addr().String()
+}
+
+// Drop closes all forwarded connections.
+func (p *testProxy) Drop() {
+	p.Lock()
+	defer p.Unlock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.conns = nil
+}
+
+// Block sets whether new connections are refused, to simulate a network failure that lasts.
+func (p *testProxy) Block(value bool) {
+	p.Lock()
+	defer p.Unlock()
+	p.blocked = value
+}
+
+// Close stops the proxy.
+func (p *testProxy) Close() {
+	p.ln.Close()
+	p.Drop()
+}
+
 func createNewCertificate(appName, certFile, keyFile string) error {
 
 	// Create a keypair.
@@ -0,0 +1,86 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package client_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awcullen/opcua/client"
+	"github.com/awcullen/opcua/ua"
+)
+
+// This example demonstrates subscribing to the server's 'CurrentTime' variable and receiving data changes on a channel.
+func ExampleNewSubscription() {
+
+	ctx := context.Background()
+
+	// open a connection to testserver running locally. Testserver is started if not already running.
+	ch, err := client.Dial(
+		ctx,
+		"opc.tcp://localhost:46010",
+		client.WithInsecureSkipVerify(), // skips verification of server certificate
+	)
+	if err != nil {
+		fmt.Printf("Error opening client connection. %s\n", err.Error())
+		return
+	}
+
+	// create a subscription. The subscription publishes and acknowledges in the background.
+	sub, err := client.NewSubscription(
+		ctx,
+		ch,
+		&ua.CreateSubscriptionRequest{
+			RequestedPublishingInterval: 1000.0,
+			RequestedMaxKeepAliveCount:  30,
+			RequestedLifetimeCount:      30 * 3,
+			PublishingEnabled:           true,
+		},
+	)
+	if err != nil {
+		fmt.Printf("Error creating subscription. %s\n", err.Error())
+		ch.Abort(ctx)
+		return
+	}
+
+	// create an item that sends each data change to a channel.
+	values := make(chan ua.DataValue, 8)
+	_, err = sub.CreateDataChangeItem(
+		ctx,
+		ua.MonitoredItemCreateRequest{
+			ItemToMonitor: ua.ReadValueID{
+				NodeID:      ua.VariableIDServerServerStatusCurrentTime,
+				AttributeID: ua.AttributeIDValue,
+			},
+			MonitoringMode: ua.MonitoringModeReporting,
+			// the ClientHandle is assigned by the subscription.
+			RequestedParameters: ua.MonitoringParameters{
+				QueueSize: 1, DiscardOldest: true, SamplingInterval: 1000.0},
+		},
+		client.DataChangeChan(values),
+	)
+	if err != nil {
+		fmt.Printf("Error creating item. %s\n", err.Error())
+		ch.Abort(ctx)
+		return
+	}
+
+	// receive 3 data changes.
+	for i := 0; i < 3; i++ {
+		<-values
+		fmt.Println("<the current utc time here>" /* value.Value */)
+	}
+
+	// close subscription and connection
+	sub.Close(ctx)
+	err = ch.Close(ctx)
+	if err != nil {
+		ch.Abort(ctx)
+		return
+	}
+
+	// Output:
+	// <the current utc time here>
+	// <the current utc time here>
+	// <the current utc time here>
+}
@@ -175,6 +175,24 @@ func WithRejectedCertificatesPath(path string) Option {
 	}
 }
 
+// WithCertificateStore sets the store of the trusted and issuer certificates and revocation lists,
+// and of the rejected certificates. The store replaces the paths of the certificates.
+func WithCertificateStore(store ua.CertificateStore) Option {
+	return func(c *Client) error {
+		c.certificateStore = store
+		return nil
+	}
+}
+
+// WithCertificateValidator sets the validator of the server certificate, so clients may share the lists
+// and chains that the validator caches. The store of the validator replaces the certificate store.
+func WithCertificateValidator(validator *ua.CertificateValidator) Option {
+	return func(c *Client) error {
+		c.certificateValidator = validator
+		return nil
+	}
+}
+
 // WithInsecureSkipVerify skips verification of server certificate. Skips checking HostName, Expiration, and Authority.
 func WithInsecureSkipVerify() Option {
 	return func(c *Client) error {
@@ -243,3 +261,21 @@ func WithTransportLimits(maxBufferSize, maxMessageSize, maxChunkCount uint32) Op
 		return nil
 	}
 }
+
+// WithReconnect reopens the secure channel and recovers the session when the connection to the server is lost.
+// The backoff returns the delay before each attempt. (default: no reconnect)
+func WithReconnect(backoff Backoff) Option {
+	return func(c *Client) error {
+		c.reconnectBackoff = backoff
+		return nil
+	}
+}
+
+// WithConnectionStateHandler sets a function that is called when the state of the connection changes.
+// The error is the reason the connection was lost, or nil.
+func WithConnectionStateHandler(handler func(state ConnectionState, err error)) Option {
+	return func(c *Client) error {
+		c.stateHandler = handler
+		return nil
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// ConnectionState is the state of the connection between the client and the server.
+type ConnectionState int32
+
+const (
+	// ConnectionStateDisconnected indicates the client is not yet connected, was closed, or stopped trying to reconnect.
+	ConnectionStateDisconnected ConnectionState = iota
+	// ConnectionStateConnected indicates the secure channel is open and the session is active.
+	ConnectionStateConnected
+	// ConnectionStateReconnecting indicates the connection was lost and the client is trying to recover it.
+	ConnectionStateReconnecting
+)
+
+// String returns the name of the connection state.
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateDisconnected:
+		return "Disconnected"
+	case ConnectionStateConnected:
+		return "Connected"
+	case ConnectionStateReconnecting:
+		return "Reconnecting"
+	default:
+		return "Unknown"
+	}
+}
+
+// Backoff returns the delay before the given reconnect attempt, starting at 1.
+// Return a negative delay to stop reconnecting.
+type Backoff func(attempt int) time.Duration
+
+// ExponentialBackoff returns a Backoff that starts at min and doubles each attempt, up to max. It never stops reconnecting.
+func ExponentialBackoff(min, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := min
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// State gets the state of the connection to the server.
+func (ch *Client) State() ConnectionState {
+	ch.stateLock.RLock()
+	defer ch.stateLock.RUnlock()
+	return ch.state
+}
+
+// setState sets the state of the connection and calls the state handler, if the state changed.
+func (ch *Client) setState(state ConnectionState, err error) {
+	ch.stateLock.Lock()
+	if ch.state == state {
+		ch.stateLock.Unlock()
+		return
+	}
+	ch.state = state
+	ch.stateLock.Unlock()
+	if ch.stateHandler != nil {
+		ch.stateHandler(state, err)
+	}
+}
+
+// stop signals the monitor that the client is closing.
+func (ch *Client) stop() {
+	ch.stopOnce.Do(func() {
+		close(ch.done)
+		if ch.reverseListener != nil {
+			ch.reverseListener.Close()
+		}
+	})
+}
+
+// isStopped returns true when the client is closing.
+func (ch *Client) isStopped() bool {
+	select {
+	case <-ch.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// monitor waits for the secure channel to fail, then reconnects. Runs until the client is closed.
+func (ch *Client) monitor() {
+	for {
+		select {
+		case <-ch.done:
+			return
+		case <-ch.channel.Closed():
+		}
+		if ch.isStopped() {
+			return
+		}
+		var err error = ch.channel.StatusCode()
+		ch.setState(ConnectionStateReconnecting, err)
+		if err := ch.reconnect(err); err != nil {
+			ch.setState(ConnectionStateDisconnected, err)
+			return
+		}
+		ch.setState(ConnectionStateConnected, nil)
+	}
+}
+
+// reconnect reopens the secure channel and recovers the session, waiting between attempts as directed by the backoff.
+func (ch *Client) reconnect(err error) error {
+	for attempt := 1; ; attempt++ {
+		delay := ch.reconnectBackoff(attempt)
+		if delay < 0 {
+			return err
+		}
+		select {
+		case <-ch.done:
+			return ua.BadSecureChannelClosed
+		case <-time.After(delay):
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(ch.connectTimeout)*time.Millisecond)
+		err = ch.recover(ctx)
+		cancel()
+		if err == nil {
+			if ch.isStopped() {
+				ch.channel.Abort(context.Background())
+				return ua.BadSecureChannelClosed
+			}
+			return nil
+		}
+		ch.channel.Abort(context.Background())
+	}
+}
+
+// recover opens a new secure channel and activates the existing session on it.
+// If the session is gone, it creates a new session and transfers the subscriptions to it.
+func (ch *Client) recover(ctx context.Context) error {
+	if err := ch.channel.Open(ctx); err != nil {
+		return err
+	}
+	if err := ch.activate(ctx, ch.serverNonce); err == nil {
+		return nil
+	}
+	if err := ch.openSession(ctx); err != nil {
+		return err
+	}
+	if err := ch.readServerArrays(ctx); err != nil {
+		return err
+	}
+	return ch.transferSubscriptions(ctx)
+}
+
+// transferSubscriptions transfers the subscriptions of the lost session to the current session,
+// then republishes any notifications that were not received before the connection was lost.
+func (ch *Client) transferSubscriptions(ctx context.Context) error {
+	ch.subscriptionsLock.Lock()
+	ids := make([]uint32, 0, len(ch.subscriptions))
+	for id := range ch.subscriptions {
+		ids = append(ids, id)
+	}
+	ch.subscriptionsLock.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	res, err := ch.channel.Request(ctx, &ua.TransferSubscriptionsRequest{
+		SubscriptionIDs:   ids,
+		SendInitialValues: true,
+	})
+	if err != nil {
+		return err
+	}
+	results := res.(*ua.TransferSubscriptionsResponse).Results
+	for i, id := range ids {
+		if i >= len(results) || results[i].StatusCode.IsBad() {
+			ch.untrackSubscription(id)
+			continue
+		}
+		ch.subscriptionsLock.Lock()
+		last := ch.subscriptions[id]
+		ch.subscriptionsLock.Unlock()
+		seqs := append([]uint32{}, results[i].AvailableSequenceNumbers...)
+		sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+		for _, seq := range seqs {
+			if seq <= last {
+				continue
+			}
+			res, err := ch.channel.Request(ctx, &ua.RepublishRequest{
+				SubscriptionID:           id,
+				RetransmitSequenceNumber: seq,
+			})
+			if err != nil {
+				// the message may have been discarded in the meantime.
+				continue
+			}
+			ch.subscriptionsLock.Lock()
+			ch.republished = append(ch.republished, &ua.PublishResponse{
+				SubscriptionID:      id,
+				NotificationMessage: res.(*ua.RepublishResponse).NotificationMessage,
+			})
+			ch.subscriptionsLock.Unlock()
+		}
+	}
+	return nil
+}
+
+// trackSubscription records a subscription to recover after a reconnect.
+func (ch *Client) trackSubscription(id uint32) {
+	ch.subscriptionsLock.Lock()
+	defer ch.subscriptionsLock.Unlock()
+	if _, ok := ch.subscriptions[id]; !ok {
+		ch.subscriptions[id] = 0
+	}
+}
+
+// untrackSubscription forgets a subscription.
+func (ch *Client) untrackSubscription(id uint32) {
+	ch.subscriptionsLock.Lock()
+	defer ch.subscriptionsLock.Unlock()
+	delete(ch.subscriptions, id)
+}
+
+// trackPublish records the sequence number of the last notification received for a subscription.
+func (ch *Client) trackPublish(res *ua.PublishResponse) {
+	if len(res.NotificationMessage.NotificationData) == 0 {
+		return
+	}
+	ch.subscriptionsLock.Lock()
+	defer ch.subscriptionsLock.Unlock()
+	if last, ok := ch.subscriptions[res.SubscriptionID]; ok && res.NotificationMessage.SequenceNumber > last {
+		ch.subscriptions[res.SubscriptionID] = res.NotificationMessage.SequenceNumber
+	}
+}
+
+// dequeueRepublished returns the next notification recovered after a reconnect, if any.
+// The acknowledgements of the request are deferred to the next PublishRequest sent to the server.
+func (ch *Client) dequeueRepublished(request *ua.PublishRequest) (*ua.PublishResponse, bool) {
+	ch.subscriptionsLock.Lock()
+	defer ch.subscriptionsLock.Unlock()
+	if len(ch.republished) == 0 {
+		return nil, false
+	}
+	res := ch.republished[0]
+	ch.republished = ch.republished[1:]
+	ch.deferredAcks = append(ch.deferredAcks, request.SubscriptionAcknowledgements...)
+	res.ResponseHeader = ua.ResponseHeader{
+		Timestamp:     time.Now(),
+		RequestHandle: request.RequestHandle,
+	}
+	res.MoreNotifications = len(ch.republished) > 0
+	res.Results = make([]ua.StatusCode, len(request.SubscriptionAcknowledgements))
+	return res, true
+}
+
+// takeDeferredAcks removes and returns the acknowledgements deferred by dequeueRepublished.
+func (ch *Client) takeDeferredAcks() []ua.SubscriptionAcknowledgement {
+	ch.subscriptionsLock.Lock()
+	defer ch.subscriptionsLock.Unlock()
+	acks := ch.deferredAcks
+	ch.deferredAcks = nil
+	return acks
+}
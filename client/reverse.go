@@ -0,0 +1,141 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// ListenReverse listens on the address for a server to connect and send a ReverseHello message.
+// The addr is in the form [host]:[port]
+// The client opens a secure channel and session with the EndpointUrl sent by the server, over connections
+// from the server. The client continues to accept connections from the same server, for reconnecting,
+// until the client is closed.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part6/7.1.3/
+func ListenReverse(ctx context.Context, addr string, opts ...Option) (*Client, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	rl := &reverseListener{ln: ln}
+	conn, serverURI, endpointURL, err := rl.accept(ctx, "")
+	if err != nil {
+		ln.Close()
+		return nil, err
+	}
+	rl.serverURI = serverURI
+	rl.pending = conn
+
+	cli, err := Dial(ctx, endpointURL, append(opts, withReverseListener(rl))...)
+	if err != nil {
+		rl.Close()
+		return nil, err
+	}
+	return cli, nil
+}
+
+// withReverseListener sets the client to open secure channels over the connections accepted by the listener.
+func withReverseListener(rl *reverseListener) Option {
+	return func(c *Client) error {
+		c.reverseListener = rl
+		return nil
+	}
+}
+
+// reverseListener accepts connections from a server that sends a ReverseHello message.
+type reverseListener struct {
+	sync.Mutex
+	ln        net.Listener
+	serverURI string
+	pending   net.Conn
+}
+
+// dial returns the next connection from the server. The lock is not held while waiting,
+// so the listener may be closed meanwhile.
+func (rl *reverseListener) dial(ctx context.Context) (net.Conn, error) {
+	rl.Lock()
+	conn := rl.pending
+	rl.pending = nil
+	rl.Unlock()
+	if conn != nil {
+		return conn, nil
+	}
+	conn, _, _, err := rl.accept(ctx, rl.serverURI)
+	return conn, err
+}
+
+// accept waits for a connection and reads the ReverseHello message. Connections from servers other than
+// the serverURI are closed, unless the serverURI is empty.
+func (rl *reverseListener) accept(ctx context.Context, serverURI string) (net.Conn, string, string, error) {
+	if ln, ok := rl.ln.(*net.TCPListener); ok {
+		stop := context.AfterFunc(ctx, func() { ln.SetDeadline(time.Now()) })
+		defer func() {
+			stop()
+			ln.SetDeadline(time.Time{})
+		}()
+	}
+	for {
+		conn, err := rl.ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, "", "", ctx.Err()
+			}
+			return nil, "", "", err
+		}
+		remoteServerURI, endpointURL, err := readReverseHello(conn)
+		if err != nil || (serverURI != "" && remoteServerURI != serverURI) {
+			conn.Close()
+			continue
+		}
+		return conn, remoteServerURI, endpointURL, nil
+	}
+}
+
+// Close closes the listener and any pending connection.
+func (rl *reverseListener) Close() error {
+	rl.Lock()
+	if rl.pending != nil {
+		rl.pending.Close()
+		rl.pending = nil
+	}
+	rl.Unlock()
+	return rl.ln.Close()
+}
+
+// readReverseHello reads the ServerUri and EndpointUrl of the ReverseHello message.
+func readReverseHello(conn net.Conn) (string, string, error) {
+	conn.SetReadDeadline(time.Now().Add(time.Duration(defaultConnectTimeout) * time.Millisecond))
+	defer conn.SetReadDeadline(time.Time{})
+	var header [8]byte
+	if _, err := io.ReadFull(conn, header[:]); err != nil {
+		return "", "", err
+	}
+	if binary.LittleEndian.Uint32(header[0:4]) != ua.MessageTypeReverseHello {
+		return "", "", ua.BadTCPMessageTypeInvalid
+	}
+	msgLen := binary.LittleEndian.Uint32(header[4:8])
+	if msgLen < 16 || msgLen > defaultMaxBufferSize {
+		return "", "", ua.BadTCPMessageTooLarge
+	}
+	body := make([]byte, msgLen-8)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return "", "", err
+	}
+	var dec = ua.NewBinaryDecoder(bytes.NewReader(body), ua.NewEncodingContext())
+	var serverURI, endpointURL string
+	if err := dec.ReadString(&serverURI); err != nil {
+		return "", "", ua.BadDecodingError
+	}
+	if err := dec.ReadString(&endpointURL); err != nil {
+		return "", "", ua.BadDecodingError
+	}
+	return serverURI, endpointURL, nil
+}
@@ -0,0 +1,391 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+const (
+	// defaultPublishRequestCount is the default number of publish requests kept outstanding by a subscription.
+	defaultPublishRequestCount int = 2
+	// publishRetryDelay is the time to wait before publishing again after an error.
+	publishRetryDelay = time.Second
+)
+
+// SubscriptionOption is a functional option to be applied to a subscription during initialization.
+type SubscriptionOption func(*Subscription) error
+
+// WithPublishRequestCount sets the number of publish requests the subscription keeps outstanding. (default: 2)
+func WithPublishRequestCount(value int) SubscriptionOption {
+	return func(s *Subscription) error {
+		if value < 1 {
+			return ua.BadInvalidArgument
+		}
+		s.publishRequestCount = value
+		return nil
+	}
+}
+
+// WithTimestampsToReturn sets the timestamps returned with the values of the monitored items. (default: Both)
+func WithTimestampsToReturn(value ua.TimestampsToReturn) SubscriptionOption {
+	return func(s *Subscription) error {
+		s.timestampsToReturn = value
+		return nil
+	}
+}
+
+// WithStatusChangeHandler sets a function that is called when the server reports a change to the status of the subscription.
+func WithStatusChangeHandler(handler func(status ua.StatusCode)) SubscriptionOption {
+	return func(s *Subscription) error {
+		s.statusChangeHandler = handler
+		return nil
+	}
+}
+
+// Subscription runs the publish pipeline of a subscription, acknowledges the notifications received,
+// and delivers them to the handlers of its monitored items.
+// A client that uses Subscriptions should not call Publish itself.
+type Subscription struct {
+	sync.Mutex
+	client                    *Client
+	id                        uint32
+	revisedPublishingInterval float64
+	revisedLifetimeCount      uint32
+	revisedMaxKeepAliveCount  uint32
+	publishRequestCount       int
+	timestampsToReturn        ua.TimestampsToReturn
+	statusChangeHandler       func(ua.StatusCode)
+	itemsLock                 sync.RWMutex
+	items                     map[uint32]*MonitoredItem
+	nextClientHandle          uint32
+	lastSequenceNumber        uint32
+	done                      chan struct{}
+	closeOnce                 sync.Once
+}
+
+// MonitoredItem is an item of a Subscription. Notifications of the item are delivered to its handler.
+type MonitoredItem struct {
+	id           uint32
+	clientHandle uint32
+	result       ua.MonitoredItemCreateResult
+	onDataChange func(ua.DataValue)
+	onEvent      func([]ua.Variant)
+}
+
+// ID gets the server assigned id of the monitored item.
+func (m *MonitoredItem) ID() uint32 {
+	return m.id
+}
+
+// ClientHandle gets the client handle of the monitored item.
+func (m *MonitoredItem) ClientHandle() uint32 {
+	return m.clientHandle
+}
+
+// Result gets the result of creating the monitored item, including the revised parameters.
+func (m *MonitoredItem) Result() ua.MonitoredItemCreateResult {
+	return m.result
+}
+
+// DataChangeChan returns a handler that sends each value to the given channel.
+// The publish pipeline waits while the channel is full.
+func DataChangeChan(c chan<- ua.DataValue) func(ua.DataValue) {
+	return func(value ua.DataValue) {
+		c <- value
+	}
+}
+
+// EventChan returns a handler that sends the fields of each event to the given channel.
+// The publish pipeline waits while the channel is full.
+func EventChan(c chan<- []ua.Variant) func([]ua.Variant) {
+	return func(fields []ua.Variant) {
+		c <- fields
+	}
+}
+
+// NewSubscription creates a subscription on the server and starts publishing.
+func NewSubscription(ctx context.Context, ch *Client, request *ua.CreateSubscriptionRequest, opts ...SubscriptionOption) (*Subscription, error) {
+	s := &Subscription{
+		client:              ch,
+		publishRequestCount: defaultPublishRequestCount,
+		timestampsToReturn:  ua.TimestampsToReturnBoth,
+		items:               make(map[uint32]*MonitoredItem),
+		done:                make(chan struct{}),
+	}
+
+	// apply each option to the default
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := ch.CreateSubscription(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	s.id = res.SubscriptionID
+	s.revisedPublishingInterval = res.RevisedPublishingInterval
+	s.revisedLifetimeCount = res.RevisedLifetimeCount
+	s.revisedMaxKeepAliveCount = res.RevisedMaxKeepAliveCount
+
+	ch.subscriptionsLock.Lock()
+	ch.publishers[s.id] = s
+	ch.subscriptionsLock.Unlock()
+
+	for i := 0; i < s.publishRequestCount; i++ {
+		go s.publish()
+	}
+	return s, nil
+}
+
+// ID gets the server assigned id of the subscription.
+func (s *Subscription) ID() uint32 {
+	return s.id
+}
+
+// RevisedPublishingInterval gets the publishing interval of the subscription, in milliseconds.
+func (s *Subscription) RevisedPublishingInterval() float64 {
+	return s.revisedPublishingInterval
+}
+
+// RevisedLifetimeCount gets the lifetime count of the subscription.
+func (s *Subscription) RevisedLifetimeCount() uint32 {
+	return s.revisedLifetimeCount
+}
+
+// RevisedMaxKeepAliveCount gets the max keep-alive count of the subscription.
+func (s *Subscription) RevisedMaxKeepAliveCount() uint32 {
+	return s.revisedMaxKeepAliveCount
+}
+
+// CreateDataChangeItem creates a monitored item that calls the handler with each data change.
+// The ClientHandle of the request is assigned by the subscription.
+func (s *Subscription) CreateDataChangeItem(ctx context.Context, item ua.MonitoredItemCreateRequest, handler func(ua.DataValue)) (*MonitoredItem, error) {
+	return s.createItem(ctx, item, &MonitoredItem{onDataChange: handler})
+}
+
+// CreateEventItem creates a monitored item that calls the handler with the fields of each event.
+// The ClientHandle of the request is assigned by the subscription.
+func (s *Subscription) CreateEventItem(ctx context.Context, item ua.MonitoredItemCreateRequest, handler func([]ua.Variant)) (*MonitoredItem, error) {
+	return s.createItem(ctx, item, &MonitoredItem{onEvent: handler})
+}
+
+// createItem registers the item before creating it, so the initial notification is not missed.
+func (s *Subscription) createItem(ctx context.Context, item ua.MonitoredItemCreateRequest, m *MonitoredItem) (*MonitoredItem, error) {
+	m.clientHandle = atomic.AddUint32(&s.nextClientHandle, 1)
+	item.RequestedParameters.ClientHandle = m.clientHandle
+	s.itemsLock.Lock()
+	s.items[m.clientHandle] = m
+	s.itemsLock.Unlock()
+
+	res, err := s.client.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     s.id,
+		TimestampsToReturn: s.timestampsToReturn,
+		ItemsToCreate:      []ua.MonitoredItemCreateRequest{item},
+	})
+	if err == nil && len(res.Results) != 1 {
+		err = ua.BadUnexpectedError
+	}
+	if err == nil && res.Results[0].StatusCode.IsBad() {
+		err = res.Results[0].StatusCode
+	}
+	if err != nil {
+		s.itemsLock.Lock()
+		delete(s.items, m.clientHandle)
+		s.itemsLock.Unlock()
+		return nil, err
+	}
+	m.id = res.Results[0].MonitoredItemID
+	m.result = res.Results[0]
+	return m, nil
+}
+
+// DeleteMonitoredItem deletes the monitored item.
+func (s *Subscription) DeleteMonitoredItem(ctx context.Context, item *MonitoredItem) error {
+	res, err := s.client.DeleteMonitoredItems(ctx, &ua.DeleteMonitoredItemsRequest{
+		SubscriptionID:   s.id,
+		MonitoredItemIDs: []uint32{item.id},
+	})
+	if err != nil {
+		return err
+	}
+	s.itemsLock.Lock()
+	delete(s.items, item.clientHandle)
+	s.itemsLock.Unlock()
+	if len(res.Results) == 1 && res.Results[0].IsBad() {
+		return res.Results[0]
+	}
+	return nil
+}
+
+// Close stops publishing and deletes the subscription from the server.
+func (s *Subscription) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.done) })
+	s.client.subscriptionsLock.Lock()
+	delete(s.client.publishers, s.id)
+	s.client.subscriptionsLock.Unlock()
+	res, err := s.client.DeleteSubscriptions(ctx, &ua.DeleteSubscriptionsRequest{
+		SubscriptionIDs: []uint32{s.id},
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Results) == 1 && res.Results[0].IsBad() {
+		return res.Results[0]
+	}
+	return nil
+}
+
+// isClosed returns true when the subscription is closed.
+func (s *Subscription) isClosed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// publish keeps a publish request outstanding until the subscription is closed.
+// Acknowledgements are queued on the client and sent with the next publish request.
+func (s *Subscription) publish() {
+	// wait for at least one keep-alive per outstanding request.
+	timeoutHint := uint32(s.revisedPublishingInterval * float64(s.revisedMaxKeepAliveCount) * float64(s.publishRequestCount+1))
+	if timeoutHint < defaultTimeoutHint {
+		timeoutHint = defaultTimeoutHint
+	}
+	for !s.isClosed() && !s.client.isStopped() {
+		res, err := s.client.Publish(context.Background(), &ua.PublishRequest{
+			RequestHeader: ua.RequestHeader{TimeoutHint: timeoutHint},
+		})
+		if err != nil {
+			select {
+			case <-s.done:
+				return
+			case <-time.After(publishRetryDelay):
+			}
+			continue
+		}
+		s.client.dispatch(res)
+	}
+}
+
+// dispatch delivers the notification message to the subscription it belongs to, which may
+// be a different subscription than the one that sent the publish request.
+func (ch *Client) dispatch(res *ua.PublishResponse) {
+	ch.subscriptionsLock.Lock()
+	s, ok := ch.publishers[res.SubscriptionID]
+	ch.subscriptionsLock.Unlock()
+	if !ok {
+		if len(res.NotificationMessage.NotificationData) > 0 {
+			ch.acknowledge(res.SubscriptionID, res.NotificationMessage.SequenceNumber)
+		}
+		return
+	}
+	s.deliver(res.NotificationMessage)
+}
+
+// acknowledge queues an acknowledgement to be sent with the next publish request.
+func (ch *Client) acknowledge(subscriptionID, sequenceNumber uint32) {
+	ch.subscriptionsLock.Lock()
+	defer ch.subscriptionsLock.Unlock()
+	ch.deferredAcks = append(ch.deferredAcks, ua.SubscriptionAcknowledgement{SubscriptionID: subscriptionID, SequenceNumber: sequenceNumber})
+}
+
+// deliver checks the sequence number of the message, republishes any messages that are missing,
+// then calls the handlers of the monitored items. The lock is held only to check the sequence number,
+// so the handlers may take their time.
+func (s *Subscription) deliver(msg ua.NotificationMessage) {
+	s.Lock()
+	// a keep-alive carries the next sequence number, a notification carries its own.
+	// Since the sequence numbers wrap, a number is newer if it is less than half the range ahead.
+	var missing []uint32
+	d := sequenceDistance(s.lastSequenceNumber, msg.SequenceNumber)
+	newer := d > 0 && (d < 1<<31 || s.lastSequenceNumber == 0)
+	if newer {
+		for seq := nextSequenceNumber(s.lastSequenceNumber); seq != msg.SequenceNumber && d < 1<<31; seq = nextSequenceNumber(seq) {
+			missing = append(missing, seq)
+			s.lastSequenceNumber = seq
+		}
+		if len(msg.NotificationData) > 0 {
+			s.lastSequenceNumber = msg.SequenceNumber
+		}
+	}
+	s.Unlock()
+
+	for _, seq := range missing {
+		res, err := s.client.Republish(context.Background(), &ua.RepublishRequest{
+			SubscriptionID:           s.id,
+			RetransmitSequenceNumber: seq,
+		})
+		if err != nil {
+			// the message is no longer available.
+			continue
+		}
+		s.client.acknowledge(s.id, seq)
+		s.notify(res.NotificationMessage)
+	}
+	if len(msg.NotificationData) == 0 {
+		return
+	}
+	s.client.acknowledge(s.id, msg.SequenceNumber)
+	if !newer {
+		// already delivered by a republish.
+		return
+	}
+	s.notify(msg)
+}
+
+// nextSequenceNumber returns the sequence number that follows seq. Sequence numbers wrap from 4294967295 to 1.
+func nextSequenceNumber(seq uint32) uint32 {
+	if seq == math.MaxUint32 {
+		return 1
+	}
+	return seq + 1
+}
+
+// sequenceDistance returns the count of sequence numbers from one to the other, skipping 0 when wrapping.
+func sequenceDistance(from, to uint32) uint32 {
+	if to >= from {
+		return to - from
+	}
+	return to - from - 1
+}
+
+// notify calls the handlers of the monitored items.
+func (s *Subscription) notify(msg ua.NotificationMessage) {
+	for _, data := range msg.NotificationData {
+		switch body := data.(type) {
+		case ua.DataChangeNotification:
+			for _, z := range body.MonitoredItems {
+				s.itemsLock.RLock()
+				m, ok := s.items[z.ClientHandle]
+				s.itemsLock.RUnlock()
+				if ok && m.onDataChange != nil {
+					m.onDataChange(z.Value)
+				}
+			}
+		case ua.EventNotificationList:
+			for _, z := range body.Events {
+				s.itemsLock.RLock()
+				m, ok := s.items[z.ClientHandle]
+				s.itemsLock.RUnlock()
+				if ok && m.onEvent != nil {
+					m.onEvent(z.EventFields)
+				}
+			}
+		case ua.StatusChangeNotification:
+			if s.statusChangeHandler != nil {
+				s.statusChangeHandler(body.Status)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package client
+
+import (
+	"math"
+	"testing"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// TestDeliverSequenceNumberWrap tests that the notifications are delivered in order when the sequence number
+// wraps from 4294967295 to 1, and that a message that was delivered already is not delivered again.
+func TestDeliverSequenceNumberWrap(t *testing.T) {
+	var delivered []ua.StatusCode
+	s := &Subscription{
+		client:             &Client{},
+		lastSequenceNumber: math.MaxUint32 - 1,
+		statusChangeHandler: func(status ua.StatusCode) {
+			delivered = append(delivered, status)
+		},
+	}
+	message := func(seq uint32, status ua.StatusCode) ua.NotificationMessage {
+		return ua.NotificationMessage{SequenceNumber: seq, NotificationData: []ua.ExtensionObject{ua.StatusChangeNotification{Status: status}}}
+	}
+	s.deliver(message(math.MaxUint32, ua.Good))
+	s.deliver(message(1, ua.GoodCompletesAsynchronously))
+	// a keep-alive carries the next sequence number.
+	s.deliver(ua.NotificationMessage{SequenceNumber: 2})
+	// a message from before the wrap is not delivered again.
+	s.deliver(message(math.MaxUint32, ua.Good))
+	if len(delivered) != 2 || delivered[0] != ua.Good || delivered[1] != ua.GoodCompletesAsynchronously {
+		t.Errorf("Error in delivered notifications. want: [%s %s], got: %v", ua.Good, ua.GoodCompletesAsynchronously, delivered)
+	}
+	if s.lastSequenceNumber != 1 {
+		t.Errorf("Error in last sequence number. want: 1, got: %d", s.lastSequenceNumber)
+	}
+	// all the messages with notifications are acknowledged.
+	if len(s.client.deferredAcks) != 3 {
+		t.Errorf("Error in acknowledgements. want: 3, got: %d", len(s.client.deferredAcks))
+	}
+
+	// the missing message across the wrap is requested, but is no longer available.
+	s.lastSequenceNumber = math.MaxUint32
+	s.deliver(message(2, ua.GoodCallAgain))
+	if len(delivered) != 3 || delivered[2] != ua.GoodCallAgain || s.lastSequenceNumber != 2 {
+		t.Errorf("Error delivering after a gap. got: %v, last sequence number: %d", delivered, s.lastSequenceNumber)
+	}
+}
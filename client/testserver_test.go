@@ -100,6 +100,8 @@ func NewTestServer() (*server.Server, error) {
 		}),
 		server.WithSecurityPolicyNone(true),
 		server.WithInsecureSkipVerify(),
+		server.WithWebSocketEndpointURL(wssEndpointURL),
+		server.WithReverseConnect(reverseClientURL, time.Second),
 	)
 	if err != nil {
 		return nil, err
@@ -234,6 +236,58 @@ func NewTestServer() (*server.Server, error) {
 		nil,
 	)
 
+	// add 'CustomVector' data type, without a Go type, so values are decoded as ua.Structure
+	typCustomVector := server.NewDataTypeNode(
+		srv,
+		ua.NodeIDNumeric{NamespaceIndex: 2, ID: 15},
+		ua.QualifiedName{NamespaceIndex: 2, Name: "CustomVector"},
+		ua.LocalizedText{Text: "CustomVector"},
+		ua.LocalizedText{Text: "A CustomVector data type for testing."},
+		nil,
+		[]ua.Reference{ // add type as subtype of 'Structure'
+			{
+				ReferenceTypeID: ua.ReferenceTypeIDHasSubtype,
+				IsInverse:       true,
+				TargetID:        ua.ExpandedNodeID{NodeID: ua.DataTypeIDStructure},
+			},
+		},
+		false,
+		ua.StructureDefinition{
+			DefaultEncodingID: ua.NodeIDNumeric{NamespaceIndex: 2, ID: 16},
+			BaseDataType:      ua.DataTypeIDStructure,
+			StructureType:     ua.StructureTypeStructure,
+			Fields: []ua.StructureField{
+				{Name: "X", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+				{Name: "Y", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+			},
+		},
+	)
+
+	// add 'CustomVector' variable
+	varCustomVector := server.NewVariableNode(
+		srv,
+		ua.NodeIDNumeric{NamespaceIndex: 2, ID: 17},
+		ua.QualifiedName{NamespaceIndex: 2, Name: "CustomVector"},
+		ua.LocalizedText{Text: "CustomVector"},
+		ua.LocalizedText{Text: "A CustomVector variable for testing."},
+		nil,
+		[]ua.Reference{ // add variable to 'Demo.Static.Scalar' folder
+			{
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				IsInverse:       true,
+				TargetID:        ua.ExpandedNodeID{NodeID: ua.ParseNodeID("ns=2;s=Demo.Static.Scalar")},
+			},
+		},
+		ua.NewDataValue(nil, 0, time.Now().UTC(), 0, time.Now().UTC(), 0),
+		typCustomVector.NodeID(),
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead|ua.AccessLevelsCurrentWrite,
+		250.0,
+		false,
+		nil,
+	)
+
 	// add 'Matrix' variable
 	varMatrix := server.NewVariableNode(
 		srv,
@@ -262,6 +316,8 @@ func NewTestServer() (*server.Server, error) {
 	nm.AddNodes(
 		typCustomStruct,
 		varCustomStruct,
+		typCustomVector,
+		varCustomVector,
 		varMatrix,
 	)
 
@@ -95,6 +95,7 @@ func main() {
 
 type statusCode struct {
 	Name        string
+	Symbol      string
 	Value       string
 	Description string
 }
@@ -111,7 +112,7 @@ func readStatusCodes(filename string) ([]statusCode, error) {
 	}
 	statusCodes := make([]statusCode, len(rows))
 	for i := range rows {
-		statusCodes[i] = statusCode{Name: goCaseReplacer.Replace(rows[i][0]), Value: rows[i][1], Description: rows[i][2]}
+		statusCodes[i] = statusCode{Name: goCaseReplacer.Replace(rows[i][0]), Symbol: rows[i][0], Value: rows[i][1], Description: rows[i][2]}
 	}
 	return statusCodes, nil
 }
@@ -399,6 +400,20 @@ func (c StatusCode) Error() string {
 		return "An unknown error occurred."
 	}
 }
+
+// Symbol returns the symbolic name of the StatusCode, e.g. "BadNodeIdUnknown".
+func (c StatusCode) Symbol() string {
+	switch c {
+	case Good:
+		return "Good"
+	{{- range $j, $v := .}}
+	case {{$v.Name}}:
+		return "{{$v.Symbol}}"
+	{{- end}}
+	default:
+		return ""
+	}
+}
 `
 
 var tmplEnum = `// Copyright 2021 Converter Systems LLC. All rights reserved.
@@ -465,6 +480,13 @@ func init() {
 	{{- range $i, $s := .}}
 		RegisterBinaryEncodingID(reflect.TypeOf((*{{$s.Name}})(nil)).Elem(), NewExpandedNodeID(ObjectID{{$s.Name}}EncodingDefaultBinary))
 	{{- end}}
+	{{- range $i, $s := .}}
+		RegisterXMLEncodingID(reflect.TypeOf((*{{$s.Name}})(nil)).Elem(), NewExpandedNodeID(ObjectID{{$s.Name}}EncodingDefaultXML))
+	{{- end}}
+	{{- range $i, $s := .}}
+		RegisterJSONEncodingID(reflect.TypeOf((*{{$s.Name}})(nil)).Elem(), NewExpandedNodeID(ObjectID{{$s.Name}}EncodingDefaultJSON))
+		RegisterJSONEncodingID(reflect.TypeOf((*{{$s.Name}})(nil)).Elem(), NewExpandedNodeID(DataTypeID{{$s.Name}}))
+	{{- end}}
 }
 `
 
@@ -0,0 +1,195 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+// Command lds is a Local Discovery Server. Servers register with the discovery server,
+// using RegisterServer or RegisterServer2, and clients find the servers using FindServers
+// or FindServersOnNetwork.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/awcullen/opcua/server"
+	"github.com/awcullen/opcua/ua"
+	"github.com/pkg/errors"
+)
+
+var (
+	host, _         = os.Hostname()
+	port            = 4840
+	SoftwareVersion = "1.0.0"
+)
+
+func main() {
+
+	// create directory with certificate and key, if not found.
+	if err := ensurePKI(); err != nil {
+		log.Println("Error creating PKI.")
+		return
+	}
+
+	// create the endpoint url from hostname and port
+	endpointURL := fmt.Sprintf("opc.tcp://%s:%d", host, port)
+
+	// create server
+	srv, err := server.New(
+		ua.ApplicationDescription{
+			ApplicationURI: fmt.Sprintf("urn:%s:lds", host),
+			ProductURI:     "http://github.com/awcullen/opcua",
+			ApplicationName: ua.LocalizedText{
+				Text:   fmt.Sprintf("lds@%s", host),
+				Locale: "en",
+			},
+			ApplicationType:     ua.ApplicationTypeDiscoveryServer,
+			GatewayServerURI:    "",
+			DiscoveryProfileURI: "",
+			DiscoveryURLs:       []string{endpointURL},
+		},
+		"./pki/server.crt",
+		"./pki/server.key",
+		endpointURL,
+		server.WithBuildInfo(
+			ua.BuildInfo{
+				ProductURI:       "http://github.com/awcullen/opcua",
+				ManufacturerName: "awcullen",
+				ProductName:      "lds",
+				SoftwareVersion:  SoftwareVersion,
+			}),
+		server.WithAnonymousIdentity(true),
+		server.WithSecurityPolicyNone(true),
+		server.WithTrustedCertificatesPaths("./pki/ApplicationInstance_PKI/trusted/certs", "./pki/ApplicationInstance_PKI/trusted/crl"),
+		server.WithIssuerCertificatesPaths("./pki/ApplicationInstance_PKI/issuers/certs", "./pki/ApplicationInstance_PKI/issuers/crl"),
+		server.WithRejectedCertificatesPath("./pki/ApplicationInstance_PKI/rejected"),
+		server.WithRegistrationTimeout(10*time.Minute),
+	)
+	if err != nil {
+		os.Exit(1)
+	}
+
+	go func() {
+		// wait for signal (this conflicts with debugger currently)
+		log.Println("Press Ctrl-C to exit...")
+		waitForSignal()
+
+		log.Println("Stopping server...")
+		srv.Close()
+	}()
+
+	// start server
+	log.Printf("Starting discovery server '%s' at '%s'\n", srv.LocalDescription().ApplicationName.Text, srv.EndpointURL())
+	if err := srv.ListenAndServe(); err != ua.BadServerHalted {
+		log.Println(errors.Wrap(err, "Error starting server"))
+	}
+}
+
+func waitForSignal() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	<-sigs
+}
+
+func createNewCertificate(appName, certFile, keyFile string) error {
+
+	// create a keypair.
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return ua.BadCertificateInvalid
+	}
+
+	// get local hostname.
+	host, _ := os.Hostname()
+
+	// get local ip address.
+	conn, err := net.Dial("udp", "8.8.8.8:53")
+	if err != nil {
+		return ua.BadCertificateInvalid
+	}
+	conn.Close()
+	localAddr := conn.LocalAddr().(*net.UDPAddr)
+
+	// create a certificate.
+	applicationURI, _ := url.Parse(fmt.Sprintf("urn:%s:%s", host, appName))
+	serialNumber, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	subjectKeyHash := sha1.New()
+	subjectKeyHash.Write(key.PublicKey.N.Bytes())
+	subjectKeyId := subjectKeyHash.Sum(nil)
+	oidDC := asn1.ObjectIdentifier([]int{0, 9, 2342, 19200300, 100, 1, 25})
+
+	template := x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: appName, ExtraNames: []pkix.AttributeTypeAndValue{{Type: oidDC, Value: host}}},
+		SubjectKeyId:          subjectKeyId,
+		AuthorityKeyId:        subjectKeyId,
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment | x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{host, "localhost"},
+		IPAddresses:           []net.IP{localAddr.IP, []byte{127, 0, 0, 1}},
+		URIs:                  []*url.URL{applicationURI},
+	}
+
+	rawcrt, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return ua.BadCertificateInvalid
+	}
+
+	if f, err := os.Create(certFile); err == nil {
+		block := &pem.Block{Type: "CERTIFICATE", Bytes: rawcrt}
+		if err := pem.Encode(f, block); err != nil {
+			f.Close()
+			return err
+		}
+		f.Close()
+	} else {
+		return err
+	}
+
+	if f, err := os.Create(keyFile); err == nil {
+		block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+		if err := pem.Encode(f, block); err != nil {
+			f.Close()
+			return err
+		}
+		f.Close()
+	} else {
+		return err
+	}
+
+	return nil
+}
+
+func ensurePKI() error {
+
+	// check if ./pki already exists
+	if _, err := os.Stat("./pki"); !os.IsNotExist(err) {
+		return nil
+	}
+
+	// make a pki directory, if not exist
+	if err := os.MkdirAll("./pki", os.ModeDir|0755); err != nil {
+		return err
+	}
+
+	// create a server cert in ./pki/server.crt
+	if err := createNewCertificate("lds", "./pki/server.crt", "./pki/server.key"); err != nil {
+		return err
+	}
+
+	return nil
+}
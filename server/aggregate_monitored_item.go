@@ -0,0 +1,312 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"math"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+var (
+	// defaultAggregateConfiguration is used when the client requests the server's defaults.
+	defaultAggregateConfiguration = ua.AggregateConfiguration{
+		UseServerCapabilitiesDefaults: true,
+		TreatUncertainAsBad:           false,
+		PercentDataBad:                100,
+		PercentDataGood:               100,
+		UseSlopedExtrapolation:        false,
+	}
+)
+
+// aggregateSample is a value sampled at a time. The time has no monotonic clock reading,
+// so it can be compared with the interval bounds, which are wall clock times.
+type aggregateSample struct {
+	time  time.Time
+	value ua.DataValue
+}
+
+// AggregateMonitoredItem specifies the node that is sampled and published as an aggregate
+// of the samples in each processing interval.
+type AggregateMonitoredItem struct {
+	sampledMonitoredItem
+	aggregateFilter ua.AggregateFilter
+	samples         []aggregateSample
+	prior           *aggregateSample
+	lastValue       *ua.DataValue
+	ts              time.Time
+	pi              time.Duration
+}
+
+// NewAggregateMonitoredItem constructs a new AggregateMonitoredItem.
+func NewAggregateMonitoredItem(sub *Subscription, node Node, itemToMonitor ua.ReadValueID, monitoringMode ua.MonitoringMode, parameters ua.MonitoringParameters, timestampsToReturn ua.TimestampsToReturn, minSamplingInterval float64) *AggregateMonitoredItem {
+	mi := &AggregateMonitoredItem{}
+	mi.init(mi, sub, node, itemToMonitor, monitoringMode, parameters, timestampsToReturn, minSamplingInterval)
+	mi.setFilter(parameters.Filter)
+
+	mi.Lock()
+	mi.startMonitoring()
+	mi.Unlock()
+	return mi
+}
+
+// FilterResult returns the revised start time, processing interval and aggregate configuration.
+func (mi *AggregateMonitoredItem) FilterResult() ua.AggregateFilterResult {
+	mi.RLock()
+	defer mi.RUnlock()
+	return mi.filterResult()
+}
+
+func (mi *AggregateMonitoredItem) filterResult() ua.AggregateFilterResult {
+	return ua.AggregateFilterResult{
+		RevisedStartTime:              mi.aggregateFilter.StartTime,
+		RevisedProcessingInterval:     mi.aggregateFilter.ProcessingInterval,
+		RevisedAggregateConfiguration: mi.aggregateFilter.AggregateConfiguration,
+	}
+}
+
+// Modify modifies the MonitoredItem.
+func (mi *AggregateMonitoredItem) Modify(req ua.MonitoredItemModifyRequest) ua.MonitoredItemModifyResult {
+	mi.Lock()
+	defer mi.Unlock()
+	mi.modify(req)
+	return ua.MonitoredItemModifyResult{RevisedSamplingInterval: mi.samplingInterval, RevisedQueueSize: mi.queueSize, FilterResult: mi.filterResult()}
+}
+
+// setFilter revises the processing interval to be a whole number of milliseconds and at least the sampling interval,
+// and revises the start time to the first interval that starts at or after the current time.
+func (mi *AggregateMonitoredItem) setFilter(filter any) {
+	af, _ := filter.(ua.AggregateFilter)
+	pi := math.Round(af.ProcessingInterval)
+	if pi < mi.samplingInterval {
+		pi = mi.samplingInterval
+	}
+	if pi < 1 {
+		pi = 1
+	}
+	af.ProcessingInterval = pi
+	mi.pi = time.Duration(pi) * time.Millisecond
+	now := time.Now()
+	if af.StartTime.IsZero() {
+		af.StartTime = now
+	}
+	if af.StartTime.Before(now) {
+		// count in milliseconds, since the start time may be centuries ago.
+		if rem := (now.UnixMilli() - af.StartTime.UnixMilli()) % int64(pi); rem > 0 {
+			af.StartTime = now.Truncate(time.Millisecond).Add(time.Duration(int64(pi)-rem) * time.Millisecond)
+		} else {
+			af.StartTime = now
+		}
+	}
+	if af.AggregateConfiguration.UseServerCapabilitiesDefaults {
+		af.AggregateConfiguration = defaultAggregateConfiguration
+	}
+	mi.aggregateFilter = af
+	mi.ts = af.StartTime
+	mi.reset()
+}
+
+func (mi *AggregateMonitoredItem) start() {}
+
+// sample stores the value with the time it was sampled, since the server timestamp of a
+// stored value may be earlier.
+func (mi *AggregateMonitoredItem) sample(value ua.DataValue) {
+	mi.samples = append(mi.samples, aggregateSample{time.Now().Round(0), value})
+}
+
+func (mi *AggregateMonitoredItem) reset() {
+	mi.samples = nil
+	mi.prior = nil
+	mi.lastValue = nil
+}
+
+func (mi *AggregateMonitoredItem) notificationsAvailable(tn time.Time, late bool, resend bool) bool {
+	_ = late
+	mi.Lock()
+	defer mi.Unlock()
+	// if disabled, then report false.
+	if mi.monitoringMode == ua.MonitoringModeDisabled {
+		return false
+	}
+	// for each processing interval that has ended, queue the aggregate of its samples.
+	for end := mi.ts.Add(mi.pi); !end.After(tn); end = mi.ts.Add(mi.pi) {
+		v := mi.aggregate(mi.ts, end)
+		mi.enqueue(withTimestamps(v, mi.timestampsToReturn))
+		mi.lastValue = &v
+		mi.ts = end
+	}
+	if resend && mi.monitoringMode == ua.MonitoringModeReporting {
+		if mi.queue.Len() == 0 && mi.lastValue != nil {
+			mi.enqueue(withTimestamps(*mi.lastValue, mi.timestampsToReturn))
+		}
+	}
+	return mi.queue.Len() > 0 && (mi.monitoringMode == ua.MonitoringModeReporting || mi.triggered)
+}
+
+// aggregate calculates the aggregate of the samples from start to end, then removes them.
+// The last sample before the start is taken to hold until the first sample of the interval.
+func (mi *AggregateMonitoredItem) aggregate(start, end time.Time) ua.DataValue {
+	// take the samples of this interval.
+	var samples []aggregateSample
+	i := 0
+	for ; i < len(mi.samples) && mi.samples[i].time.Before(end); i++ {
+		s := mi.samples[i]
+		if s.time.Before(start) {
+			mi.prior = &s
+			continue
+		}
+		samples = append(samples, s)
+	}
+	mi.samples = mi.samples[i:]
+
+	config := mi.aggregateFilter.AggregateConfiguration
+	isGood := func(s aggregateSample) (float64, bool) {
+		if s.value.StatusCode.IsBad() || (s.value.StatusCode.IsUncertain() && config.TreatUncertainAsBad) {
+			return 0, false
+		}
+		if mi.aggregateFilter.AggregateType == ua.ObjectIDAggregateFunctionCount {
+			return 0, true
+		}
+		return toFloat(s.value.Value)
+	}
+
+	// the points of the interval, beginning with the prior sample which holds at the start.
+	points := samples
+	partial := false
+	if mi.prior != nil {
+		points = append([]aggregateSample{{start, mi.prior.value}}, samples...)
+	} else if len(samples) == 0 || samples[0].time.After(start) {
+		partial = true
+	}
+	if len(samples) > 0 {
+		last := samples[len(samples)-1]
+		mi.prior = &last
+	}
+
+	// measure the duration of good data, and integrate the good values over time.
+	var goodDuration time.Duration
+	var area float64
+	for j, p := range points {
+		next := end
+		if j+1 < len(points) {
+			next = points[j+1].time
+		}
+		f, ok := isGood(p)
+		if !ok {
+			continue
+		}
+		d := next.Sub(p.time)
+		goodDuration += d
+		// interpolate between good points, else hold the value until the next point.
+		if j+1 < len(points) {
+			if f2, ok := isGood(points[j+1]); ok {
+				area += (f + f2) / 2 * d.Seconds()
+				continue
+			}
+		}
+		area += f * d.Seconds()
+	}
+	total := end.Sub(start)
+	percentGood := 100 * goodDuration.Seconds() / total.Seconds()
+	percentBad := 100 - percentGood
+
+	var statusCode ua.StatusCode
+	switch {
+	case percentBad >= float64(config.PercentDataBad):
+		statusCode = ua.StatusCode(ua.SeverityBad)
+	case percentGood >= float64(config.PercentDataGood):
+		statusCode = ua.Good
+	default:
+		statusCode = ua.UncertainDataSubNormal
+	}
+	bits := ua.InfoTypeDataValue | ua.HistorianBitsCalculated
+	if partial {
+		bits |= ua.HistorianBitsPartial
+	}
+
+	// calculate the value from the good samples of the interval.
+	var value ua.Variant
+	var count int32
+	var sum float64
+	var minValue, maxValue float64
+	var minVariant, maxVariant ua.Variant
+	for _, s := range samples {
+		f, ok := isGood(s)
+		if !ok {
+			continue
+		}
+		if count == 0 || f < minValue {
+			minValue, minVariant = f, s.value.Value
+		}
+		if count == 0 || f > maxValue {
+			maxValue, maxVariant = f, s.value.Value
+		}
+		sum += f
+		count++
+	}
+	switch mi.aggregateFilter.AggregateType {
+	case ua.ObjectIDAggregateFunctionCount:
+		value = count
+	case ua.ObjectIDAggregateFunctionAverage:
+		if count > 0 {
+			value = sum / float64(count)
+		}
+	case ua.ObjectIDAggregateFunctionMinimum:
+		value = minVariant
+	case ua.ObjectIDAggregateFunctionMaximum:
+		value = maxVariant
+	case ua.ObjectIDAggregateFunctionTimeAverage:
+		if goodDuration > 0 {
+			value = area / goodDuration.Seconds()
+		}
+	}
+	if value == nil {
+		return ua.NewDataValue(nil, ua.BadNoData, start, 0, time.Now(), 0)
+	}
+	return ua.NewDataValue(value, ua.StatusCode(uint32(statusCode)|bits), start, 0, time.Now(), 0)
+}
+
+// isSupportedAggregate returns true if the server can calculate the aggregate for monitored items.
+func isSupportedAggregate(aggregateType ua.NodeID) bool {
+	switch aggregateType {
+	case ua.ObjectIDAggregateFunctionAverage, ua.ObjectIDAggregateFunctionTimeAverage,
+		ua.ObjectIDAggregateFunctionMinimum, ua.ObjectIDAggregateFunctionMaximum,
+		ua.ObjectIDAggregateFunctionCount:
+		return true
+	default:
+		return false
+	}
+}
+
+// isAggregateItem returns true if the item publishes aggregates.
+func isAggregateItem(item MonitoredItem) bool {
+	_, ok := item.(*AggregateMonitoredItem)
+	return ok
+}
+
+// validateAggregateFilter checks the aggregate type and configuration can be calculated for the variable.
+func (srv *Server) validateAggregateFilter(af ua.AggregateFilter, n *VariableNode) ua.StatusCode {
+	if !isSupportedAggregate(af.AggregateType) {
+		return ua.BadAggregateNotSupported
+	}
+	if af.AggregateType != ua.ObjectIDAggregateFunctionCount {
+		switch srv.NamespaceManager().FindVariantType(n.DataType()) {
+		case ua.VariantTypeByte, ua.VariantTypeSByte:
+		case ua.VariantTypeInt16, ua.VariantTypeInt32, ua.VariantTypeInt64:
+		case ua.VariantTypeUInt16, ua.VariantTypeUInt32, ua.VariantTypeUInt64:
+		case ua.VariantTypeFloat, ua.VariantTypeDouble:
+		default:
+			return ua.BadAggregateInvalidInputs
+		}
+		if n.ValueRank() != ua.ValueRankScalar {
+			return ua.BadAggregateInvalidInputs
+		}
+	}
+	if c := af.AggregateConfiguration; !c.UseServerCapabilitiesDefaults {
+		if c.PercentDataBad > 100 || c.PercentDataGood > 100 || int(c.PercentDataGood) < 100-int(c.PercentDataBad) {
+			return ua.BadAggregateConfigurationRejected
+		}
+	}
+	return ua.Good
+}
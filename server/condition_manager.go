@@ -0,0 +1,855 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+	"github.com/google/uuid"
+)
+
+// ConditionManager manages the conditions of a server and serves the methods of
+// ConditionType and AcknowledgeableConditionType, including ConditionRefresh.
+type ConditionManager struct {
+	sync.RWMutex
+	server         *Server
+	conditions     map[ua.NodeID]*Condition
+	shelvingStates map[ua.NodeID]*Condition
+}
+
+// NewConditionManager instantiates a new ConditionManager and installs the handlers of the condition methods.
+func NewConditionManager(server *Server) *ConditionManager {
+	m := &ConditionManager{server: server, conditions: make(map[ua.NodeID]*Condition), shelvingStates: make(map[ua.NodeID]*Condition)}
+	nm := server.NamespaceManager()
+	install := func(handler func(*Session, ua.CallMethodRequest) ua.CallMethodResult, ids ...ua.NodeID) {
+		for _, id := range ids {
+			if n, ok := nm.FindMethod(id); ok {
+				n.SetCallMethodHandler(handler)
+			}
+		}
+	}
+	install(m.onEnable, ua.MethodIDConditionTypeEnable, ua.MethodIDAcknowledgeableConditionTypeEnable, ua.MethodIDAlarmConditionTypeEnable)
+	install(m.onDisable, ua.MethodIDConditionTypeDisable, ua.MethodIDAcknowledgeableConditionTypeDisable, ua.MethodIDAlarmConditionTypeDisable)
+	install(m.onAddComment, ua.MethodIDConditionTypeAddComment, ua.MethodIDAcknowledgeableConditionTypeAddComment, ua.MethodIDAlarmConditionTypeAddComment)
+	install(m.onAcknowledge, ua.MethodIDAcknowledgeableConditionTypeAcknowledge, ua.MethodIDAlarmConditionTypeAcknowledge)
+	install(m.onConfirm, ua.MethodIDAcknowledgeableConditionTypeConfirm, ua.MethodIDAlarmConditionTypeConfirm)
+	install(m.onConditionRefresh, ua.MethodIDConditionTypeConditionRefresh, ua.MethodIDAcknowledgeableConditionTypeConditionRefresh, ua.MethodIDAlarmConditionTypeConditionRefresh)
+	install(m.onConditionRefresh2, ua.MethodIDConditionTypeConditionRefresh2, ua.MethodIDAcknowledgeableConditionTypeConditionRefresh2, ua.MethodIDAlarmConditionTypeConditionRefresh2)
+	install(m.onTimedShelve, ua.MethodIDShelvedStateMachineTypeTimedShelve, ua.MethodIDAlarmConditionTypeShelvingStateTimedShelve)
+	install(m.onOneShotShelve, ua.MethodIDShelvedStateMachineTypeOneShotShelve, ua.MethodIDAlarmConditionTypeShelvingStateOneShotShelve)
+	install(m.onUnshelve, ua.MethodIDShelvedStateMachineTypeUnshelve, ua.MethodIDAlarmConditionTypeShelvingStateUnshelve)
+	return m
+}
+
+// AddCondition adds a condition of the given type to the address space, as a component of the source node.
+// The condition reports its events to the source node. An alarm also gets a ShelvingState component.
+func (m *ConditionManager) AddCondition(nodeID, typeID ua.NodeID, browseName ua.QualifiedName, source *ObjectNode, opts ...ConditionOption) (*Condition, error) {
+	nm := m.server.NamespaceManager()
+	if typeID != ua.ObjectTypeIDConditionType && !nm.IsSubtype(typeID, ua.ObjectTypeIDConditionType) {
+		return nil, ua.BadTypeDefinitionInvalid
+	}
+	n := NewObjectNode(
+		m.server,
+		nodeID,
+		browseName,
+		ua.NewLocalizedText(browseName.Name, ""),
+		ua.NewLocalizedText("", ""),
+		nil,
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(typeID)),
+			ua.NewReference(ua.ReferenceTypeIDHasComponent, true, ua.NewExpandedNodeID(source.NodeID())),
+			ua.NewReference(ua.ReferenceTypeIDHasCondition, true, ua.NewExpandedNodeID(source.NodeID())),
+		},
+		ua.EventNotifierNone,
+	)
+	c := &Condition{
+		manager:         m,
+		node:            n,
+		eventType:       typeID,
+		source:          source,
+		conditionName:   browseName.Name,
+		conditionClass:  ua.ObjectTypeIDBaseConditionClassType,
+		enabled:         true,
+		acknowledgeable: typeID == ua.ObjectTypeIDAcknowledgeableConditionType || nm.IsSubtype(typeID, ua.ObjectTypeIDAcknowledgeableConditionType),
+		alarm:           typeID == ua.ObjectTypeIDAlarmConditionType || nm.IsSubtype(typeID, ua.ObjectTypeIDAlarmConditionType),
+		state:           conditionState{acked: true, confirmed: true, time: time.Now()},
+	}
+	if c.alarm {
+		c.shelvingState = ua.ObjectIDShelvedStateMachineTypeUnshelved
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := nm.AddNode(n); err != nil {
+		return nil, err
+	}
+	if c.alarm {
+		if err := m.addShelvingState(c); err != nil {
+			return nil, err
+		}
+	}
+	m.Lock()
+	m.conditions[nodeID] = c
+	m.Unlock()
+	return c, nil
+}
+
+// FindCondition returns the condition with the given NodeID.
+func (m *ConditionManager) FindCondition(nodeID ua.NodeID) (*Condition, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	c, ok := m.conditions[nodeID]
+	return c, ok
+}
+
+// DeleteCondition removes the condition from the address space.
+func (m *ConditionManager) DeleteCondition(c *Condition) error {
+	c.Lock()
+	if c.shelveTimer != nil {
+		c.shelveTimer.Stop()
+	}
+	c.Unlock()
+	m.Lock()
+	delete(m.conditions, c.NodeID())
+	if c.shelvingNode != nil {
+		delete(m.shelvingStates, c.shelvingNode.NodeID())
+	}
+	m.Unlock()
+	return m.server.NamespaceManager().DeleteNode(c.node, true)
+}
+
+// Conditions returns the conditions of the server.
+func (m *ConditionManager) Conditions() []*Condition {
+	m.RLock()
+	defer m.RUnlock()
+	list := make([]*Condition, 0, len(m.conditions))
+	for _, c := range m.conditions {
+		list = append(list, c)
+	}
+	return list
+}
+
+// ConditionOption is a functional option to be applied to a condition during construction.
+type ConditionOption func(*Condition)
+
+// WithConfirmRequired sets the condition to require a Confirm after it is acknowledged.
+func WithConfirmRequired() ConditionOption {
+	return func(c *Condition) {
+		c.confirmRequired = true
+	}
+}
+
+// WithConditionClass sets the class of the condition, e.g. ProcessConditionClassType.
+func WithConditionClass(classID ua.NodeID, className ua.LocalizedText) ConditionOption {
+	return func(c *Condition) {
+		c.conditionClass = classID
+		c.conditionClassName = className
+	}
+}
+
+// conditionState is the state of a condition, or of one of its branches.
+type conditionState struct {
+	branchID     ua.NodeID
+	eventID      ua.ByteString
+	time         time.Time
+	message      ua.LocalizedText
+	severity     uint16
+	lastSeverity uint16
+	quality      ua.StatusCode
+	retain       bool
+	active       bool
+	acked        bool
+	confirmed    bool
+	comment      ua.LocalizedText
+	clientUserID string
+	fields       map[string]ua.Variant // fields of subtypes, replaced as a whole
+}
+
+// Condition is an instance of ConditionType or one of its subtypes.
+// The main branch holds the current state. Previous states that still require an acknowledgement
+// are kept in branches.
+type Condition struct {
+	sync.RWMutex
+	manager            *ConditionManager
+	node               *ObjectNode
+	eventType          ua.NodeID
+	source             *ObjectNode
+	conditionName      string
+	conditionClass     ua.NodeID
+	conditionClassName ua.LocalizedText
+	enabled            bool
+	acknowledgeable    bool
+	confirmRequired    bool
+	alarm              bool
+	state              conditionState
+	branches           []*conditionState
+	emitLater          []ua.Event
+	shelvingNode       *ObjectNode
+	shelvingState      ua.NodeID
+	unshelveTime       time.Time
+	maxTimeShelved     time.Duration
+	shelveTimer        *time.Timer
+}
+
+// NodeID returns the NodeID of the condition.
+func (c *Condition) NodeID() ua.NodeID {
+	return c.node.NodeID()
+}
+
+// Source returns the node that the condition reports to.
+func (c *Condition) Source() *ObjectNode {
+	return c.source
+}
+
+// Enabled returns true if the condition is enabled.
+func (c *Condition) Enabled() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.enabled
+}
+
+// Retain returns true if the condition is of interest to clients.
+func (c *Condition) Retain() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.state.retain
+}
+
+// Active returns true if the alarm is active.
+func (c *Condition) Active() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.state.active
+}
+
+// Acked returns true if the current state of the condition is acknowledged.
+func (c *Condition) Acked() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.state.acked
+}
+
+// Confirmed returns true if the current state of the condition is confirmed.
+func (c *Condition) Confirmed() bool {
+	c.RLock()
+	defer c.RUnlock()
+	return c.state.confirmed
+}
+
+// Quality returns the quality of the values that the condition is based upon.
+func (c *Condition) Quality() ua.StatusCode {
+	c.RLock()
+	defer c.RUnlock()
+	return c.state.quality
+}
+
+// BranchCount returns the number of previous states that still require an acknowledgement or confirmation.
+func (c *Condition) BranchCount() int {
+	c.RLock()
+	defer c.RUnlock()
+	return len(c.branches)
+}
+
+// Report reports a new state of the condition with the given severity and message.
+// An acknowledgeable condition then requires an acknowledgement.
+func (c *Condition) Report(severity uint16, message ua.LocalizedText) {
+	c.Lock()
+	c.branchIfUnacked()
+	c.state.lastSeverity, c.state.severity = c.state.severity, severity
+	c.state.message = message
+	if c.acknowledgeable {
+		c.state.acked = false
+		c.state.confirmed = !c.confirmRequired
+	}
+	evts := c.update(&c.state, true)
+	c.Unlock()
+	c.emit(evts...)
+}
+
+// SetActive sets the active state of an alarm with the given severity and message.
+// An alarm becoming active requires an acknowledgement. If the previous activation is not yet
+// acknowledged, it is kept in a branch.
+func (c *Condition) SetActive(active bool, severity uint16, message ua.LocalizedText) error {
+	return c.setActive(active, severity, message, nil)
+}
+
+// setActive sets the active state of an alarm, along with the fields of subtypes.
+func (c *Condition) setActive(active bool, severity uint16, message ua.LocalizedText, fields map[string]ua.Variant) error {
+	c.Lock()
+	if !c.alarm {
+		c.Unlock()
+		return ua.BadNotSupported
+	}
+	if active && !c.state.active {
+		c.branchIfUnacked()
+		c.state.acked = false
+		c.state.confirmed = !c.confirmRequired
+	}
+	if fields != nil {
+		c.state.fields = fields
+	}
+	if !active && c.state.active && c.shelvingState == ua.ObjectIDShelvedStateMachineTypeOneShotShelved {
+		// a one-shot shelf ends when the alarm returns to normal.
+		c.setShelvingState(ua.ObjectIDShelvedStateMachineTypeUnshelved, 0)
+	}
+	c.state.active = active
+	c.state.lastSeverity, c.state.severity = c.state.severity, severity
+	c.state.message = message
+	evts := c.update(&c.state, true)
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// SetQuality sets the quality of the values that the condition is based upon.
+func (c *Condition) SetQuality(quality ua.StatusCode) {
+	c.Lock()
+	c.state.quality = quality
+	evts := c.update(&c.state, true)
+	c.Unlock()
+	c.emit(evts...)
+}
+
+// Enable enables the condition and reports its current state.
+func (c *Condition) Enable() error {
+	c.Lock()
+	if c.enabled {
+		c.Unlock()
+		return ua.BadConditionAlreadyEnabled
+	}
+	c.enabled = true
+	evts := c.update(&c.state, true)
+	for _, b := range c.branches {
+		evts = append(evts, c.update(b, true)...)
+	}
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// Disable disables the condition. Clients receive a final event with Retain false,
+// and no more events until the condition is enabled.
+func (c *Condition) Disable() error {
+	c.Lock()
+	if !c.enabled {
+		c.Unlock()
+		return ua.BadConditionAlreadyDisabled
+	}
+	c.enabled = false
+	c.state.eventID = newEventID()
+	c.state.time = time.Now()
+	c.state.retain = false
+	evts := []ua.Event{c.event(&c.state)}
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// Acknowledge acknowledges the state of the condition that was reported with the given EventId.
+func (c *Condition) Acknowledge(eventID ua.ByteString, comment ua.LocalizedText, clientUserID string) error {
+	c.Lock()
+	if !c.acknowledgeable {
+		c.Unlock()
+		return ua.BadNotSupported
+	}
+	if !c.enabled {
+		c.Unlock()
+		return ua.BadConditionDisabled
+	}
+	s, ok := c.findState(eventID)
+	if !ok {
+		c.Unlock()
+		return ua.BadEventIDUnknown
+	}
+	if s.acked {
+		c.Unlock()
+		return ua.BadConditionBranchAlreadyAcked
+	}
+	s.acked = true
+	c.setComment(s, comment, clientUserID)
+	evts := c.update(s, true)
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// Confirm confirms the state of the condition that was reported with the given EventId.
+func (c *Condition) Confirm(eventID ua.ByteString, comment ua.LocalizedText, clientUserID string) error {
+	c.Lock()
+	if !c.acknowledgeable || !c.confirmRequired {
+		c.Unlock()
+		return ua.BadNotSupported
+	}
+	if !c.enabled {
+		c.Unlock()
+		return ua.BadConditionDisabled
+	}
+	s, ok := c.findState(eventID)
+	if !ok {
+		c.Unlock()
+		return ua.BadEventIDUnknown
+	}
+	if s.confirmed {
+		c.Unlock()
+		return ua.BadConditionBranchAlreadyConfirmed
+	}
+	s.confirmed = true
+	c.setComment(s, comment, clientUserID)
+	evts := c.update(s, true)
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// AddComment adds a comment to the state of the condition that was reported with the given EventId.
+func (c *Condition) AddComment(eventID ua.ByteString, comment ua.LocalizedText, clientUserID string) error {
+	c.Lock()
+	if !c.enabled {
+		c.Unlock()
+		return ua.BadConditionDisabled
+	}
+	s, ok := c.findState(eventID)
+	if !ok {
+		c.Unlock()
+		return ua.BadEventIDUnknown
+	}
+	c.setComment(s, comment, clientUserID)
+	evts := c.update(s, true)
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// findState returns the state of the main branch or of a previous branch that was reported with the given EventId.
+func (c *Condition) findState(eventID ua.ByteString) (*conditionState, bool) {
+	if c.state.eventID == eventID {
+		return &c.state, true
+	}
+	for _, b := range c.branches {
+		if b.eventID == eventID {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+func (c *Condition) setComment(s *conditionState, comment ua.LocalizedText, clientUserID string) {
+	if comment.Text != "" || comment.Locale != "" {
+		s.comment = comment
+	}
+	s.clientUserID = clientUserID
+}
+
+// branchIfUnacked moves the current state to a new branch if it still requires an acknowledgement or confirmation.
+func (c *Condition) branchIfUnacked() {
+	if !c.acknowledgeable || !c.state.retain || (c.state.acked && c.state.confirmed) || c.state.eventID == "" {
+		return
+	}
+	b := c.state
+	b.branchID = ua.NewNodeIDGUID(1, uuid.New())
+	c.branches = append(c.branches, &b)
+	if c.enabled {
+		c.emitLater = append(c.emitLater, c.update(&b, true)...)
+	}
+}
+
+// update recalculates Retain and, if the condition is enabled, returns the event that reports the state.
+// A branch that no longer requires an acknowledgement or confirmation is removed.
+func (c *Condition) update(s *conditionState, newEvent bool) []ua.Event {
+	if newEvent {
+		s.eventID = newEventID()
+		s.time = time.Now()
+	}
+	s.retain = c.enabled && (s.active || !s.acked || !s.confirmed || (!c.acknowledgeable && !c.alarm && s.branchID == nil))
+	if s.branchID != nil && !s.retain {
+		for i, b := range c.branches {
+			if b == s {
+				c.branches = append(c.branches[:i], c.branches[i+1:]...)
+				break
+			}
+		}
+	}
+	evts := c.emitLater
+	c.emitLater = nil
+	if !c.enabled {
+		return evts
+	}
+	return append(evts, c.event(s))
+}
+
+// event returns the event that reports the state.
+func (c *Condition) event(s *conditionState) ua.Event {
+	f := map[string]ua.Variant{
+		"EventId":            s.eventID,
+		"EventType":          c.eventType,
+		"SourceNode":         c.source.NodeID(),
+		"SourceName":         c.source.DisplayName().Text,
+		"Time":               s.time,
+		"ReceiveTime":        time.Now(),
+		"Message":            s.message,
+		"Severity":           s.severity,
+		"ConditionClassId":   c.conditionClass,
+		"ConditionClassName": c.conditionClassName,
+		"ConditionName":      c.conditionName,
+		"BranchId":           s.branchID,
+		"Retain":             s.retain,
+		"EnabledState":       twoStateText(c.enabled, "Enabled", "Disabled"),
+		"EnabledState/Id":    c.enabled,
+		"Quality":            s.quality,
+		"LastSeverity":       s.lastSeverity,
+		"Comment":            s.comment,
+		"ClientUserId":       s.clientUserID,
+	}
+	if c.acknowledgeable {
+		f["AckedState"] = twoStateText(s.acked, "Acknowledged", "Unacknowledged")
+		f["AckedState/Id"] = s.acked
+		if c.confirmRequired {
+			f["ConfirmedState"] = twoStateText(s.confirmed, "Confirmed", "Unconfirmed")
+			f["ConfirmedState/Id"] = s.confirmed
+		}
+	}
+	if c.alarm {
+		f["ActiveState"] = twoStateText(s.active, "Active", "Inactive")
+		f["ActiveState/Id"] = s.active
+	}
+	if c.alarm {
+		c.addShelvingFields(f)
+	}
+	for k, v := range s.fields {
+		f[k] = v
+	}
+	return &conditionEvent{conditionID: c.NodeID(), fields: f}
+}
+
+// emit reports the events to the source of the condition.
+func (c *Condition) emit(evts ...ua.Event) {
+	for _, evt := range evts {
+		c.manager.server.NamespaceManager().OnEvent(c.source, evt)
+	}
+}
+
+// retainedEvents returns the events that report the retained states of an enabled condition.
+func (c *Condition) retainedEvents() []ua.Event {
+	c.RLock()
+	defer c.RUnlock()
+	if !c.enabled {
+		return nil
+	}
+	evts := []ua.Event{}
+	for _, b := range c.branches {
+		if b.retain {
+			evts = append(evts, c.event(b))
+		}
+	}
+	if c.state.retain {
+		evts = append(evts, c.event(&c.state))
+	}
+	return evts
+}
+
+func twoStateText(value bool, trueText, falseText string) ua.LocalizedText {
+	if value {
+		return ua.NewLocalizedText(trueText, "")
+	}
+	return ua.NewLocalizedText(falseText, "")
+}
+
+// newEventID returns a new random EventId.
+func newEventID() ua.ByteString {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return ua.ByteString(b)
+}
+
+// conditionEvent is an event of ConditionType or one of its subtypes. Fields are selected by their browse path.
+type conditionEvent struct {
+	conditionID ua.NodeID
+	fields      map[string]ua.Variant
+}
+
+// GetAttribute returns the value of the field selected by the clause.
+func (e *conditionEvent) GetAttribute(clause ua.SimpleAttributeOperand) ua.Variant {
+	names := make([]string, len(clause.BrowsePath))
+	for i, qn := range clause.BrowsePath {
+		names[i] = qn.Name
+	}
+	path := strings.Join(names, "/")
+	if clause.AttributeID == ua.AttributeIDNodeID && path == "" {
+		return e.conditionID
+	}
+	if clause.AttributeID != ua.AttributeIDValue {
+		return nil
+	}
+	return e.fields[path]
+}
+
+// notifiers returns the nodes that receive the events reported to the source node.
+// Like NamespaceManager.OnEvent, it follows HasNotifier references until the Server node.
+func (m *ConditionManager) notifiers(source *ObjectNode) map[ua.NodeID]bool {
+	nm := m.server.NamespaceManager()
+	set := map[ua.NodeID]bool{}
+	for target := source; target != nil && !set[target.NodeID()]; {
+		set[target.NodeID()] = true
+		if target.NodeID() == ua.ObjectIDServer {
+			break
+		}
+		var next *ObjectNode
+		for _, r := range target.References() {
+			if r.IsInverse && r.ReferenceTypeID == ua.ReferenceTypeIDHasNotifier {
+				next, _ = nm.FindObject(ua.ToNodeID(r.TargetID, nm.NamespaceUris()))
+				break
+			}
+		}
+		target = next
+	}
+	return set
+}
+
+// refresh sends the retained conditions to the event items, between a RefreshStartEvent and a RefreshEndEvent.
+func (m *ConditionManager) refresh(items []*EventMonitoredItem) {
+	start := &ua.BaseEvent{
+		EventID:     newEventID(),
+		EventType:   ua.ObjectTypeIDRefreshStartEventType,
+		SourceNode:  ua.ObjectIDServer,
+		SourceName:  "Server",
+		Time:        time.Now(),
+		ReceiveTime: time.Now(),
+		Message:     ua.NewLocalizedText("ConditionRefresh started.", ""),
+		Severity:    100,
+	}
+	for _, mi := range items {
+		mi.onRefreshEvent(start)
+	}
+	for _, c := range m.Conditions() {
+		evts := c.retainedEvents()
+		if len(evts) == 0 {
+			continue
+		}
+		notifiers := m.notifiers(c.source)
+		for _, mi := range items {
+			if notifiers[mi.Node().NodeID()] {
+				for _, evt := range evts {
+					mi.OnEvent(evt)
+				}
+			}
+		}
+	}
+	end := &ua.BaseEvent{
+		EventID:     newEventID(),
+		EventType:   ua.ObjectTypeIDRefreshEndEventType,
+		SourceNode:  ua.ObjectIDServer,
+		SourceName:  "Server",
+		Time:        time.Now(),
+		ReceiveTime: time.Now(),
+		Message:     ua.NewLocalizedText("ConditionRefresh ended.", ""),
+		Severity:    100,
+	}
+	for _, mi := range items {
+		mi.onRefreshEvent(end)
+	}
+}
+
+// findCallCondition returns the condition that is the object of the method call.
+func (m *ConditionManager) findCallCondition(req ua.CallMethodRequest) (*Condition, ua.StatusCode) {
+	c, ok := m.FindCondition(req.ObjectID)
+	if !ok {
+		return nil, ua.BadNodeIDInvalid
+	}
+	return c, ua.Good
+}
+
+// clientUserID returns the name of the user of the session. A user with a certificate is named by its subject,
+// and a user with an issued token is named by the thumbprint of the token.
+func clientUserID(session *Session) string {
+	if session == nil {
+		return ""
+	}
+	switch id := session.UserIdentity().(type) {
+	case ua.UserNameIdentity:
+		return id.UserName
+	case ua.X509Identity:
+		if crt, err := x509.ParseCertificate([]byte(id.Certificate)); err == nil {
+			return crt.Subject.String()
+		}
+		return fmt.Sprintf("%x", sha1.Sum([]byte(id.Certificate)))
+	case ua.IssuedIdentity:
+		return fmt.Sprintf("%x", sha1.Sum([]byte(id.TokenData)))
+	default:
+		return ""
+	}
+}
+
+func (m *ConditionManager) onEnable(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) > 0 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	c, sc := m.findCallCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.Enable(); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+func (m *ConditionManager) onDisable(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) > 0 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	c, sc := m.findCallCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.Disable(); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+// commentArguments returns the EventId and Comment arguments of Acknowledge, Confirm and AddComment.
+func commentArguments(req ua.CallMethodRequest) (ua.ByteString, ua.LocalizedText, *ua.CallMethodResult) {
+	if len(req.InputArguments) < 2 {
+		return "", ua.LocalizedText{}, &ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 2 {
+		return "", ua.LocalizedText{}, &ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	statusCode := ua.Good
+	inputArgumentResults := make([]ua.StatusCode, 2)
+	eventID, ok := req.InputArguments[0].(ua.ByteString)
+	if !ok {
+		statusCode = ua.BadInvalidArgument
+		inputArgumentResults[0] = ua.BadTypeMismatch
+	}
+	comment, ok := req.InputArguments[1].(ua.LocalizedText)
+	if !ok && req.InputArguments[1] != nil {
+		statusCode = ua.BadInvalidArgument
+		inputArgumentResults[1] = ua.BadTypeMismatch
+	}
+	if statusCode == ua.BadInvalidArgument {
+		return "", ua.LocalizedText{}, &ua.CallMethodResult{StatusCode: statusCode, InputArgumentResults: inputArgumentResults}
+	}
+	return eventID, comment, nil
+}
+
+func (m *ConditionManager) onAcknowledge(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	eventID, comment, res := commentArguments(req)
+	if res != nil {
+		return *res
+	}
+	c, sc := m.findCallCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.Acknowledge(eventID, comment, clientUserID(session)); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+func (m *ConditionManager) onConfirm(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	eventID, comment, res := commentArguments(req)
+	if res != nil {
+		return *res
+	}
+	c, sc := m.findCallCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.Confirm(eventID, comment, clientUserID(session)); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+func (m *ConditionManager) onAddComment(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	eventID, comment, res := commentArguments(req)
+	if res != nil {
+		return *res
+	}
+	c, sc := m.findCallCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.AddComment(eventID, comment, clientUserID(session)); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+func (m *ConditionManager) onConditionRefresh(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	subscriptionID, ok := req.InputArguments[0].(uint32)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+	}
+	sub, ok := m.server.SubscriptionManager().Get(subscriptionID)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadSubscriptionIDInvalid}
+	}
+	if session == nil || sub.session.Load() != session {
+		return ua.CallMethodResult{StatusCode: ua.BadUserAccessDenied}
+	}
+	items := []*EventMonitoredItem{}
+	for _, item := range sub.Items() {
+		if mi, ok := item.(*EventMonitoredItem); ok {
+			items = append(items, mi)
+		}
+	}
+	m.refresh(items)
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+func (m *ConditionManager) onConditionRefresh2(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	statusCode := ua.Good
+	inputArgumentResults := make([]ua.StatusCode, 2)
+	subscriptionID, ok := req.InputArguments[0].(uint32)
+	if !ok {
+		statusCode = ua.BadInvalidArgument
+		inputArgumentResults[0] = ua.BadTypeMismatch
+	}
+	monitoredItemID, ok := req.InputArguments[1].(uint32)
+	if !ok {
+		statusCode = ua.BadInvalidArgument
+		inputArgumentResults[1] = ua.BadTypeMismatch
+	}
+	if statusCode == ua.BadInvalidArgument {
+		return ua.CallMethodResult{StatusCode: statusCode, InputArgumentResults: inputArgumentResults}
+	}
+	sub, ok := m.server.SubscriptionManager().Get(subscriptionID)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadSubscriptionIDInvalid}
+	}
+	if session == nil || sub.session.Load() != session {
+		return ua.CallMethodResult{StatusCode: ua.BadUserAccessDenied}
+	}
+	item, ok := sub.FindItem(monitoredItemID)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadMonitoredItemIDInvalid}
+	}
+	mi, ok := item.(*EventMonitoredItem)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadMonitoredItemIDInvalid}
+	}
+	m.refresh([]*EventMonitoredItem{mi})
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
@@ -0,0 +1,808 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+	"github.com/google/uuid"
+)
+
+var (
+	attributeOperandEventType  = ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDBaseEventType, BrowsePath: ua.ParseBrowsePath("EventType"), AttributeID: ua.AttributeIDValue}
+	attributeOperandSourceNode = ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDBaseEventType, BrowsePath: ua.ParseBrowsePath("SourceNode"), AttributeID: ua.AttributeIDValue}
+)
+
+// validateEventFilter checks the select clauses and where clause of the EventFilter.
+// Returns BadMonitoredItemFilterInvalid if the where clause cannot be evaluated.
+// Returns an EventFilterResult if any clause has an error, else nil.
+func (srv *Server) validateEventFilter(filter ua.EventFilter) (ua.StatusCode, ua.ExtensionObject) {
+	statusCode := ua.Good
+	hasErrors := false
+	selectResults := make([]ua.StatusCode, len(filter.SelectClauses))
+	for i, clause := range filter.SelectClauses {
+		selectResults[i] = srv.validateSimpleAttributeOperand(clause)
+		if selectResults[i].IsBad() {
+			hasErrors = true
+		}
+	}
+	elements := filter.WhereClause.Elements
+	elementResults := make([]ua.ContentFilterElementResult, len(elements))
+	for i := range elements {
+		elementResults[i] = srv.validateContentFilterElement(elements, i)
+		if elementResults[i].StatusCode.IsBad() {
+			hasErrors = true
+			statusCode = ua.BadMonitoredItemFilterInvalid
+		}
+	}
+	if !hasErrors {
+		return statusCode, nil
+	}
+	return statusCode, ua.EventFilterResult{
+		SelectClauseResults: selectResults,
+		WhereClauseResult:   ua.ContentFilterResult{ElementResults: elementResults},
+	}
+}
+
+// validateSimpleAttributeOperand checks the attribute and type definition of the operand.
+func (srv *Server) validateSimpleAttributeOperand(op ua.SimpleAttributeOperand) ua.StatusCode {
+	if op.AttributeID < ua.AttributeIDNodeID || op.AttributeID > ua.AttributeIDAccessLevelEx {
+		return ua.BadAttributeIDInvalid
+	}
+	if op.TypeDefinitionID != nil {
+		n, ok := srv.NamespaceManager().FindNode(op.TypeDefinitionID)
+		if !ok || n.NodeClass() != ua.NodeClassObjectType {
+			return ua.BadTypeDefinitionInvalid
+		}
+	}
+	for _, name := range op.BrowsePath {
+		if name.Name == "" {
+			return ua.BadBrowseNameInvalid
+		}
+	}
+	return ua.Good
+}
+
+// validateContentFilterElement checks the operator and operands of the element at the given index.
+func (srv *Server) validateContentFilterElement(elements []ua.ContentFilterElement, idx int) ua.ContentFilterElementResult {
+	element := elements[idx]
+	ops := element.FilterOperands
+	var count int
+	switch element.FilterOperator {
+	case ua.FilterOperatorIsNull, ua.FilterOperatorNot, ua.FilterOperatorInView, ua.FilterOperatorOfType:
+		count = 1
+	case ua.FilterOperatorEquals, ua.FilterOperatorGreaterThan, ua.FilterOperatorLessThan,
+		ua.FilterOperatorGreaterThanOrEqual, ua.FilterOperatorLessThanOrEqual, ua.FilterOperatorLike,
+		ua.FilterOperatorAnd, ua.FilterOperatorOr, ua.FilterOperatorCast,
+		ua.FilterOperatorBitwiseAnd, ua.FilterOperatorBitwiseOr:
+		count = 2
+	case ua.FilterOperatorBetween:
+		count = 3
+	case ua.FilterOperatorInList:
+		count = -2 // at least two
+	case ua.FilterOperatorRelatedTo:
+		return ua.ContentFilterElementResult{StatusCode: ua.BadFilterOperatorUnsupported}
+	default:
+		return ua.ContentFilterElementResult{StatusCode: ua.BadFilterOperatorInvalid}
+	}
+	if (count > 0 && len(ops) != count) || (count < 0 && len(ops) < -count) {
+		return ua.ContentFilterElementResult{StatusCode: ua.BadFilterOperandCountMismatch}
+	}
+	m := srv.NamespaceManager()
+	results := make([]ua.StatusCode, len(ops))
+	hasErrors := false
+	for i, op := range ops {
+		switch o := op.(type) {
+		case ua.LiteralOperand:
+			var want ua.NodeClass
+			switch {
+			case element.FilterOperator == ua.FilterOperatorOfType:
+				want = ua.NodeClassObjectType
+			case element.FilterOperator == ua.FilterOperatorInView:
+				want = ua.NodeClassView
+			case element.FilterOperator == ua.FilterOperatorCast && i == 1:
+				want = ua.NodeClassDataType
+			default:
+				continue
+			}
+			id, ok := o.Value.(ua.NodeID)
+			if !ok {
+				results[i] = ua.BadFilterOperandInvalid
+				break
+			}
+			if n, ok := m.FindNode(id); !ok || n.NodeClass() != want {
+				results[i] = ua.BadFilterOperandInvalid
+			}
+		case ua.SimpleAttributeOperand:
+			if element.FilterOperator == ua.FilterOperatorOfType || element.FilterOperator == ua.FilterOperatorInView ||
+				(element.FilterOperator == ua.FilterOperatorCast && i == 1) {
+				results[i] = ua.BadFilterOperandInvalid
+				break
+			}
+			results[i] = srv.validateSimpleAttributeOperand(o)
+		case ua.ElementOperand:
+			if int(o.Index) <= idx || int(o.Index) >= len(elements) {
+				results[i] = ua.BadFilterOperandInvalid
+			}
+		default:
+			// AttributeOperand is for queries and not supported for events.
+			results[i] = ua.BadFilterOperandInvalid
+		}
+		if results[i].IsBad() {
+			hasErrors = true
+		}
+	}
+	if hasErrors {
+		return ua.ContentFilterElementResult{StatusCode: ua.BadFilterOperandInvalid, OperandStatusCodes: results}
+	}
+	return ua.ContentFilterElementResult{}
+}
+
+// filterEvaluator evaluates the elements of a ContentFilter for an event.
+// A nil result stands for NULL, e.g. when the event does not have the selected field.
+type filterEvaluator struct {
+	m         *NamespaceManager
+	elements  []ua.ContentFilterElement
+	evt       ua.Event
+	results   []ua.Variant
+	evaluated []bool
+}
+
+// evaluateWhereClause returns true if the event satisfies the where clause.
+// An empty where clause is satisfied by every event.
+func evaluateWhereClause(m *NamespaceManager, whereClause ua.ContentFilter, evt ua.Event) bool {
+	if len(whereClause.Elements) == 0 {
+		return true
+	}
+	e := &filterEvaluator{
+		m:         m,
+		elements:  whereClause.Elements,
+		evt:       evt,
+		results:   make([]ua.Variant, len(whereClause.Elements)),
+		evaluated: make([]bool, len(whereClause.Elements)),
+	}
+	res, ok := e.evaluate(0).(bool)
+	return ok && res
+}
+
+// evaluate returns the result of the element at the given index. Each element is evaluated once per event,
+// however many operands refer to it.
+func (e *filterEvaluator) evaluate(idx int) ua.Variant {
+	if !e.evaluated[idx] {
+		e.results[idx] = e.evaluateElement(idx)
+		e.evaluated[idx] = true
+	}
+	return e.results[idx]
+}
+
+// evaluateElement computes the result of the element at the given index.
+func (e *filterEvaluator) evaluateElement(idx int) ua.Variant {
+	element := e.elements[idx]
+	ops := element.FilterOperands
+	operand := func(i int) ua.Variant {
+		if i >= len(ops) {
+			return nil
+		}
+		return e.operand(idx, ops[i])
+	}
+	switch element.FilterOperator {
+
+	case ua.FilterOperatorEquals:
+		a, b := operand(0), operand(1)
+		if a == nil || b == nil {
+			return nil
+		}
+		return equalOperands(a, b)
+
+	case ua.FilterOperatorIsNull:
+		return operand(0) == nil
+
+	case ua.FilterOperatorGreaterThan, ua.FilterOperatorLessThan,
+		ua.FilterOperatorGreaterThanOrEqual, ua.FilterOperatorLessThanOrEqual:
+		a, b := operand(0), operand(1)
+		if a == nil || b == nil {
+			return nil
+		}
+		c, ok := compareOperands(a, b)
+		if !ok {
+			return false
+		}
+		switch element.FilterOperator {
+		case ua.FilterOperatorGreaterThan:
+			return c > 0
+		case ua.FilterOperatorLessThan:
+			return c < 0
+		case ua.FilterOperatorGreaterThanOrEqual:
+			return c >= 0
+		default:
+			return c <= 0
+		}
+
+	case ua.FilterOperatorLike:
+		a, b := operand(0), operand(1)
+		if a == nil || b == nil {
+			return nil
+		}
+		s, ok1 := castTo(a, ua.VariantTypeString)
+		p, ok2 := castTo(b, ua.VariantTypeString)
+		if !ok1 || !ok2 {
+			return false
+		}
+		re, err := likeToRegexp(p.(string))
+		if err != nil {
+			return false
+		}
+		return re.MatchString(s.(string))
+
+	case ua.FilterOperatorNot:
+		a, ok := operand(0).(bool)
+		if !ok {
+			return nil
+		}
+		return !a
+
+	case ua.FilterOperatorBetween:
+		a, b, c := operand(0), operand(1), operand(2)
+		if a == nil || b == nil || c == nil {
+			return nil
+		}
+		lo, ok1 := compareOperands(a, b)
+		hi, ok2 := compareOperands(a, c)
+		return ok1 && ok2 && lo >= 0 && hi <= 0
+
+	case ua.FilterOperatorInList:
+		a := operand(0)
+		if a == nil {
+			return nil
+		}
+		for i := 1; i < len(ops); i++ {
+			if b := operand(i); b != nil && equalOperands(a, b) {
+				return true
+			}
+		}
+		return false
+
+	case ua.FilterOperatorAnd:
+		a, b := operand(0), operand(1)
+		if a == false || b == false {
+			return false
+		}
+		if a != true || b != true {
+			return nil
+		}
+		return true
+
+	case ua.FilterOperatorOr:
+		a, b := operand(0), operand(1)
+		if a == true || b == true {
+			return true
+		}
+		if a != false || b != false {
+			return nil
+		}
+		return false
+
+	case ua.FilterOperatorCast:
+		a := operand(0)
+		dt, ok := operand(1).(ua.NodeID)
+		if a == nil || !ok {
+			return nil
+		}
+		v, ok := castTo(a, e.m.FindVariantType(dt))
+		if !ok {
+			return nil
+		}
+		return v
+
+	case ua.FilterOperatorInView:
+		view, ok := operand(0).(ua.NodeID)
+		if !ok {
+			return false
+		}
+		source, ok := e.evt.GetAttribute(attributeOperandSourceNode).(ua.NodeID)
+		if !ok {
+			return false
+		}
+		return e.isInView(view, source)
+
+	case ua.FilterOperatorOfType:
+		b, ok := operand(0).(ua.NodeID)
+		if !ok {
+			return false
+		}
+		c, ok := e.evt.GetAttribute(attributeOperandEventType).(ua.NodeID)
+		if !ok {
+			return false
+		}
+		return c == b || e.m.IsSubtype(c, b)
+
+	case ua.FilterOperatorBitwiseAnd, ua.FilterOperatorBitwiseOr:
+		a, b := operand(0), operand(1)
+		if a == nil || b == nil {
+			return nil
+		}
+		a, b, ok := implicitConvert(a, b)
+		if !ok {
+			return nil
+		}
+		va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+		r := reflect.New(va.Type()).Elem()
+		switch va.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if element.FilterOperator == ua.FilterOperatorBitwiseAnd {
+				r.SetInt(va.Int() & vb.Int())
+			} else {
+				r.SetInt(va.Int() | vb.Int())
+			}
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if element.FilterOperator == ua.FilterOperatorBitwiseAnd {
+				r.SetUint(va.Uint() & vb.Uint())
+			} else {
+				r.SetUint(va.Uint() | vb.Uint())
+			}
+		default:
+			return nil
+		}
+		return r.Interface()
+
+	default:
+		return nil
+	}
+}
+
+// operand returns the value of an operand of the element at the given index.
+func (e *filterEvaluator) operand(idx int, op ua.ExtensionObject) ua.Variant {
+	switch o := op.(type) {
+	case ua.LiteralOperand:
+		return o.Value
+	case ua.SimpleAttributeOperand:
+		return e.evt.GetAttribute(o)
+	case ua.ElementOperand:
+		// an element may only refer to elements that follow it, so evaluation always terminates.
+		if int(o.Index) <= idx || int(o.Index) >= len(e.elements) {
+			return nil
+		}
+		return e.evaluate(int(o.Index))
+	default:
+		return nil
+	}
+}
+
+// isInView returns true if the node can be reached from the view by following hierarchical references.
+func (e *filterEvaluator) isInView(viewID, nodeID ua.NodeID) bool {
+	visited := map[ua.NodeID]bool{viewID: true}
+	queue := []ua.NodeID{viewID}
+	for len(queue) > 0 {
+		n, ok := e.m.FindNode(queue[0])
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, r := range n.References() {
+			if r.IsInverse || !e.m.IsSubtype(r.ReferenceTypeID, ua.ReferenceTypeIDHierarchicalReferences) {
+				continue
+			}
+			id := ua.ToNodeID(r.TargetID, e.m.NamespaceUris())
+			if id == nodeID {
+				return true
+			}
+			if !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+	return false
+}
+
+// equalOperands returns true if the operands are equal after implicit conversion to a common type.
+func equalOperands(a, b ua.Variant) bool {
+	a, b, ok := implicitConvert(a, b)
+	if !ok {
+		return false
+	}
+	if c, ok := compareSame(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareOperands compares the operands after implicit conversion to a common type.
+func compareOperands(a, b ua.Variant) (int, bool) {
+	a, b, ok := implicitConvert(a, b)
+	if !ok {
+		return 0, false
+	}
+	return compareSame(a, b)
+}
+
+// compareSame compares two ordered values of the same type.
+func compareSame(a, b ua.Variant) (int, bool) {
+	if ta, ok := a.(time.Time); ok {
+		return ta.Compare(b.(time.Time)), true
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp3(va.Int() < vb.Int(), va.Int() > vb.Int()), true
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp3(va.Uint() < vb.Uint(), va.Uint() > vb.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return cmp3(va.Float() < vb.Float(), va.Float() > vb.Float()), true
+	case reflect.String:
+		return strings.Compare(va.String(), vb.String()), true
+	case reflect.Bool:
+		return cmp3(!va.Bool() && vb.Bool(), va.Bool() && !vb.Bool()), true
+	default:
+		return 0, false
+	}
+}
+
+func cmp3(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// implicitConvert converts the operand with the lower precedence to the type of the other operand.
+// See Part 4, 7.4.3 for the precedence of data types.
+func implicitConvert(a, b ua.Variant) (ua.Variant, ua.Variant, bool) {
+	if reflect.TypeOf(a) == reflect.TypeOf(b) {
+		return a, b, true
+	}
+	ra, ta := conversionRank(a)
+	rb, tb := conversionRank(b)
+	if ra == 0 || rb == 0 {
+		// NodeIDs of different kinds are still comparable.
+		if _, ok := a.(ua.NodeID); ok {
+			if _, ok := b.(ua.NodeID); ok {
+				return a, b, true
+			}
+		}
+		return nil, nil, false
+	}
+	if ra > rb {
+		b2, ok := castTo(b, ta)
+		return a, b2, ok
+	}
+	a2, ok := castTo(a, tb)
+	return a2, b, ok
+}
+
+// conversionRank returns the precedence of the value's type for implicit conversion, and its VariantType.
+func conversionRank(v ua.Variant) (int, byte) {
+	switch v.(type) {
+	case float64:
+		return 18, ua.VariantTypeDouble
+	case float32:
+		return 17, ua.VariantTypeFloat
+	case int64:
+		return 16, ua.VariantTypeInt64
+	case uint64:
+		return 15, ua.VariantTypeUInt64
+	case int32:
+		return 14, ua.VariantTypeInt32
+	case uint32:
+		return 13, ua.VariantTypeUInt32
+	case ua.StatusCode:
+		return 12, ua.VariantTypeStatusCode
+	case int16:
+		return 11, ua.VariantTypeInt16
+	case uint16:
+		return 10, ua.VariantTypeUInt16
+	case int8:
+		return 9, ua.VariantTypeSByte
+	case byte:
+		return 8, ua.VariantTypeByte
+	case bool:
+		return 7, ua.VariantTypeBoolean
+	case uuid.UUID:
+		return 6, ua.VariantTypeGUID
+	case string:
+		return 5, ua.VariantTypeString
+	case ua.ExpandedNodeID:
+		return 4, ua.VariantTypeExpandedNodeID
+	case ua.NodeID:
+		return 3, ua.VariantTypeNodeID
+	case ua.LocalizedText:
+		return 2, ua.VariantTypeLocalizedText
+	case ua.QualifiedName:
+		return 1, ua.VariantTypeQualifiedName
+	default:
+		return 0, ua.VariantTypeNull
+	}
+}
+
+// castTo converts the value to the given VariantType. Returns false if the conversion is not defined or fails.
+func castTo(v ua.Variant, vt byte) (ua.Variant, bool) {
+	switch vt {
+	case ua.VariantTypeBoolean:
+		switch x := v.(type) {
+		case bool:
+			return x, true
+		case string:
+			b, err := strconv.ParseBool(x)
+			return b, err == nil
+		}
+		if f, ok := toFloat(v); ok {
+			return f != 0, true
+		}
+	case ua.VariantTypeSByte:
+		if i, ok := toInt(v, math.MinInt8, math.MaxInt8); ok {
+			return int8(i), true
+		}
+	case ua.VariantTypeInt16:
+		if i, ok := toInt(v, math.MinInt16, math.MaxInt16); ok {
+			return int16(i), true
+		}
+	case ua.VariantTypeInt32:
+		if i, ok := toInt(v, math.MinInt32, math.MaxInt32); ok {
+			return int32(i), true
+		}
+	case ua.VariantTypeInt64:
+		if i, ok := toInt(v, math.MinInt64, math.MaxInt64); ok {
+			return i, true
+		}
+	case ua.VariantTypeByte:
+		if u, ok := toUint(v, math.MaxUint8); ok {
+			return byte(u), true
+		}
+	case ua.VariantTypeUInt16:
+		if u, ok := toUint(v, math.MaxUint16); ok {
+			return uint16(u), true
+		}
+	case ua.VariantTypeUInt32:
+		if u, ok := toUint(v, math.MaxUint32); ok {
+			return uint32(u), true
+		}
+	case ua.VariantTypeUInt64:
+		if u, ok := toUint(v, math.MaxUint64); ok {
+			return u, true
+		}
+	case ua.VariantTypeStatusCode:
+		if u, ok := toUint(v, math.MaxUint32); ok {
+			return ua.StatusCode(u), true
+		}
+	case ua.VariantTypeFloat:
+		if f, ok := toFloat(v); ok && math.Abs(f) <= math.MaxFloat32 {
+			return float32(f), true
+		}
+	case ua.VariantTypeDouble:
+		if f, ok := toFloat(v); ok {
+			return f, true
+		}
+	case ua.VariantTypeString:
+		switch x := v.(type) {
+		case string:
+			return x, true
+		case bool:
+			return strconv.FormatBool(x), true
+		case float32:
+			return strconv.FormatFloat(float64(x), 'g', -1, 32), true
+		case float64:
+			return strconv.FormatFloat(x, 'g', -1, 64), true
+		case uuid.UUID:
+			return x.String(), true
+		case ua.LocalizedText:
+			return x.Text, true
+		case ua.QualifiedName:
+			return x.String(), true
+		case ua.ExpandedNodeID:
+			return x.String(), true
+		case ua.NodeID:
+			return fmt.Sprint(x), true
+		case time.Time:
+			return x.UTC().Format(time.RFC3339Nano), true
+		}
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.FormatInt(rv.Int(), 10), true
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return strconv.FormatUint(rv.Uint(), 10), true
+		}
+	case ua.VariantTypeDateTime:
+		switch x := v.(type) {
+		case time.Time:
+			return x, true
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, x)
+			return t, err == nil
+		}
+	case ua.VariantTypeGUID:
+		switch x := v.(type) {
+		case uuid.UUID:
+			return x, true
+		case string:
+			g, err := uuid.Parse(x)
+			return g, err == nil
+		}
+	case ua.VariantTypeNodeID:
+		switch x := v.(type) {
+		case ua.NodeID:
+			return x, true
+		case ua.ExpandedNodeID:
+			if x.NamespaceURI == "" && x.ServerIndex == 0 && x.NodeID != nil {
+				return x.NodeID, true
+			}
+		case string:
+			if id := ua.ParseNodeID(x); id != nil {
+				return id, true
+			}
+		}
+	case ua.VariantTypeExpandedNodeID:
+		switch x := v.(type) {
+		case ua.ExpandedNodeID:
+			return x, true
+		case ua.NodeID:
+			return ua.NewExpandedNodeID(x), true
+		case string:
+			if id := ua.ParseExpandedNodeID(x); id.NodeID != nil {
+				return id, true
+			}
+		}
+	case ua.VariantTypeLocalizedText:
+		switch x := v.(type) {
+		case ua.LocalizedText:
+			return x, true
+		case string:
+			return ua.NewLocalizedText(x, ""), true
+		}
+	case ua.VariantTypeQualifiedName:
+		switch x := v.(type) {
+		case ua.QualifiedName:
+			return x, true
+		case string:
+			return ua.ParseQualifiedName(x), true
+		}
+	}
+	return nil, false
+}
+
+// toFloat returns the numeric or boolean value as a float64.
+func toFloat(v ua.Variant) (float64, bool) {
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	case ua.StatusCode:
+		return float64(x), true
+	case string:
+		f, err := strconv.ParseFloat(x, 64)
+		return f, err == nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// toInt returns the value as an int64, if it is in range. Floating point values are rounded.
+func toInt(v ua.Variant, min, max int64) (int64, bool) {
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		i, err := strconv.ParseInt(x, 10, 64)
+		return i, err == nil && i >= min && i <= max
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		return i, i >= min && i <= max
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := rv.Uint()
+		return int64(u), u <= uint64(max)
+	case reflect.Float32, reflect.Float64:
+		f := math.Round(rv.Float())
+		if f < float64(min) || f >= float64(max)+1 || math.IsNaN(f) {
+			return 0, false
+		}
+		return int64(f), true
+	}
+	return 0, false
+}
+
+// toUint returns the value as a uint64, if it is in range. Floating point values are rounded.
+func toUint(v ua.Variant, max uint64) (uint64, bool) {
+	switch x := v.(type) {
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		u, err := strconv.ParseUint(x, 10, 64)
+		return u, err == nil && u <= max
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := rv.Int()
+		return uint64(i), i >= 0 && uint64(i) <= max
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := rv.Uint()
+		return u, u <= max
+	case reflect.Float32, reflect.Float64:
+		f := math.Round(rv.Float())
+		if f < 0 || f >= float64(max)+1 || math.IsNaN(f) {
+			return 0, false
+		}
+		return uint64(f), true
+	}
+	return 0, false
+}
+
+// likeToRegexp converts the pattern of the Like operator to a regular expression.
+// '%' matches any string, '_' matches any single character, '[]' matches any character in a set or range,
+// '[^]' matches any character not in the set, and '\' escapes the next character.
+func likeToRegexp(pattern string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("(?s)^")
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '%':
+			sb.WriteString(".*")
+		case '_':
+			sb.WriteString(".")
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				c = runes[i]
+			}
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		case '[':
+			j := i + 1
+			for j < len(runes) && runes[j] != ']' {
+				j++
+			}
+			if j >= len(runes) || j == i+1 {
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			set := runes[i+1 : j]
+			sb.WriteString("[")
+			if set[0] == '^' && len(set) > 1 {
+				sb.WriteString("^")
+				set = set[1:]
+			}
+			for _, r := range set {
+				if r == '\\' || r == '[' || r == ']' || r == '^' {
+					sb.WriteRune('\\')
+				}
+				sb.WriteRune(r)
+			}
+			sb.WriteString("]")
+			i = j
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
@@ -6,59 +6,28 @@ import (
 	"bytes"
 	"math"
 	"reflect"
-	"sync/atomic"
 	"time"
 
-	"sync"
-
 	"github.com/awcullen/opcua/ua"
 	deque "github.com/gammazero/deque"
 )
 
 // DataChangeMonitoredItem specifies the node and attribute that is monitored for data changes.
 type DataChangeMonitoredItem struct {
-	sync.RWMutex
-	id                  uint32
-	itemToMonitor       ua.ReadValueID
-	monitoringMode      ua.MonitoringMode
-	clientHandle        uint32
-	samplingInterval    float64
-	queueSize           uint32
-	discardOldest       bool
-	timestampsToReturn  ua.TimestampsToReturn
-	minSamplingInterval float64
-	queue               deque.Deque[ua.DataValue]
-	node                Node
+	sampledMonitoredItem
 	dataChangeFilter    ua.DataChangeFilter
+	percentDeadband     float64
 	previousQueuedValue ua.DataValue
-	sub                 *Subscription
-	srv                 *Server
 	prequeue            deque.Deque[ua.DataValue]
 	ts                  time.Time
-	ti                  time.Duration
-	triggeredItems      []MonitoredItem
-	triggered           bool
 }
 
 // NewDataChangeMonitoredItem constructs a new DataChangeMonitoredItem.
 func NewDataChangeMonitoredItem(sub *Subscription, node Node, itemToMonitor ua.ReadValueID, monitoringMode ua.MonitoringMode, parameters ua.MonitoringParameters, timestampsToReturn ua.TimestampsToReturn, minSamplingInterval float64) *DataChangeMonitoredItem {
 	mi := &DataChangeMonitoredItem{
-		sub:                 sub,
-		srv:                 sub.manager.server,
-		node:                node,
-		id:                  atomic.AddUint32(&monitoredItemID, 1),
-		itemToMonitor:       itemToMonitor,
-		monitoringMode:      monitoringMode,
-		clientHandle:        parameters.ClientHandle,
-		discardOldest:       parameters.DiscardOldest,
-		timestampsToReturn:  timestampsToReturn,
-		minSamplingInterval: minSamplingInterval,
-		queue:               deque.Deque[ua.DataValue]{},
-		prequeue:            deque.Deque[ua.DataValue]{},
 		previousQueuedValue: ua.NewDataValue(nil, ua.BadWaitingForInitialData, time.Time{}, 0, time.Time{}, 0),
 	}
-	mi.setQueueSize(parameters.QueueSize)
-	mi.setSamplingInterval(parameters.SamplingInterval)
+	mi.init(mi, sub, node, itemToMonitor, monitoringMode, parameters, timestampsToReturn, minSamplingInterval)
 	mi.setFilter(parameters.Filter)
 
 	mi.Lock()
@@ -67,289 +36,40 @@ func NewDataChangeMonitoredItem(sub *Subscription, node Node, itemToMonitor ua.R
 	return mi
 }
 
-// ID returns the identifier of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) ID() uint32 {
-	return mi.id
-}
-
-// Node returns the Node of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) Node() Node {
-	return mi.node
-}
-
-// ItemToMonitor returns the ReadValueID of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) ItemToMonitor() ua.ReadValueID {
-	return mi.itemToMonitor
-}
-
-// SamplingInterval returns the sampling interval in ms of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) SamplingInterval() float64 {
-	mi.RLock()
-	defer mi.RUnlock()
-	return mi.samplingInterval
-}
-
-// QueueSize returns the queue size of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) QueueSize() uint32 {
-	mi.RLock()
-	defer mi.RUnlock()
-	return mi.queueSize
-}
-
-// MonitoringMode returns the monitoring mode of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) MonitoringMode() ua.MonitoringMode {
-	mi.RLock()
-	defer mi.RUnlock()
-	return mi.monitoringMode
-}
-
-// ClientHandle returns the client handle of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) ClientHandle() uint32 {
-	mi.RLock()
-	defer mi.RUnlock()
-	return mi.clientHandle
-}
-
-// Triggered returns true when the MonitoredItem is triggered.
-func (mi *DataChangeMonitoredItem) Triggered() bool {
-	mi.RLock()
-	defer mi.RUnlock()
-	return mi.triggered
-}
-
-// SetTriggered sets when the MonitoredItem is triggered.
-func (mi *DataChangeMonitoredItem) SetTriggered(val bool) {
-	mi.Lock()
-	defer mi.Unlock()
-	mi.triggered = val
-}
-
 // Modify modifies the MonitoredItem.
 func (mi *DataChangeMonitoredItem) Modify(req ua.MonitoredItemModifyRequest) ua.MonitoredItemModifyResult {
 	mi.Lock()
 	defer mi.Unlock()
-	mi.stopMonitoring()
-	mi.clientHandle = req.RequestedParameters.ClientHandle
-	mi.discardOldest = req.RequestedParameters.DiscardOldest
-	mi.setQueueSize(req.RequestedParameters.QueueSize)
-	mi.setSamplingInterval(req.RequestedParameters.SamplingInterval)
-	mi.setFilter(req.RequestedParameters.Filter)
-	mi.startMonitoring()
+	mi.modify(req)
 	return ua.MonitoredItemModifyResult{RevisedSamplingInterval: mi.samplingInterval, RevisedQueueSize: mi.queueSize}
 }
 
-// Delete deletes the DataMonitoredItem.
-func (mi *DataChangeMonitoredItem) Delete() {
-	mi.Lock()
-	defer mi.Unlock()
-	mi.stopMonitoring()
-	mi.queue.Clear()
-	mi.node = nil
-	mi.previousQueuedValue = ua.NewDataValue(nil, ua.BadWaitingForInitialData, time.Time{}, 0, time.Time{}, 0)
-	mi.sub = nil
-	mi.prequeue.Clear()
-	mi.triggeredItems = nil
-}
-
-// SetMonitoringMode sets the MonitoringMode of the MonitoredItem.
-func (mi *DataChangeMonitoredItem) SetMonitoringMode(mode ua.MonitoringMode) {
-	mi.Lock()
-	defer mi.Unlock()
-	if mi.monitoringMode == mode {
-		return
-	}
-	mi.stopMonitoring()
-	mi.monitoringMode = mode
-	if mode == ua.MonitoringModeDisabled {
-		mi.queue.Clear()
-		mi.previousQueuedValue = ua.NewDataValue(nil, ua.BadWaitingForInitialData, time.Time{}, 0, time.Time{}, 0)
-		mi.sub.disabledMonitoredItemCount++
-	} else {
-		mi.sub.disabledMonitoredItemCount--
-	}
-	mi.startMonitoring()
-}
-
-func (mi *DataChangeMonitoredItem) setQueueSize(queueSize uint32) {
-	if queueSize > maxQueueSize {
-		queueSize = maxQueueSize
-	}
-	if queueSize < 1 {
-		queueSize = 1
-	}
-	mi.queueSize = queueSize
-
-	// trim to size
-	overflow := false
-	if mi.discardOldest {
-		for mi.queue.Len() > int(mi.queueSize) {
-			mi.queue.PopFront()
-			overflow = true
-		}
-		if overflow && mi.queue.Len() > 1 {
-			// set overflow bit of statuscode
-			v := mi.queue.Front()
-			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
-		}
-	} else {
-		for mi.queue.Len() > int(mi.queueSize) {
-			mi.queue.PopBack()
-			overflow = true
-		}
-		if overflow && mi.queue.Len() > 1 {
-			// set overflow bit of statuscode
-			v := mi.queue.Back()
-			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
-		}
-	}
-}
-
-func (mi *DataChangeMonitoredItem) setSamplingInterval(samplingInterval float64) {
-	switch mi.itemToMonitor.AttributeID {
-	case ua.AttributeIDValue:
-		if samplingInterval < 0 {
-			samplingInterval = mi.sub.publishingInterval
-		}
-		if samplingInterval < mi.minSamplingInterval {
-			samplingInterval = mi.minSamplingInterval
-		}
-		if samplingInterval > maxSamplingInterval {
-			samplingInterval = maxSamplingInterval
-		}
-		if v, ok := mi.node.(*VariableNode); ok {
-			if min := v.MinimumSamplingInterval(); samplingInterval < min {
-				samplingInterval = min
-			}
-		}
-	default:
-		if samplingInterval < 0 {
-			samplingInterval = mi.sub.publishingInterval
-		}
-		if samplingInterval < mi.minSamplingInterval {
-			samplingInterval = mi.minSamplingInterval
-		}
-		if samplingInterval > maxSamplingInterval {
-			samplingInterval = maxSamplingInterval
-		}
-	}
-	mi.samplingInterval = samplingInterval
-	mi.ti = time.Duration(mi.samplingInterval) * time.Millisecond
-}
-
 func (mi *DataChangeMonitoredItem) setFilter(filter any) {
 	if dcf, ok := filter.(ua.DataChangeFilter); ok {
 		mi.dataChangeFilter = dcf
 	} else {
 		mi.dataChangeFilter = ua.DataChangeFilter{Trigger: ua.DataChangeTriggerStatusValue}
 	}
-}
-
-func (mi *DataChangeMonitoredItem) startMonitoring() {
-	mi.ts = time.Now()
-	if mi.monitoringMode == ua.MonitoringModeDisabled {
-		return
-	}
-	v := mi.srv.readValue(mi.sub.session, mi.itemToMonitor)
-	mi.prequeue.PushBack(v)
-	mi.Unlock()
-	mi.srv.Scheduler().GetPollGroup(time.Duration(mi.samplingInterval) * time.Millisecond).Subscribe(mi)
-	mi.Lock()
-}
-
-func (mi *DataChangeMonitoredItem) stopMonitoring() {
-	mi.Unlock()
-	mi.srv.Scheduler().GetPollGroup(time.Duration(mi.samplingInterval) * time.Millisecond).Unsubscribe(mi)
-	mi.Lock()
-}
-
-// Poll reads the value of the itemToMonitor.
-func (mi *DataChangeMonitoredItem) Poll() {
-	mi.Lock()
-	if n := mi.node; n != nil {
-		v := mi.srv.readValue(mi.sub.session, mi.itemToMonitor)
-		mi.prequeue.PushBack(v)
-	}
-	mi.Unlock()
-}
-
-// AddTriggeredItem adds a item to be triggered by this item.
-func (mi *DataChangeMonitoredItem) AddTriggeredItem(item MonitoredItem) bool {
-	mi.Lock()
-	mi.triggeredItems = append(mi.triggeredItems, item)
-	mi.Unlock()
-	return true
-}
-
-// RemoveTriggeredItem removes an item to be triggered by this item.
-func (mi *DataChangeMonitoredItem) RemoveTriggeredItem(item MonitoredItem) bool {
-	mi.Lock()
-	ret := false
-	for i, e := range mi.triggeredItems {
-		if e.ID() == item.ID() {
-			mi.triggeredItems[i] = mi.triggeredItems[len(mi.triggeredItems)-1]
-			mi.triggeredItems[len(mi.triggeredItems)-1] = nil
-			mi.triggeredItems = mi.triggeredItems[:len(mi.triggeredItems)-1]
-			ret = true
-			break
+	// convert the percent deadband to an absolute deadband, using the EURange of the variable.
+	mi.percentDeadband = 0
+	if ua.DeadbandType(mi.dataChangeFilter.DeadbandType) == ua.DeadbandTypePercent {
+		if r, ok := findEURange(mi.srv.NamespaceManager(), mi.node); ok {
+			mi.percentDeadband = mi.dataChangeFilter.DeadbandValue / 100.0 * (r.High - r.Low)
 		}
 	}
-	mi.Unlock()
-	return ret
 }
 
-func (mi *DataChangeMonitoredItem) enqueue(item ua.DataValue) {
-	overflow := false
-	if mi.discardOldest {
-		for mi.queue.Len() >= int(mi.queueSize) {
-			mi.queue.PopFront() // discard oldest
-			overflow = true
-		}
-		mi.queue.PushBack(item)
-		if overflow && mi.queueSize > 1 {
-			// set overflow bit of statuscode
-			v := mi.queue.Front()
-			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
-			mi.sub.monitoringQueueOverflowCount++
-		}
-	} else {
-		for mi.queue.Len() >= int(mi.queueSize) {
-			mi.queue.PopBack() // discard newest
-			overflow = true
-		}
-		mi.queue.PushBack(item)
-		if overflow && mi.queueSize > 1 {
-			// set overflow bit of statuscode
-			v := mi.queue.Back()
-			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
-			mi.sub.monitoringQueueOverflowCount++
-		}
-	}
-	if mi.triggeredItems != nil {
-		for _, item := range mi.triggeredItems {
-			item.SetTriggered(true)
-			// log.Printf("Item %d triggered %d", mi.id, item.id)
-		}
-	}
+func (mi *DataChangeMonitoredItem) start() {
+	mi.ts = time.Now()
+}
 
+func (mi *DataChangeMonitoredItem) sample(value ua.DataValue) {
+	mi.prequeue.PushBack(value)
 }
 
-func (mi *DataChangeMonitoredItem) notifications(max int) (notifications []any, more bool) {
-	mi.Lock()
-	defer mi.Unlock()
-	notifications = make([]any, 0, 4)
-	for i := 0; i < max; i++ {
-		if mi.queue.Len() > 0 {
-			notifications = append(notifications, mi.queue.PopFront())
-		} else {
-			break
-		}
-	}
-	more = mi.queue.Len() > 0
-	if mi.triggered && !more {
-		mi.triggered = false
-		// log.Printf("Reset triggered %d", mi.id)
-	}
-	return notifications, more
+func (mi *DataChangeMonitoredItem) reset() {
+	mi.previousQueuedValue = ua.NewDataValue(nil, ua.BadWaitingForInitialData, time.Time{}, 0, time.Time{}, 0)
+	mi.prequeue.Clear()
 }
 
 func (mi *DataChangeMonitoredItem) notificationsAvailable(tn time.Time, late bool, resend bool) bool {
@@ -363,11 +83,11 @@ func (mi *DataChangeMonitoredItem) notificationsAvailable(tn time.Time, late boo
 	}
 	// update queue and report if queue has notifications available.
 	// if in sampling interval mode, queue the last value of each sampling interval
-	if mi.ti > 0 {
-		// log.Printf("Sample from %s to %s", mi.ts.Add(-mi.ti).Format(time.StampMilli), tn.Format(time.StampMilli))
+	if ti := time.Duration(mi.samplingInterval) * time.Millisecond; ti > 0 {
+		// log.Printf("Sample from %s to %s", mi.ts.Add(-ti).Format(time.StampMilli), tn.Format(time.StampMilli))
 		v := mi.previousQueuedValue
 		// for each interval
-		for ; !mi.ts.After(tn); mi.ts = mi.ts.Add(mi.ti) {
+		for ; !mi.ts.After(tn); mi.ts = mi.ts.Add(ti) {
 			// for each value in prequeue
 			for mi.prequeue.Len() > 0 {
 				// peek
@@ -402,7 +122,7 @@ func (mi *DataChangeMonitoredItem) notificationsAvailable(tn time.Time, late boo
 	}
 	if resend && mi.monitoringMode == ua.MonitoringModeReporting {
 		if mi.queue.Len() == 0 {
-			v := mi.srv.readValue(mi.sub.session, mi.itemToMonitor)
+			v := mi.srv.readValue(mi.sub.session.Load(), mi.itemToMonitor)
 			mi.enqueue(withTimestamps(v, mi.timestampsToReturn))
 			mi.previousQueuedValue = v
 		}
@@ -425,7 +145,7 @@ func (mi *DataChangeMonitoredItem) isDataChange(current, previous ua.DataValue)
 		case ua.DeadbandTypeAbsolute:
 			return !equalDeadbandAbsolute(current.Value, previous.Value, dcf.DeadbandValue)
 		case ua.DeadbandTypePercent:
-			return true
+			return !equalDeadbandAbsolute(current.Value, previous.Value, mi.percentDeadband)
 		}
 	case ua.DataChangeTriggerStatusValueTimestamp:
 		if current.StatusCode&0xFFFFF000 != previous.StatusCode&0xFFFFF000 {
@@ -440,7 +160,7 @@ func (mi *DataChangeMonitoredItem) isDataChange(current, previous ua.DataValue)
 		case ua.DeadbandTypeAbsolute:
 			return !equalDeadbandAbsolute(current.Value, previous.Value, dcf.DeadbandValue)
 		case ua.DeadbandTypePercent:
-			return true
+			return !equalDeadbandAbsolute(current.Value, previous.Value, mi.percentDeadband)
 		}
 	}
 	return true
@@ -458,7 +178,7 @@ func equalDeadbandAbsolute(current, previous ua.Variant, deadband float64) bool
 	switch vc.Kind() {
 	case reflect.Array:
 		for i := 0; i < vc.Len(); i++ {
-			if !equalDeadbandAbsolute(vc.Index(i), vp.Index(i), deadband) {
+			if !equalDeadbandAbsolute(vc.Index(i).Interface(), vp.Index(i).Interface(), deadband) {
 				return false
 			}
 		}
@@ -478,7 +198,7 @@ func equalDeadbandAbsolute(current, previous ua.Variant, deadband float64) bool
 			return bytes.Equal(vc.Bytes(), vp.Bytes())
 		}
 		for i := 0; i < vc.Len(); i++ {
-			if !equalDeadbandAbsolute(vc.Index(i), vp.Index(i), deadband) {
+			if !equalDeadbandAbsolute(vc.Index(i).Interface(), vp.Index(i).Interface(), deadband) {
 				return false
 			}
 		}
@@ -486,13 +206,23 @@ func equalDeadbandAbsolute(current, previous ua.Variant, deadband float64) bool
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return math.Abs(float64(vc.Int()-vp.Int())) <= deadband
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return math.Abs(float64(vc.Uint()-vp.Uint())) <= deadband
+		return math.Abs(float64(vc.Uint())-float64(vp.Uint())) <= deadband
 	case reflect.Float32, reflect.Float64:
 		return math.Abs(vc.Float()-vp.Float()) <= deadband
 	}
 	return false
 }
 
+// findEURange returns the EURange property of an AnalogItemType variable.
+func findEURange(m *NamespaceManager, n Node) (ua.Range, bool) {
+	p, ok := m.FindProperty(n, ua.QualifiedName{NamespaceIndex: 0, Name: "EURange"})
+	if !ok {
+		return ua.Range{}, false
+	}
+	r, ok := p.Value().Value.(ua.Range)
+	return r, ok
+}
+
 // withTimestamps returns a new instance of DataValue with only the selected timestamps.
 func withTimestamps(value ua.DataValue, timestampsToReturn ua.TimestampsToReturn) ua.DataValue {
 	switch timestampsToReturn {
@@ -0,0 +1,237 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+const (
+	// the default time a registration remains, if the server does not register again. (10 min)
+	defaultRegistrationTimeout = 10 * time.Minute
+)
+
+// DiscoveryManager manages the servers registered with a discovery server.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part12/4.3/
+type DiscoveryManager struct {
+	sync.Mutex
+	server               *Server
+	registrations        map[string]*registration
+	lastRecordID         uint32
+	lastCounterResetTime time.Time
+}
+
+// registration is a server registered with the discovery server.
+type registration struct {
+	server             ua.RegisteredServer
+	mdnsServerName     string
+	serverCapabilities []string
+	recordIDs          []uint32
+	expiration         time.Time
+}
+
+// NewDiscoveryManager instantiates a new DiscoveryManager.
+func NewDiscoveryManager(server *Server) *DiscoveryManager {
+	return &DiscoveryManager{
+		server:               server,
+		registrations:        make(map[string]*registration),
+		lastCounterResetTime: time.Now(),
+	}
+}
+
+// RegisterServer adds, updates or removes the registration of a server. A server that is not online
+// is removed. The registration expires after the registration timeout, or when the semaphore file is deleted.
+// The results are the status of each discovery configuration.
+func (m *DiscoveryManager) RegisterServer(server ua.RegisteredServer, configs []ua.ExtensionObject) ([]ua.StatusCode, error) {
+	if server.ServerURI == "" {
+		return nil, ua.BadServerURIInvalid
+	}
+	if len(server.ServerNames) == 0 {
+		return nil, ua.BadServerNameMissing
+	}
+	if len(server.DiscoveryURLs) == 0 {
+		return nil, ua.BadDiscoveryURLMissing
+	}
+	if server.ServerType == ua.ApplicationTypeClient {
+		return nil, ua.BadInvalidArgument
+	}
+	if server.SemaphoreFilePath != "" {
+		if _, err := os.Stat(server.SemaphoreFilePath); err != nil {
+			return nil, ua.BadSempahoreFileMissing
+		}
+	}
+	r := &registration{
+		server:         server,
+		mdnsServerName: server.ServerNames[0].Text,
+		expiration:     time.Now().Add(m.server.registrationTimeout),
+	}
+	results := make([]ua.StatusCode, len(configs))
+	for i, config := range configs {
+		switch config := config.(type) {
+		case ua.MdnsDiscoveryConfiguration:
+			if config.MdnsServerName != "" {
+				r.mdnsServerName = config.MdnsServerName
+			}
+			r.serverCapabilities = config.ServerCapabilities
+		default:
+			results[i] = ua.BadNotSupported
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	old, ok := m.registrations[server.ServerURI]
+	if !server.IsOnline {
+		delete(m.registrations, server.ServerURI)
+		return results, nil
+	}
+	// keep the record ids, unless the discovery urls changed.
+	if ok && slices.Equal(old.server.DiscoveryURLs, server.DiscoveryURLs) {
+		r.recordIDs = old.recordIDs
+	} else {
+		r.recordIDs = make([]uint32, len(server.DiscoveryURLs))
+		for i := range r.recordIDs {
+			m.lastRecordID++
+			r.recordIDs[i] = m.lastRecordID
+		}
+	}
+	m.registrations[server.ServerURI] = r
+	return results, nil
+}
+
+// RegisteredServers returns the servers that are registered with the discovery server.
+func (m *DiscoveryManager) RegisteredServers() []ua.RegisteredServer {
+	m.Lock()
+	defer m.Unlock()
+	m.removeExpired()
+	servers := make([]ua.RegisteredServer, 0, len(m.registrations))
+	for _, r := range m.registrations {
+		servers = append(servers, r.server)
+	}
+	sort.Slice(servers, func(i, j int) bool { return servers[i].ServerURI < servers[j].ServerURI })
+	return servers
+}
+
+// FindServers returns the description of the local server and the registered servers. If serverURIs is
+// not empty, only the servers with a matching ApplicationURI are returned. The ApplicationName of a
+// registered server is selected from its ServerNames using the localeIDs.
+func (m *DiscoveryManager) FindServers(serverURIs, localeIDs []string) []ua.ApplicationDescription {
+	descriptions := []ua.ApplicationDescription{m.server.LocalDescription()}
+	for _, s := range m.RegisteredServers() {
+		descriptions = append(descriptions, ua.ApplicationDescription{
+			ApplicationURI:   s.ServerURI,
+			ProductURI:       s.ProductURI,
+			ApplicationName:  selectLocalizedText(s.ServerNames, localeIDs),
+			ApplicationType:  s.ServerType,
+			GatewayServerURI: s.GatewayServerURI,
+			DiscoveryURLs:    s.DiscoveryURLs,
+		})
+	}
+	if len(serverURIs) == 0 {
+		return descriptions
+	}
+	filtered := make([]ua.ApplicationDescription, 0, len(descriptions))
+	for _, d := range descriptions {
+		for _, su := range serverURIs {
+			if d.ApplicationURI == su {
+				filtered = append(filtered, d)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// FindServersOnNetwork returns a record for each discovery url of the registered servers, ordered by record id,
+// starting at the startingRecordID. If maxRecords is not zero, no more than maxRecords are returned. If
+// capabilityFilter is not empty, only the servers with all the capabilities are returned.
+// Also returns the time the record ids were last reset.
+func (m *DiscoveryManager) FindServersOnNetwork(startingRecordID, maxRecords uint32, capabilityFilter []string) ([]ua.ServerOnNetwork, time.Time) {
+	m.Lock()
+	defer m.Unlock()
+	m.removeExpired()
+	records := []ua.ServerOnNetwork{}
+	for _, r := range m.registrations {
+		if !containsAll(r.serverCapabilities, capabilityFilter) {
+			continue
+		}
+		for i, id := range r.recordIDs {
+			if id < startingRecordID {
+				continue
+			}
+			records = append(records, ua.ServerOnNetwork{
+				RecordID:           id,
+				ServerName:         r.mdnsServerName,
+				DiscoveryURL:       r.server.DiscoveryURLs[i],
+				ServerCapabilities: r.serverCapabilities,
+			})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].RecordID < records[j].RecordID })
+	if maxRecords > 0 && uint32(len(records)) > maxRecords {
+		records = records[:maxRecords]
+	}
+	return records, m.lastCounterResetTime
+}
+
+// removeExpired removes the registrations that timed out, or whose semaphore file was deleted.
+func (m *DiscoveryManager) removeExpired() {
+	now := time.Now()
+	for uri, r := range m.registrations {
+		if now.After(r.expiration) {
+			delete(m.registrations, uri)
+			continue
+		}
+		if path := r.server.SemaphoreFilePath; path != "" {
+			if _, err := os.Stat(path); err != nil {
+				delete(m.registrations, uri)
+			}
+		}
+	}
+}
+
+// selectLocalizedText returns the text that best matches the localeIDs, else the first text.
+func selectLocalizedText(texts []ua.LocalizedText, localeIDs []string) ua.LocalizedText {
+	if len(texts) == 0 {
+		return ua.LocalizedText{}
+	}
+	for _, id := range localeIDs {
+		for _, t := range texts {
+			if strings.EqualFold(t.Locale, id) {
+				return t
+			}
+		}
+		// match the language, e.g. 'en' matches 'en-US'.
+		lang, _, _ := strings.Cut(id, "-")
+		for _, t := range texts {
+			if l, _, _ := strings.Cut(t.Locale, "-"); strings.EqualFold(l, lang) {
+				return t
+			}
+		}
+	}
+	return texts[0]
+}
+
+// containsAll returns true if every value of b is found in a.
+func containsAll(a, b []string) bool {
+	for _, v := range b {
+		found := false
+		for _, w := range a {
+			if strings.EqualFold(v, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/awcullen/opcua/client"
+	"github.com/awcullen/opcua/ua"
+)
+
+const (
+	// the default interval between registrations with a discovery server. (30 sec)
+	defaultDiscoveryRegistrationInterval = 30 * time.Second
+	// the delay before the first retry of a registration that failed. (1 sec)
+	minDiscoveryRegistrationRetryDelay = time.Second
+	// the time to wait for a registration with a discovery server. (30 sec)
+	discoveryRegistrationTimeout = 30 * time.Second
+	// the time to wait for the unregistration when the server closes, which delays the shutdown. (5 sec)
+	discoveryUnregistrationTimeout = 5 * time.Second
+)
+
+// discoveryRegistration is a discovery server that the server registers with.
+type discoveryRegistration struct {
+	discoveryURL string
+	interval     time.Duration
+}
+
+// registerWithDiscoveryServer registers the server with the discovery server at the interval, until
+// the server closes. Then the server registers that it is no longer online. A registration that fails
+// is retried sooner, doubling the delay after each failure, up to the interval.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part12/4.3/
+func (srv *Server) registerWithDiscoveryServer(discoveryURL string, interval time.Duration, wg *sync.WaitGroup) {
+	defer wg.Done()
+	retryDelay := time.Duration(0)
+	for {
+		delay := interval
+		ctx, cancel := context.WithTimeout(context.Background(), discoveryRegistrationTimeout)
+		err := srv.registerWithDiscoveryServer2(ctx, discoveryURL, true)
+		cancel()
+		if err != nil {
+			log.Printf("Error registering with discovery server '%s'. %s\n", discoveryURL, err)
+			retryDelay = min(max(2*retryDelay, minDiscoveryRegistrationRetryDelay), interval)
+			delay = retryDelay
+		} else {
+			retryDelay = 0
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-srv.closing:
+			timer.Stop()
+			ctx, cancel := context.WithTimeout(context.Background(), discoveryUnregistrationTimeout)
+			defer cancel()
+			if err := srv.registerWithDiscoveryServer2(ctx, discoveryURL, false); err != nil {
+				log.Printf("Error unregistering with discovery server '%s'. %s\n", discoveryURL, err)
+			}
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// registerWithDiscoveryServer2 calls RegisterServer2 on the discovery server, over a channel secured with the certificate of the server.
+func (srv *Server) registerWithDiscoveryServer2(ctx context.Context, discoveryURL string, isOnline bool) error {
+	cert, key := srv.localCertificateAndKey()
+	opts := []client.Option{
+		client.WithSecurityPolicyURI(ua.SecurityPolicyURIBasic256Sha256, ua.MessageSecurityModeSignAndEncrypt),
+		client.WithClientCertificate(cert, key),
+		client.WithCertificateValidator(srv.certificateValidator),
+	}
+	if srv.discoveryInsecureSkipVerify {
+		opts = append(opts, client.WithInsecureSkipVerify())
+	}
+	ch, err := client.Dial(ctx, discoveryURL, opts...)
+	if err != nil {
+		return err
+	}
+	desc := srv.LocalDescription()
+	discoveryURLs := desc.DiscoveryURLs
+	if len(discoveryURLs) == 0 {
+		discoveryURLs = []string{srv.EndpointURL()}
+	}
+	capabilities := []string{"DA"}
+	if srv.Historian() != nil {
+		capabilities = append(capabilities, "HD")
+	}
+	req := &ua.RegisterServer2Request{
+		Server: ua.RegisteredServer{
+			ServerURI:        desc.ApplicationURI,
+			ProductURI:       desc.ProductURI,
+			ServerNames:      []ua.LocalizedText{desc.ApplicationName},
+			ServerType:       desc.ApplicationType,
+			GatewayServerURI: desc.GatewayServerURI,
+			DiscoveryURLs:    discoveryURLs,
+			IsOnline:         isOnline,
+		},
+		DiscoveryConfiguration: []ua.ExtensionObject{
+			ua.MdnsDiscoveryConfiguration{
+				MdnsServerName:     desc.ApplicationName.Text,
+				ServerCapabilities: capabilities,
+			},
+		},
+	}
+	_, err = ch.RegisterServer2(ctx, req)
+	if err != nil {
+		ch.Abort(ctx)
+		return err
+	}
+	return ch.Close(ctx)
+}
@@ -212,62 +212,18 @@ func (mi *EventMonitoredItem) enqueue(item []ua.Variant) {
 
 func (mi *EventMonitoredItem) OnEvent(evt ua.Event) {
 	mi.Lock()
-	if res, ok := mi.whereClause(evt, 0).(bool); ok && res {
+	if evaluateWhereClause(mi.srv.namespaceManager, mi.eventFilter.WhereClause, evt) {
 		mi.enqueue(mi.selectFields(evt))
 	}
 	mi.Unlock()
 }
 
-var (
-	attributeOperandEventType = ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDBaseEventType, BrowsePath: ua.ParseBrowsePath("EventType"), AttributeID: ua.AttributeIDValue}
-)
-
-func (mi *EventMonitoredItem) whereClause(evt ua.Event, idx int) any {
-	if idx >= len(mi.eventFilter.WhereClause.Elements) {
-		return true
-	}
-	element := mi.eventFilter.WhereClause.Elements[idx]
-	switch element.FilterOperator {
-
-	case ua.FilterOperatorEquals:
-		var a, b ua.Variant
-		switch c := element.FilterOperands[0].(type) {
-		case ua.LiteralOperand:
-			a = c.Value
-		case ua.SimpleAttributeOperand:
-			a = evt.GetAttribute(c)
-		case ua.ElementOperand:
-			a = mi.whereClause(evt, int(c.Index))
-		default:
-			return false
-		}
-		switch c := element.FilterOperands[1].(type) {
-		case ua.LiteralOperand:
-			b = c.Value
-		case ua.SimpleAttributeOperand:
-			b = evt.GetAttribute(c)
-		case ua.ElementOperand:
-			b = mi.whereClause(evt, int(c.Index))
-		default:
-			return false
-		}
-		return a == b
-
-	case ua.FilterOperatorOfType:
-		if a, ok := element.FilterOperands[0].(ua.LiteralOperand); ok {
-			if b, ok := a.Value.(ua.NodeID); ok {
-				if c, ok := evt.GetAttribute(attributeOperandEventType).(ua.NodeID); ok {
-					if c == b || mi.srv.namespaceManager.IsSubtype(c, b) {
-						return true
-					}
-				}
-			}
-		}
-		return false
-
-	default:
-		return false
-	}
+// onRefreshEvent enqueues the RefreshStartEvent or RefreshEndEvent of a ConditionRefresh,
+// regardless of the where clause.
+func (mi *EventMonitoredItem) onRefreshEvent(evt ua.Event) {
+	mi.Lock()
+	mi.enqueue(mi.selectFields(evt))
+	mi.Unlock()
 }
 
 func (mi *EventMonitoredItem) selectFields(evt ua.Event) []ua.Variant {
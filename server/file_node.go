@@ -0,0 +1,252 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// FileNode is an object of FileType, whose methods let clients open, read and write a file.
+// The file is stored by any io.ReadWriteSeeker, such as an os.File or a MemoryFile.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part5/C.2/
+type FileNode struct {
+	server   *Server
+	object   *ObjectNode
+	nodes    []Node
+	file     *fileType
+	writable bool
+}
+
+// NewFileNode instantiates a new FileNode, with the storage. If writable is false, clients may only open the file for reading.
+// The references should include the reference from the parent, e.g. an inverse HasComponent or Organizes reference.
+// Add the Nodes to the NamespaceManager to expose the file to clients.
+func NewFileNode(server *Server, nodeID ua.NodeID, browseName ua.QualifiedName, displayName ua.LocalizedText, description ua.LocalizedText, rolePermissions []ua.RolePermissionType, references []ua.Reference, storage io.ReadWriteSeeker, writable bool) *FileNode {
+	n := &FileNode{
+		server:   server,
+		writable: writable,
+	}
+	n.file = newFileType(storage, func() bool { return n.writable }, n.userWritable)
+	n.object = NewObjectNode(
+		server,
+		nodeID,
+		browseName,
+		displayName,
+		description,
+		rolePermissions,
+		append([]ua.Reference{ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.ObjectTypeIDFileType))}, references...),
+		byte(0),
+	)
+	n.nodes = append(n.nodes, n.object)
+
+	// properties
+	addProperty := func(name string, dataType ua.NodeID, handler func(*Session, ua.ReadValueID) ua.DataValue) {
+		p := NewVariableNode(
+			server,
+			childNodeID(nodeID, name),
+			ua.NewQualifiedName(0, name),
+			ua.NewLocalizedText(name, ""),
+			ua.NewLocalizedText("", ""),
+			rolePermissions,
+			[]ua.Reference{
+				ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDPropertyType)),
+				ua.NewReference(ua.ReferenceTypeIDHasProperty, true, ua.NewExpandedNodeID(nodeID)),
+			},
+			ua.NewDataValue(nil, ua.BadWaitingForInitialData, time.Now(), 0, time.Now(), 0),
+			dataType,
+			ua.ValueRankScalar,
+			[]uint32{},
+			ua.AccessLevelsCurrentRead,
+			0,
+			false,
+			nil,
+		)
+		p.SetReadValueHandler(handler)
+		n.nodes = append(n.nodes, p)
+	}
+	addProperty("Size", ua.DataTypeIDUInt64, func(session *Session, req ua.ReadValueID) ua.DataValue {
+		size, err := n.Size()
+		if err != nil {
+			return ua.NewDataValue(nil, ua.BadResourceUnavailable, time.Time{}, 0, time.Now(), 0)
+		}
+		return ua.NewDataValue(size, 0, time.Now(), 0, time.Now(), 0)
+	})
+	addProperty("Writable", ua.DataTypeIDBoolean, func(session *Session, req ua.ReadValueID) ua.DataValue {
+		return ua.NewDataValue(n.writable, 0, time.Now(), 0, time.Now(), 0)
+	})
+	addProperty("UserWritable", ua.DataTypeIDBoolean, func(session *Session, req ua.ReadValueID) ua.DataValue {
+		return ua.NewDataValue(n.userWritable(session), 0, time.Now(), 0, time.Now(), 0)
+	})
+	addProperty("OpenCount", ua.DataTypeIDUInt16, func(session *Session, req ua.ReadValueID) ua.DataValue {
+		return ua.NewDataValue(n.OpenCount(), 0, time.Now(), 0, time.Now(), 0)
+	})
+
+	// methods
+	nm := server.NamespaceManager()
+	addMethod := func(name string, typeInputArguments, typeOutputArguments ua.NodeID, handler func(*Session, ua.CallMethodRequest) ua.CallMethodResult) {
+		m := NewMethodNode(
+			server,
+			childNodeID(nodeID, name),
+			ua.NewQualifiedName(0, name),
+			ua.NewLocalizedText(name, ""),
+			ua.NewLocalizedText("", ""),
+			rolePermissions,
+			[]ua.Reference{
+				ua.NewReference(ua.ReferenceTypeIDHasComponent, true, ua.NewExpandedNodeID(nodeID)),
+			},
+			true,
+		)
+		m.SetCallMethodHandler(handler)
+		n.nodes = append(n.nodes, m)
+		// the arguments are the same as the arguments of the method of the FileType.
+		for _, id := range []ua.NodeID{typeInputArguments, typeOutputArguments} {
+			if id == nil {
+				continue
+			}
+			if v, ok := nm.FindVariable(id); ok {
+				n.nodes = append(n.nodes, NewVariableNode(
+					server,
+					childNodeID(m.NodeID(), v.BrowseName().Name),
+					v.BrowseName(),
+					v.DisplayName(),
+					v.Description(),
+					rolePermissions,
+					[]ua.Reference{
+						ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDPropertyType)),
+						ua.NewReference(ua.ReferenceTypeIDHasProperty, true, ua.NewExpandedNodeID(m.NodeID())),
+					},
+					v.Value(),
+					ua.DataTypeIDArgument,
+					ua.ValueRankOneDimension,
+					[]uint32{0},
+					ua.AccessLevelsCurrentRead,
+					0,
+					false,
+					nil,
+				))
+			}
+		}
+	}
+	addMethod("Open", ua.VariableIDFileTypeOpenInputArguments, ua.VariableIDFileTypeOpenOutputArguments, n.file.callOpen)
+	addMethod("Close", ua.VariableIDFileTypeCloseInputArguments, nil, n.file.callClose)
+	addMethod("Read", ua.VariableIDFileTypeReadInputArguments, ua.VariableIDFileTypeReadOutputArguments, n.file.callRead)
+	addMethod("Write", ua.VariableIDFileTypeWriteInputArguments, nil, n.file.callWrite)
+	addMethod("GetPosition", ua.VariableIDFileTypeGetPositionInputArguments, ua.VariableIDFileTypeGetPositionOutputArguments, n.file.callGetPosition)
+	addMethod("SetPosition", ua.VariableIDFileTypeSetPositionInputArguments, nil, n.file.callSetPosition)
+	return n
+}
+
+// NodeID returns the NodeID of the object.
+func (n *FileNode) NodeID() ua.NodeID {
+	return n.object.NodeID()
+}
+
+// Nodes returns the object, with its properties and methods.
+func (n *FileNode) Nodes() []Node {
+	return n.nodes
+}
+
+// Size returns the size of the file in bytes.
+func (n *FileNode) Size() (uint64, error) {
+	return n.file.size()
+}
+
+// SetMaxSize sets the size that clients may write the file up to. (default: 16Mb)
+func (n *FileNode) SetMaxSize(size int64) {
+	n.file.Lock()
+	defer n.file.Unlock()
+	n.file.maxSize = size
+}
+
+// OpenCount returns the number of handles that are open.
+func (n *FileNode) OpenCount() uint16 {
+	return n.file.openCount()
+}
+
+// userWritable returns true if the file is writable, and the user has permission to write.
+func (n *FileNode) userWritable(session *Session) bool {
+	if !n.writable || session == nil {
+		return false
+	}
+	return IsUserPermitted(n.object.UserRolePermissions(session.UserIdentity()), ua.PermissionTypeWrite)
+}
+
+// MemoryFile is an in-memory file, that may be the storage of a FileNode.
+type MemoryFile struct {
+	sync.Mutex
+	data     []byte
+	position int64
+}
+
+// NewMemoryFile instantiates a new MemoryFile with the initial data.
+func NewMemoryFile(data []byte) *MemoryFile {
+	return &MemoryFile{data: data}
+}
+
+// Bytes returns a copy of the data of the file.
+func (f *MemoryFile) Bytes() []byte {
+	f.Lock()
+	defer f.Unlock()
+	return append([]byte(nil), f.data...)
+}
+
+// Read reads from the file at the position.
+func (f *MemoryFile) Read(p []byte) (int, error) {
+	f.Lock()
+	defer f.Unlock()
+	if f.position >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.position:])
+	f.position += int64(n)
+	return n, nil
+}
+
+// Write writes to the file at the position, extending the file as needed.
+func (f *MemoryFile) Write(p []byte) (int, error) {
+	f.Lock()
+	defer f.Unlock()
+	if end := f.position + int64(len(p)); end > int64(len(f.data)) {
+		f.data = append(f.data, make([]byte, end-int64(len(f.data)))...)
+	}
+	n := copy(f.data[f.position:], p)
+	f.position += int64(n)
+	return n, nil
+}
+
+// Seek sets the position for the next Read or Write.
+func (f *MemoryFile) Seek(offset int64, whence int) (int64, error) {
+	f.Lock()
+	defer f.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.position
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	default:
+		return 0, ua.BadInvalidArgument
+	}
+	if offset < 0 {
+		return 0, ua.BadInvalidArgument
+	}
+	f.position = offset
+	return offset, nil
+}
+
+// Truncate changes the size of the file.
+func (f *MemoryFile) Truncate(size int64) error {
+	f.Lock()
+	defer f.Unlock()
+	if size < 0 {
+		return ua.BadInvalidArgument
+	}
+	if size > int64(len(f.data)) {
+		f.data = append(f.data, make([]byte, size-int64(len(f.data)))...)
+	}
+	f.data = f.data[:size]
+	return nil
+}
@@ -0,0 +1,384 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"io"
+	"sync"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+const (
+	// defaultMaxFileSize is the size that clients may write the file up to, unless set with SetMaxSize.
+	defaultMaxFileSize = 16 * 1024 * 1024
+	// fileReadOverhead is the part of the response message reserved for all but the data that is read.
+	fileReadOverhead = 1024
+)
+
+// fileType implements the methods of the FileType, that let clients open, read and write a file kept
+// in a storage. It is shared by the FileNode and the TrustList, which decide who may write the file.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part5/C.2/
+type fileType struct {
+	sync.Mutex
+	storage      io.ReadWriteSeeker
+	maxSize      int64
+	handles      map[uint32]*fileHandle
+	lastHandle   uint32
+	writable     func() bool
+	userWritable func(session *Session) bool
+}
+
+// fileHandle is a handle to the file, opened by a session.
+type fileHandle struct {
+	session  *Session
+	mode     byte
+	position int64
+}
+
+// newFileType instantiates a new fileType, with the storage. The file may be opened for writing if
+// writable returns true, by the sessions for which userWritable returns true.
+func newFileType(storage io.ReadWriteSeeker, writable func() bool, userWritable func(session *Session) bool) *fileType {
+	return &fileType{
+		storage:      storage,
+		maxSize:      defaultMaxFileSize,
+		handles:      make(map[uint32]*fileHandle),
+		writable:     writable,
+		userWritable: userWritable,
+	}
+}
+
+// size returns the size of the file in bytes.
+func (f *fileType) size() (uint64, error) {
+	f.Lock()
+	defer f.Unlock()
+	size, err := f.storage.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(size), nil
+}
+
+// openCount returns the number of handles that are open.
+func (f *fileType) openCount() uint16 {
+	f.Lock()
+	defer f.Unlock()
+	return uint16(len(f.handles))
+}
+
+// open opens the file with the mode, and returns a handle. While the file is open for writing,
+// it may not be opened again. The handles are closed when the session is deleted.
+func (f *fileType) open(session *Session, mode byte) (uint32, error) {
+	f.Lock()
+	defer f.Unlock()
+	return f.openLocked(session, mode)
+}
+
+// openLocked opens the file with the mode, while the lock is held.
+func (f *fileType) openLocked(session *Session, mode byte) (uint32, error) {
+	if session == nil {
+		return 0, ua.BadUserAccessDenied
+	}
+	read := mode&byte(ua.OpenFileModeRead) != 0
+	write := mode&byte(ua.OpenFileModeWrite) != 0
+	if mode&^byte(ua.OpenFileModeRead|ua.OpenFileModeWrite|ua.OpenFileModeEraseExisting|ua.OpenFileModeAppend) != 0 || (!read && !write) {
+		return 0, ua.BadInvalidArgument
+	}
+	if !write && mode&byte(ua.OpenFileModeEraseExisting|ua.OpenFileModeAppend) != 0 {
+		return 0, ua.BadInvalidArgument
+	}
+	if write && !f.userWritable(session) {
+		if !f.writable() {
+			return 0, ua.BadNotWritable
+		}
+		return 0, ua.BadUserAccessDenied
+	}
+	for _, h := range f.handles {
+		if write {
+			return 0, ua.BadNotWritable
+		}
+		if h.mode&byte(ua.OpenFileModeWrite) != 0 {
+			return 0, ua.BadNotReadable
+		}
+	}
+	h := &fileHandle{session: session, mode: mode}
+	if mode&byte(ua.OpenFileModeEraseExisting) != 0 {
+		t, ok := f.storage.(interface{ Truncate(int64) error })
+		if !ok {
+			return 0, ua.BadNotSupported
+		}
+		if err := t.Truncate(0); err != nil {
+			return 0, ua.BadNotWritable
+		}
+	}
+	if mode&byte(ua.OpenFileModeAppend) != 0 {
+		size, err := f.storage.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, ua.BadResourceUnavailable
+		}
+		h.position = size
+	}
+	f.lastHandle++
+	f.handles[f.lastHandle] = h
+	session.onDelete(f, func() { f.closeSession(session) })
+	return f.lastHandle, nil
+}
+
+// close closes the handle.
+func (f *fileType) close(session *Session, handle uint32) error {
+	f.Lock()
+	defer f.Unlock()
+	if _, err := f.handle(session, handle); err != nil {
+		return err
+	}
+	delete(f.handles, handle)
+	return nil
+}
+
+// closeSession closes the handles opened by the session.
+func (f *fileType) closeSession(session *Session) {
+	f.Lock()
+	defer f.Unlock()
+	for handle, h := range f.handles {
+		if h.session == session {
+			delete(f.handles, handle)
+		}
+	}
+}
+
+// read reads up to length bytes from the file, at the position of the handle.
+// The length is limited so the data fits in the response message of the session.
+func (f *fileType) read(session *Session, handle uint32, length int32) ([]byte, error) {
+	f.Lock()
+	defer f.Unlock()
+	h, err := f.handle(session, handle)
+	if err != nil {
+		return nil, err
+	}
+	if h.mode&byte(ua.OpenFileModeRead) == 0 || length < 0 {
+		return nil, ua.BadInvalidState
+	}
+	size, err := f.storage.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, ua.BadResourceUnavailable
+	}
+	if _, err := f.storage.Seek(h.position, io.SeekStart); err != nil {
+		return nil, ua.BadResourceUnavailable
+	}
+	count := int64(length)
+	if limit := int64(session.maxResponseMessageSize); limit > 0 {
+		count = min(count, max(0, limit-fileReadOverhead))
+	}
+	buf := make([]byte, max(0, min(count, size-h.position)))
+	m, err := io.ReadFull(f.storage, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, ua.BadResourceUnavailable
+	}
+	h.position += int64(m)
+	return buf[:m], nil
+}
+
+// write writes the data to the file, at the position of the handle. The file may not grow past the max size.
+func (f *fileType) write(session *Session, handle uint32, data []byte) error {
+	f.Lock()
+	defer f.Unlock()
+	h, err := f.handle(session, handle)
+	if err != nil {
+		return err
+	}
+	if h.mode&byte(ua.OpenFileModeWrite) == 0 {
+		return ua.BadInvalidState
+	}
+	if h.position+int64(len(data)) > f.maxSize {
+		return ua.BadEncodingLimitsExceeded
+	}
+	if _, err := f.storage.Seek(h.position, io.SeekStart); err != nil {
+		return ua.BadResourceUnavailable
+	}
+	m, err := f.storage.Write(data)
+	h.position += int64(m)
+	if err != nil {
+		return ua.BadResourceUnavailable
+	}
+	return nil
+}
+
+// getPosition returns the position of the handle.
+func (f *fileType) getPosition(session *Session, handle uint32) (uint64, error) {
+	f.Lock()
+	defer f.Unlock()
+	h, err := f.handle(session, handle)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(h.position), nil
+}
+
+// setPosition sets the position of the handle. A position past the end is set to the end.
+func (f *fileType) setPosition(session *Session, handle uint32, position uint64) error {
+	f.Lock()
+	defer f.Unlock()
+	h, err := f.handle(session, handle)
+	if err != nil {
+		return err
+	}
+	size, err := f.storage.Seek(0, io.SeekEnd)
+	if err != nil {
+		return ua.BadResourceUnavailable
+	}
+	h.position = int64(min(position, uint64(size)))
+	return nil
+}
+
+// handle returns the handle opened by the session, while the lock is held.
+func (f *fileType) handle(session *Session, handle uint32) (*fileHandle, error) {
+	h, ok := f.handles[handle]
+	if !ok || h.session != session {
+		return nil, ua.BadInvalidArgument
+	}
+	return h, nil
+}
+
+// callOpen handles the Open method.
+func (f *fileType) callOpen(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	mode, ok := req.InputArguments[0].(byte)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+	}
+	handle, err := f.open(session, mode)
+	if err != nil {
+		return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{handle}}
+}
+
+// callClose handles the Close method.
+func (f *fileType) callClose(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	handle, ok := req.InputArguments[0].(uint32)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+	}
+	if err := f.close(session, handle); err != nil {
+		return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+// callRead handles the Read method.
+func (f *fileType) callRead(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	opResult := ua.Good
+	argsResults := make([]ua.StatusCode, 2)
+	handle, ok := req.InputArguments[0].(uint32)
+	if !ok {
+		opResult = ua.BadInvalidArgument
+		argsResults[0] = ua.BadTypeMismatch
+	}
+	length, ok := req.InputArguments[1].(int32)
+	if !ok {
+		opResult = ua.BadInvalidArgument
+		argsResults[1] = ua.BadTypeMismatch
+	}
+	if opResult == ua.BadInvalidArgument {
+		return ua.CallMethodResult{StatusCode: opResult, InputArgumentResults: argsResults}
+	}
+	data, err := f.read(session, handle, length)
+	if err != nil {
+		return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{ua.ByteString(data)}}
+}
+
+// callWrite handles the Write method.
+func (f *fileType) callWrite(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	opResult := ua.Good
+	argsResults := make([]ua.StatusCode, 2)
+	handle, ok := req.InputArguments[0].(uint32)
+	if !ok {
+		opResult = ua.BadInvalidArgument
+		argsResults[0] = ua.BadTypeMismatch
+	}
+	data, ok := req.InputArguments[1].(ua.ByteString)
+	if !ok && req.InputArguments[1] != nil {
+		opResult = ua.BadInvalidArgument
+		argsResults[1] = ua.BadTypeMismatch
+	}
+	if opResult == ua.BadInvalidArgument {
+		return ua.CallMethodResult{StatusCode: opResult, InputArgumentResults: argsResults}
+	}
+	if err := f.write(session, handle, []byte(data)); err != nil {
+		return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+// callGetPosition handles the GetPosition method.
+func (f *fileType) callGetPosition(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	handle, ok := req.InputArguments[0].(uint32)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+	}
+	position, err := f.getPosition(session, handle)
+	if err != nil {
+		return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{position}}
+}
+
+// callSetPosition handles the SetPosition method.
+func (f *fileType) callSetPosition(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 2 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	opResult := ua.Good
+	argsResults := make([]ua.StatusCode, 2)
+	handle, ok := req.InputArguments[0].(uint32)
+	if !ok {
+		opResult = ua.BadInvalidArgument
+		argsResults[0] = ua.BadTypeMismatch
+	}
+	position, ok := req.InputArguments[1].(uint64)
+	if !ok {
+		opResult = ua.BadInvalidArgument
+		argsResults[1] = ua.BadTypeMismatch
+	}
+	if opResult == ua.BadInvalidArgument {
+		return ua.CallMethodResult{StatusCode: opResult, InputArgumentResults: argsResults}
+	}
+	if err := f.setPosition(session, handle, position); err != nil {
+		return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
@@ -28,6 +28,39 @@ type HistoryWriter interface {
 	WriteValue(ctx context.Context, nodeID ua.NodeID, value ua.DataValue) error
 }
 
+// HistoryUpdater provides methods to update and delete historical data. A historian may
+// optionally implement this interface to support the HistoryUpdate service.
+type HistoryUpdater interface {
+
+	// UpdateData inserts, replaces or upserts the data values in storage. Implementation returns
+	// a status code for every value provided in 'details', e.g. GoodEntryInserted, GoodEntryReplaced,
+	// BadEntryExists or BadNoEntryExists. Implementation may check context for timeout.
+	// See OPC UA Part 11 chapter 6.9.2 for Update Data functionality.
+	UpdateData(ctx context.Context, details ua.UpdateDataDetails) ua.HistoryUpdateResult
+
+	// UpdateEvent inserts, replaces or upserts the events in storage. Implementation returns
+	// a status code for every event provided in 'details'. Implementation may check context for timeout.
+	// See OPC UA Part 11 chapter 6.9.4 for Update Event functionality.
+	UpdateEvent(ctx context.Context, details ua.UpdateEventDetails) ua.HistoryUpdateResult
+
+	// DeleteRawModified deletes the raw or modified data values from storage, given StartTime, EndTime
+	// and other parameters in 'details'. Implementation may check context for timeout.
+	// See OPC UA Part 11 chapter 6.9.5 for Delete Raw Modified functionality.
+	DeleteRawModified(ctx context.Context, details ua.DeleteRawModifiedDetails) ua.HistoryUpdateResult
+
+	// DeleteAtTime deletes the data values from storage at the timestamps provided in 'details'.
+	// Implementation returns a status code for every timestamp, e.g. BadNoEntryExists.
+	// Implementation may check context for timeout.
+	// See OPC UA Part 11 chapter 6.9.6 for Delete At Time functionality.
+	DeleteAtTime(ctx context.Context, details ua.DeleteAtTimeDetails) ua.HistoryUpdateResult
+
+	// DeleteEvent deletes the events from storage with the EventIds provided in 'details'.
+	// Implementation returns a status code for every EventId, e.g. BadNoEntryExists.
+	// Implementation may check context for timeout.
+	// See OPC UA Part 11 chapter 6.9.7 for Delete Event functionality.
+	DeleteEvent(ctx context.Context, details ua.DeleteEventDetails) ua.HistoryUpdateResult
+}
+
 // HistoryReader provides methods to read historical data.
 type HistoryReader interface {
 
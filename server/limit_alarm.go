@@ -0,0 +1,384 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// limit states of a LimitAlarm.
+const (
+	limitLowLow uint8 = 1 << iota
+	limitLow
+	limitHigh
+	limitHighHigh
+	limitNone uint8 = 0
+)
+
+// LimitAlarm is an alarm of ExclusiveLimitAlarmType, NonExclusiveLimitAlarmType or one of the
+// deviation alarm types. It is evaluated whenever the value of its input node is set.
+// A deviation alarm evaluates the difference between the input node and its setpoint node.
+type LimitAlarm struct {
+	*Condition
+	mu                sync.Mutex
+	conditionOptions  []ConditionOption
+	input             *VariableNode
+	setpoint          *VariableNode
+	exclusive         bool
+	highHighLimit     float64
+	highLimit         float64
+	lowLimit          float64
+	lowLowLimit       float64
+	deadband          float64
+	onDelay           time.Duration
+	offDelay          time.Duration
+	highHighSeverity  uint16
+	highSeverity      uint16
+	lowSeverity       uint16
+	lowLowSeverity    uint16
+	normalSeverity    uint16
+	exceededLimits    uint8 // each of the limits that are exceeded, considering the deadband
+	limits            uint8 // the limits that are active, only the most severe if exclusive
+	reported          uint8 // the limits that are reported, considering the delays
+	pending           bool
+	pendingGeneration uint64
+}
+
+// LimitAlarmOption is a functional option to be applied to a limit alarm during construction.
+type LimitAlarmOption func(*LimitAlarm)
+
+// WithHighHighLimit sets the HighHighLimit of the alarm.
+func WithHighHighLimit(value float64) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.highHighLimit = value
+	}
+}
+
+// WithHighLimit sets the HighLimit of the alarm.
+func WithHighLimit(value float64) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.highLimit = value
+	}
+}
+
+// WithLowLimit sets the LowLimit of the alarm.
+func WithLowLimit(value float64) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.lowLimit = value
+	}
+}
+
+// WithLowLowLimit sets the LowLowLimit of the alarm.
+func WithLowLowLimit(value float64) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.lowLowLimit = value
+	}
+}
+
+// WithLimitDeadband sets the deadband that the value must return within a limit, before the limit is no longer exceeded.
+func WithLimitDeadband(value float64) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.deadband = value
+	}
+}
+
+// WithOnDelay sets the time that a limit must be exceeded, before the alarm becomes active.
+func WithOnDelay(value time.Duration) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.onDelay = value
+	}
+}
+
+// WithOffDelay sets the time that no limit must be exceeded, before the alarm becomes inactive.
+func WithOffDelay(value time.Duration) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.offDelay = value
+	}
+}
+
+// WithLimitSeverities sets the severity that the alarm reports for each limit. Default is 900, 700, 700, 900.
+func WithLimitSeverities(highHigh, high, low, lowLow uint16) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.highHighSeverity = highHigh
+		a.highSeverity = high
+		a.lowSeverity = low
+		a.lowLowSeverity = lowLow
+	}
+}
+
+// WithNormalSeverity sets the severity that the alarm reports when the value returns within the limits. Default is 100.
+func WithNormalSeverity(value uint16) LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.normalSeverity = value
+	}
+}
+
+// WithLimitConfirmRequired sets the alarm to require a Confirm after it is acknowledged.
+func WithLimitConfirmRequired() LimitAlarmOption {
+	return func(a *LimitAlarm) {
+		a.conditionOptions = append(a.conditionOptions, WithConfirmRequired())
+	}
+}
+
+// AddExclusiveLimitAlarm adds an alarm of ExclusiveLimitAlarmType that evaluates the value of the input node.
+// Only the most severe of the exceeded limits is active.
+func (m *ConditionManager) AddExclusiveLimitAlarm(nodeID ua.NodeID, browseName ua.QualifiedName, source *ObjectNode, input *VariableNode, opts ...LimitAlarmOption) (*LimitAlarm, error) {
+	return m.addLimitAlarm(nodeID, ua.ObjectTypeIDExclusiveLimitAlarmType, browseName, source, input, nil, true, opts...)
+}
+
+// AddNonExclusiveLimitAlarm adds an alarm of NonExclusiveLimitAlarmType that evaluates the value of the input node.
+// Each of the exceeded limits is active.
+func (m *ConditionManager) AddNonExclusiveLimitAlarm(nodeID ua.NodeID, browseName ua.QualifiedName, source *ObjectNode, input *VariableNode, opts ...LimitAlarmOption) (*LimitAlarm, error) {
+	return m.addLimitAlarm(nodeID, ua.ObjectTypeIDNonExclusiveLimitAlarmType, browseName, source, input, nil, false, opts...)
+}
+
+// AddDeviationAlarm adds an alarm of ExclusiveDeviationAlarmType that evaluates the deviation of the input node from the setpoint node.
+// The limits are relative to the setpoint.
+func (m *ConditionManager) AddDeviationAlarm(nodeID ua.NodeID, browseName ua.QualifiedName, source *ObjectNode, input, setpoint *VariableNode, opts ...LimitAlarmOption) (*LimitAlarm, error) {
+	return m.addLimitAlarm(nodeID, ua.ObjectTypeIDExclusiveDeviationAlarmType, browseName, source, input, setpoint, true, opts...)
+}
+
+func (m *ConditionManager) addLimitAlarm(nodeID, typeID ua.NodeID, browseName ua.QualifiedName, source *ObjectNode, input, setpoint *VariableNode, exclusive bool, opts ...LimitAlarmOption) (*LimitAlarm, error) {
+	if input == nil {
+		return nil, ua.BadNodeIDInvalid
+	}
+	a := &LimitAlarm{
+		input:            input,
+		setpoint:         setpoint,
+		exclusive:        exclusive,
+		highHighLimit:    math.NaN(),
+		highLimit:        math.NaN(),
+		lowLimit:         math.NaN(),
+		lowLowLimit:      math.NaN(),
+		highHighSeverity: 900,
+		highSeverity:     700,
+		lowSeverity:      700,
+		lowLowSeverity:   900,
+		normalSeverity:   100,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	c, err := m.AddCondition(nodeID, typeID, browseName, source, append(a.conditionOptions, func(c *Condition) {
+		c.state.fields = a.fields(limitNone)
+	})...)
+	if err != nil {
+		return nil, err
+	}
+	a.Condition = c
+	input.AddValueListener(a)
+	if setpoint != nil {
+		setpoint.AddValueListener(a)
+	}
+	a.evaluate()
+	return a, nil
+}
+
+// Input returns the node whose value is evaluated.
+func (a *LimitAlarm) Input() *VariableNode {
+	return a.input
+}
+
+// Close stops evaluating the value of the input node.
+func (a *LimitAlarm) Close() {
+	a.input.RemoveValueListener(a)
+	if a.setpoint != nil {
+		a.setpoint.RemoveValueListener(a)
+	}
+	a.mu.Lock()
+	a.pendingGeneration++
+	a.mu.Unlock()
+}
+
+// OnValueChanged evaluates the alarm when the value of the input or setpoint node is set.
+func (a *LimitAlarm) OnValueChanged(n *VariableNode, value ua.DataValue) {
+	a.evaluate()
+}
+
+// evaluate compares the value to the limits and reports a change of the limit state, once the delay has passed.
+func (a *LimitAlarm) evaluate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	value, quality, ok := a.inputValue()
+	if !ok {
+		// keep the limit state, while the value is unknown.
+		a.pending = false
+		a.pendingGeneration++
+		if a.Quality() != quality {
+			a.SetQuality(quality)
+		}
+		return
+	}
+	a.exceededLimits = a.exceeded(value)
+	a.limits = a.activeLimits(a.exceededLimits)
+	if a.limits == a.reported {
+		a.pending = false
+		a.pendingGeneration++
+		if a.Quality() != quality {
+			a.SetQuality(quality)
+		}
+		return
+	}
+	var delay time.Duration
+	switch {
+	case a.reported == limitNone:
+		delay = a.onDelay
+	case a.limits == limitNone:
+		delay = a.offDelay
+	}
+	if delay <= 0 {
+		a.pending = false
+		a.pendingGeneration++
+		a.report(quality)
+		return
+	}
+	if a.pending {
+		// the delay started earlier, for the transition between active and inactive.
+		return
+	}
+	a.pending = true
+	a.pendingGeneration++
+	generation := a.pendingGeneration
+	time.AfterFunc(delay, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if !a.pending || a.pendingGeneration != generation {
+			return
+		}
+		a.pending = false
+		_, quality, _ := a.inputValue()
+		a.report(quality)
+	})
+}
+
+// inputValue returns the value of the input, less the setpoint if any, and the quality of the value.
+// The value is valid if both the input and setpoint are numbers, and neither has a bad quality.
+func (a *LimitAlarm) inputValue() (float64, ua.StatusCode, bool) {
+	dv := a.input.Value()
+	value, ok := toFloat(dv.Value)
+	quality := dv.StatusCode
+	if a.setpoint != nil {
+		sp := a.setpoint.Value()
+		spValue, ok2 := toFloat(sp.Value)
+		value, ok = value-spValue, ok && ok2
+		if sp.StatusCode.IsBad() {
+			quality = sp.StatusCode
+		}
+	}
+	return value, quality, ok && !quality.IsBad()
+}
+
+// exceeded returns each of the limits that the value exceeds. A limit that was exceeded remains exceeded until the value
+// returns within the limit by more than the deadband, even while a more severe limit is active.
+func (a *LimitAlarm) exceeded(value float64) uint8 {
+	var limits uint8
+	high := func(limit float64, state uint8) {
+		if !math.IsNaN(limit) && (value >= limit || (a.exceededLimits&state != 0 && value > limit-a.deadband)) {
+			limits |= state
+		}
+	}
+	low := func(limit float64, state uint8) {
+		if !math.IsNaN(limit) && (value <= limit || (a.exceededLimits&state != 0 && value < limit+a.deadband)) {
+			limits |= state
+		}
+	}
+	high(a.highHighLimit, limitHighHigh)
+	high(a.highLimit, limitHigh)
+	low(a.lowLimit, limitLow)
+	low(a.lowLowLimit, limitLowLow)
+	return limits
+}
+
+// activeLimits returns the limits that are active. If the alarm is exclusive, only the most severe limit is active.
+func (a *LimitAlarm) activeLimits(limits uint8) uint8 {
+	if a.exclusive {
+		// only the most severe limit is active.
+		switch {
+		case limits&limitHighHigh != 0:
+			return limitHighHigh
+		case limits&limitLowLow != 0:
+			return limitLowLow
+		case limits&limitHigh != 0:
+			return limitHigh
+		case limits&limitLow != 0:
+			return limitLow
+		}
+	}
+	return limits
+}
+
+// report reports the current limit state. Must be called while holding the lock of the alarm.
+func (a *LimitAlarm) report(quality ua.StatusCode) {
+	a.reported = a.limits
+	var severity uint16
+	var message string
+	switch {
+	case a.reported&limitHighHigh != 0:
+		severity, message = a.highHighSeverity, "HighHigh limit exceeded"
+	case a.reported&limitLowLow != 0:
+		severity, message = a.lowLowSeverity, "LowLow limit exceeded"
+	case a.reported&limitHigh != 0:
+		severity, message = a.highSeverity, "High limit exceeded"
+	case a.reported&limitLow != 0:
+		severity, message = a.lowSeverity, "Low limit exceeded"
+	default:
+		severity, message = a.normalSeverity, "Value within limits"
+	}
+	a.Condition.Lock()
+	a.state.quality = quality
+	a.Condition.Unlock()
+	a.Condition.setActive(a.reported != limitNone, severity, ua.NewLocalizedText(message, ""), a.fields(a.reported))
+}
+
+// fields returns the fields of the events of the limit alarm types.
+func (a *LimitAlarm) fields(limits uint8) map[string]ua.Variant {
+	f := map[string]ua.Variant{
+		"InputNode": a.input.NodeID(),
+		"OnDelay":   float64(a.onDelay) / float64(time.Millisecond),
+		"OffDelay":  float64(a.offDelay) / float64(time.Millisecond),
+	}
+	if a.setpoint != nil {
+		f["SetpointNode"] = a.setpoint.NodeID()
+	}
+	limit := func(name string, value float64) {
+		if !math.IsNaN(value) {
+			f[name] = value
+		}
+	}
+	limit("HighHighLimit", a.highHighLimit)
+	limit("HighLimit", a.highLimit)
+	limit("LowLimit", a.lowLimit)
+	limit("LowLowLimit", a.lowLowLimit)
+	if a.exclusive {
+		switch limits {
+		case limitHighHigh:
+			f["LimitState/CurrentState"] = ua.NewLocalizedText("HighHigh", "")
+			f["LimitState/CurrentState/Id"] = ua.ObjectIDExclusiveLimitStateMachineTypeHighHigh
+		case limitHigh:
+			f["LimitState/CurrentState"] = ua.NewLocalizedText("High", "")
+			f["LimitState/CurrentState/Id"] = ua.ObjectIDExclusiveLimitStateMachineTypeHigh
+		case limitLow:
+			f["LimitState/CurrentState"] = ua.NewLocalizedText("Low", "")
+			f["LimitState/CurrentState/Id"] = ua.ObjectIDExclusiveLimitStateMachineTypeLow
+		case limitLowLow:
+			f["LimitState/CurrentState"] = ua.NewLocalizedText("LowLow", "")
+			f["LimitState/CurrentState/Id"] = ua.ObjectIDExclusiveLimitStateMachineTypeLowLow
+		}
+		return f
+	}
+	state := func(name string, limit float64, bit uint8) {
+		if !math.IsNaN(limit) {
+			f[name] = twoStateText(limits&bit != 0, name[:len(name)-len("State")], "Normal")
+			f[name+"/Id"] = limits&bit != 0
+		}
+	}
+	state("HighHighState", a.highHighLimit, limitHighHigh)
+	state("HighState", a.highLimit, limitHigh)
+	state("LowState", a.lowLimit, limitLow)
+	state("LowLowState", a.lowLowLimit, limitLowLow)
+	return f
+}
@@ -22,6 +22,25 @@ var (
 	hasChildandSubtypes = []ua.NodeID{ua.ReferenceTypeIDHasComponent, ua.ReferenceTypeIDHasProperty, ua.ReferenceTypeIDHasSubtype, ua.ReferenceTypeIDHasOrderedComponent}
 )
 
+// NodeManager provides the nodes of a namespace, e.g. from an external source.
+// The nodes may be created on demand, using NewVariableNode and the like, and need not be kept.
+// Read and Write call the read and write value handlers of a variable, so the values may be kept
+// by the external source, see SetReadValueHandler and SetWriteValueHandler.
+type NodeManager interface {
+	// FindNode returns the node with the given NodeID.
+	FindNode(id ua.NodeID) (Node, bool)
+	// References returns the references of the node with the given NodeID. Browse and TranslateBrowsePaths
+	// follow these references, without finding the node. The targets of the references are found with FindNode.
+	References(id ua.NodeID) ([]ua.Reference, bool)
+}
+
+// NodeRolePermissions may be implemented by a NodeManager that provides the references of a node without the node.
+// Browse permits the users by these RolePermissions, or by the RolePermissions of the server if nil.
+type NodeRolePermissions interface {
+	// RolePermissions returns the RolePermissions of the node with the given NodeID.
+	RolePermissions(id ua.NodeID) []ua.RolePermissionType
+}
+
 // NamespaceManager manages the namespaces for a server.
 type NamespaceManager struct {
 	sync.RWMutex
@@ -29,8 +48,11 @@ type NamespaceManager struct {
 	namespaces     []string
 	nodes          map[ua.NodeID]Node
 	variantTypeMap map[ua.NodeID]byte
+	nodeManagers   map[uint16]NodeManager
 }
 
+var _ NodeManager = (*NamespaceManager)(nil)
+
 // NewNamespaceManager instantiates a new NamespaceManager.
 func NewNamespaceManager(server *Server) *NamespaceManager {
 	return &NamespaceManager{
@@ -38,6 +60,7 @@ func NewNamespaceManager(server *Server) *NamespaceManager {
 		namespaces:     []string{"http://opcfoundation.org/UA/", server.LocalDescription().ApplicationURI},
 		nodes:          make(map[ua.NodeID]Node, 4096),
 		variantTypeMap: make(map[ua.NodeID]byte, 32),
+		nodeManagers:   make(map[uint16]NodeManager),
 	}
 }
 
@@ -69,19 +92,93 @@ func (m *NamespaceManager) NamespaceUris() []string {
 	return m.namespaces
 }
 
+// RegisterNodeManager registers the NodeManager that provides the nodes of the namespace with the given index.
+// Nodes that are added to the namespace with AddNodes are found first.
+// Namespace 0 is always provided by the NamespaceManager. A nil NodeManager removes the registration.
+func (m *NamespaceManager) RegisterNodeManager(nsIndex uint16, nodeManager NodeManager) error {
+	m.Lock()
+	defer m.Unlock()
+	if nsIndex == 0 || int(nsIndex) >= len(m.namespaces) {
+		return ua.BadInvalidArgument
+	}
+	if nodeManager == nil {
+		delete(m.nodeManagers, nsIndex)
+		return nil
+	}
+	m.nodeManagers[nsIndex] = nodeManager
+	return nil
+}
+
+// NodeManager returns the NodeManager that is registered for the namespace with the given index.
+func (m *NamespaceManager) NodeManager(nsIndex uint16) (NodeManager, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	nodeManager, ok := m.nodeManagers[nsIndex]
+	return nodeManager, ok
+}
+
 // FindNode returns the node with the given NodeID from the namespace.
 func (m *NamespaceManager) FindNode(id ua.NodeID) (node Node, ok bool) {
 	m.RLock()
-	defer m.RUnlock()
 	node, ok = m.nodes[id]
-	return
+	nodeManager := m.nodeManagers[namespaceIndex(id)]
+	m.RUnlock()
+	if ok || nodeManager == nil {
+		return
+	}
+	// call the NodeManager without holding the lock, since it may find other nodes.
+	return nodeManager.FindNode(id)
+}
+
+// References returns the references of the node with the given NodeID from the namespace.
+func (m *NamespaceManager) References(id ua.NodeID) ([]ua.Reference, bool) {
+	m.RLock()
+	node, ok := m.nodes[id]
+	nodeManager := m.nodeManagers[namespaceIndex(id)]
+	m.RUnlock()
+	if ok {
+		return node.References(), true
+	}
+	if nodeManager == nil {
+		return nil, false
+	}
+	return nodeManager.References(id)
+}
+
+// UserRolePermissions returns the RolePermissions of the node with the given NodeID for the user. The RolePermissions
+// of a node that is provided without the node are those of its NodeManager, see NodeRolePermissions.
+func (m *NamespaceManager) UserRolePermissions(id ua.NodeID, userIdentity any) []ua.RolePermissionType {
+	if node, ok := m.FindNode(id); ok {
+		return node.UserRolePermissions(userIdentity)
+	}
+	filteredPermissions := []ua.RolePermissionType{}
+	roles, err := m.server.GetRoles(userIdentity, "", "")
+	if err != nil {
+		return filteredPermissions
+	}
+	m.RLock()
+	nodeManager := m.nodeManagers[namespaceIndex(id)]
+	m.RUnlock()
+	var rolePermissions []ua.RolePermissionType
+	if p, ok := nodeManager.(NodeRolePermissions); ok {
+		rolePermissions = p.RolePermissions(id)
+	}
+	if rolePermissions == nil {
+		rolePermissions = m.server.RolePermissions()
+	}
+	for _, role := range roles {
+		for _, rp := range rolePermissions {
+			if rp.RoleID == role {
+				filteredPermissions = append(filteredPermissions, rp)
+			}
+		}
+	}
+	return filteredPermissions
 }
 
 // FindObject returns the node with the given NodeID from the namespace.
 func (m *NamespaceManager) FindObject(id ua.NodeID) (node *ObjectNode, ok bool) {
-	m.RLock()
-	defer m.RUnlock()
-	if node1, ok1 := m.nodes[id]; ok1 {
+	if node1, ok1 := m.FindNode(id); ok1 {
 		node, ok = node1.(*ObjectNode)
 	}
 	return
@@ -89,9 +186,7 @@ func (m *NamespaceManager) FindObject(id ua.NodeID) (node *ObjectNode, ok bool)
 
 // FindVariable returns the node with the given NodeID from the namespace.
 func (m *NamespaceManager) FindVariable(id ua.NodeID) (node *VariableNode, ok bool) {
-	m.RLock()
-	defer m.RUnlock()
-	if node1, ok1 := m.nodes[id]; ok1 {
+	if node1, ok1 := m.FindNode(id); ok1 {
 		node, ok = node1.(*VariableNode)
 	}
 	return
@@ -135,9 +230,7 @@ func (m *NamespaceManager) FindComponent(startNode Node, browseName ua.Qualified
 
 // FindMethod returns the node with the given NodeID from the namespace.
 func (m *NamespaceManager) FindMethod(id ua.NodeID) (node *MethodNode, ok bool) {
-	m.RLock()
-	defer m.RUnlock()
-	if node1, ok1 := m.nodes[id]; ok1 {
+	if node1, ok1 := m.FindNode(id); ok1 {
 		node, ok = node1.(*MethodNode)
 	}
 	return
@@ -373,7 +466,7 @@ func (m *NamespaceManager) addNodes(nodes []Node) error {
 						TargetID:        ua.NewExpandedNodeID(id)}
 					t.SetReferences(append(t.References(), inverseRef))
 				}
-			} else {
+			} else if _, ok := m.nodeManagers[namespaceIndex(ua.ToNodeID(r.TargetID, m.namespaces))]; !ok {
 				log.Printf("Error finding reference target: %s\n", r.TargetID)
 			}
 		}
@@ -398,25 +491,37 @@ func (m *NamespaceManager) AddNode(node Node) error {
 }
 
 // DeleteNodes removes the nodes from the namespace.
-// This method removes the inverse refs as well.
+// This method removes the inverse refs as well. If deleteChildren is true, the children are removed as well.
 func (m *NamespaceManager) DeleteNodes(nodes []Node, deleteChildren bool) error {
+	return m.deleteNodes(nodes, deleteChildren, true)
+}
+
+// deleteNodes removes the nodes from the namespace. If deleteTargetReferences is true,
+// the references of other nodes to the nodes are removed as well.
+func (m *NamespaceManager) deleteNodes(nodes []Node, deleteChildren, deleteTargetReferences bool) error {
 	m.Lock()
 	defer m.Unlock()
 	children := []Node{}
-	for _, node := range nodes {
-		children = append(children, m.GetChildren(node, m.namespaces, hasChildandSubtypes)...)
+	if deleteChildren {
+		for _, node := range nodes {
+			children = append(children, m.GetChildren(node, m.namespaces, hasChildandSubtypes)...)
+		}
 	}
 	for _, node := range children {
-		m.deleteNodeandInverseReferences(node, m.namespaces)
+		m.deleteNodeandInverseReferences(node, m.namespaces, deleteTargetReferences)
 	}
 	for _, node := range nodes {
-		m.deleteNodeandInverseReferences(node, m.namespaces)
+		m.deleteNodeandInverseReferences(node, m.namespaces, deleteTargetReferences)
 	}
 	return nil
 }
 
-func (m *NamespaceManager) deleteNodeandInverseReferences(node Node, uris []string) error {
+func (m *NamespaceManager) deleteNodeandInverseReferences(node Node, uris []string, deleteInverseReferences bool) error {
 	id := node.NodeID()
+	if !deleteInverseReferences {
+		delete(m.nodes, id)
+		return nil
+	}
 	// delete inverse references from target nodes.
 	for _, r := range node.References() {
 		if r.ReferenceTypeID == ua.ReferenceTypeIDHasTypeDefinition || r.ReferenceTypeID == ua.ReferenceTypeIDHasModellingRule {
@@ -443,11 +548,69 @@ func (m *NamespaceManager) deleteNodeandInverseReferences(node Node, uris []stri
 }
 
 // DeleteNode removes the node from the namespace.
-// This method removes the inverse refs as well.
+// This method removes the inverse refs as well. If deleteChildren is true, the children are removed as well.
 func (m *NamespaceManager) DeleteNode(node Node, deleteChildren bool) error {
 	return m.DeleteNodes([]Node{node}, deleteChildren)
 }
 
+// AddReference adds the reference to the source node.
+// This method adds the inverse ref to the target node as well.
+func (m *NamespaceManager) AddReference(source Node, ref ua.Reference) error {
+	m.Lock()
+	defer m.Unlock()
+	targetID := ua.ToNodeID(ref.TargetID, m.namespaces)
+	target, ok := m.nodes[targetID]
+	if !ok {
+		if _, ok := m.nodeManagers[namespaceIndex(targetID)]; ok {
+			// the NodeManager provides the inverse reference of its node.
+			source.SetReferences(append(source.References(), ref))
+			return nil
+		}
+		return ua.BadTargetNodeIDInvalid
+	}
+	source.SetReferences(append(source.References(), ref))
+	target.SetReferences(append(target.References(), ua.Reference{
+		ReferenceTypeID: ref.ReferenceTypeID,
+		IsInverse:       !ref.IsInverse,
+		TargetID:        ua.NewExpandedNodeID(source.NodeID())}))
+	return nil
+}
+
+// DeleteReference removes the reference from the source node.
+// If deleteBidirectional is true, this method removes the inverse ref from the target node as well.
+func (m *NamespaceManager) DeleteReference(source Node, ref ua.Reference, deleteBidirectional bool) error {
+	m.Lock()
+	defer m.Unlock()
+	targetID := ua.ToNodeID(ref.TargetID, m.namespaces)
+	found := false
+	refs := []ua.Reference{}
+	for _, r := range source.References() {
+		if r.ReferenceTypeID == ref.ReferenceTypeID && r.IsInverse == ref.IsInverse && ua.ToNodeID(r.TargetID, m.namespaces) == targetID {
+			found = true
+			continue
+		}
+		refs = append(refs, r)
+	}
+	if !found {
+		return ua.BadNotFound
+	}
+	source.SetReferences(refs)
+	if deleteBidirectional {
+		if target, ok := m.nodes[targetID]; ok {
+			id := source.NodeID()
+			refs := []ua.Reference{}
+			for _, r := range target.References() {
+				if r.ReferenceTypeID == ref.ReferenceTypeID && r.IsInverse != ref.IsInverse && ua.ToNodeID(r.TargetID, m.namespaces) == id {
+					continue
+				}
+				refs = append(refs, r)
+			}
+			target.SetReferences(refs)
+		}
+	}
+	return nil
+}
+
 // GetSubTypes traverses the tree to get all target nodes with HasSubtype reference type.
 func (m *NamespaceManager) GetSubTypes(node Node) []Node {
 	children := []Node{}
@@ -491,6 +654,203 @@ func (m *NamespaceManager) GetChildren(node Node, uris []string, withRefTypes []
 	return children
 }
 
+// NodeIDFactory returns the NodeID of a new node, given the NodeID of its parent and its browse name.
+type NodeIDFactory func(parentID ua.NodeID, browseName ua.QualifiedName) ua.NodeID
+
+// InstantiateOption is a functional option to be applied when instantiating a type.
+type InstantiateOption func(*instantiateOptions)
+
+type instantiateOptions struct {
+	allOptional     bool
+	optional        map[ua.QualifiedName]bool
+	referenceTypeID ua.NodeID
+}
+
+// WithOptionalChildren instantiates the Optional children with the given browse names too.
+// Without browse names, all Optional children are instantiated.
+func WithOptionalChildren(browseNames ...ua.QualifiedName) InstantiateOption {
+	return func(o *instantiateOptions) {
+		if len(browseNames) == 0 {
+			o.allOptional = true
+			return
+		}
+		for _, bn := range browseNames {
+			o.optional[bn] = true
+		}
+	}
+}
+
+// WithParentReferenceType sets the type of the reference from the parent to the new instance. Default is HasComponent.
+func WithParentReferenceType(referenceTypeID ua.NodeID) InstantiateOption {
+	return func(o *instantiateOptions) {
+		o.referenceTypeID = referenceTypeID
+	}
+}
+
+// Instantiate creates an instance of the ObjectType or VariableType and adds it to the namespace as a child of the parent.
+// The children of the type and its supertypes with ModellingRule Mandatory are instantiated, recursively.
+// Methods are referenced, rather than copied. If nodeIDFactory is nil, the NodeIDs of the children
+// are the NodeID of the parent, followed by a dot and the browse name.
+// Returns the new ObjectNode or VariableNode.
+func (m *NamespaceManager) Instantiate(typeID, parentID ua.NodeID, browseName ua.QualifiedName, nodeIDFactory NodeIDFactory, opts ...InstantiateOption) (Node, error) {
+	o := &instantiateOptions{optional: map[ua.QualifiedName]bool{}, referenceTypeID: ua.ReferenceTypeIDHasComponent}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if nodeIDFactory == nil {
+		nodeIDFactory = func(parentID ua.NodeID, browseName ua.QualifiedName) ua.NodeID {
+			return childNodeID(parentID, browseName.Name)
+		}
+	}
+	typeNode, ok := m.FindNode(typeID)
+	if !ok {
+		return nil, ua.BadTypeDefinitionInvalid
+	}
+	switch n := typeNode.(type) {
+	case *ObjectTypeNode:
+		if n.IsAbstract() {
+			return nil, ua.BadTypeDefinitionInvalid
+		}
+	case *VariableTypeNode:
+		if n.IsAbstract() {
+			return nil, ua.BadTypeDefinitionInvalid
+		}
+	default:
+		return nil, ua.BadTypeDefinitionInvalid
+	}
+	if _, ok := m.FindNode(parentID); !ok {
+		return nil, ua.BadParentNodeIDInvalid
+	}
+	b := &instanceBuilder{m: m, nodeIDFactory: nodeIDFactory, options: o}
+	root, err := b.instantiate(typeNode, typeID, nodeIDFactory(parentID, browseName), browseName, ua.NewReference(o.referenceTypeID, true, ua.NewExpandedNodeID(parentID)))
+	if err != nil {
+		return nil, err
+	}
+	if err := m.AddNodes(b.nodes...); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// instanceBuilder collects the nodes of a new instance.
+type instanceBuilder struct {
+	m             *NamespaceManager
+	nodeIDFactory NodeIDFactory
+	options       *instantiateOptions
+	nodes         []Node
+}
+
+// instantiate creates a node from the declaration, i.e. the type itself or an instance declaration of the type,
+// then the children of the declaration and its type definition.
+func (b *instanceBuilder) instantiate(decl Node, typeDefID, nodeID ua.NodeID, browseName ua.QualifiedName, parentRef ua.Reference) (Node, error) {
+	if _, ok := b.m.FindNode(nodeID); ok {
+		return nil, ua.BadNodeIDExists
+	}
+	for _, n := range b.nodes {
+		if n.NodeID() == nodeID {
+			return nil, ua.BadNodeIDExists
+		}
+	}
+	refs := []ua.Reference{
+		ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(typeDefID)),
+		parentRef,
+	}
+	displayName := ua.NewLocalizedText(browseName.Name, "")
+	var n Node
+	switch d := decl.(type) {
+	case *ObjectTypeNode:
+		n = NewObjectNode(b.m.server, nodeID, browseName, displayName, ua.NewLocalizedText("", ""), d.RolePermissions(), refs, ua.EventNotifierNone)
+	case *VariableTypeNode:
+		n = NewVariableNode(b.m.server, nodeID, browseName, displayName, ua.NewLocalizedText("", ""), d.RolePermissions(), refs,
+			d.Value(), d.DataType(), d.ValueRank(), d.ArrayDimensions(), ua.AccessLevelsCurrentRead, 0, false, nil)
+	case *ObjectNode:
+		n = NewObjectNode(b.m.server, nodeID, browseName, d.DisplayName(), d.Description(), d.RolePermissions(), refs, d.EventNotifier())
+	case *VariableNode:
+		n = NewVariableNode(b.m.server, nodeID, browseName, d.DisplayName(), d.Description(), d.RolePermissions(), refs,
+			d.Value(), d.DataType(), d.ValueRank(), d.ArrayDimensions(), d.AccessLevel(), d.MinimumSamplingInterval(), false, nil)
+	default:
+		return nil, ua.BadNodeClassInvalid
+	}
+	b.nodes = append(b.nodes, n)
+
+	// the instance declaration overrides the children of its type definition, which override the children of the supertypes.
+	sources := []Node{}
+	if _, ok := decl.(*ObjectTypeNode); !ok {
+		if _, ok := decl.(*VariableTypeNode); !ok {
+			sources = append(sources, decl)
+		}
+	}
+	for id, i := typeDefID, 0; id != nil && i < 100; id, i = b.m.FindSuperType(id), i+1 {
+		if t, ok := b.m.FindNode(id); ok {
+			sources = append(sources, t)
+		}
+	}
+	seen := map[ua.QualifiedName]bool{}
+	for _, source := range sources {
+		for _, r := range source.References() {
+			if r.IsInverse || (r.ReferenceTypeID != ua.ReferenceTypeIDHasComponent && r.ReferenceTypeID != ua.ReferenceTypeIDHasProperty && !b.m.IsSubtype(r.ReferenceTypeID, ua.ReferenceTypeIDAggregates)) {
+				continue
+			}
+			child, ok := b.m.FindNode(ua.ToNodeID(r.TargetID, b.m.NamespaceUris()))
+			if !ok || seen[child.BrowseName()] || !b.isInstantiated(child) {
+				continue
+			}
+			seen[child.BrowseName()] = true
+			if method, ok := child.(*MethodNode); ok {
+				n.SetReferences(append(n.References(), ua.NewReference(r.ReferenceTypeID, false, ua.NewExpandedNodeID(method.NodeID()))))
+				continue
+			}
+			childTypeDefID := b.typeDefinition(child)
+			if childTypeDefID == nil {
+				continue
+			}
+			childID := b.nodeIDFactory(nodeID, child.BrowseName())
+			if _, err := b.instantiate(child, childTypeDefID, childID, child.BrowseName(), ua.NewReference(r.ReferenceTypeID, true, ua.NewExpandedNodeID(nodeID))); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// isInstantiated returns true if the instance declaration has ModellingRule Mandatory, or Optional when selected.
+func (b *instanceBuilder) isInstantiated(decl Node) bool {
+	for _, r := range decl.References() {
+		if !r.IsInverse && r.ReferenceTypeID == ua.ReferenceTypeIDHasModellingRule {
+			switch ua.ToNodeID(r.TargetID, b.m.NamespaceUris()) {
+			case ua.ObjectIDModellingRuleMandatory:
+				return true
+			case ua.ObjectIDModellingRuleOptional:
+				return b.options.allOptional || b.options.optional[decl.BrowseName()]
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// typeDefinition returns the type definition of the node.
+func (b *instanceBuilder) typeDefinition(n Node) ua.NodeID {
+	for _, r := range n.References() {
+		if !r.IsInverse && r.ReferenceTypeID == ua.ReferenceTypeIDHasTypeDefinition {
+			return ua.ToNodeID(r.TargetID, b.m.NamespaceUris())
+		}
+	}
+	return nil
+}
+
+// childNodeID returns a NodeID for a child of the given node.
+func childNodeID(parent ua.NodeID, name string) ua.NodeID {
+	switch id := parent.(type) {
+	case ua.NodeIDString:
+		return ua.NewNodeIDString(id.NamespaceIndex, id.ID+"."+name)
+	case ua.NodeIDNumeric:
+		return ua.NewNodeIDString(id.NamespaceIndex, fmt.Sprintf("%d.%s", id.ID, name))
+	default:
+		return ua.NewNodeIDGUID(namespaceIndex(parent), uuid.New())
+	}
+}
+
 // OnEvent raises the event, starting from the target node, follows HasNotifier references until the Server node.
 func (m *NamespaceManager) OnEvent(target *ObjectNode, evt ua.Event) error {
 	for target.nodeID != ua.ObjectIDServer {
@@ -534,6 +894,22 @@ func Contains(nodes []ua.NodeID, node ua.NodeID) bool {
 	return false
 }
 
+// namespaceIndex returns the namespace index of the given node id.
+func namespaceIndex(id ua.NodeID) uint16 {
+	switch id := id.(type) {
+	case ua.NodeIDNumeric:
+		return id.NamespaceIndex
+	case ua.NodeIDString:
+		return id.NamespaceIndex
+	case ua.NodeIDGUID:
+		return id.NamespaceIndex
+	case ua.NodeIDOpaque:
+		return id.NamespaceIndex
+	default:
+		return 0
+	}
+}
+
 // LoadNodeSetFromFile loads the UANodeSet XML from a file with the given path into the namespace.
 func (m *NamespaceManager) LoadNodeSetFromFile(path string) error {
 	buf, err := os.ReadFile(path)
@@ -573,6 +949,43 @@ func (m *NamespaceManager) LoadNodeSetFromBuffer(buf []byte) error {
 		aliases[a.Alias] = a.NodeID
 	}
 
+	// index the browse names and supertypes, to resolve the definitions of data types.
+	browseNames := make(map[ua.NodeID]string, len(set.Nodes))
+	superTypes := make(map[ua.NodeID]ua.NodeID, len(set.Nodes))
+	for _, n := range set.Nodes {
+		id := toNodeID(n.NodeID, aliases, nsMap)
+		if id == nil {
+			continue
+		}
+		browseNames[id] = n.BrowseName
+		for _, r := range n.References {
+			if r.IsForward == "false" && toNodeID(r.ReferenceType, aliases, nsMap) == ua.ReferenceTypeIDHasSubtype {
+				superTypes[id] = toNodeID(r.TargetNodeID, aliases, nsMap)
+			}
+		}
+	}
+
+	// register the definitions of the data types, so values of the data types may be encoded.
+	uris := m.NamespaceUris()
+	definitions := make(map[ua.NodeID]any)
+	for _, n := range set.Nodes {
+		if n.XMLName.Local != "UADataType" {
+			continue
+		}
+		id := toNodeID(n.NodeID, aliases, nsMap)
+		switch def := m.toDataTypeDefinition(n, id, browseNames, superTypes, aliases, nsMap).(type) {
+		case ua.StructureDefinition:
+			ua.RegisterStructureDefinition(id, def, uris)
+			if xmlEncodingID := toEncodingID(n, "Default XML", browseNames, aliases, nsMap); xmlEncodingID != nil {
+				ua.RegisterStructureXMLEncoding(id, toBrowseName(n.BrowseName, nsMap).Name, xmlEncodingID, uris)
+			}
+			definitions[id] = def
+		case ua.EnumDefinition:
+			ua.RegisterEnumDefinition(id, def, uris)
+			definitions[id] = def
+		}
+	}
+
 	nodes := make([]Node, len(set.Nodes))
 	for i, n := range set.Nodes {
 		switch n.XMLName.Local {
@@ -596,23 +1009,24 @@ func (m *NamespaceManager) LoadNodeSetFromBuffer(buf []byte) error {
 				toLocalizedText(n.Description),
 				nil,
 				toRefs(n.References, aliases, nsMap),
-				toDataValue(n.Value, n.DataType, aliases, nsMap, toInt32(n.ValueRank, -1), m),
+				m.toValue(n, aliases, nsMap),
 				toNodeID(n.DataType, aliases, nsMap),
 				toInt32(n.ValueRank, -1),
 				toDims(n.ArrayDimensions, toInt32(n.ValueRank, -1)),
 				n.IsAbstract,
 			)
 		case "UADataType":
+			id := toNodeID(n.NodeID, aliases, nsMap)
 			nodes[i] = NewDataTypeNode(
 				srv,
-				toNodeID(n.NodeID, aliases, nsMap),
+				id,
 				toBrowseName(n.BrowseName, nsMap),
 				toLocalizedText(n.DisplayName),
 				toLocalizedText(n.Description),
 				nil,
 				toRefs(n.References, aliases, nsMap),
 				n.IsAbstract,
-				nil,
+				definitions[id],
 			)
 		case "UAReferenceType":
 			nodes[i] = NewReferenceTypeNode(
@@ -647,7 +1061,7 @@ func (m *NamespaceManager) LoadNodeSetFromBuffer(buf []byte) error {
 				toLocalizedText(n.Description),
 				nil,
 				toRefs(n.References, aliases, nsMap),
-				toDataValue(n.Value, n.DataType, aliases, nsMap, toInt32(n.ValueRank, -1), m),
+				m.toValue(n, aliases, nsMap),
 				toNodeID(n.DataType, aliases, nsMap),
 				toInt32(n.ValueRank, -1),
 				toDims(n.ArrayDimensions, toInt32(n.ValueRank, -1)),
@@ -689,6 +1103,94 @@ func (m *NamespaceManager) LoadNodeSetFromBuffer(buf []byte) error {
 	return nil
 }
 
+// toDataTypeDefinition returns the StructureDefinition or EnumDefinition of the data type, or nil if none.
+func (m *NamespaceManager) toDataTypeDefinition(n ua.UANode, id ua.NodeID, browseNames map[ua.NodeID]string, superTypes map[ua.NodeID]ua.NodeID, aliases map[string]string, nsMap map[uint16]uint16) any {
+	if n.Definition == nil || id == nil {
+		return nil
+	}
+	superType := superTypes[id]
+	if isEnumeration(superType, superTypes, m) {
+		def := ua.EnumDefinition{Fields: make([]ua.EnumField, len(n.Definition.Field))}
+		for i, f := range n.Definition.Field {
+			def.Fields[i] = ua.EnumField{
+				Value:       int64(f.Value),
+				DisplayName: ua.LocalizedText{Text: f.Name},
+				Description: ua.LocalizedText{Text: f.Description},
+				Name:        f.Name,
+			}
+		}
+		return def
+	}
+	def := ua.StructureDefinition{
+		BaseDataType:  superType,
+		StructureType: ua.StructureTypeStructure,
+		Fields:        make([]ua.StructureField, len(n.Definition.Field)),
+	}
+	if n.Definition.IsUnion {
+		def.StructureType = ua.StructureTypeUnion
+	}
+	def.DefaultEncodingID = toEncodingID(n, "Default Binary", browseNames, aliases, nsMap)
+	for i, f := range n.Definition.Field {
+		rank := int32(f.ValueRank)
+		def.Fields[i] = ua.StructureField{
+			Name:            f.Name,
+			Description:     ua.LocalizedText{Text: f.Description},
+			DataType:        toNodeID(f.DataType, aliases, nsMap),
+			ValueRank:       rank,
+			ArrayDimensions: toDims("", rank),
+			IsOptional:      f.IsOptional,
+		}
+		if f.IsOptional && def.StructureType == ua.StructureTypeStructure {
+			def.StructureType = ua.StructureTypeStructureWithOptionalFields
+		}
+	}
+	return def
+}
+
+// toEncodingID returns the target of the HasEncoding reference of the data type with the given browse name, e.g. "Default Binary", or nil if none.
+func toEncodingID(n ua.UANode, name string, browseNames map[ua.NodeID]string, aliases map[string]string, nsMap map[uint16]uint16) ua.NodeID {
+	for _, r := range n.References {
+		if r.IsForward != "false" && toNodeID(r.ReferenceType, aliases, nsMap) == ua.ReferenceTypeIDHasEncoding {
+			if target := toNodeID(r.TargetNodeID, aliases, nsMap); target != nil && browseNames[target] == name {
+				return target
+			}
+		}
+	}
+	return nil
+}
+
+// toValue returns the value of the variable or variable type. Values that are not supported by toDataValue,
+// e.g. values of the structures defined by the nodeset, are decoded with the XMLDecoder.
+func (m *NamespaceManager) toValue(n ua.UANode, aliases map[string]string, nsMap map[uint16]uint16) ua.DataValue {
+	dv := toDataValue(n.Value, n.DataType, aliases, nsMap, toInt32(n.ValueRank, -1), m)
+	if dv.Value != nil || strings.TrimSpace(n.Value.InnerXML) == "" {
+		return dv
+	}
+	dec := ua.NewXMLDecoder(strings.NewReader(n.Value.InnerXML), namespaceTable(m.NamespaceUris()))
+	dec.SetNamespaceMap(func(ns uint16) uint16 { return toNamespaceIndex(ns, nsMap) })
+	var v ua.Variant
+	if err := dec.ReadVariant(&v); err != nil || v == nil {
+		return dv
+	}
+	now := time.Now()
+	return ua.NewDataValue(v, 0, now, 0, now, 0)
+}
+
+// isEnumeration returns true if the data type is Enumeration or a subtype of Enumeration.
+func isEnumeration(id ua.NodeID, superTypes map[ua.NodeID]ua.NodeID, m *NamespaceManager) bool {
+	for i := 0; id != nil && i < 100; i++ {
+		if id == ua.DataTypeIDEnumeration {
+			return true
+		}
+		superType, ok := superTypes[id]
+		if !ok {
+			return m.IsSubtype(id, ua.DataTypeIDEnumeration)
+		}
+		id = superType
+	}
+	return false
+}
+
 func toNodeID(s string, aliases map[string]string, nsMap map[uint16]uint16) ua.NodeID {
 	if alias, exists := aliases[s]; exists {
 		s = alias
@@ -778,6 +1280,13 @@ func toBrowseName(s string, nsMap map[uint16]uint16) ua.QualifiedName {
 	return ua.NewQualifiedName(uint16(ns), s)
 }
 
+func toNamespaceIndex(ns uint16, nsMap map[uint16]uint16) uint16 {
+	if ns2, exists := nsMap[ns]; exists {
+		return ns2
+	}
+	return ns
+}
+
 func toLocalizedText(s ua.UALocalizedText) ua.LocalizedText {
 	if len(s.Text) > 0 {
 		return ua.NewLocalizedText(s.Text, s.Locale)
@@ -905,7 +1414,7 @@ func toDataValue(s ua.UAVariant, dataType string, aliases map[string]string, nsM
 			case ua.DataTypeIDQualifiedName:
 				if s.QualifiedName != nil {
 					item := *s.QualifiedName
-					return ua.NewDataValue(ua.QualifiedName{NamespaceIndex: item.NamespaceIndex, Name: strings.TrimSpace(item.Name)}, 0, now, 0, now, 0)
+					return ua.NewDataValue(ua.QualifiedName{NamespaceIndex: toNamespaceIndex(item.NamespaceIndex, nsMap), Name: strings.TrimSpace(item.Name)}, 0, now, 0, now, 0)
 				}
 			case ua.DataTypeIDDuration:
 				if s.Double != nil {
@@ -914,7 +1423,7 @@ func toDataValue(s ua.UAVariant, dataType string, aliases map[string]string, nsM
 			case ua.DataTypeIDNodeID:
 				if s.NodeID != nil {
 					item := *s.NodeID
-					return ua.NewDataValue(ua.ParseNodeID(strings.TrimSpace(item.Identifier)), 0, now, 0, now, 0)
+					return ua.NewDataValue(toNodeID(strings.TrimSpace(item.Identifier), aliases, nsMap), 0, now, 0, now, 0)
 				}
 			case ua.DataTypeIDExpandedNodeID:
 				if s.ExpandedNodeID != nil {
@@ -1007,9 +1516,9 @@ func toDataValue(s ua.UAVariant, dataType string, aliases map[string]string, nsM
 					return ua.NewDataValue(ua.LocalizedText{Text: strings.TrimSpace(item.Text), Locale: strings.TrimSpace(item.Locale)}, 0, now, 0, now, 0)
 				case s.QualifiedName != nil:
 					item := *s.QualifiedName
-					return ua.NewDataValue(ua.QualifiedName{NamespaceIndex: item.NamespaceIndex, Name: strings.TrimSpace(item.Name)}, 0, now, 0, now, 0)
+					return ua.NewDataValue(ua.QualifiedName{NamespaceIndex: toNamespaceIndex(item.NamespaceIndex, nsMap), Name: strings.TrimSpace(item.Name)}, 0, now, 0, now, 0)
 				case s.NodeID != nil:
-					return ua.NewDataValue(ua.ParseNodeID(strings.TrimSpace(s.NodeID.Identifier)), 0, now, 0, now, 0)
+					return ua.NewDataValue(toNodeID(strings.TrimSpace(s.NodeID.Identifier), aliases, nsMap), 0, now, 0, now, 0)
 				case s.ExpandedNodeID != nil:
 					return ua.NewDataValue(ua.ParseExpandedNodeID(strings.TrimSpace(s.ExpandedNodeID.Identifier)), 0, now, 0, now, 0)
 				}
@@ -1139,7 +1648,7 @@ func toDataValue(s ua.UAVariant, dataType string, aliases map[string]string, nsM
 					list := s.ListOfQualifiedName.List
 					list2 := make([]ua.QualifiedName, len(list))
 					for i, item := range list {
-						list2[i] = ua.QualifiedName{NamespaceIndex: item.NamespaceIndex, Name: strings.TrimSpace(item.Name)}
+						list2[i] = ua.QualifiedName{NamespaceIndex: toNamespaceIndex(item.NamespaceIndex, nsMap), Name: strings.TrimSpace(item.Name)}
 					}
 					return ua.NewDataValue(list2, 0, now, 0, now, 0)
 				}
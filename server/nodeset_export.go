@@ -0,0 +1,416 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+const (
+	nodeSetNamespace = "http://opcfoundation.org/UA/2011/03/UANodeSet.xsd"
+)
+
+// ExportNodeSet writes the nodes of the given namespaces to w as a UANodeSet XML document.
+// The document contains the attributes and references of each node, the definitions of data types
+// and the current values of variables. If no namespaces are given, all namespaces but the
+// standard namespace are exported. Nodes provided by a NodeManager are not exported.
+func (m *NamespaceManager) ExportNodeSet(w io.Writer, namespaceURIs ...string) error {
+	uris := m.NamespaceUris()
+	if len(namespaceURIs) == 0 {
+		namespaceURIs = uris[1:]
+	}
+	x := &nodeSetExporter{
+		manager: m,
+		uris:    uris,
+		nsMap:   map[uint16]uint16{0: 0},
+		aliases: make(map[string]string, 32),
+	}
+	selected := make(map[uint16]bool, len(namespaceURIs))
+	for _, nsu := range namespaceURIs {
+		i := indexOfString(uris, nsu)
+		if i < 1 {
+			return ua.BadInvalidArgument
+		}
+		selected[uint16(i)] = true
+		x.namespaceIndex(uint16(i))
+	}
+
+	m.RLock()
+	nodes := make([]Node, 0, 256)
+	for id, n := range m.nodes {
+		if selected[namespaceIndex(id)] {
+			nodes = append(nodes, n)
+		}
+	}
+	m.RUnlock()
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i].NodeID(), nodes[j].NodeID()
+		if nsa, nsb := namespaceIndex(a), namespaceIndex(b); nsa != nsb {
+			return nsa < nsb
+		}
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	})
+
+	set := &exportNodeSet{
+		XMLName:      xml.Name{Local: "UANodeSet"},
+		Xmlns:        nodeSetNamespace,
+		LastModified: time.Now().UTC().Round(time.Second),
+		Nodes:        make([]*exportNode, 0, len(nodes)),
+	}
+	for _, n := range nodes {
+		if en := x.node(n); en != nil {
+			set.Nodes = append(set.Nodes, en)
+		}
+	}
+	set.NamespaceUris = x.namespaceUris
+	set.Aliases = x.aliasList()
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(set); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// exportNodeSet supports writing UANodeSet to xml.
+type exportNodeSet struct {
+	XMLName       xml.Name
+	Xmlns         string        `xml:"xmlns,attr"`
+	LastModified  time.Time     `xml:"LastModified,attr"`
+	NamespaceUris []string      `xml:"NamespaceUris>Uri,omitempty"`
+	Aliases       []exportAlias `xml:"Aliases>Alias,omitempty"`
+	Nodes         []*exportNode
+}
+
+// exportAlias supports writing UANodeSet to xml.
+type exportAlias struct {
+	Alias  string `xml:"Alias,attr"`
+	NodeID string `xml:",chardata"`
+}
+
+// exportNode supports writing UANodeSet to xml.
+type exportNode struct {
+	XMLName                 xml.Name
+	NodeID                  string            `xml:"NodeId,attr"`
+	BrowseName              string            `xml:"BrowseName,attr"`
+	IsAbstract              bool              `xml:"IsAbstract,attr,omitempty"`
+	Symmetric               bool              `xml:"Symmetric,attr,omitempty"`
+	ContainsNoLoops         bool              `xml:"ContainsNoLoops,attr,omitempty"`
+	EventNotifier           uint8             `xml:"EventNotifier,attr,omitempty"`
+	DataType                string            `xml:"DataType,attr,omitempty"`
+	ValueRank               string            `xml:"ValueRank,attr,omitempty"`
+	ArrayDimensions         string            `xml:"ArrayDimensions,attr,omitempty"`
+	AccessLevel             string            `xml:"AccessLevel,attr,omitempty"`
+	MinimumSamplingInterval float64           `xml:"MinimumSamplingInterval,attr,omitempty"`
+	Historizing             bool              `xml:"Historizing,attr,omitempty"`
+	Executable              string            `xml:"Executable,attr,omitempty"`
+	DisplayName             exportText        `xml:"DisplayName"`
+	Description             *exportText       `xml:"Description,omitempty"`
+	References              []exportReference `xml:"References>Reference,omitempty"`
+	InverseName             *exportText       `xml:"InverseName,omitempty"`
+	Definition              *exportDefinition `xml:"Definition,omitempty"`
+	Value                   *xmlValue         `xml:"Value,omitempty"`
+}
+
+// exportText supports writing UANodeSet to xml.
+type exportText struct {
+	Locale string `xml:"Locale,attr,omitempty"`
+	Text   string `xml:",chardata"`
+}
+
+// exportReference supports writing UANodeSet to xml.
+type exportReference struct {
+	ReferenceType string `xml:"ReferenceType,attr"`
+	IsForward     string `xml:"IsForward,attr,omitempty"`
+	TargetNodeID  string `xml:",chardata"`
+}
+
+// exportDefinition supports writing UANodeSet to xml.
+type exportDefinition struct {
+	Name    string        `xml:"Name,attr"`
+	IsUnion bool          `xml:"IsUnion,attr,omitempty"`
+	Fields  []exportField `xml:"Field"`
+}
+
+// exportField supports writing UANodeSet to xml.
+type exportField struct {
+	Name            string      `xml:"Name,attr"`
+	DataType        string      `xml:"DataType,attr,omitempty"`
+	ValueRank       string      `xml:"ValueRank,attr,omitempty"`
+	ArrayDimensions string      `xml:"ArrayDimensions,attr,omitempty"`
+	MaxStringLength uint32      `xml:"MaxStringLength,attr,omitempty"`
+	Value           *int64      `xml:"Value,attr,omitempty"`
+	IsOptional      bool        `xml:"IsOptional,attr,omitempty"`
+	DisplayName     *exportText `xml:"DisplayName,omitempty"`
+	Description     *exportText `xml:"Description,omitempty"`
+}
+
+// xmlValue is the Value element of a node, containing the xml encoding of the value, see OPC UA Part 6, 5.3.
+type xmlValue struct {
+	Raw string `xml:",innerxml"`
+}
+
+// namespaceTable is an EncodingContext with the given table of NamespaceURIs.
+type namespaceTable []string
+
+// NamespaceURIs returns the table of NamespaceURIs.
+func (t namespaceTable) NamespaceURIs() []string { return t }
+
+// nodeSetExporter converts the nodes of the namespace manager for writing to xml. The namespace
+// indexes of the server are mapped to the indexes of the NamespaceUris of the document.
+type nodeSetExporter struct {
+	manager       *NamespaceManager
+	uris          []string
+	nsMap         map[uint16]uint16
+	namespaceUris []string
+	aliases       map[string]string
+}
+
+// namespaceIndex returns the index of the namespace in the document, adding it if needed.
+func (x *nodeSetExporter) namespaceIndex(ns uint16) uint16 {
+	if i, ok := x.nsMap[ns]; ok {
+		return i
+	}
+	if int(ns) >= len(x.uris) {
+		return ns
+	}
+	x.namespaceUris = append(x.namespaceUris, x.uris[ns])
+	i := uint16(len(x.namespaceUris))
+	x.nsMap[ns] = i
+	return i
+}
+
+// nodeID returns the string form of the NodeID, using the namespace indexes of the document.
+func (x *nodeSetExporter) nodeID(id ua.NodeID) string {
+	switch id := id.(type) {
+	case ua.NodeIDNumeric:
+		return ua.NewNodeIDNumeric(x.namespaceIndex(id.NamespaceIndex), id.ID).String()
+	case ua.NodeIDString:
+		return ua.NewNodeIDString(x.namespaceIndex(id.NamespaceIndex), id.ID).String()
+	case ua.NodeIDGUID:
+		return ua.NewNodeIDGUID(x.namespaceIndex(id.NamespaceIndex), id.ID).String()
+	case ua.NodeIDOpaque:
+		return ua.NewNodeIDOpaque(x.namespaceIndex(id.NamespaceIndex), id.ID).String()
+	default:
+		return "i=0"
+	}
+}
+
+// expandedNodeID returns the string form of the ExpandedNodeID, using the namespace indexes of the document.
+func (x *nodeSetExporter) expandedNodeID(id ua.ExpandedNodeID) string {
+	if id.ServerIndex > 0 {
+		return id.String()
+	}
+	n := ua.ToNodeID(id, x.uris)
+	if n == nil {
+		return id.String()
+	}
+	return x.nodeID(n)
+}
+
+// alias returns the alias of a DataType or ReferenceType of the standard namespace, or the string form of the NodeID.
+func (x *nodeSetExporter) alias(id ua.NodeID) string {
+	if id == nil {
+		return ""
+	}
+	s := x.nodeID(id)
+	if namespaceIndex(id) != 0 {
+		return s
+	}
+	n, ok := x.manager.FindNode(id)
+	if !ok {
+		return s
+	}
+	name := n.BrowseName().Name
+	if prev, ok := x.aliases[name]; ok && prev != s {
+		return s
+	}
+	x.aliases[name] = s
+	return name
+}
+
+// aliasList returns the aliases, sorted by name.
+func (x *nodeSetExporter) aliasList() []exportAlias {
+	list := make([]exportAlias, 0, len(x.aliases))
+	for name, s := range x.aliases {
+		list = append(list, exportAlias{Alias: name, NodeID: s})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Alias < list[j].Alias })
+	return list
+}
+
+// browseName returns the string form of the QualifiedName, using the namespace indexes of the document.
+func (x *nodeSetExporter) browseName(qn ua.QualifiedName) string {
+	if qn.NamespaceIndex == 0 {
+		return qn.Name
+	}
+	return fmt.Sprintf("%d:%s", x.namespaceIndex(qn.NamespaceIndex), qn.Name)
+}
+
+// node converts the node. Returns nil if the NodeClass is not supported.
+func (x *nodeSetExporter) node(n Node) *exportNode {
+	en := &exportNode{
+		NodeID:      x.nodeID(n.NodeID()),
+		BrowseName:  x.browseName(n.BrowseName()),
+		DisplayName: exportText{Locale: n.DisplayName().Locale, Text: n.DisplayName().Text},
+	}
+	if d := n.Description(); d.Text != "" {
+		en.Description = &exportText{Locale: d.Locale, Text: d.Text}
+	}
+	switch n := n.(type) {
+	case *ObjectTypeNode:
+		en.XMLName.Local = "UAObjectType"
+		en.IsAbstract = n.IsAbstract()
+	case *VariableTypeNode:
+		en.XMLName.Local = "UAVariableType"
+		en.IsAbstract = n.IsAbstract()
+		en.DataType = x.alias(n.DataType())
+		en.ValueRank = valueRankAttr(n.ValueRank())
+		en.ArrayDimensions = arrayDimensionsAttr(n.ArrayDimensions())
+		en.Value = x.variantValue(n.Value().Value)
+	case *DataTypeNode:
+		en.XMLName.Local = "UADataType"
+		en.IsAbstract = n.IsAbstract()
+		en.Definition = x.definition(en.BrowseName, n.DataTypeDefinition())
+	case *ReferenceTypeNode:
+		en.XMLName.Local = "UAReferenceType"
+		en.IsAbstract = n.IsAbstract()
+		en.Symmetric = n.Symmetric()
+		if in := n.InverseName(); in.Text != "" {
+			en.InverseName = &exportText{Locale: in.Locale, Text: in.Text}
+		}
+	case *ObjectNode:
+		en.XMLName.Local = "UAObject"
+		en.EventNotifier = n.EventNotifier()
+	case *VariableNode:
+		en.XMLName.Local = "UAVariable"
+		en.DataType = x.alias(n.DataType())
+		en.ValueRank = valueRankAttr(n.ValueRank())
+		en.ArrayDimensions = arrayDimensionsAttr(n.ArrayDimensions())
+		if al := n.AccessLevel(); al != ua.AccessLevelsCurrentRead {
+			en.AccessLevel = strconv.FormatUint(uint64(al), 10)
+		}
+		en.MinimumSamplingInterval = n.MinimumSamplingInterval()
+		en.Historizing = n.Historizing()
+		en.Value = x.variantValue(n.Value().Value)
+	case *MethodNode:
+		en.XMLName.Local = "UAMethod"
+		if !n.Executable() {
+			en.Executable = "false"
+		}
+	case *ViewNode:
+		en.XMLName.Local = "UAView"
+		en.ContainsNoLoops = n.ContainsNoLoops()
+		en.EventNotifier = n.EventNotifier()
+	default:
+		return nil
+	}
+	for _, r := range n.References() {
+		if r.TargetID.ServerIndex > 0 {
+			continue
+		}
+		er := exportReference{
+			ReferenceType: x.alias(r.ReferenceTypeID),
+			TargetNodeID:  x.expandedNodeID(r.TargetID),
+		}
+		if r.IsInverse {
+			er.IsForward = "false"
+		}
+		en.References = append(en.References, er)
+	}
+	return en
+}
+
+// definition converts the StructureDefinition or EnumDefinition of a DataType.
+func (x *nodeSetExporter) definition(name string, def any) *exportDefinition {
+	switch def := def.(type) {
+	case *ua.StructureDefinition:
+		if def == nil {
+			return nil
+		}
+		return x.definition(name, *def)
+	case *ua.EnumDefinition:
+		if def == nil {
+			return nil
+		}
+		return x.definition(name, *def)
+	case ua.StructureDefinition:
+		ed := &exportDefinition{Name: name, IsUnion: def.StructureType == ua.StructureTypeUnion}
+		for _, f := range def.Fields {
+			ef := exportField{
+				Name:            f.Name,
+				DataType:        x.alias(f.DataType),
+				ValueRank:       valueRankAttr(f.ValueRank),
+				ArrayDimensions: arrayDimensionsAttr(f.ArrayDimensions),
+				MaxStringLength: f.MaxStringLength,
+				IsOptional:      f.IsOptional,
+			}
+			if f.Description.Text != "" {
+				ef.Description = &exportText{Locale: f.Description.Locale, Text: f.Description.Text}
+			}
+			ed.Fields = append(ed.Fields, ef)
+		}
+		return ed
+	case ua.EnumDefinition:
+		ed := &exportDefinition{Name: name}
+		for _, f := range def.Fields {
+			value := f.Value
+			ef := exportField{Name: f.Name, Value: &value}
+			if f.DisplayName.Text != "" && f.DisplayName.Text != f.Name {
+				ef.DisplayName = &exportText{Locale: f.DisplayName.Locale, Text: f.DisplayName.Text}
+			}
+			if f.Description.Text != "" {
+				ef.Description = &exportText{Locale: f.Description.Locale, Text: f.Description.Text}
+			}
+			ed.Fields = append(ed.Fields, ef)
+		}
+		return ed
+	default:
+		return nil
+	}
+}
+
+// variantValue returns the Value element of a variable, or nil if the value is empty or cannot be encoded.
+func (x *nodeSetExporter) variantValue(v ua.Variant) *xmlValue {
+	if v == nil {
+		return nil
+	}
+	buf := new(bytes.Buffer)
+	enc := ua.NewXMLEncoder(buf, namespaceTable(x.uris))
+	enc.SetNamespaceMap(x.namespaceIndex)
+	if err := enc.WriteVariant(v); err != nil {
+		return nil
+	}
+	return &xmlValue{Raw: buf.String()}
+}
+
+// valueRankAttr returns the ValueRank attribute, or empty for the default of scalar.
+func valueRankAttr(rank int32) string {
+	if rank == ua.ValueRankScalar {
+		return ""
+	}
+	return strconv.FormatInt(int64(rank), 10)
+}
+
+// arrayDimensionsAttr returns the ArrayDimensions attribute as a comma separated list.
+func arrayDimensionsAttr(dims []uint32) string {
+	s := make([]string, len(dims))
+	for i, d := range dims {
+		s[i] = strconv.FormatUint(uint64(d), 10)
+	}
+	return strings.Join(s, ",")
+}
@@ -2,7 +2,11 @@
 
 package server
 
-import "github.com/awcullen/opcua/ua"
+import (
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
 
 // Option is a functional option to be applied to a server during initialization.
 type Option func(*Server) error
@@ -69,6 +73,15 @@ func WithRejectedCertificatesPath(path string) Option {
 	}
 }
 
+// WithCertificateStore sets the store of the trusted and issuer certificates and revocation lists,
+// and of the rejected certificates. The store replaces the paths of the certificates.
+func WithCertificateStore(store ua.CertificateStore) Option {
+	return func(srv *Server) error {
+		srv.certificateStore = store
+		return nil
+	}
+}
+
 // WithInsecureSkipVerify skips verification of client certificate. Skips checking HostName, Expiration, and Authority.
 func WithInsecureSkipVerify() Option {
 	return func(srv *Server) error {
@@ -89,6 +102,62 @@ func WithTransportLimits(maxBufferSize, maxMessageSize, maxChunkCount uint32) Op
 	}
 }
 
+// WithWebSocketEndpointURL adds endpoints for the WebSocket transport. The endpointURL is in the form opc.wss://[host]:[port]
+// The TLS connection uses the certificate of the server.
+func WithWebSocketEndpointURL(endpointURL string) Option {
+	return func(srv *Server) error {
+		srv.webSocketEndpointURL = endpointURL
+		return nil
+	}
+}
+
+// WithReverseConnect sets the server to dial the client listening at the clientURL and send a ReverseHello message,
+// so the client may open a secure channel through a firewall or NAT. The clientURL is in the form opc.tcp://[host]:[port]
+// The interval is the time to wait before dialing again after a failure. (default interval: 5 sec, minimum: 1 sec)
+func WithReverseConnect(clientURL string, interval time.Duration) Option {
+	return func(srv *Server) error {
+		if interval <= 0 {
+			interval = defaultReverseConnectInterval
+		}
+		if interval < minReverseConnectInterval {
+			interval = minReverseConnectInterval
+		}
+		srv.reverseConnectClients = append(srv.reverseConnectClients, reverseConnectClient{clientURL, interval})
+		return nil
+	}
+}
+
+// WithDiscoveryRegistration sets the server to register with the discovery server at the discoveryURL, repeating
+// at the interval. The discoveryURL is in the form opc.tcp://[host]:[port]
+// The channel is secured with the certificate of the server. (default interval: 30 sec)
+func WithDiscoveryRegistration(discoveryURL string, interval time.Duration) Option {
+	return func(srv *Server) error {
+		if interval <= 0 {
+			interval = defaultDiscoveryRegistrationInterval
+		}
+		srv.discoveryRegistrations = append(srv.discoveryRegistrations, discoveryRegistration{discoveryURL, interval})
+		return nil
+	}
+}
+
+// WithDiscoveryInsecureSkipVerify skips verification of the certificate of the discovery servers that the server
+// registers with. Skips checking HostName, Expiration, and Authority.
+func WithDiscoveryInsecureSkipVerify() Option {
+	return func(srv *Server) error {
+		srv.discoveryInsecureSkipVerify = true
+		return nil
+	}
+}
+
+// WithRegistrationTimeout sets the time a registration remains with a discovery server, if the server does not
+// register again. (default: 10 min)
+func WithRegistrationTimeout(value time.Duration) Option {
+	return func(srv *Server) error {
+		srv.registrationTimeout = value
+		return nil
+	}
+}
+
 // WithMaxWorkerThreads sets the default number of worker threads that may be created. (default: 4)
 func WithMaxWorkerThreads(value int) Option {
 	return func(opts *Server) error {
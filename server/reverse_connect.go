@@ -0,0 +1,112 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+const (
+	// the time to wait when dialing a client for reverse connect.
+	reverseConnectTimeout = 10 * time.Second
+	// the time to wait before dialing a client again after a failure, if not set.
+	defaultReverseConnectInterval = 5 * time.Second
+	// the least time to wait before dialing a client again after a failure.
+	minReverseConnectInterval = time.Second
+)
+
+// reverseConnectClient is a client that the server dials for reverse connect.
+type reverseConnectClient struct {
+	clientURL string
+	interval  time.Duration
+}
+
+// reverseConnect keeps a connection waiting at the client, so the client may open a secure channel
+// through a firewall or NAT. After the client uses the connection, the server dials the client again.
+// After a failure, the server waits for the interval before dialing the client again.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part6/7.1.3/
+func (srv *Server) reverseConnect(clientURL string, interval time.Duration, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for {
+		if err := srv.reverseHello(clientURL, wg); err != nil {
+			select {
+			case <-srv.closing:
+				return
+			case <-time.After(interval):
+			}
+			continue
+		}
+		select {
+		case <-srv.closing:
+			return
+		default:
+		}
+	}
+}
+
+// reverseHello dials the client and sends a ReverseHello message, then waits for the client to
+// send a Hello message. The connection is then served like any accepted connection.
+func (srv *Server) reverseHello(clientURL string, wg *sync.WaitGroup) error {
+	remoteURL, err := url.Parse(clientURL)
+	if err != nil {
+		return ua.BadTCPEndpointURLInvalid
+	}
+	conn, err := net.DialTimeout("tcp", remoteURL.Host, reverseConnectTimeout)
+	if err != nil {
+		return err
+	}
+
+	serverURI := srv.localDescription.ApplicationURI
+	buf := make([]byte, 16+len(serverURI)+len(srv.endpointURL))
+	var writer = ua.NewWriter(buf)
+	var enc = ua.NewBinaryEncoder(writer, ua.NewEncodingContext())
+	enc.WriteUInt32(ua.MessageTypeReverseHello)
+	enc.WriteUInt32(uint32(len(buf)))
+	enc.WriteString(serverURI)
+	enc.WriteString(srv.endpointURL)
+	if _, err := conn.Write(writer.Bytes()); err != nil {
+		conn.Close()
+		return err
+	}
+
+	// wait until the client sends the first byte of the Hello message, or the server closes.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-srv.closing:
+			conn.Close()
+		case <-done:
+		}
+	}()
+	first := make([]byte, 1)
+	if _, err := conn.Read(first); err != nil {
+		conn.Close()
+		return err
+	}
+
+	wg.Add(1)
+	go srv.handleConnection(&reverseConn{Conn: conn, first: first}, ua.TransportProfileURIUaTcpTransport, wg)
+	return nil
+}
+
+// reverseConn is a connection that replays the first byte, which was read while waiting for the client.
+type reverseConn struct {
+	net.Conn
+	first []byte
+}
+
+// Read reads the first byte, then reads from the connection.
+func (c *reverseConn) Read(p []byte) (int, error) {
+	if len(c.first) > 0 && len(p) > 0 {
+		n := copy(p, c.first)
+		c.first = c.first[n:]
+		return n, nil
+	}
+	return c.Conn.Read(p)
+}
@@ -0,0 +1,334 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+	deque "github.com/gammazero/deque"
+)
+
+// sampler receives the samples of a sampledMonitoredItem, and keeps the state that depends on the filter.
+// The methods are called while the lock of the item is held.
+type sampler interface {
+	// start is called when the item starts monitoring, before the first sample.
+	start()
+	// sample receives the value that was read from the itemToMonitor.
+	sample(value ua.DataValue)
+	// setFilter sets the filter of the item.
+	setFilter(filter any)
+	// reset discards the samples and state, when the item is disabled or deleted.
+	reset()
+}
+
+// sampledMonitoredItem samples the attribute of a node at the sampling interval, and queues the notifications.
+// It is embedded by DataChangeMonitoredItem and AggregateMonitoredItem, which decide what to queue.
+type sampledMonitoredItem struct {
+	sync.RWMutex
+	id                  uint32
+	itemToMonitor       ua.ReadValueID
+	monitoringMode      ua.MonitoringMode
+	clientHandle        uint32
+	samplingInterval    float64
+	queueSize           uint32
+	discardOldest       bool
+	timestampsToReturn  ua.TimestampsToReturn
+	minSamplingInterval float64
+	queue               deque.Deque[ua.DataValue]
+	node                Node
+	sub                 *Subscription
+	srv                 *Server
+	sampler             sampler
+	triggeredItems      []MonitoredItem
+	triggered           bool
+}
+
+// init sets the parameters of the item. The sampler is called once the item starts monitoring.
+func (mi *sampledMonitoredItem) init(sampler sampler, sub *Subscription, node Node, itemToMonitor ua.ReadValueID, monitoringMode ua.MonitoringMode, parameters ua.MonitoringParameters, timestampsToReturn ua.TimestampsToReturn, minSamplingInterval float64) {
+	mi.sampler = sampler
+	mi.sub = sub
+	mi.srv = sub.manager.server
+	mi.node = node
+	mi.id = atomic.AddUint32(&monitoredItemID, 1)
+	mi.itemToMonitor = itemToMonitor
+	mi.monitoringMode = monitoringMode
+	mi.clientHandle = parameters.ClientHandle
+	mi.discardOldest = parameters.DiscardOldest
+	mi.timestampsToReturn = timestampsToReturn
+	mi.minSamplingInterval = minSamplingInterval
+	mi.setQueueSize(parameters.QueueSize)
+	mi.setSamplingInterval(parameters.SamplingInterval)
+}
+
+// ID returns the identifier of the MonitoredItem.
+func (mi *sampledMonitoredItem) ID() uint32 {
+	return mi.id
+}
+
+// Node returns the Node of the MonitoredItem.
+func (mi *sampledMonitoredItem) Node() Node {
+	return mi.node
+}
+
+// ItemToMonitor returns the ReadValueID of the MonitoredItem.
+func (mi *sampledMonitoredItem) ItemToMonitor() ua.ReadValueID {
+	return mi.itemToMonitor
+}
+
+// SamplingInterval returns the sampling interval in ms of the MonitoredItem.
+func (mi *sampledMonitoredItem) SamplingInterval() float64 {
+	mi.RLock()
+	defer mi.RUnlock()
+	return mi.samplingInterval
+}
+
+// QueueSize returns the queue size of the MonitoredItem.
+func (mi *sampledMonitoredItem) QueueSize() uint32 {
+	mi.RLock()
+	defer mi.RUnlock()
+	return mi.queueSize
+}
+
+// MonitoringMode returns the monitoring mode of the MonitoredItem.
+func (mi *sampledMonitoredItem) MonitoringMode() ua.MonitoringMode {
+	mi.RLock()
+	defer mi.RUnlock()
+	return mi.monitoringMode
+}
+
+// ClientHandle returns the client handle of the MonitoredItem.
+func (mi *sampledMonitoredItem) ClientHandle() uint32 {
+	mi.RLock()
+	defer mi.RUnlock()
+	return mi.clientHandle
+}
+
+// Triggered returns true when the MonitoredItem is triggered.
+func (mi *sampledMonitoredItem) Triggered() bool {
+	mi.RLock()
+	defer mi.RUnlock()
+	return mi.triggered
+}
+
+// SetTriggered sets when the MonitoredItem is triggered.
+func (mi *sampledMonitoredItem) SetTriggered(val bool) {
+	mi.Lock()
+	defer mi.Unlock()
+	mi.triggered = val
+}
+
+// modify sets the parameters and filter of the item, and restarts monitoring.
+func (mi *sampledMonitoredItem) modify(req ua.MonitoredItemModifyRequest) {
+	mi.stopMonitoring()
+	mi.clientHandle = req.RequestedParameters.ClientHandle
+	mi.discardOldest = req.RequestedParameters.DiscardOldest
+	mi.setQueueSize(req.RequestedParameters.QueueSize)
+	mi.setSamplingInterval(req.RequestedParameters.SamplingInterval)
+	mi.sampler.setFilter(req.RequestedParameters.Filter)
+	mi.startMonitoring()
+}
+
+// Delete deletes the MonitoredItem.
+func (mi *sampledMonitoredItem) Delete() {
+	mi.Lock()
+	defer mi.Unlock()
+	mi.stopMonitoring()
+	mi.queue.Clear()
+	mi.sampler.reset()
+	mi.node = nil
+	mi.sub = nil
+	mi.triggeredItems = nil
+}
+
+// SetMonitoringMode sets the MonitoringMode of the MonitoredItem.
+func (mi *sampledMonitoredItem) SetMonitoringMode(mode ua.MonitoringMode) {
+	mi.Lock()
+	defer mi.Unlock()
+	if mi.monitoringMode == mode {
+		return
+	}
+	mi.stopMonitoring()
+	mi.monitoringMode = mode
+	if mode == ua.MonitoringModeDisabled {
+		mi.queue.Clear()
+		mi.sampler.reset()
+		mi.sub.disabledMonitoredItemCount++
+	} else {
+		mi.sub.disabledMonitoredItemCount--
+	}
+	mi.startMonitoring()
+}
+
+func (mi *sampledMonitoredItem) setQueueSize(queueSize uint32) {
+	if queueSize > maxQueueSize {
+		queueSize = maxQueueSize
+	}
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	mi.queueSize = queueSize
+
+	// trim to size
+	overflow := false
+	if mi.discardOldest {
+		for mi.queue.Len() > int(mi.queueSize) {
+			mi.queue.PopFront()
+			overflow = true
+		}
+		if overflow && mi.queue.Len() > 1 {
+			// set overflow bit of statuscode
+			v := mi.queue.Front()
+			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
+		}
+	} else {
+		for mi.queue.Len() > int(mi.queueSize) {
+			mi.queue.PopBack()
+			overflow = true
+		}
+		if overflow && mi.queue.Len() > 1 {
+			// set overflow bit of statuscode
+			v := mi.queue.Back()
+			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
+		}
+	}
+}
+
+func (mi *sampledMonitoredItem) setSamplingInterval(samplingInterval float64) {
+	switch mi.itemToMonitor.AttributeID {
+	case ua.AttributeIDValue:
+		if samplingInterval < 0 {
+			samplingInterval = mi.sub.publishingInterval
+		}
+		if samplingInterval < mi.minSamplingInterval {
+			samplingInterval = mi.minSamplingInterval
+		}
+		if samplingInterval > maxSamplingInterval {
+			samplingInterval = maxSamplingInterval
+		}
+		if v, ok := mi.node.(*VariableNode); ok {
+			if min := v.MinimumSamplingInterval(); samplingInterval < min {
+				samplingInterval = min
+			}
+		}
+	default:
+		if samplingInterval < 0 {
+			samplingInterval = mi.sub.publishingInterval
+		}
+		if samplingInterval < mi.minSamplingInterval {
+			samplingInterval = mi.minSamplingInterval
+		}
+		if samplingInterval > maxSamplingInterval {
+			samplingInterval = maxSamplingInterval
+		}
+	}
+	mi.samplingInterval = samplingInterval
+}
+
+func (mi *sampledMonitoredItem) startMonitoring() {
+	mi.sampler.start()
+	if mi.monitoringMode == ua.MonitoringModeDisabled {
+		return
+	}
+	mi.sampler.sample(mi.srv.readValue(mi.sub.session.Load(), mi.itemToMonitor))
+	mi.Unlock()
+	mi.srv.Scheduler().GetPollGroup(time.Duration(mi.samplingInterval) * time.Millisecond).Subscribe(mi)
+	mi.Lock()
+}
+
+func (mi *sampledMonitoredItem) stopMonitoring() {
+	mi.Unlock()
+	mi.srv.Scheduler().GetPollGroup(time.Duration(mi.samplingInterval) * time.Millisecond).Unsubscribe(mi)
+	mi.Lock()
+}
+
+// Poll reads the value of the itemToMonitor.
+func (mi *sampledMonitoredItem) Poll() {
+	mi.Lock()
+	if n := mi.node; n != nil {
+		mi.sampler.sample(mi.srv.readValue(mi.sub.session.Load(), mi.itemToMonitor))
+	}
+	mi.Unlock()
+}
+
+// AddTriggeredItem adds a item to be triggered by this item.
+func (mi *sampledMonitoredItem) AddTriggeredItem(item MonitoredItem) bool {
+	mi.Lock()
+	mi.triggeredItems = append(mi.triggeredItems, item)
+	mi.Unlock()
+	return true
+}
+
+// RemoveTriggeredItem removes an item to be triggered by this item.
+func (mi *sampledMonitoredItem) RemoveTriggeredItem(item MonitoredItem) bool {
+	mi.Lock()
+	ret := false
+	for i, e := range mi.triggeredItems {
+		if e.ID() == item.ID() {
+			mi.triggeredItems[i] = mi.triggeredItems[len(mi.triggeredItems)-1]
+			mi.triggeredItems[len(mi.triggeredItems)-1] = nil
+			mi.triggeredItems = mi.triggeredItems[:len(mi.triggeredItems)-1]
+			ret = true
+			break
+		}
+	}
+	mi.Unlock()
+	return ret
+}
+
+func (mi *sampledMonitoredItem) enqueue(item ua.DataValue) {
+	overflow := false
+	if mi.discardOldest {
+		for mi.queue.Len() >= int(mi.queueSize) {
+			mi.queue.PopFront() // discard oldest
+			overflow = true
+		}
+		mi.queue.PushBack(item)
+		if overflow && mi.queueSize > 1 {
+			// set overflow bit of statuscode
+			v := mi.queue.Front()
+			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
+			mi.sub.monitoringQueueOverflowCount++
+		}
+	} else {
+		for mi.queue.Len() >= int(mi.queueSize) {
+			mi.queue.PopBack() // discard newest
+			overflow = true
+		}
+		mi.queue.PushBack(item)
+		if overflow && mi.queueSize > 1 {
+			// set overflow bit of statuscode
+			v := mi.queue.Back()
+			v.StatusCode = ua.StatusCode(uint32(v.StatusCode) | ua.InfoTypeDataValue | ua.Overflow)
+			mi.sub.monitoringQueueOverflowCount++
+		}
+	}
+	if mi.triggeredItems != nil {
+		for _, item := range mi.triggeredItems {
+			item.SetTriggered(true)
+			// log.Printf("Item %d triggered %d", mi.id, item.id)
+		}
+	}
+
+}
+
+func (mi *sampledMonitoredItem) notifications(max int) (notifications []any, more bool) {
+	mi.Lock()
+	defer mi.Unlock()
+	notifications = make([]any, 0, 4)
+	for i := 0; i < max; i++ {
+		if mi.queue.Len() > 0 {
+			notifications = append(notifications, mi.queue.PopFront())
+		} else {
+			break
+		}
+	}
+	more = mi.queue.Len() > 0
+	if mi.triggered && !more {
+		mi.triggered = false
+		// log.Printf("Reset triggered %d", mi.id)
+	}
+	return notifications, more
+}
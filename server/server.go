@@ -55,7 +55,14 @@ type Server struct {
 	issuerCertsPath                      string
 	issuerCRLsPath                       string
 	rejectedCertsPath                    string
+	certificateStore                     ua.CertificateStore
+	certificateValidator                 *ua.CertificateValidator
 	endpointURL                          string
+	webSocketEndpointURL                 string
+	reverseConnectClients                []reverseConnectClient
+	discoveryRegistrations               []discoveryRegistration
+	discoveryInsecureSkipVerify          bool
+	registrationTimeout                  time.Duration
 	suppressCertificateExpired           bool
 	suppressCertificateChainIncomplete   bool
 	suppressCertificateRevocationUnknown bool
@@ -67,6 +74,9 @@ type Server struct {
 	trace                                bool
 	localCertificate                     []byte
 	localPrivateKey                      *rsa.PrivateKey
+	localKeyPair                         tls.Certificate
+	pendingPrivateKey                    *rsa.PrivateKey
+	pendingKeyPair                       *tls.Certificate
 	closing                              chan struct{}
 	state                                ua.ServerState
 	secondsTillShutdown                  uint32
@@ -75,6 +85,8 @@ type Server struct {
 	sessionManager                       *SessionManager
 	subscriptionManager                  *SubscriptionManager
 	namespaceManager                     *NamespaceManager
+	conditionManager                     *ConditionManager
+	discoveryManager                     *DiscoveryManager
 	serverUris                           []string
 	startTime                            time.Time
 	serverDiagnosticsSummary             *ua.ServerDiagnosticsSummaryDataType
@@ -120,6 +132,7 @@ func New(localDescription ua.ApplicationDescription, certPath, keyPath, endpoint
 		rolesProvider:                      DefaultRolesProvider,
 		rolePermissions:                    DefaultRolePermissions,
 		lastChannelID:                      mathrand.Uint32(),
+		registrationTimeout:                defaultRegistrationTimeout,
 	}
 
 	// apply each option to the default
@@ -129,24 +142,33 @@ func New(localDescription ua.ApplicationDescription, certPath, keyPath, endpoint
 		}
 	}
 
+	if srv.certificateStore == nil {
+		srv.certificateStore = ua.NewDirectoryCertificateStore(srv.trustedCertsPath, srv.trustedCRLsPath, srv.issuerCertsPath, srv.issuerCRLsPath, srv.rejectedCertsPath)
+	}
+	srv.certificateValidator = ua.NewCertificateValidator(srv.certificateStore)
+
 	srv.workerpool = workerpool.New(srv.maxWorkerThreads)
 	srv.sessionManager = NewSessionManager(srv)
 	srv.subscriptionManager = NewSubscriptionManager(srv)
 	srv.namespaceManager = NewNamespaceManager(srv)
 	srv.scheduler = NewScheduler(srv)
+	srv.discoveryManager = NewDiscoveryManager(srv)
 
 	cert, err := tls.LoadX509KeyPair(srv.certPath, srv.keyPath)
 	if err != nil {
 		log.Printf("Error loading x509 key pair. %s\n", err)
 		return nil, err
 	}
+	srv.localKeyPair = cert
 	srv.localCertificate = bytes.Join(cert.Certificate, []byte{})
 	srv.localPrivateKey, _ = cert.PrivateKey.(*rsa.PrivateKey)
+	srv.endpoints = srv.buildEndpointDescriptions()
 
 	if err := srv.initializeNamespace(); err != nil {
 		log.Printf("Error initializing namespace. %s\n", err)
 		return nil, err
 	}
+	srv.conditionManager = NewConditionManager(srv)
 	return srv, nil
 }
 
@@ -164,6 +186,18 @@ func (srv *Server) LocalCertificate() []byte {
 	return srv.localCertificate
 }
 
+// CertificateStore gets the store of the trusted and issuer certificates and revocation lists.
+func (srv *Server) CertificateStore() ua.CertificateStore {
+	return srv.certificateStore
+}
+
+// localCertificateAndKey gets the certificate and private key for the local application.
+func (srv *Server) localCertificateAndKey() ([]byte, *rsa.PrivateKey) {
+	srv.RLock()
+	defer srv.RUnlock()
+	return srv.localCertificate, srv.localPrivateKey
+}
+
 // EndpointURL gets the endpoint url.
 func (srv *Server) EndpointURL() string {
 	srv.RLock()
@@ -171,13 +205,17 @@ func (srv *Server) EndpointURL() string {
 	return srv.endpointURL
 }
 
+// WebSocketEndpointURL gets the endpoint url of the WebSocket transport, if any.
+func (srv *Server) WebSocketEndpointURL() string {
+	srv.RLock()
+	defer srv.RUnlock()
+	return srv.webSocketEndpointURL
+}
+
 // Endpoints gets the endpoint descriptions.
 func (srv *Server) Endpoints() []ua.EndpointDescription {
 	srv.RLock()
 	defer srv.RUnlock()
-	if srv.endpoints == nil {
-		srv.endpoints = srv.buildEndpointDescriptions()
-	}
 	return srv.endpoints
 }
 
@@ -244,6 +282,20 @@ func (srv *Server) SubscriptionManager() *SubscriptionManager {
 	return srv.subscriptionManager
 }
 
+// ConditionManager gets the condition manager.
+func (srv *Server) ConditionManager() *ConditionManager {
+	srv.RLock()
+	defer srv.RUnlock()
+	return srv.conditionManager
+}
+
+// DiscoveryManager gets the manager of the servers registered with a discovery server.
+func (srv *Server) DiscoveryManager() *DiscoveryManager {
+	srv.RLock()
+	defer srv.RUnlock()
+	return srv.discoveryManager
+}
+
 // Scheduler gets the polling scheduler.
 func (srv *Server) Scheduler() *Scheduler {
 	srv.RLock()
@@ -310,7 +362,51 @@ func (srv *Server) ListenAndServe() error {
 	}()
 
 	var wg sync.WaitGroup
-	err = srv.serve(ln, &wg)
+	if srv.webSocketEndpointURL != "" {
+		wsURL, err := url.Parse(srv.webSocketEndpointURL)
+		if err != nil {
+			ln.Close()
+			return ua.BadTCPEndpointURLInvalid
+		}
+		wsPort := wsURL.Port()
+		if wsPort == "" {
+			wsPort = "443"
+		}
+		// get the certificate for each connection, so an updated certificate is used by new connections.
+		wsln, err := ua.ListenWebSocket(":"+wsPort, &tls.Config{GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			srv.RLock()
+			defer srv.RUnlock()
+			keyPair := srv.localKeyPair
+			return &keyPair, nil
+		}})
+		if err != nil {
+			ln.Close()
+			return ua.BadResourceUnavailable
+		}
+
+		go func() {
+			<-srv.closing
+			wsln.Close()
+		}()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			srv.serve(wsln, ua.TransportProfileURIWssTransport, &wg)
+		}()
+	}
+
+	for _, c := range srv.reverseConnectClients {
+		wg.Add(1)
+		go srv.reverseConnect(c.clientURL, c.interval, &wg)
+	}
+
+	for _, r := range srv.discoveryRegistrations {
+		wg.Add(1)
+		go srv.registerWithDiscoveryServer(r.discoveryURL, r.interval, &wg)
+	}
+
+	err = srv.serve(ln, ua.TransportProfileURIUaTcpTransport, &wg)
 
 	// wait until channels closed
 	wg.Wait()
@@ -349,7 +445,7 @@ func (srv *Server) GetRoles(userIdentity any, applicationURI string, endpointURL
 	return srv.rolesProvider.GetRoles(userIdentity, applicationURI, endpointURL)
 }
 
-func (srv *Server) serve(ln net.Listener, wg *sync.WaitGroup) error {
+func (srv *Server) serve(ln net.Listener, transportProfileURI string, wg *sync.WaitGroup) error {
 	var delay time.Duration
 	for {
 		conn, err := ln.Accept()
@@ -374,15 +470,15 @@ func (srv *Server) serve(ln net.Listener, wg *sync.WaitGroup) error {
 		}
 		delay = 0
 		wg.Add(1)
-		go srv.handleConnection(conn, wg)
+		go srv.handleConnection(conn, transportProfileURI, wg)
 	}
 }
 
 // handleConnection wraps conn in ServerSecureChannel and handles service requests.
-func (srv *Server) handleConnection(conn net.Conn, wg *sync.WaitGroup) {
+func (srv *Server) handleConnection(conn net.Conn, transportProfileURI string, wg *sync.WaitGroup) {
 	defer conn.Close()
 	defer wg.Done()
-	ch := newServerSecureChannel(srv, conn, srv.trace)
+	ch := newServerSecureChannel(srv, conn, transportProfileURI, srv.trace)
 	err := ch.Open()
 	if err != nil {
 		// log.Printf("Error opening secure channel. %s\n", err)
@@ -449,6 +545,8 @@ func (srv *Server) handleRequest(ch *serverSecureChannel, req ua.ServiceRequest,
 		return srv.handleModifySubscription(ch, requestid, req)
 	case *ua.SetPublishingModeRequest:
 		return srv.handleSetPublishingMode(ch, requestid, req)
+	case *ua.TransferSubscriptionsRequest:
+		return srv.handleTransferSubscriptions(ch, requestid, req)
 	case *ua.DeleteSubscriptionsRequest:
 		return srv.handleDeleteSubscriptions(ch, requestid, req)
 	case *ua.CreateMonitoredItemsRequest:
@@ -461,6 +559,8 @@ func (srv *Server) handleRequest(ch *serverSecureChannel, req ua.ServiceRequest,
 		return srv.handleDeleteMonitoredItems(ch, requestid, req)
 	case *ua.HistoryReadRequest:
 		return srv.handleHistoryRead(ch, requestid, req)
+	case *ua.HistoryUpdateRequest:
+		return srv.handleHistoryUpdate(ch, requestid, req)
 	case *ua.CreateSessionRequest:
 		return srv.handleCreateSession(ch, requestid, req)
 	case *ua.ActivateSessionRequest:
@@ -475,6 +575,20 @@ func (srv *Server) handleRequest(ch *serverSecureChannel, req ua.ServiceRequest,
 		return srv.findServers(ch, requestid, req)
 	case *ua.GetEndpointsRequest:
 		return srv.getEndpoints(ch, requestid, req)
+	case *ua.FindServersOnNetworkRequest:
+		return srv.findServersOnNetwork(ch, requestid, req)
+	case *ua.RegisterServerRequest:
+		return srv.registerServer(ch, requestid, req)
+	case *ua.RegisterServer2Request:
+		return srv.registerServer2(ch, requestid, req)
+	case *ua.AddNodesRequest:
+		return srv.handleAddNodes(ch, requestid, req)
+	case *ua.AddReferencesRequest:
+		return srv.handleAddReferences(ch, requestid, req)
+	case *ua.DeleteNodesRequest:
+		return srv.handleDeleteNodes(ch, requestid, req)
+	case *ua.DeleteReferencesRequest:
+		return srv.handleDeleteReferences(ch, requestid, req)
 	case *ua.RegisterNodesRequest:
 		return srv.handleRegisterNodes(ch, requestid, req)
 	case *ua.UnregisterNodesRequest:
@@ -876,7 +990,7 @@ func (srv *Server) initializeNamespace() error {
 			if !ok {
 				return ua.CallMethodResult{StatusCode: ua.BadSubscriptionIDInvalid}
 			}
-			if session == nil || sub.session != session {
+			if session == nil || sub.session.Load() != session {
 				return ua.CallMethodResult{StatusCode: ua.BadUserAccessDenied}
 			}
 			svrHandles := []uint32{}
@@ -911,7 +1025,7 @@ func (srv *Server) initializeNamespace() error {
 			if !ok {
 				return ua.CallMethodResult{StatusCode: ua.BadSubscriptionIDInvalid}
 			}
-			if session == nil || sub.session != session {
+			if session == nil || sub.session.Load() != session {
 				return ua.CallMethodResult{StatusCode: ua.BadUserAccessDenied}
 			}
 			svrHandles := []uint32{}
@@ -946,17 +1060,27 @@ func (srv *Server) initializeNamespace() error {
 			if !ok {
 				return ua.CallMethodResult{StatusCode: ua.BadSubscriptionIDInvalid}
 			}
-			if session == nil || sub.session != session {
+			if session == nil || sub.session.Load() != session {
 				return ua.CallMethodResult{StatusCode: ua.BadUserAccessDenied}
 			}
 			sub.resendData()
 			return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
 		})
 	}
+	srv.initializeServerConfiguration()
 	return nil
 }
 
 func (srv *Server) buildEndpointDescriptions() []ua.EndpointDescription {
+	eds := srv.buildTransportEndpointDescriptions(srv.endpointURL, ua.TransportProfileURIUaTcpTransport)
+	if srv.webSocketEndpointURL != "" {
+		eds = append(eds, srv.buildTransportEndpointDescriptions(srv.webSocketEndpointURL, ua.TransportProfileURIWssTransport)...)
+	}
+	return eds
+}
+
+// buildTransportEndpointDescriptions builds the endpoint descriptions for the endpoint url of a transport.
+func (srv *Server) buildTransportEndpointDescriptions(endpointURL, transportProfileURI string) []ua.EndpointDescription {
 	eds := []ua.EndpointDescription{}
 	if srv.allowSecurityPolicyNone {
 		toks := []ua.UserTokenPolicy{}
@@ -982,12 +1106,12 @@ func (srv *Server) buildEndpointDescriptions() []ua.EndpointDescription {
 			})
 		}
 		eds = append(eds, ua.EndpointDescription{
-			EndpointURL:         srv.endpointURL,
+			EndpointURL:         endpointURL,
 			Server:              srv.localDescription,
-			ServerCertificate:   ua.ByteString(srv.LocalCertificate()),
+			ServerCertificate:   ua.ByteString(srv.localCertificate),
 			SecurityMode:        ua.MessageSecurityModeNone,
 			SecurityPolicyURI:   ua.SecurityPolicyURINone,
-			TransportProfileURI: ua.TransportProfileURIUaTcpTransport,
+			TransportProfileURI: transportProfileURI,
 			SecurityLevel:       byte(len(eds)),
 			UserIdentityTokens:  toks,
 		})
@@ -1024,12 +1148,12 @@ func (srv *Server) buildEndpointDescriptions() []ua.EndpointDescription {
 			})
 		}
 		eds = append(eds, ua.EndpointDescription{
-			EndpointURL:         srv.endpointURL,
+			EndpointURL:         endpointURL,
 			Server:              srv.localDescription,
-			ServerCertificate:   ua.ByteString(srv.LocalCertificate()),
+			ServerCertificate:   ua.ByteString(srv.localCertificate),
 			SecurityMode:        ua.MessageSecurityModeSign,
 			SecurityPolicyURI:   uri,
-			TransportProfileURI: ua.TransportProfileURIUaTcpTransport,
+			TransportProfileURI: transportProfileURI,
 			SecurityLevel:       byte(len(eds)),
 			UserIdentityTokens:  toks,
 		})
@@ -1058,12 +1182,12 @@ func (srv *Server) buildEndpointDescriptions() []ua.EndpointDescription {
 			})
 		}
 		eds = append(eds, ua.EndpointDescription{
-			EndpointURL:         srv.endpointURL,
+			EndpointURL:         endpointURL,
 			Server:              srv.localDescription,
-			ServerCertificate:   ua.ByteString(srv.LocalCertificate()),
+			ServerCertificate:   ua.ByteString(srv.localCertificate),
 			SecurityMode:        ua.MessageSecurityModeSignAndEncrypt,
 			SecurityPolicyURI:   uri,
-			TransportProfileURI: ua.TransportProfileURIUaTcpTransport,
+			TransportProfileURI: transportProfileURI,
 			SecurityLevel:       byte(len(eds)),
 			UserIdentityTokens:  toks,
 		})
@@ -0,0 +1,503 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// initializeServerConfiguration installs the methods of the ServerConfiguration object, which a
+// GDS or admin client calls to push a new application certificate to the server.
+// See https://reference.opcfoundation.org/v104/GDS/docs/7.10/
+func (srv *Server) initializeServerConfiguration() {
+	nm := srv.NamespaceManager()
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationServerCapabilities); ok {
+		capabilities := []string{"DA"}
+		if srv.historian != nil {
+			capabilities = append(capabilities, "HD")
+		}
+		n.SetValue(ua.NewDataValue(capabilities, 0, time.Now(), 0, time.Now(), 0))
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationSupportedPrivateKeyFormats); ok {
+		n.SetValue(ua.NewDataValue([]string{"PEM"}, 0, time.Now(), 0, time.Now(), 0))
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationMaxTrustListSize); ok {
+		n.SetValue(ua.NewDataValue(uint32(0), 0, time.Now(), 0, time.Now(), 0))
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationMulticastDNSEnabled); ok {
+		n.SetValue(ua.NewDataValue(false, 0, time.Now(), 0, time.Now(), 0))
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationCertificateGroupsDefaultApplicationGroupCertificateTypes); ok {
+		n.SetValue(ua.NewDataValue([]ua.NodeID{ua.ObjectTypeIDRsaSha256ApplicationCertificateType}, 0, time.Now(), 0, time.Now(), 0))
+	}
+
+	// only the SecurityAdmin role may call the methods.
+	rolePermissions := srv.securityAdminRolePermissions()
+	for _, id := range []ua.NodeID{
+		ua.MethodIDServerConfigurationCreateSigningRequest,
+		ua.MethodIDServerConfigurationUpdateCertificate,
+		ua.MethodIDServerConfigurationApplyChanges,
+		ua.MethodIDServerConfigurationGetRejectedList,
+	} {
+		if n, ok := nm.FindMethod(id); ok {
+			n.rolePermissions = rolePermissions
+		}
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationCreateSigningRequest); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) < 5 {
+				return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+			}
+			if len(req.InputArguments) > 5 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			opResult := ua.Good
+			argsResults := make([]ua.StatusCode, 5)
+			groupID, ok := req.InputArguments[0].(ua.NodeID)
+			if !ok && req.InputArguments[0] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[0] = ua.BadTypeMismatch
+			}
+			typeID, ok := req.InputArguments[1].(ua.NodeID)
+			if !ok && req.InputArguments[1] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[1] = ua.BadTypeMismatch
+			}
+			subjectName, ok := req.InputArguments[2].(string)
+			if !ok && req.InputArguments[2] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[2] = ua.BadTypeMismatch
+			}
+			regeneratePrivateKey, ok := req.InputArguments[3].(bool)
+			if !ok {
+				opResult = ua.BadInvalidArgument
+				argsResults[3] = ua.BadTypeMismatch
+			}
+			if _, ok := req.InputArguments[4].(ua.ByteString); !ok && req.InputArguments[4] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[4] = ua.BadTypeMismatch
+			}
+			if opResult == ua.BadInvalidArgument {
+				return ua.CallMethodResult{StatusCode: opResult, InputArgumentResults: argsResults}
+			}
+			if !isDefaultApplicationGroup(groupID) || !isApplicationCertificateType(typeID) {
+				return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument}
+			}
+			csr, err := srv.createSigningRequest(subjectName, regeneratePrivateKey)
+			if err != nil {
+				return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument}
+			}
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{ua.ByteString(csr)}}
+		})
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationUpdateCertificate); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) < 6 {
+				return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+			}
+			if len(req.InputArguments) > 6 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			opResult := ua.Good
+			argsResults := make([]ua.StatusCode, 6)
+			groupID, ok := req.InputArguments[0].(ua.NodeID)
+			if !ok && req.InputArguments[0] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[0] = ua.BadTypeMismatch
+			}
+			typeID, ok := req.InputArguments[1].(ua.NodeID)
+			if !ok && req.InputArguments[1] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[1] = ua.BadTypeMismatch
+			}
+			certificate, ok := req.InputArguments[2].(ua.ByteString)
+			if !ok {
+				opResult = ua.BadInvalidArgument
+				argsResults[2] = ua.BadTypeMismatch
+			}
+			issuerCertificates, ok := req.InputArguments[3].([]ua.ByteString)
+			if !ok && req.InputArguments[3] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[3] = ua.BadTypeMismatch
+			}
+			privateKeyFormat, ok := req.InputArguments[4].(string)
+			if !ok && req.InputArguments[4] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[4] = ua.BadTypeMismatch
+			}
+			privateKey, ok := req.InputArguments[5].(ua.ByteString)
+			if !ok && req.InputArguments[5] != nil {
+				opResult = ua.BadInvalidArgument
+				argsResults[5] = ua.BadTypeMismatch
+			}
+			if opResult == ua.BadInvalidArgument {
+				return ua.CallMethodResult{StatusCode: opResult, InputArgumentResults: argsResults}
+			}
+			if !isDefaultApplicationGroup(groupID) || !isApplicationCertificateType(typeID) {
+				return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument}
+			}
+			if err := srv.updateCertificate([]byte(certificate), issuerCertificates, privateKeyFormat, []byte(privateKey)); err != nil {
+				if code, ok := err.(ua.StatusCode); ok {
+					return ua.CallMethodResult{StatusCode: code}
+				}
+				return ua.CallMethodResult{StatusCode: ua.BadCertificateInvalid}
+			}
+			// the certificate is used after ApplyChanges is called.
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{true}}
+		})
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationApplyChanges); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) > 0 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			if err := srv.applyChanges(); err != nil {
+				log.Printf("Error applying changes to server configuration. %s\n", err)
+				return ua.CallMethodResult{StatusCode: ua.BadConfigurationError}
+			}
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+		})
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationGetRejectedList); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) > 0 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{srv.rejectedCertificates()}}
+		})
+	}
+
+	srv.initializeTrustList()
+}
+
+// checkSecurityAdmin returns Good if the session is encrypted, and the user has the SecurityAdmin role.
+func (srv *Server) checkSecurityAdmin(session *Session) ua.StatusCode {
+	if session == nil {
+		return ua.BadUserAccessDenied
+	}
+	if session.SecurityMode() != ua.MessageSecurityModeSignAndEncrypt {
+		return ua.BadSecurityModeInsufficient
+	}
+	roles, err := srv.GetRoles(session.UserIdentity(), "", "")
+	if err != nil {
+		return ua.BadUserAccessDenied
+	}
+	for _, role := range roles {
+		if role == ua.ObjectIDWellKnownRoleSecurityAdmin {
+			return ua.Good
+		}
+	}
+	return ua.BadUserAccessDenied
+}
+
+// securityAdminRolePermissions returns the role permissions of the server, where only the SecurityAdmin role has permission to call.
+func (srv *Server) securityAdminRolePermissions() []ua.RolePermissionType {
+	rolePermissions := []ua.RolePermissionType{}
+	found := false
+	for _, rp := range srv.RolePermissions() {
+		if rp.RoleID == ua.ObjectIDWellKnownRoleSecurityAdmin {
+			rp.Permissions |= ua.PermissionTypeCall
+			found = true
+		} else {
+			rp.Permissions &^= ua.PermissionTypeCall
+		}
+		rolePermissions = append(rolePermissions, rp)
+	}
+	if !found {
+		rolePermissions = append(rolePermissions, ua.RolePermissionType{RoleID: ua.ObjectIDWellKnownRoleSecurityAdmin, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeCall})
+	}
+	return rolePermissions
+}
+
+// createSigningRequest returns a PKCS #10 certificate signing request for the application certificate.
+// If subjectName is empty, the subject of the current certificate is used. If regeneratePrivateKey
+// is true, the request is signed with a new private key, which is used by a later UpdateCertificate.
+func (srv *Server) createSigningRequest(subjectName string, regeneratePrivateKey bool) ([]byte, error) {
+	srv.Lock()
+	defer srv.Unlock()
+	current := srv.localKeyPair.Leaf
+	if current == nil {
+		crt, err := x509.ParseCertificate(srv.localKeyPair.Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+		current = crt
+	}
+	subject := current.Subject
+	if subjectName != "" {
+		name, err := parseSubjectName(subjectName)
+		if err != nil {
+			return nil, err
+		}
+		subject = name
+	}
+	key := srv.localPrivateKey
+	if regeneratePrivateKey {
+		newKey, err := rsa.GenerateKey(rand.Reader, srv.localPrivateKey.N.BitLen())
+		if err != nil {
+			return nil, err
+		}
+		key = newKey
+		srv.pendingPrivateKey = newKey
+	}
+	template := &x509.CertificateRequest{
+		Subject:            subject,
+		SignatureAlgorithm: x509.SHA256WithRSA,
+		DNSNames:           current.DNSNames,
+		IPAddresses:        current.IPAddresses,
+		URIs:               current.URIs,
+	}
+	return x509.CreateCertificateRequest(rand.Reader, template, key)
+}
+
+// updateCertificate stages a new application certificate, until ApplyChanges is called. If privateKey
+// is empty, the certificate must match the private key of the last signing request, or the current private key.
+// The certificate must pass the same validation as the certificates of remote applications.
+func (srv *Server) updateCertificate(certificate []byte, issuerCertificates []ua.ByteString, privateKeyFormat string, privateKey []byte) error {
+	crt, err := x509.ParseCertificate(certificate)
+	if err != nil {
+		return ua.BadCertificateInvalid
+	}
+	chain := [][]byte{crt.Raw}
+	certs := []*x509.Certificate{crt}
+	for _, issuer := range issuerCertificates {
+		c, err := x509.ParseCertificate([]byte(issuer))
+		if err != nil {
+			return ua.BadCertificateInvalid
+		}
+		chain = append(chain, []byte(issuer))
+		certs = append(certs, c)
+	}
+	now := time.Now()
+	if now.Before(crt.NotBefore) || now.After(crt.NotAfter) {
+		return ua.BadCertificateTimeInvalid
+	}
+	applicationURI := srv.LocalDescription().ApplicationURI
+	valid := false
+	for _, uri := range crt.URIs {
+		if uri.String() == applicationURI {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ua.BadCertificateURIInvalid
+	}
+	// the chain is validated with the issuer certificates, and the trusted and issuer lists of the store.
+	if err := srv.certificateValidator.Validate(
+		certs,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		"",
+		true,
+		srv.suppressCertificateExpired,
+		srv.suppressCertificateChainIncomplete,
+		srv.suppressCertificateRevocationUnknown,
+	); err != nil {
+		return err
+	}
+
+	srv.Lock()
+	defer srv.Unlock()
+	key := srv.localPrivateKey
+	if srv.pendingPrivateKey != nil {
+		key = srv.pendingPrivateKey
+	}
+	if len(privateKey) > 0 {
+		if !strings.EqualFold(privateKeyFormat, "PEM") {
+			return ua.BadNotSupported
+		}
+		key, err = parsePrivateKey(privateKey)
+		if err != nil {
+			return ua.BadSecurityChecksFailed
+		}
+	}
+	if pub, ok := crt.PublicKey.(*rsa.PublicKey); !ok || !pub.Equal(&key.PublicKey) {
+		return ua.BadSecurityChecksFailed
+	}
+	srv.pendingKeyPair = &tls.Certificate{Certificate: chain, PrivateKey: key, Leaf: crt}
+	return nil
+}
+
+// applyChanges stores the staged certificate and private key, then swaps them in. New secure channels
+// use the new certificate, while existing secure channels continue with the certificate they opened with.
+func (srv *Server) applyChanges() error {
+	srv.Lock()
+	defer srv.Unlock()
+	keyPair := srv.pendingKeyPair
+	if keyPair == nil {
+		return nil
+	}
+	key := keyPair.PrivateKey.(*rsa.PrivateKey)
+	// write both files before replacing either, so a failure cannot leave a certificate that does not match the key.
+	certTemp, err := writeTempPEM(srv.certPath, "CERTIFICATE", keyPair.Certificate...)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(certTemp)
+	keyTemp, err := writeTempPEM(srv.keyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(keyTemp)
+	if err := os.Rename(keyTemp, srv.keyPath); err != nil {
+		return err
+	}
+	if err := os.Rename(certTemp, srv.certPath); err != nil {
+		// restore the private key of the current certificate.
+		if srv.localPrivateKey != nil {
+			if keyTemp, err := writeTempPEM(srv.keyPath, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(srv.localPrivateKey)); err == nil {
+				if os.Rename(keyTemp, srv.keyPath) != nil {
+					os.Remove(keyTemp)
+				}
+			}
+		}
+		return err
+	}
+	srv.localKeyPair = *keyPair
+	srv.localCertificate = bytes.Join(keyPair.Certificate, []byte{})
+	srv.localPrivateKey = key
+	srv.pendingKeyPair = nil
+	srv.pendingPrivateKey = nil
+	srv.endpoints = srv.buildEndpointDescriptions()
+	return nil
+}
+
+// rejectedCertificates returns the certificates found in the rejected certificates of the store.
+func (srv *Server) rejectedCertificates() []ua.ByteString {
+	certs := []ua.ByteString{}
+	ders, err := srv.certificateStore.Rejected()
+	if err != nil {
+		return certs
+	}
+	for _, der := range ders {
+		certs = append(certs, ua.ByteString(der))
+	}
+	return certs
+}
+
+// isDefaultApplicationGroup returns true if the id is null or the DefaultApplicationGroup.
+func isDefaultApplicationGroup(id ua.NodeID) bool {
+	return id == nil || id == ua.NodeIDNumeric{} || id == ua.ObjectIDServerConfigurationCertificateGroupsDefaultApplicationGroup
+}
+
+// isApplicationCertificateType returns true if the id is null or a supported application certificate type.
+func isApplicationCertificateType(id ua.NodeID) bool {
+	switch id {
+	case nil, ua.NodeIDNumeric{}, ua.ObjectTypeIDApplicationCertificateType, ua.ObjectTypeIDRsaMinApplicationCertificateType, ua.ObjectTypeIDRsaSha256ApplicationCertificateType:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseSubjectName parses a subject name in the form 'CN=name,O=organization,DC=host'.
+// Fields may also be separated by '/'.
+func parseSubjectName(s string) (pkix.Name, error) {
+	name := pkix.Name{}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '/' })
+	if len(fields) == 0 {
+		return name, ua.BadInvalidArgument
+	}
+	for _, field := range fields {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok {
+			return name, ua.BadInvalidArgument
+		}
+		v = strings.Trim(strings.TrimSpace(v), "\"")
+		switch strings.ToUpper(strings.TrimSpace(k)) {
+		case "CN":
+			name.CommonName = v
+		case "O":
+			name.Organization = append(name.Organization, v)
+		case "OU":
+			name.OrganizationalUnit = append(name.OrganizationalUnit, v)
+		case "L":
+			name.Locality = append(name.Locality, v)
+		case "S", "ST":
+			name.Province = append(name.Province, v)
+		case "C":
+			name.Country = append(name.Country, v)
+		case "DC":
+			name.ExtraNames = append(name.ExtraNames, pkix.AttributeTypeAndValue{Type: oidDomainComponent, Value: v})
+		default:
+			return name, ua.BadInvalidArgument
+		}
+	}
+	return name, nil
+}
+
+// oidDomainComponent is the object identifier of the domainComponent attribute.
+var oidDomainComponent = asn1.ObjectIdentifier{0, 9, 2342, 19200300, 100, 1, 25}
+
+// parsePrivateKey parses a PEM encoded RSA private key, in PKCS #1 or PKCS #8 form.
+func parsePrivateKey(buf []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return nil, ua.BadSecurityChecksFailed
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := key.(*rsa.PrivateKey); ok {
+		return key, nil
+	}
+	return nil, ua.BadSecurityChecksFailed
+}
+
+// writeTempPEM writes the blocks to a temporary PEM file in the directory of path, and returns its name.
+func writeTempPEM(path, blockType string, blocks ...[]byte) (string, error) {
+	var buf bytes.Buffer
+	for _, b := range blocks {
+		if err := pem.Encode(&buf, &pem.Block{Type: blockType, Bytes: b}); err != nil {
+			return "", err
+		}
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
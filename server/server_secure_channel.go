@@ -95,14 +95,16 @@ type serverSecureChannel struct {
 	maxRequestMessageSize  uint32
 	maxRequestChunkCount   uint32
 	endpointURL            string
+	transportProfileURI    string
 	conn                   net.Conn
 }
 
 // newServerSecureChannel initializes a new instance of the UaTcpSecureChannel.
-func newServerSecureChannel(srv *Server, conn net.Conn, trace bool) *serverSecureChannel {
+func newServerSecureChannel(srv *Server, conn net.Conn, transportProfileURI string, trace bool) *serverSecureChannel {
 	ch := &serverSecureChannel{
 		srv:                   srv,
 		conn:                  conn,
+		transportProfileURI:   transportProfileURI,
 		receiveBufferSize:     srv.maxBufferSize,
 		sendBufferSize:        srv.maxBufferSize,
 		maxRequestMessageSize: srv.maxMessageSize,
@@ -113,10 +115,10 @@ func newServerSecureChannel(srv *Server, conn net.Conn, trace bool) *serverSecur
 		channelID:             srv.getNextChannelID(),
 		securityPolicyURI:     ua.SecurityPolicyURINone,
 		securityPolicy:        new(ua.SecurityPolicyNone),
-		localCertificate:      srv.localCertificate,
-		localPrivateKey:       srv.localPrivateKey,
 		tokenExpiration:       time.Now().Add(20 * time.Second),
 	}
+	// the channel keeps the certificate of the server when opened, until closed.
+	ch.localCertificate, ch.localPrivateKey = srv.localCertificateAndKey()
 	return ch
 }
 
@@ -286,7 +288,7 @@ func (ch *serverSecureChannel) Open() error {
 	}
 	ch.remoteNonce = []byte(oscr.ClientNonce)
 	for _, ep := range ch.srv.Endpoints() {
-		if ep.TransportProfileURI == ua.TransportProfileURIUaTcpTransport && ep.SecurityPolicyURI == ch.securityPolicyURI && ep.SecurityMode == ch.securityMode {
+		if ep.TransportProfileURI == ch.transportProfileURI && ep.SecurityPolicyURI == ch.securityPolicyURI && ep.SecurityMode == ch.securityMode {
 			ch.localEndpoint = ep
 			break
 		}
@@ -306,7 +308,7 @@ func (ch *serverSecureChannel) Open() error {
 					SecurityPolicyURI: ua.SecurityPolicyURINone,
 				},
 			},
-			TransportProfileURI: ua.TransportProfileURIUaTcpTransport,
+			TransportProfileURI: ch.transportProfileURI,
 			SecurityLevel:       0,
 		}
 	}
@@ -320,15 +322,10 @@ func (ch *serverSecureChannel) Open() error {
 		if err != nil {
 			return ua.BadSecurityChecksFailed
 		}
-		err = ua.ValidateCertificate(
+		err = ch.srv.certificateValidator.Validate(
 			certs,
 			[]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 			"",
-			ch.srv.trustedCertsPath,
-			ch.srv.trustedCRLsPath,
-			ch.srv.issuerCertsPath,
-			ch.srv.issuerCRLsPath,
-			ch.srv.rejectedCertsPath,
 			false,
 			ch.srv.suppressCertificateExpired,
 			ch.srv.suppressCertificateChainIncomplete,
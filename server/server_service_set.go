@@ -26,19 +26,7 @@ import (
 
 // FindServers returns the Servers known to a Server or Discovery Server.
 func (srv *Server) findServers(ch *serverSecureChannel, requestid uint32, req *ua.FindServersRequest) error {
-	srvs := make([]ua.ApplicationDescription, 0, 1)
-	for _, s := range []ua.ApplicationDescription{srv.LocalDescription()} {
-		if len(req.ServerURIs) > 0 {
-			for _, su := range req.ServerURIs {
-				if s.ApplicationURI == su {
-					srvs = append(srvs, s)
-					break
-				}
-			}
-		} else {
-			srvs = append(srvs, s)
-		}
-	}
+	srvs := srv.DiscoveryManager().FindServers(req.ServerURIs, req.LocaleIDs)
 	err := ch.Write(
 		&ua.FindServersResponse{
 			ResponseHeader: ua.ResponseHeader{
@@ -55,6 +43,162 @@ func (srv *Server) findServers(ch *serverSecureChannel, requestid uint32, req *u
 	return nil
 }
 
+// findServersOnNetwork returns the servers registered with the discovery server.
+func (srv *Server) findServersOnNetwork(ch *serverSecureChannel, requestid uint32, req *ua.FindServersOnNetworkRequest) error {
+	if srv.LocalDescription().ApplicationType != ua.ApplicationTypeDiscoveryServer {
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadServiceUnsupported,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	records, resetTime := srv.DiscoveryManager().FindServersOnNetwork(req.StartingRecordID, req.MaxRecordsToReturn, req.ServerCapabilityFilter)
+	err := ch.Write(
+		&ua.FindServersOnNetworkResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHandle,
+			},
+			LastCounterResetTime: resetTime,
+			Servers:              records,
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// registerServer registers a server with the discovery server.
+func (srv *Server) registerServer(ch *serverSecureChannel, requestid uint32, req *ua.RegisterServerRequest) error {
+	if err := srv.checkRegistration(ch, req.Server); err != nil {
+		err = ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: err.(ua.StatusCode),
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if _, err := srv.DiscoveryManager().RegisterServer(req.Server, nil); err != nil {
+		err = ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: err.(ua.StatusCode),
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	err := ch.Write(
+		&ua.RegisterServerResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHandle,
+			},
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// registerServer2 registers a server with the discovery server, with the discovery configuration.
+func (srv *Server) registerServer2(ch *serverSecureChannel, requestid uint32, req *ua.RegisterServer2Request) error {
+	if err := srv.checkRegistration(ch, req.Server); err != nil {
+		err = ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: err.(ua.StatusCode),
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	results, err := srv.DiscoveryManager().RegisterServer(req.Server, req.DiscoveryConfiguration)
+	if err != nil {
+		err = ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: err.(ua.StatusCode),
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	err = ch.Write(
+		&ua.RegisterServer2Response{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHandle,
+			},
+			ConfigurationResults: results,
+			DiagnosticInfos:      []ua.DiagnosticInfo{},
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkRegistration checks that the server is a discovery server, and the registering server
+// signed the channel with a certificate that has the ServerUri.
+func (srv *Server) checkRegistration(ch *serverSecureChannel, server ua.RegisteredServer) error {
+	if srv.LocalDescription().ApplicationType != ua.ApplicationTypeDiscoveryServer {
+		return ua.BadServiceUnsupported
+	}
+	if ch.SecurityMode() != ua.MessageSecurityModeSign && ch.SecurityMode() != ua.MessageSecurityModeSignAndEncrypt {
+		return ua.BadSecurityModeInsufficient
+	}
+	if crts, err := x509.ParseCertificates(ch.RemoteCertificate()); err == nil && len(crts) > 0 {
+		for _, crturi := range crts[0].URIs {
+			if crturi.String() == server.ServerURI {
+				return nil
+			}
+		}
+	}
+	return ua.BadServerURIInvalid
+}
+
 // GetEndpoints returns the endpoint descriptions supported by the server.
 func (srv *Server) getEndpoints(ch *serverSecureChannel, requestid uint32, req *ua.GetEndpointsRequest) error {
 	eps := make([]ua.EndpointDescription, 0, len(srv.Endpoints()))
@@ -164,7 +308,7 @@ func (srv *Server) handleCreateSession(ch *serverSecureChannel, requestid uint32
 		hash.Write([]byte(req.ClientCertificate))
 		hash.Write([]byte(req.ClientNonce))
 		hashed := hash.Sum(nil)
-		signature, err := rsa.SignPKCS1v15(rand.Reader, srv.localPrivateKey, crypto.SHA1, hashed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, ch.localPrivateKey, crypto.SHA1, hashed)
 		if err != nil {
 			return err
 		}
@@ -178,7 +322,7 @@ func (srv *Server) handleCreateSession(ch *serverSecureChannel, requestid uint32
 		hash.Write([]byte(req.ClientCertificate))
 		hash.Write([]byte(req.ClientNonce))
 		hashed := hash.Sum(nil)
-		signature, err := rsa.SignPKCS1v15(rand.Reader, srv.localPrivateKey, crypto.SHA256, hashed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, ch.localPrivateKey, crypto.SHA256, hashed)
 		if err != nil {
 			return err
 		}
@@ -192,7 +336,7 @@ func (srv *Server) handleCreateSession(ch *serverSecureChannel, requestid uint32
 		hash.Write([]byte(req.ClientCertificate))
 		hash.Write([]byte(req.ClientNonce))
 		hashed := hash.Sum(nil)
-		signature, err := rsa.SignPSS(rand.Reader, srv.localPrivateKey, crypto.SHA256, hashed, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		signature, err := rsa.SignPSS(rand.Reader, ch.localPrivateKey, crypto.SHA256, hashed, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
 		if err != nil {
 			return err
 		}
@@ -260,7 +404,7 @@ func (srv *Server) handleCreateSession(ch *serverSecureChannel, requestid uint32
 			AuthenticationToken:        session.authenticationToken,
 			RevisedSessionTimeout:      session.timeout,
 			ServerNonce:                session.sessionNonce,
-			ServerCertificate:          ua.ByteString(srv.LocalCertificate()),
+			ServerCertificate:          ua.ByteString(ch.localCertificate),
 			ServerEndpoints:            srv.Endpoints(),
 			ServerSoftwareCertificates: nil,
 			ServerSignature:            serverSignature,
@@ -312,21 +456,21 @@ func (srv *Server) handleActivateSession(ch *serverSecureChannel, requestid uint
 	switch ch.SecurityPolicyURI() {
 	case ua.SecurityPolicyURIBasic128Rsa15, ua.SecurityPolicyURIBasic256:
 		hash := crypto.SHA1.New()
-		hash.Write(srv.LocalCertificate())
+		hash.Write(ch.localCertificate)
 		hash.Write([]byte(session.SessionNonce()))
 		hashed := hash.Sum(nil)
 		err = rsa.VerifyPKCS1v15(ch.RemotePublicKey(), crypto.SHA1, hashed, []byte(req.ClientSignature.Signature))
 
 	case ua.SecurityPolicyURIBasic256Sha256, ua.SecurityPolicyURIAes128Sha256RsaOaep:
 		hash := crypto.SHA256.New()
-		hash.Write(srv.LocalCertificate())
+		hash.Write(ch.localCertificate)
 		hash.Write([]byte(session.SessionNonce()))
 		hashed := hash.Sum(nil)
 		err = rsa.VerifyPKCS1v15(ch.RemotePublicKey(), crypto.SHA256, hashed, []byte(req.ClientSignature.Signature))
 
 	case ua.SecurityPolicyURIAes256Sha256RsaPss:
 		hash := crypto.SHA256.New()
-		hash.Write(srv.LocalCertificate())
+		hash.Write(ch.localCertificate)
 		hash.Write([]byte(session.SessionNonce()))
 		hashed := hash.Sum(nil)
 		err = rsa.VerifyPSS(ch.RemotePublicKey(), crypto.SHA256, hashed, []byte(req.ClientSignature.Signature), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
@@ -464,21 +608,21 @@ func (srv *Server) handleActivateSession(ch *serverSecureChannel, requestid uint
 		switch secPolicyURI {
 		case ua.SecurityPolicyURIBasic128Rsa15, ua.SecurityPolicyURIBasic256:
 			hash := crypto.SHA1.New()
-			hash.Write(srv.LocalCertificate())
+			hash.Write(ch.localCertificate)
 			hash.Write([]byte(session.SessionNonce()))
 			hashed := hash.Sum(nil)
 			err = rsa.VerifyPKCS1v15(userKey, crypto.SHA1, hashed, []byte(req.UserTokenSignature.Signature))
 
 		case ua.SecurityPolicyURIBasic256Sha256, ua.SecurityPolicyURIAes128Sha256RsaOaep:
 			hash := crypto.SHA256.New()
-			hash.Write(srv.LocalCertificate())
+			hash.Write(ch.localCertificate)
 			hash.Write([]byte(session.SessionNonce()))
 			hashed := hash.Sum(nil)
 			err = rsa.VerifyPKCS1v15(userKey, crypto.SHA256, hashed, []byte(req.UserTokenSignature.Signature))
 
 		case ua.SecurityPolicyURIAes256Sha256RsaPss:
 			hash := crypto.SHA256.New()
-			hash.Write(srv.LocalCertificate())
+			hash.Write(ch.localCertificate)
 			hash.Write([]byte(session.SessionNonce()))
 			hashed := hash.Sum(nil)
 			err = rsa.VerifyPSS(userKey, crypto.SHA256, hashed, []byte(req.UserTokenSignature.Signature), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
@@ -584,11 +728,11 @@ func (srv *Server) handleActivateSession(ch *serverSecureChannel, requestid uint
 			plainBuf := buffer.NewPartitionAt(ch.bufferPool)
 			cipherBuf := buffer.NewPartitionAt(ch.bufferPool)
 			cipherBuf.Write(cipherBytes)
-			cipherText := make([]byte, int32(len(srv.localPrivateKey.D.Bytes())))
+			cipherText := make([]byte, ch.localPrivateKey.Size())
 			for cipherBuf.Len() > 0 {
 				cipherBuf.Read(cipherText)
 				// decrypt with local private key.
-				plainText, err := rsa.DecryptPKCS1v15(rand.Reader, srv.localPrivateKey, cipherText)
+				plainText, err := rsa.DecryptPKCS1v15(rand.Reader, ch.localPrivateKey, cipherText)
 				if err != nil {
 					return err
 				}
@@ -644,11 +788,11 @@ func (srv *Server) handleActivateSession(ch *serverSecureChannel, requestid uint
 			plainBuf := buffer.NewPartitionAt(ch.bufferPool)
 			cipherBuf := buffer.NewPartitionAt(ch.bufferPool)
 			cipherBuf.Write(cipherBytes)
-			cipherText := make([]byte, int32(len(srv.localPrivateKey.D.Bytes())))
+			cipherText := make([]byte, ch.localPrivateKey.Size())
 			for cipherBuf.Len() > 0 {
 				cipherBuf.Read(cipherText)
 				// decrypt with local private key.
-				plainText, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, srv.localPrivateKey, cipherText, []byte{})
+				plainText, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, ch.localPrivateKey, cipherText, []byte{})
 				if err != nil {
 					return err
 				}
@@ -704,11 +848,11 @@ func (srv *Server) handleActivateSession(ch *serverSecureChannel, requestid uint
 			plainBuf := buffer.NewPartitionAt(ch.bufferPool)
 			cipherBuf := buffer.NewPartitionAt(ch.bufferPool)
 			cipherBuf.Write(cipherBytes)
-			cipherText := make([]byte, int32(len(srv.localPrivateKey.D.Bytes())))
+			cipherText := make([]byte, ch.localPrivateKey.Size())
 			for cipherBuf.Len() > 0 {
 				cipherBuf.Read(cipherText)
 				// decrypt with local private key.
-				plainText, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, srv.localPrivateKey, cipherText, []byte{})
+				plainText, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, ch.localPrivateKey, cipherText, []byte{})
 				if err != nil {
 					return err
 				}
@@ -893,13 +1037,507 @@ func (srv *Server) handleCloseSession(ch *serverSecureChannel, requestid uint32,
 	id := session.SecureChannelId()
 	if id == 0 {
 		srv.serverDiagnosticsSummary.RejectedRequestsCount++
-		srv.SessionManager().Delete(session)
+		srv.SessionManager().Delete(session)
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionNotActivated,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if id != ch.ChannelID() {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSecureChannelIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// delete subscriptions if requested
+	if req.DeleteSubscriptions {
+		sm := srv.SubscriptionManager()
+		for _, s := range sm.GetBySession(session) {
+			sm.Delete(s)
+			s.Delete()
+		}
+	}
+
+	// delete session
+	srv.sessionManager.Delete(session)
+
+	// log.Printf("Deleted session '%s'.\n", session.SessionName())
+
+	err := ch.Write(
+		&ua.CloseSessionResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHeader.RequestHandle,
+			},
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// handleCancel cancels a request.
+func (srv *Server) handleCancel(ch *serverSecureChannel, requestid uint32, req *ua.CancelRequest) error {
+	// discovery only?
+	if ch.discoveryOnly {
+		srv.serverDiagnosticsSummary.SecurityRejectedRequestsCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		ch.Abort(ua.BadSecurityPolicyRejected, "")
+		return nil
+	}
+	// get session
+	session, ok := srv.sessionManager.Get(req.AuthenticationToken)
+	if !ok {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	// check channelId
+	id := session.SecureChannelId()
+	if id == 0 {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		srv.SessionManager().Delete(session)
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionNotActivated,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if id != ch.ChannelID() {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSecureChannelIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	err := ch.Write(
+		&ua.CancelResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHeader.RequestHandle,
+			},
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddNodes adds one or more Nodes into the AddressSpace hierarchy.
+func (srv *Server) handleAddNodes(ch *serverSecureChannel, requestid uint32, req *ua.AddNodesRequest) error {
+	// discovery only?
+	if ch.discoveryOnly {
+		srv.serverDiagnosticsSummary.SecurityRejectedRequestsCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		ch.Abort(ua.BadSecurityPolicyRejected, "")
+		return nil
+	}
+	// get session
+	session, ok := srv.SessionManager().Get(req.AuthenticationToken)
+	if !ok {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	session.addNodesCount++
+	session.requestCount++
+	// check channelId
+	id := session.SecureChannelId()
+	if id == 0 {
+		session.addNodesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		srv.SessionManager().Delete(session)
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionNotActivated,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if id != ch.ChannelID() {
+		session.addNodesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSecureChannelIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// check nothing to do
+	l := len(req.NodesToAdd)
+	if l == 0 {
+		session.addNodesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadNothingToDo,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	// check too many operations
+	if l > int(srv.serverCapabilities.OperationLimits.MaxNodesPerNodeManagement) {
+		session.addNodesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadTooManyOperations,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	results := make([]ua.AddNodesResult, l)
+
+	// handle requests in order, since later items may refer to nodes added by earlier items.
+	for i, item := range req.NodesToAdd {
+		results[i] = srv.addNode(session, item)
+	}
+
+	err := ch.Write(
+		&ua.AddNodesResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHeader.RequestHandle,
+			},
+			Results: results,
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddReferences adds one or more References to one or more Nodes.
+func (srv *Server) handleAddReferences(ch *serverSecureChannel, requestid uint32, req *ua.AddReferencesRequest) error {
+	// discovery only?
+	if ch.discoveryOnly {
+		srv.serverDiagnosticsSummary.SecurityRejectedRequestsCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		ch.Abort(ua.BadSecurityPolicyRejected, "")
+		return nil
+	}
+	// get session
+	session, ok := srv.SessionManager().Get(req.AuthenticationToken)
+	if !ok {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	session.addReferencesCount++
+	session.requestCount++
+	// check channelId
+	id := session.SecureChannelId()
+	if id == 0 {
+		session.addReferencesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		srv.SessionManager().Delete(session)
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionNotActivated,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if id != ch.ChannelID() {
+		session.addReferencesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSecureChannelIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// check nothing to do
+	l := len(req.ReferencesToAdd)
+	if l == 0 {
+		session.addReferencesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadNothingToDo,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	// check too many operations
+	if l > int(srv.serverCapabilities.OperationLimits.MaxNodesPerNodeManagement) {
+		session.addReferencesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadTooManyOperations,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	results := make([]ua.StatusCode, l)
+
+	for i, item := range req.ReferencesToAdd {
+		results[i] = srv.addReference(session, item)
+	}
+
+	err := ch.Write(
+		&ua.AddReferencesResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHeader.RequestHandle,
+			},
+			Results: results,
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteNodes deletes one or more Nodes from the AddressSpace.
+func (srv *Server) handleDeleteNodes(ch *serverSecureChannel, requestid uint32, req *ua.DeleteNodesRequest) error {
+	// discovery only?
+	if ch.discoveryOnly {
+		srv.serverDiagnosticsSummary.SecurityRejectedRequestsCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		ch.Abort(ua.BadSecurityPolicyRejected, "")
+		return nil
+	}
+	// get session
+	session, ok := srv.SessionManager().Get(req.AuthenticationToken)
+	if !ok {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	session.deleteNodesCount++
+	session.requestCount++
+	// check channelId
+	id := session.SecureChannelId()
+	if id == 0 {
+		session.deleteNodesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		srv.SessionManager().Delete(session)
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionNotActivated,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if id != ch.ChannelID() {
+		session.deleteNodesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSecureChannelIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// check nothing to do
+	l := len(req.NodesToDelete)
+	if l == 0 {
+		session.deleteNodesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
 		err := ch.Write(
 			&ua.ServiceFault{
 				ResponseHeader: ua.ResponseHeader{
 					Timestamp:     time.Now(),
 					RequestHandle: req.RequestHandle,
-					ServiceResult: ua.BadSessionNotActivated,
+					ServiceResult: ua.BadNothingToDo,
 				},
 			},
 			requestid,
@@ -909,14 +1547,17 @@ func (srv *Server) handleCloseSession(ch *serverSecureChannel, requestid uint32,
 		}
 		return nil
 	}
-	if id != ch.ChannelID() {
+	// check too many operations
+	if l > int(srv.serverCapabilities.OperationLimits.MaxNodesPerNodeManagement) {
+		session.deleteNodesErrorCount++
+		session.errorCount++
 		srv.serverDiagnosticsSummary.RejectedRequestsCount++
 		err := ch.Write(
 			&ua.ServiceFault{
 				ResponseHeader: ua.ResponseHeader{
 					Timestamp:     time.Now(),
 					RequestHandle: req.RequestHandle,
-					ServiceResult: ua.BadSecureChannelIDInvalid,
+					ServiceResult: ua.BadTooManyOperations,
 				},
 			},
 			requestid,
@@ -927,26 +1568,19 @@ func (srv *Server) handleCloseSession(ch *serverSecureChannel, requestid uint32,
 		return nil
 	}
 
-	// delete subscriptions if requested
-	if req.DeleteSubscriptions {
-		sm := srv.SubscriptionManager()
-		for _, s := range sm.GetBySession(session) {
-			sm.Delete(s)
-			s.Delete()
-		}
-	}
-
-	// delete session
-	srv.sessionManager.Delete(session)
+	results := make([]ua.StatusCode, l)
 
-	// log.Printf("Deleted session '%s'.\n", session.SessionName())
+	for i, item := range req.NodesToDelete {
+		results[i] = srv.deleteNode(session, item)
+	}
 
 	err := ch.Write(
-		&ua.CloseSessionResponse{
+		&ua.DeleteNodesResponse{
 			ResponseHeader: ua.ResponseHeader{
 				Timestamp:     time.Now(),
 				RequestHandle: req.RequestHeader.RequestHandle,
 			},
+			Results: results,
 		},
 		requestid,
 	)
@@ -956,8 +1590,8 @@ func (srv *Server) handleCloseSession(ch *serverSecureChannel, requestid uint32,
 	return nil
 }
 
-// handleCancel cancels a request.
-func (srv *Server) handleCancel(ch *serverSecureChannel, requestid uint32, req *ua.CancelRequest) error {
+// DeleteReferences deletes one or more References of a Node.
+func (srv *Server) handleDeleteReferences(ch *serverSecureChannel, requestid uint32, req *ua.DeleteReferencesRequest) error {
 	// discovery only?
 	if ch.discoveryOnly {
 		srv.serverDiagnosticsSummary.SecurityRejectedRequestsCount++
@@ -966,7 +1600,7 @@ func (srv *Server) handleCancel(ch *serverSecureChannel, requestid uint32, req *
 		return nil
 	}
 	// get session
-	session, ok := srv.sessionManager.Get(req.AuthenticationToken)
+	session, ok := srv.SessionManager().Get(req.AuthenticationToken)
 	if !ok {
 		srv.serverDiagnosticsSummary.RejectedRequestsCount++
 		err := ch.Write(
@@ -984,9 +1618,13 @@ func (srv *Server) handleCancel(ch *serverSecureChannel, requestid uint32, req *
 		}
 		return nil
 	}
+	session.deleteReferencesCount++
+	session.requestCount++
 	// check channelId
 	id := session.SecureChannelId()
 	if id == 0 {
+		session.deleteReferencesErrorCount++
+		session.errorCount++
 		srv.serverDiagnosticsSummary.RejectedRequestsCount++
 		srv.SessionManager().Delete(session)
 		err := ch.Write(
@@ -1005,6 +1643,8 @@ func (srv *Server) handleCancel(ch *serverSecureChannel, requestid uint32, req *
 		return nil
 	}
 	if id != ch.ChannelID() {
+		session.deleteReferencesErrorCount++
+		session.errorCount++
 		srv.serverDiagnosticsSummary.RejectedRequestsCount++
 		err := ch.Write(
 			&ua.ServiceFault{
@@ -1022,12 +1662,61 @@ func (srv *Server) handleCancel(ch *serverSecureChannel, requestid uint32, req *
 		return nil
 	}
 
+	// check nothing to do
+	l := len(req.ReferencesToDelete)
+	if l == 0 {
+		session.deleteReferencesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadNothingToDo,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	// check too many operations
+	if l > int(srv.serverCapabilities.OperationLimits.MaxNodesPerNodeManagement) {
+		session.deleteReferencesErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadTooManyOperations,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	results := make([]ua.StatusCode, l)
+
+	for i, item := range req.ReferencesToDelete {
+		results[i] = srv.deleteReference(session, item)
+	}
+
 	err := ch.Write(
-		&ua.CancelResponse{
+		&ua.DeleteReferencesResponse{
 			ResponseHeader: ua.ResponseHeader{
 				Timestamp:     time.Now(),
 				RequestHandle: req.RequestHeader.RequestHandle,
 			},
+			Results: results,
 		},
 		requestid,
 	)
@@ -1037,11 +1726,6 @@ func (srv *Server) handleCancel(ch *serverSecureChannel, requestid uint32, req *
 	return nil
 }
 
-// AddNodes adds one or more Nodes into the AddressSpace hierarchy.
-// AddReferences adds one or more References to one or more Nodes.
-// DeleteNodes deletes one or more Nodes from the AddressSpace.
-// DeleteReferences deletes one or more References of a Node.
-
 func (srv *Server) handleBrowse(ch *serverSecureChannel, requestid uint32, req *ua.BrowseRequest) error {
 	// discovery only?
 	if ch.discoveryOnly {
@@ -1213,13 +1897,14 @@ func (srv *Server) handleBrowse(ch *serverSecureChannel, requestid uint32, req *
 				return
 			}
 			m := srv.NamespaceManager()
-			node, ok := m.FindNode(d.NodeID)
+			// a NodeManager may provide the references of a node, without the node.
+			refs, ok := m.References(d.NodeID)
 			if !ok {
 				results[i] = ua.BrowseResult{StatusCode: ua.BadNodeIDUnknown}
 				wg.Done()
 				return
 			}
-			rp := node.UserRolePermissions(session.UserIdentity())
+			rp := m.UserRolePermissions(d.NodeID, session.UserIdentity())
 			if !IsUserPermitted(rp, ua.PermissionTypeBrowse) {
 				results[i] = ua.BrowseResult{StatusCode: ua.BadNodeIDUnknown}
 				wg.Done()
@@ -1242,7 +1927,6 @@ func (srv *Server) handleBrowse(ch *serverSecureChannel, requestid uint32, req *
 					return
 				}
 			}
-			refs := node.References()
 			rds := make([]ua.ReferenceDescription, 0, len(refs))
 			for _, r := range refs {
 				if !(both || r.IsInverse == isInverse) {
@@ -2023,11 +2707,10 @@ func (srv *Server) target(nodeID ua.NodeID, element ua.RelativePathElement) ([]u
 	isInverse := element.IsInverse
 	targetName := element.TargetName
 	m := srv.NamespaceManager()
-	node, ok := m.FindNode(nodeID)
+	refs, ok := m.References(nodeID)
 	if !ok {
 		return nil, ua.BadNodeIDUnknown
 	}
-	refs := node.References()
 	targets := make([]ua.ExpandedNodeID, 0, 4)
 	for _, r := range refs {
 		if !(r.IsInverse == isInverse) {
@@ -2632,6 +3315,175 @@ func (srv *Server) handleHistoryRead(ch *serverSecureChannel, requestid uint32,
 	return nil
 }
 
+// HistoryUpdate updates or deletes historical values or events.
+func (srv *Server) handleHistoryUpdate(ch *serverSecureChannel, requestid uint32, req *ua.HistoryUpdateRequest) error {
+	// discovery only?
+	if ch.discoveryOnly {
+		srv.serverDiagnosticsSummary.SecurityRejectedRequestsCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		ch.Abort(ua.BadSecurityPolicyRejected, "")
+		return nil
+	}
+	// get session
+	session, ok := srv.SessionManager().Get(req.AuthenticationToken)
+	if !ok {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	session.historyUpdateCount++
+	session.requestCount++
+	// check channelId
+	id := session.SecureChannelId()
+	if id == 0 {
+		session.historyUpdateErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		srv.SessionManager().Delete(session)
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionNotActivated,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if id != ch.ChannelID() {
+		session.historyUpdateErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSecureChannelIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// check nothing to do
+	l := len(req.HistoryUpdateDetails)
+	if l == 0 {
+		session.historyUpdateErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadNothingToDo,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	// check too many operations
+	var dataCount, eventCount int
+	for _, details := range req.HistoryUpdateDetails {
+		switch details.(type) {
+		case ua.UpdateEventDetails, ua.DeleteEventDetails:
+			eventCount++
+		default:
+			dataCount++
+		}
+	}
+	if dataCount > int(srv.serverCapabilities.OperationLimits.MaxNodesPerHistoryUpdateData) || eventCount > int(srv.serverCapabilities.OperationLimits.MaxNodesPerHistoryUpdateEvents) {
+		session.historyUpdateErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadTooManyOperations,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// check if historian installed
+	h, ok := srv.historian.(HistoryUpdater)
+	if !ok {
+		session.historyUpdateErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadHistoryOperationUnsupported,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	results := make([]ua.HistoryUpdateResult, l)
+
+	// handle requests in order, since later items may update the same node as earlier items.
+	for i, details := range req.HistoryUpdateDetails {
+		results[i] = srv.historyUpdate(ctx, session, h, details)
+	}
+
+	err := ch.Write(
+		&ua.HistoryUpdateResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHeader.RequestHandle,
+			},
+			Results: results,
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // readRange returns slice of value specified by IndexRange
 func readRange(source ua.DataValue, indexRange string) ua.DataValue {
 	if indexRange == "" {
@@ -3876,6 +4728,21 @@ func (srv *Server) handleCreateMonitoredItems(ch *serverSecureChannel, requestid
 				results[i] = ua.MonitoredItemCreateResult{StatusCode: sc}
 				continue
 			}
+			if af, ok := item.RequestedParameters.Filter.(ua.AggregateFilter); ok {
+				if sc := srv.validateAggregateFilter(af, n2); sc != ua.Good {
+					results[i] = ua.MonitoredItemCreateResult{StatusCode: sc}
+					continue
+				}
+				mi := NewAggregateMonitoredItem(sub, n, item.ItemToMonitor, item.MonitoringMode, item.RequestedParameters, req.TimestampsToReturn, minSupportedSampleRate)
+				sub.AppendItem(mi)
+				results[i] = ua.MonitoredItemCreateResult{
+					MonitoredItemID:         mi.ID(),
+					RevisedSamplingInterval: mi.SamplingInterval(),
+					RevisedQueueSize:        mi.QueueSize(),
+					FilterResult:            mi.FilterResult(),
+				}
+				continue
+			}
 			if item.RequestedParameters.Filter == nil {
 				item.RequestedParameters.Filter = ua.DataChangeFilter{Trigger: ua.DataChangeTriggerStatusValue}
 			}
@@ -3896,6 +4763,12 @@ func (srv *Server) handleCreateMonitoredItems(ch *serverSecureChannel, requestid
 					continue
 				}
 			}
+			if dcf.DeadbandType == uint32(ua.DeadbandTypePercent) {
+				if _, ok := findEURange(srv.NamespaceManager(), n2); !ok || dcf.DeadbandValue < 0 || dcf.DeadbandValue > 100 {
+					results[i] = ua.MonitoredItemCreateResult{StatusCode: ua.BadDeadbandFilterInvalid}
+					continue
+				}
+			}
 			mi := NewDataChangeMonitoredItem(sub, n, item.ItemToMonitor, item.MonitoringMode, item.RequestedParameters, req.TimestampsToReturn, minSupportedSampleRate)
 			sub.AppendItem(mi)
 			results[i] = ua.MonitoredItemCreateResult{
@@ -3920,17 +4793,23 @@ func (srv *Server) handleCreateMonitoredItems(ch *serverSecureChannel, requestid
 				results[i] = ua.MonitoredItemCreateResult{StatusCode: ua.BadUserAccessDenied}
 				continue
 			}
-			_, ok = item.RequestedParameters.Filter.(ua.EventFilter)
+			ef, ok := item.RequestedParameters.Filter.(ua.EventFilter)
 			if !ok {
 				results[i] = ua.MonitoredItemCreateResult{StatusCode: ua.BadFilterNotAllowed}
 				continue
 			}
+			code, filterResult := srv.validateEventFilter(ef)
+			if code.IsBad() {
+				results[i] = ua.MonitoredItemCreateResult{StatusCode: code, FilterResult: filterResult}
+				continue
+			}
 			mi := NewEventMonitoredItem(sub, n, item.ItemToMonitor, item.MonitoringMode, item.RequestedParameters)
 			sub.AppendItem(mi)
 			results[i] = ua.MonitoredItemCreateResult{
 				MonitoredItemID:         mi.ID(),
 				RevisedSamplingInterval: mi.SamplingInterval(),
 				RevisedQueueSize:        mi.QueueSize(),
+				FilterResult:            filterResult,
 			}
 			continue
 		default:
@@ -4135,6 +5014,23 @@ func (srv *Server) handleModifyMonitoredItems(ch *serverSecureChannel, requestid
 		if item, ok := sub.FindItem(modifyReq.MonitoredItemID); ok {
 			attr := item.ItemToMonitor().AttributeID
 			switch {
+			case attr == ua.AttributeIDValue && isAggregateItem(item):
+				af, ok := modifyReq.RequestedParameters.Filter.(ua.AggregateFilter)
+				if !ok {
+					results[i] = ua.MonitoredItemModifyResult{StatusCode: ua.BadFilterNotAllowed}
+					continue
+				}
+				n, ok := item.Node().(*VariableNode)
+				if !ok {
+					results[i] = ua.MonitoredItemModifyResult{StatusCode: ua.BadMonitoredItemFilterUnsupported}
+					continue
+				}
+				if sc := srv.validateAggregateFilter(af, n); sc != ua.Good {
+					results[i] = ua.MonitoredItemModifyResult{StatusCode: sc}
+					continue
+				}
+				results[i] = item.Modify(modifyReq)
+				continue
 			case attr == ua.AttributeIDValue:
 				if modifyReq.RequestedParameters.Filter == nil {
 					modifyReq.RequestedParameters.Filter = ua.DataChangeFilter{Trigger: ua.DataChangeTriggerStatusValue}
@@ -4145,7 +5041,12 @@ func (srv *Server) handleModifyMonitoredItems(ch *serverSecureChannel, requestid
 					continue
 				}
 				if dcf.DeadbandType != uint32(ua.DeadbandTypeNone) {
-					destType := srv.NamespaceManager().FindVariantType(item.Node().(*VariableNode).DataType())
+					n, ok := item.Node().(*VariableNode)
+					if !ok {
+						results[i] = ua.MonitoredItemModifyResult{StatusCode: ua.BadMonitoredItemFilterUnsupported}
+						continue
+					}
+					destType := srv.NamespaceManager().FindVariantType(n.DataType())
 					switch destType {
 					case ua.VariantTypeByte, ua.VariantTypeSByte:
 					case ua.VariantTypeInt16, ua.VariantTypeInt32, ua.VariantTypeInt64:
@@ -4156,18 +5057,30 @@ func (srv *Server) handleModifyMonitoredItems(ch *serverSecureChannel, requestid
 						continue
 					}
 				}
+				if dcf.DeadbandType == uint32(ua.DeadbandTypePercent) {
+					if _, ok := findEURange(srv.NamespaceManager(), item.Node()); !ok || dcf.DeadbandValue < 0 || dcf.DeadbandValue > 100 {
+						results[i] = ua.MonitoredItemModifyResult{StatusCode: ua.BadDeadbandFilterInvalid}
+						continue
+					}
+				}
 				results[i] = item.Modify(modifyReq)
 				continue
 			case attr == ua.AttributeIDEventNotifier:
 				if modifyReq.RequestedParameters.Filter == nil {
 					modifyReq.RequestedParameters.Filter = ua.EventFilter{} // TODO: get EventBase select clause
 				}
-				_, ok := modifyReq.RequestedParameters.Filter.(ua.EventFilter)
+				ef, ok := modifyReq.RequestedParameters.Filter.(ua.EventFilter)
 				if !ok {
 					results[i] = ua.MonitoredItemModifyResult{StatusCode: ua.BadFilterNotAllowed}
 					continue
 				}
+				code, filterResult := srv.validateEventFilter(ef)
+				if code.IsBad() {
+					results[i] = ua.MonitoredItemModifyResult{StatusCode: code, FilterResult: filterResult}
+					continue
+				}
 				results[i] = item.Modify(modifyReq)
+				results[i].FilterResult = filterResult
 				continue
 			default:
 				if modifyReq.RequestedParameters.Filter != nil {
@@ -5103,7 +6016,119 @@ func (srv *Server) handleSetPublishingMode(ch *serverSecureChannel, requestid ui
 	return nil
 }
 
-// TransferSubscriptions transfers a Subscription and its MonitoredItems from one Session to another.
+// TransferSubscriptions transfers Subscriptions and their MonitoredItems from one Session to another.
+func (srv *Server) handleTransferSubscriptions(ch *serverSecureChannel, requestid uint32, req *ua.TransferSubscriptionsRequest) error {
+	// discovery only?
+	if ch.discoveryOnly {
+		srv.serverDiagnosticsSummary.SecurityRejectedRequestsCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		ch.Abort(ua.BadSecurityPolicyRejected, "")
+		return nil
+	}
+	// get session
+	session, ok := srv.SessionManager().Get(req.AuthenticationToken)
+	if !ok {
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	session.transferSubscriptionsCount++
+	session.requestCount++
+	// check channelId
+	id := session.SecureChannelId()
+	if id == 0 {
+		session.transferSubscriptionsErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		srv.SessionManager().Delete(session)
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSessionNotActivated,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	if id != ch.ChannelID() {
+		session.transferSubscriptionsErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadSecureChannelIDInvalid,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// check nothing to do
+	l := len(req.SubscriptionIDs)
+	if l == 0 {
+		session.transferSubscriptionsErrorCount++
+		session.errorCount++
+		srv.serverDiagnosticsSummary.RejectedRequestsCount++
+		err := ch.Write(
+			&ua.ServiceFault{
+				ResponseHeader: ua.ResponseHeader{
+					Timestamp:     time.Now(),
+					RequestHandle: req.RequestHandle,
+					ServiceResult: ua.BadNothingToDo,
+				},
+			},
+			requestid,
+		)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+	results := make([]ua.TransferResult, l)
+	sm := srv.SubscriptionManager()
+	for i, id := range req.SubscriptionIDs {
+		results[i] = sm.Transfer(id, session, req.SendInitialValues)
+	}
+	err := ch.Write(
+		&ua.TransferSubscriptionsResponse{
+			ResponseHeader: ua.ResponseHeader{
+				Timestamp:     time.Now(),
+				RequestHandle: req.RequestHeader.RequestHandle,
+			},
+			Results: results,
+		},
+		requestid,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
 
 // DeleteSubscriptions deletes one or more Subscriptions.
 func (srv *Server) handleDeleteSubscriptions(ch *serverSecureChannel, requestid uint32, req *ua.DeleteSubscriptionsRequest) error {
@@ -5495,6 +6520,11 @@ func (srv *Server) handleRepublish(ch *serverSecureChannel, requestid uint32, re
 	}
 
 	s, ok := srv.SubscriptionManager().Get(req.SubscriptionID)
+	if ok {
+		s.RLock()
+		ok = s.session.Load() == session
+		s.RUnlock()
+	}
 	if !ok {
 		session.republishErrorCount++
 		session.errorCount++
@@ -6247,3 +7277,549 @@ func (srv *Server) readValue(session *Session, readValueId ua.ReadValueID) ua.Da
 		return ua.NewDataValue(nil, ua.BadAttributeIDInvalid, time.Time{}, 0, time.Now(), 0)
 	}
 }
+
+// addNode adds a node to the namespace, as described by the AddNodesItem.
+func (srv *Server) addNode(session *Session, item ua.AddNodesItem) ua.AddNodesResult {
+	if session == nil {
+		return ua.AddNodesResult{StatusCode: ua.BadUserAccessDenied}
+	}
+	m := srv.NamespaceManager()
+	// check parent
+	if item.ParentNodeID.ServerIndex != 0 {
+		return ua.AddNodesResult{StatusCode: ua.BadParentNodeIDInvalid}
+	}
+	parent, ok := m.FindNode(ua.ToNodeID(item.ParentNodeID, m.NamespaceUris()))
+	if !ok {
+		return ua.AddNodesResult{StatusCode: ua.BadParentNodeIDInvalid}
+	}
+	rp := parent.UserRolePermissions(session.UserIdentity())
+	if !IsUserPermitted(rp, ua.PermissionTypeBrowse) {
+		return ua.AddNodesResult{StatusCode: ua.BadParentNodeIDInvalid}
+	}
+	if !IsUserPermitted(rp, ua.PermissionTypeAddNode) {
+		return ua.AddNodesResult{StatusCode: ua.BadUserAccessDenied}
+	}
+	// check reference type
+	rt, ok := m.FindNode(item.ReferenceTypeID)
+	if !ok {
+		return ua.AddNodesResult{StatusCode: ua.BadReferenceTypeIDInvalid}
+	}
+	rt1, ok := rt.(*ReferenceTypeNode)
+	if !ok {
+		return ua.AddNodesResult{StatusCode: ua.BadReferenceTypeIDInvalid}
+	}
+	if rt1.IsAbstract() {
+		return ua.AddNodesResult{StatusCode: ua.BadReferenceNotAllowed}
+	}
+	// check browse name
+	if item.BrowseName.Name == "" {
+		return ua.AddNodesResult{StatusCode: ua.BadBrowseNameInvalid}
+	}
+	// check node class is compatible with the parent and reference type
+	isSubtypeRef := item.ReferenceTypeID == ua.ReferenceTypeIDHasSubtype || m.IsSubtype(item.ReferenceTypeID, ua.ReferenceTypeIDHasSubtype)
+	switch item.NodeClass {
+	case ua.NodeClassObject, ua.NodeClassVariable, ua.NodeClassMethod, ua.NodeClassView:
+		if isSubtypeRef || !m.IsSubtype(item.ReferenceTypeID, ua.ReferenceTypeIDHierarchicalReferences) {
+			return ua.AddNodesResult{StatusCode: ua.BadReferenceNotAllowed}
+		}
+	case ua.NodeClassObjectType, ua.NodeClassVariableType, ua.NodeClassReferenceType, ua.NodeClassDataType:
+		if !isSubtypeRef {
+			return ua.AddNodesResult{StatusCode: ua.BadReferenceNotAllowed}
+		}
+		if parent.NodeClass() != item.NodeClass {
+			return ua.AddNodesResult{StatusCode: ua.BadParentNodeIDInvalid}
+		}
+	default:
+		return ua.AddNodesResult{StatusCode: ua.BadNodeClassInvalid}
+	}
+	// check type definition
+	var typeDefinitionID ua.NodeID
+	switch item.NodeClass {
+	case ua.NodeClassObject:
+		if item.TypeDefinition.ServerIndex != 0 {
+			return ua.AddNodesResult{StatusCode: ua.BadTypeDefinitionInvalid}
+		}
+		typeDefinitionID = ua.ToNodeID(item.TypeDefinition, m.NamespaceUris())
+		td, ok := m.FindNode(typeDefinitionID)
+		if !ok {
+			return ua.AddNodesResult{StatusCode: ua.BadTypeDefinitionInvalid}
+		}
+		td1, ok := td.(*ObjectTypeNode)
+		if !ok || td1.IsAbstract() {
+			return ua.AddNodesResult{StatusCode: ua.BadTypeDefinitionInvalid}
+		}
+	case ua.NodeClassVariable:
+		if item.TypeDefinition.ServerIndex != 0 {
+			return ua.AddNodesResult{StatusCode: ua.BadTypeDefinitionInvalid}
+		}
+		typeDefinitionID = ua.ToNodeID(item.TypeDefinition, m.NamespaceUris())
+		td, ok := m.FindNode(typeDefinitionID)
+		if !ok {
+			return ua.AddNodesResult{StatusCode: ua.BadTypeDefinitionInvalid}
+		}
+		td1, ok := td.(*VariableTypeNode)
+		if !ok || td1.IsAbstract() {
+			return ua.AddNodesResult{StatusCode: ua.BadTypeDefinitionInvalid}
+		}
+	default:
+		if item.TypeDefinition.NodeID != nil {
+			return ua.AddNodesResult{StatusCode: ua.BadTypeDefinitionInvalid}
+		}
+	}
+	// check browse name is unique among the children of the parent
+	for _, r := range parent.References() {
+		if r.IsInverse || r.ReferenceTypeID != item.ReferenceTypeID {
+			continue
+		}
+		if sibling, ok := m.FindNode(ua.ToNodeID(r.TargetID, m.NamespaceUris())); ok && sibling.BrowseName() == item.BrowseName {
+			return ua.AddNodesResult{StatusCode: ua.BadBrowseNameDuplicated}
+		}
+	}
+	// check requested node id
+	var nodeID ua.NodeID
+	if item.RequestedNewNodeID.NodeID != nil {
+		if item.RequestedNewNodeID.ServerIndex != 0 {
+			return ua.AddNodesResult{StatusCode: ua.BadNodeIDRejected}
+		}
+		nodeID = ua.ToNodeID(item.RequestedNewNodeID, m.NamespaceUris())
+		if nodeID == nil {
+			return ua.AddNodesResult{StatusCode: ua.BadNodeIDRejected}
+		}
+		if ns := namespaceIndex(nodeID); ns == 0 || int(ns) >= m.Len() {
+			return ua.AddNodesResult{StatusCode: ua.BadNodeIDRejected}
+		}
+		if _, ok := m.FindNode(nodeID); ok {
+			return ua.AddNodesResult{StatusCode: ua.BadNodeIDExists}
+		}
+	} else {
+		// the new node is in the namespace of its browse name, or else of its parent.
+		ns := item.BrowseName.NamespaceIndex
+		if ns == 0 {
+			ns = namespaceIndex(parent.NodeID())
+		}
+		if ns == 0 || int(ns) >= m.Len() {
+			return ua.AddNodesResult{StatusCode: ua.BadBrowseNameInvalid}
+		}
+		nodeID = ua.NewNodeIDGUID(ns, uuid.New())
+	}
+	refs := []ua.Reference{
+		ua.NewReference(item.ReferenceTypeID, true, ua.NewExpandedNodeID(parent.NodeID())),
+	}
+	if typeDefinitionID != nil {
+		refs = append(refs, ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(typeDefinitionID)))
+	}
+	node, status := srv.newNodeFromAttributes(nodeID, item.BrowseName, item.NodeClass, item.NodeAttributes, refs)
+	if status.IsBad() {
+		return ua.AddNodesResult{StatusCode: status}
+	}
+	if err := m.AddNode(node); err != nil {
+		return ua.AddNodesResult{StatusCode: ua.BadInternalError}
+	}
+	return ua.AddNodesResult{AddedNodeID: nodeID}
+}
+
+// newNodeFromAttributes constructs a node of the given class from the NodeAttributes of an AddNodesItem.
+// Attributes not included in the SpecifiedAttributes mask are set to their defaults.
+func (srv *Server) newNodeFromAttributes(nodeID ua.NodeID, browseName ua.QualifiedName, nodeClass ua.NodeClass, attributes ua.ExtensionObject, refs []ua.Reference) (Node, ua.StatusCode) {
+	m := srv.NamespaceManager()
+	displayName := func(mask uint32, value ua.LocalizedText) ua.LocalizedText {
+		if mask&uint32(ua.NodeAttributesMaskDisplayName) == 0 {
+			return ua.NewLocalizedText(browseName.Name, "")
+		}
+		return value
+	}
+	switch nodeClass {
+	case ua.NodeClassObject:
+		attrs, ok := attributes.(ua.ObjectAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		return NewObjectNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			attrs.EventNotifier,
+		), ua.Good
+	case ua.NodeClassVariable:
+		attrs, ok := attributes.(ua.VariableAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		dataType := ua.DataTypeIDBaseDataType
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskDataType) != 0 {
+			dt, ok := m.FindNode(attrs.DataType)
+			if !ok || dt.NodeClass() != ua.NodeClassDataType {
+				return nil, ua.BadNodeAttributesInvalid
+			}
+			dataType = attrs.DataType
+		}
+		valueRank := ua.ValueRankScalar
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskValueRank) != 0 {
+			if attrs.ValueRank < ua.ValueRankScalarOrOneDimension {
+				return nil, ua.BadNodeAttributesInvalid
+			}
+			valueRank = attrs.ValueRank
+		}
+		arrayDimensions := []uint32{}
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskArrayDimensions) != 0 && attrs.ArrayDimensions != nil {
+			if valueRank <= 0 || len(attrs.ArrayDimensions) != int(valueRank) {
+				return nil, ua.BadNodeAttributesInvalid
+			}
+			arrayDimensions = attrs.ArrayDimensions
+		}
+		accessLevel := ua.AccessLevelsCurrentRead
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskAccessLevel) != 0 {
+			accessLevel = attrs.AccessLevel
+		}
+		value := ua.NewDataValue(nil, ua.BadWaitingForInitialData, time.Now(), 0, time.Now(), 0)
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskValue) != 0 {
+			value = ua.NewDataValue(attrs.Value, ua.Good, time.Now(), 0, time.Now(), 0)
+		}
+		return NewVariableNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			value,
+			dataType,
+			valueRank,
+			arrayDimensions,
+			accessLevel,
+			attrs.MinimumSamplingInterval,
+			attrs.Historizing && srv.historian != nil,
+			srv.historian,
+		), ua.Good
+	case ua.NodeClassMethod:
+		attrs, ok := attributes.(ua.MethodAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		return NewMethodNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			attrs.Executable,
+		), ua.Good
+	case ua.NodeClassView:
+		attrs, ok := attributes.(ua.ViewAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		return NewViewNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			attrs.ContainsNoLoops,
+			attrs.EventNotifier,
+		), ua.Good
+	case ua.NodeClassObjectType:
+		attrs, ok := attributes.(ua.ObjectTypeAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		return NewObjectTypeNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			attrs.IsAbstract,
+		), ua.Good
+	case ua.NodeClassVariableType:
+		attrs, ok := attributes.(ua.VariableTypeAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		dataType := ua.DataTypeIDBaseDataType
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskDataType) != 0 {
+			dt, ok := m.FindNode(attrs.DataType)
+			if !ok || dt.NodeClass() != ua.NodeClassDataType {
+				return nil, ua.BadNodeAttributesInvalid
+			}
+			dataType = attrs.DataType
+		}
+		valueRank := ua.ValueRankScalar
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskValueRank) != 0 {
+			valueRank = attrs.ValueRank
+		}
+		arrayDimensions := []uint32{}
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskArrayDimensions) != 0 && attrs.ArrayDimensions != nil {
+			arrayDimensions = attrs.ArrayDimensions
+		}
+		value := ua.NewDataValue(nil, 0, time.Now(), 0, time.Now(), 0)
+		if attrs.SpecifiedAttributes&uint32(ua.NodeAttributesMaskValue) != 0 {
+			value = ua.NewDataValue(attrs.Value, 0, time.Now(), 0, time.Now(), 0)
+		}
+		return NewVariableTypeNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			value,
+			dataType,
+			valueRank,
+			arrayDimensions,
+			attrs.IsAbstract,
+		), ua.Good
+	case ua.NodeClassReferenceType:
+		attrs, ok := attributes.(ua.ReferenceTypeAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		return NewReferenceTypeNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			attrs.IsAbstract,
+			attrs.Symmetric,
+			attrs.InverseName,
+		), ua.Good
+	case ua.NodeClassDataType:
+		attrs, ok := attributes.(ua.DataTypeAttributes)
+		if !ok {
+			return nil, ua.BadNodeAttributesInvalid
+		}
+		return NewDataTypeNode(
+			srv,
+			nodeID,
+			browseName,
+			displayName(attrs.SpecifiedAttributes, attrs.DisplayName),
+			attrs.Description,
+			nil,
+			refs,
+			attrs.IsAbstract,
+			nil,
+		), ua.Good
+	default:
+		return nil, ua.BadNodeClassInvalid
+	}
+}
+
+// addReference adds a reference to the source node, and the inverse reference to the target node.
+func (srv *Server) addReference(session *Session, item ua.AddReferencesItem) ua.StatusCode {
+	if session == nil {
+		return ua.BadUserAccessDenied
+	}
+	m := srv.NamespaceManager()
+	source, ok := m.FindNode(item.SourceNodeID)
+	if !ok {
+		return ua.BadSourceNodeIDInvalid
+	}
+	rp := source.UserRolePermissions(session.UserIdentity())
+	if !IsUserPermitted(rp, ua.PermissionTypeBrowse) {
+		return ua.BadSourceNodeIDInvalid
+	}
+	if !IsUserPermitted(rp, ua.PermissionTypeAddReference) {
+		return ua.BadUserAccessDenied
+	}
+	rt, ok := m.FindNode(item.ReferenceTypeID)
+	if !ok {
+		return ua.BadReferenceTypeIDInvalid
+	}
+	rt1, ok := rt.(*ReferenceTypeNode)
+	if !ok || rt1.IsAbstract() {
+		return ua.BadReferenceTypeIDInvalid
+	}
+	if item.TargetServerURI != "" || item.TargetNodeID.ServerIndex != 0 {
+		return ua.BadReferenceLocalOnly
+	}
+	target, ok := m.FindNode(ua.ToNodeID(item.TargetNodeID, m.NamespaceUris()))
+	if !ok {
+		return ua.BadTargetNodeIDInvalid
+	}
+	if item.TargetNodeClass != ua.NodeClassUnspecified && item.TargetNodeClass != target.NodeClass() {
+		return ua.BadNodeClassInvalid
+	}
+	if source.NodeID() == target.NodeID() {
+		return ua.BadInvalidSelfReference
+	}
+	for _, r := range source.References() {
+		if r.ReferenceTypeID == item.ReferenceTypeID && r.IsInverse == !item.IsForward && ua.ToNodeID(r.TargetID, m.NamespaceUris()) == target.NodeID() {
+			return ua.BadDuplicateReferenceNotAllowed
+		}
+	}
+	if err := m.AddReference(source, ua.NewReference(item.ReferenceTypeID, !item.IsForward, ua.NewExpandedNodeID(target.NodeID()))); err != nil {
+		return ua.BadInternalError
+	}
+	return ua.Good
+}
+
+// deleteNode deletes a node and its children from the namespace.
+func (srv *Server) deleteNode(session *Session, item ua.DeleteNodesItem) ua.StatusCode {
+	if session == nil {
+		return ua.BadUserAccessDenied
+	}
+	m := srv.NamespaceManager()
+	n, ok := m.FindNode(item.NodeID)
+	if !ok {
+		return ua.BadNodeIDUnknown
+	}
+	rp := n.UserRolePermissions(session.UserIdentity())
+	if !IsUserPermitted(rp, ua.PermissionTypeBrowse) {
+		return ua.BadNodeIDUnknown
+	}
+	if !IsUserPermitted(rp, ua.PermissionTypeDeleteNode) {
+		return ua.BadUserAccessDenied
+	}
+	// nodes of the standard namespace may not be removed by clients.
+	if namespaceIndex(n.NodeID()) == 0 {
+		return ua.BadNoDeleteRights
+	}
+	// if DeleteTargetReferences is false, the references of other nodes to the node are kept.
+	if err := m.deleteNodes([]Node{n}, true, item.DeleteTargetReferences); err != nil {
+		return ua.BadInternalError
+	}
+	return ua.Good
+}
+
+// deleteReference deletes a reference from the source node, and optionally the inverse reference from the target node.
+func (srv *Server) deleteReference(session *Session, item ua.DeleteReferencesItem) ua.StatusCode {
+	if session == nil {
+		return ua.BadUserAccessDenied
+	}
+	m := srv.NamespaceManager()
+	source, ok := m.FindNode(item.SourceNodeID)
+	if !ok {
+		return ua.BadSourceNodeIDInvalid
+	}
+	rp := source.UserRolePermissions(session.UserIdentity())
+	if !IsUserPermitted(rp, ua.PermissionTypeBrowse) {
+		return ua.BadSourceNodeIDInvalid
+	}
+	if !IsUserPermitted(rp, ua.PermissionTypeRemoveReference) {
+		return ua.BadUserAccessDenied
+	}
+	if _, ok := m.FindNode(item.ReferenceTypeID); !ok {
+		return ua.BadReferenceTypeIDInvalid
+	}
+	if item.TargetNodeID.ServerIndex != 0 {
+		return ua.BadServerIndexInvalid
+	}
+	targetID := ua.ToNodeID(item.TargetNodeID, m.NamespaceUris())
+	if targetID == nil {
+		return ua.BadTargetNodeIDInvalid
+	}
+	if err := m.DeleteReference(source, ua.NewReference(item.ReferenceTypeID, !item.IsForward, ua.NewExpandedNodeID(targetID)), item.DeleteBidirectional); err != nil {
+		if code, ok := err.(ua.StatusCode); ok {
+			return code
+		}
+		return ua.BadInternalError
+	}
+	return ua.Good
+}
+
+// historyUpdate checks the user's permissions to update the history of a node, then forwards the details to the historian.
+func (srv *Server) historyUpdate(ctx context.Context, session *Session, h HistoryUpdater, details ua.ExtensionObject) ua.HistoryUpdateResult {
+	var nodeID ua.NodeID
+	var permissionType ua.PermissionType
+	var isEvent bool
+	switch details := details.(type) {
+	case ua.UpdateDataDetails:
+		nodeID = details.NodeID
+		switch details.PerformInsertReplace {
+		case ua.PerformUpdateTypeInsert:
+			permissionType = ua.PermissionTypeInsertHistory
+		case ua.PerformUpdateTypeReplace:
+			permissionType = ua.PermissionTypeModifyHistory
+		case ua.PerformUpdateTypeUpdate:
+			permissionType = ua.PermissionTypeInsertHistory | ua.PermissionTypeModifyHistory
+		default:
+			return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationInvalid}
+		}
+	case ua.UpdateEventDetails:
+		nodeID = details.NodeID
+		isEvent = true
+		switch details.PerformInsertReplace {
+		case ua.PerformUpdateTypeInsert:
+			permissionType = ua.PermissionTypeInsertHistory
+		case ua.PerformUpdateTypeReplace:
+			permissionType = ua.PermissionTypeModifyHistory
+		case ua.PerformUpdateTypeUpdate:
+			permissionType = ua.PermissionTypeInsertHistory | ua.PermissionTypeModifyHistory
+		default:
+			return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationInvalid}
+		}
+	case ua.DeleteRawModifiedDetails:
+		nodeID = details.NodeID
+		permissionType = ua.PermissionTypeDeleteHistory
+	case ua.DeleteAtTimeDetails:
+		nodeID = details.NodeID
+		permissionType = ua.PermissionTypeDeleteHistory
+	case ua.DeleteEventDetails:
+		nodeID = details.NodeID
+		isEvent = true
+		permissionType = ua.PermissionTypeDeleteHistory
+	default:
+		return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+	}
+	n, ok := srv.NamespaceManager().FindNode(nodeID)
+	if !ok {
+		return ua.HistoryUpdateResult{StatusCode: ua.BadNodeIDUnknown}
+	}
+	rp := n.UserRolePermissions(session.UserIdentity())
+	if !IsUserPermitted(rp, ua.PermissionTypeBrowse) {
+		return ua.HistoryUpdateResult{StatusCode: ua.BadNodeIDUnknown}
+	}
+	if isEvent {
+		switch n1 := n.(type) {
+		case *ObjectNode:
+			if n1.EventNotifier()&ua.EventNotifierHistoryWrite == 0 {
+				return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+			}
+		case *ViewNode:
+			if n1.EventNotifier()&ua.EventNotifierHistoryWrite == 0 {
+				return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+			}
+		default:
+			return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+		}
+	} else {
+		n1, ok := n.(*VariableNode)
+		if !ok || n1.AccessLevel()&ua.AccessLevelsHistoryWrite == 0 {
+			return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+		}
+	}
+	// every bit of the required permissions must be granted.
+	for _, p := range []ua.PermissionType{ua.PermissionTypeInsertHistory, ua.PermissionTypeModifyHistory, ua.PermissionTypeDeleteHistory} {
+		if permissionType&p != 0 && !IsUserPermitted(rp, p) {
+			return ua.HistoryUpdateResult{StatusCode: ua.BadUserAccessDenied}
+		}
+	}
+	switch details := details.(type) {
+	case ua.UpdateDataDetails:
+		return h.UpdateData(ctx, details)
+	case ua.UpdateEventDetails:
+		return h.UpdateEvent(ctx, details)
+	case ua.DeleteRawModifiedDetails:
+		return h.DeleteRawModified(ctx, details)
+	case ua.DeleteAtTimeDetails:
+		return h.DeleteAtTime(ctx, details)
+	case ua.DeleteEventDetails:
+		return h.DeleteEvent(ctx, details)
+	}
+	return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+}
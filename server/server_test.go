@@ -3,6 +3,7 @@
 package server_test
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
@@ -11,16 +12,20 @@ import (
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/pem"
+	"encoding/xml"
 	"fmt"
 	"math/big"
 	"net"
 	"net/url"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/awcullen/opcua/client"
+	"github.com/awcullen/opcua/server"
 	"github.com/awcullen/opcua/ua"
 
 	"github.com/pkg/errors"
@@ -53,6 +58,13 @@ func TestMain(m *testing.M) {
 				os.Exit(3)
 			}
 		}()
+		// wait until the server is listening.
+		for i := 0; i < 50; i++ {
+			if _, err := client.FindServers(context.Background(), &ua.FindServersRequest{EndpointURL: endpointURL}); err == nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
 	}
 	// run the tests
 	res := m.Run()
@@ -600,6 +612,203 @@ func TestSubscribe(t *testing.T) {
 	}
 }
 
+// TestTransferSubscriptions tests transferring a subscription to another session of the same user.
+func TestTransferSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	t.Logf("Success connecting to server: %s", ch.EndpointURL())
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 500.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	_, err = ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			{
+				ItemToMonitor: ua.ReadValueID{
+					AttributeID: ua.AttributeIDValue,
+					NodeID:      ua.VariableIDServerServerStatusCurrentTime,
+				},
+				MonitoringMode: ua.MonitoringModeReporting,
+				RequestedParameters: ua.MonitoringParameters{
+					ClientHandle: 42, QueueSize: 1, DiscardOldest: true, SamplingInterval: 500.0,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating item"))
+		ch.Abort(ctx)
+		return
+	}
+	// receive a notification, but do not acknowledge it.
+	var seqNum uint32
+	for seqNum == 0 {
+		res2, err := ch.Publish(ctx, &ua.PublishRequest{
+			RequestHeader:                ua.RequestHeader{TimeoutHint: 60000},
+			SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{},
+		})
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error publishing"))
+			ch.Abort(ctx)
+			return
+		}
+		if len(res2.NotificationMessage.NotificationData) > 0 {
+			seqNum = res2.NotificationMessage.SequenceNumber
+		}
+	}
+
+	// another user may not take the subscription.
+	ch2, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("user1", "password"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		ch.Abort(ctx)
+		return
+	}
+	res3, err := ch2.TransferSubscriptions(ctx, &ua.TransferSubscriptionsRequest{
+		SubscriptionIDs: []uint32{res.SubscriptionID},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error transferring subscriptions"))
+		ch.Abort(ctx)
+		ch2.Abort(ctx)
+		return
+	}
+	if res3.Results[0].StatusCode != ua.BadUserAccessDenied {
+		t.Errorf("Error transferring subscription to another user. want: %s, got: %s", ua.BadUserAccessDenied, res3.Results[0].StatusCode)
+	}
+	ch2.Close(ctx)
+
+	// the same user may take the subscription.
+	ch3, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		ch.Abort(ctx)
+		return
+	}
+	res4, err := ch3.TransferSubscriptions(ctx, &ua.TransferSubscriptionsRequest{
+		SubscriptionIDs:   []uint32{res.SubscriptionID},
+		SendInitialValues: true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error transferring subscriptions"))
+		ch.Abort(ctx)
+		ch3.Abort(ctx)
+		return
+	}
+	if res4.Results[0].StatusCode != ua.Good || !containsUint32(res4.Results[0].AvailableSequenceNumbers, seqNum) {
+		t.Errorf("Error transferring subscription. got: %s, %v", res4.Results[0].StatusCode, res4.Results[0].AvailableSequenceNumbers)
+	}
+
+	// the previous session is notified.
+	res5, err := ch.Publish(ctx, &ua.PublishRequest{
+		RequestHeader:                ua.RequestHeader{TimeoutHint: 60000},
+		SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error publishing"))
+	} else if len(res5.NotificationMessage.NotificationData) != 1 || res5.NotificationMessage.NotificationData[0] != (ua.StatusChangeNotification{Status: ua.GoodSubscriptionTransferred}) {
+		t.Errorf("Error receiving status change. got: %v", res5.NotificationMessage.NotificationData)
+	}
+	ch.Close(ctx)
+
+	// the new session may republish unacknowledged messages.
+	res6, err := ch3.Republish(ctx, &ua.RepublishRequest{
+		SubscriptionID:           res.SubscriptionID,
+		RetransmitSequenceNumber: seqNum,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error republishing"))
+	} else if res6.NotificationMessage.SequenceNumber != seqNum {
+		t.Errorf("Error republishing. want: %d, got: %d", seqNum, res6.NotificationMessage.SequenceNumber)
+	}
+
+	// the new session receives the initial values.
+	res7, err := ch3.Publish(ctx, &ua.PublishRequest{
+		RequestHeader:                ua.RequestHeader{TimeoutHint: 60000},
+		SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error publishing"))
+	} else if res7.SubscriptionID != res.SubscriptionID || len(res7.NotificationMessage.NotificationData) == 0 {
+		t.Errorf("Error publishing after transfer. got: %v", res7.NotificationMessage)
+	}
+
+	// the transfer is counted in the diagnostics of the new session.
+	res8, err := ch3.TranslateBrowsePathsToNodeIDs(ctx, &ua.TranslateBrowsePathsToNodeIDsRequest{
+		BrowsePaths: []ua.BrowsePath{
+			{
+				StartingNode: ch3.SessionID(),
+				RelativePath: ua.RelativePath{
+					Elements: []ua.RelativePathElement{
+						{TargetName: ua.ParseQualifiedName("SessionDiagnostics")},
+					},
+				},
+			},
+		},
+	})
+	if err != nil || res8.Results[0].StatusCode.IsBad() || len(res8.Results[0].Targets) == 0 {
+		t.Errorf("Error finding session diagnostics. got: %v, %v", res8, err)
+	} else {
+		res9, err := ch3.Read(ctx, &ua.ReadRequest{
+			NodesToRead: []ua.ReadValueID{
+				{NodeID: ua.ToNodeID(res8.Results[0].Targets[0].TargetID, nil), AttributeID: ua.AttributeIDValue},
+			},
+		})
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error reading session diagnostics"))
+		} else if d, ok := res9.Results[0].Value.(ua.SessionDiagnosticsDataType); !ok || d.TransferSubscriptionsCount.TotalCount != 1 {
+			t.Errorf("Error counting transfer in session diagnostics. got: %v", res9.Results[0].Value)
+		}
+	}
+
+	err = ch3.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch3.Abort(ctx)
+		return
+	}
+}
+
+func containsUint32(values []uint32, value uint32) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // TestCallMethod tests calling a method of the server and passing Aurguments.
 func TestCallMethod(t *testing.T) {
 	ctx := context.Background()
@@ -705,18 +914,14 @@ func TestTranslate(t *testing.T) {
 	}
 }
 
-/*
-// TestReadHistory demonstrates reading history from the UaCPPServer available from https://www.unified-automation.com/
-func TestReadHistory(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping long running test")
-	}
+// TestAddDeleteNodes tests adding and deleting nodes and references of the address space.
+func TestAddDeleteNodes(t *testing.T) {
 	ctx := context.Background()
 	ch, err := client.Dial(
 		ctx,
-		"opc.tcp://localhost:48010",
+		endpointURL,
 		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
-		client.WithInsecureSkipVerify(), // skips verification of server certificate
+		client.WithInsecureSkipVerify(),
 		client.WithUserNameIdentity("root", "secret"),
 	)
 	if err != nil {
@@ -725,127 +930,2769 @@ func TestReadHistory(t *testing.T) {
 	}
 	t.Logf("Success connecting to server: %s", ch.EndpointURL())
 
-	t.Logf("Start logging of data...")
-	req := &ua.CallRequest{
-		MethodsToCall: []ua.CallMethodRequest{
+	objectID := ua.ParseNodeID("ns=2;s=Demo.Added")
+	variableID := ua.ParseNodeID("ns=2;s=Demo.Added.Value")
+	req := &ua.AddNodesRequest{
+		NodesToAdd: []ua.AddNodesItem{
 			{
-				ObjectID:       ua.ParseNodeID("ns=2;s=Demo.History"), // parent node
-				MethodID:       ua.ParseNodeID("ns=2;s=Demo.History.StartLogging"),
-				InputArguments: []ua.Variant{},
+				ParentNodeID:       ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Demo")),
+				ReferenceTypeID:    ua.ReferenceTypeIDOrganizes,
+				RequestedNewNodeID: ua.NewExpandedNodeID(objectID),
+				BrowseName:         ua.ParseQualifiedName("2:Added"),
+				NodeClass:          ua.NodeClassObject,
+				NodeAttributes:     ua.ObjectAttributes{},
+				TypeDefinition:     ua.NewExpandedNodeID(ua.ObjectTypeIDFolderType),
+			},
+			{
+				ParentNodeID:       ua.NewExpandedNodeID(objectID),
+				ReferenceTypeID:    ua.ReferenceTypeIDHasComponent,
+				RequestedNewNodeID: ua.NewExpandedNodeID(variableID),
+				BrowseName:         ua.ParseQualifiedName("2:Value"),
+				NodeClass:          ua.NodeClassVariable,
+				NodeAttributes: ua.VariableAttributes{
+					SpecifiedAttributes: uint32(ua.NodeAttributesMaskValue | ua.NodeAttributesMaskDataType | ua.NodeAttributesMaskAccessLevel),
+					Value:               float64(42.0),
+					DataType:            ua.DataTypeIDDouble,
+					AccessLevel:         ua.AccessLevelsCurrentRead | ua.AccessLevelsCurrentWrite,
+				},
+				TypeDefinition: ua.NewExpandedNodeID(ua.VariableTypeIDBaseDataVariableType),
+			},
+			{
+				ParentNodeID:       ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Demo")),
+				ReferenceTypeID:    ua.ReferenceTypeIDOrganizes,
+				RequestedNewNodeID: ua.NewExpandedNodeID(objectID),
+				BrowseName:         ua.ParseQualifiedName("2:Added2"),
+				NodeClass:          ua.NodeClassObject,
+				NodeAttributes:     ua.ObjectAttributes{},
+				TypeDefinition:     ua.NewExpandedNodeID(ua.ObjectTypeIDFolderType),
+			},
+			{
+				ParentNodeID:    ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=NotFound")),
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				BrowseName:      ua.ParseQualifiedName("2:Orphan"),
+				NodeClass:       ua.NodeClassObject,
+				NodeAttributes:  ua.ObjectAttributes{},
+				TypeDefinition:  ua.NewExpandedNodeID(ua.ObjectTypeIDFolderType),
+			},
+			{
+				ParentNodeID:    ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Demo")),
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				BrowseName:      ua.ParseQualifiedName("2:Untyped"),
+				NodeClass:       ua.NodeClassObject,
+				NodeAttributes:  ua.ObjectAttributes{},
+				TypeDefinition:  ua.NewExpandedNodeID(ua.VariableTypeIDBaseDataVariableType),
+			},
+			{
+				ParentNodeID:    ua.NewExpandedNodeID(objectID),
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				BrowseName:      ua.ParseQualifiedName("2:Generated"),
+				NodeClass:       ua.NodeClassObject,
+				NodeAttributes:  ua.ObjectAttributes{},
+				TypeDefinition:  ua.NewExpandedNodeID(ua.ObjectTypeIDFolderType),
 			},
 		},
 	}
-
-	res, err := ch.Call(ctx, req)
+	res, err := ch.AddNodes(ctx, req)
 	if err != nil {
-		t.Error(errors.Wrap(err, "Error calling method"))
+		t.Error(errors.Wrap(err, "Error adding nodes"))
 		ch.Abort(ctx)
 		return
 	}
-	_ = res
-
-	t.Logf("Collecting 10 seconds of data...")
-	time.Sleep(10 * time.Second)
-
-	t.Log("Reading history for last 10 seconds")
-	var cp ua.ByteString
-	for {
-		req2 := &ua.HistoryReadRequest{
-			HistoryReadDetails: ua.ReadRawModifiedDetails{
-				StartTime:        time.Now().Add(-1 * time.Minute),
-				EndTime:          time.Now(),
-				NumValuesPerNode: 100,
-				ReturnBounds:     false,
-			},
-			TimestampsToReturn:        ua.TimestampsToReturnBoth,
-			ReleaseContinuationPoints: false,
-			NodesToRead: []ua.HistoryReadValueID{
-				{NodeID: ua.ParseNodeID("ns=2;s=Demo.History.DoubleWithHistory"), ContinuationPoint: cp},
-			},
-		}
-
-		res2, err := ch.HistoryRead(ctx, req2)
-		if err != nil {
-			t.Error(errors.Wrap(err, "Error reading"))
-			ch.Abort(ctx)
-			return
-		}
-
-		if res2.Results[0].StatusCode.IsBad() {
-			t.Errorf("Error reading values for node '%s'. %s", req2.NodesToRead[0].NodeID, res2.Results[0].StatusCode)
-			ch.Abort(ctx)
-			return
-		}
-
-		if historyData, ok := res2.Results[0].HistoryData.(ua.HistoryData); ok {
-			t.Logf("Found %d value(s) for node '%s':", len(historyData.DataValues), req2.NodesToRead[0].NodeID)
-			for _, result := range historyData.DataValues {
-				t.Logf("Read %v, q: %#X, ts: %s", result.Value, uint32(result.StatusCode), result.SourceTimestamp)
-			}
-		}
-
-		cp = res2.Results[0].ContinuationPoint
-		if cp == "" {
-			break
+	expected := []ua.StatusCode{ua.Good, ua.Good, ua.BadNodeIDExists, ua.BadParentNodeIDInvalid, ua.BadTypeDefinitionInvalid, ua.Good}
+	for i, r := range res.Results {
+		if r.StatusCode != expected[i] {
+			t.Errorf("Error adding node %d. want: %s, got: %s", i, expected[i], r.StatusCode)
 		}
 	}
-	t.Log("Now read the 1 sec average of the last 10 seconds...")
-
-	req3 := &ua.HistoryReadRequest{
-		HistoryReadDetails: ua.ReadProcessedDetails{
-			StartTime:          time.Now().Add(-10 * time.Second),
-			EndTime:            time.Now(),
-			ProcessingInterval: 1000.0,
-			AggregateType:      []ua.NodeID{ua.ObjectIDAggregateFunctionAverage},
-		},
-		TimestampsToReturn:        ua.TimestampsToReturnBoth,
-		ReleaseContinuationPoints: false,
-		NodesToRead: []ua.HistoryReadValueID{
-			{NodeID: ua.ParseNodeID("ns=2;s=Demo.History.DoubleWithHistory")},
-		},
+	// a node without a requested NodeID is in the namespace of its browse name.
+	if id, ok := res.Results[5].AddedNodeID.(ua.NodeIDGUID); !ok || id.NamespaceIndex != 2 {
+		t.Errorf("Error generating NodeID of added node. got: %v", res.Results[5].AddedNodeID)
 	}
 
-	res3, err := ch.HistoryRead(ctx, req3)
+	res2, err := ch.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{
+			{NodeID: variableID, AttributeID: ua.AttributeIDValue},
+			{NodeID: variableID, AttributeID: ua.AttributeIDDisplayName},
+		},
+	})
 	if err != nil {
 		t.Error(errors.Wrap(err, "Error reading"))
 		ch.Abort(ctx)
 		return
 	}
+	if v, ok := res2.Results[0].Value.(float64); !ok || v != 42.0 {
+		t.Errorf("Error reading value of added node. got: %v", res2.Results[0].Value)
+	}
+	if v, ok := res2.Results[1].Value.(ua.LocalizedText); !ok || v.Text != "Value" {
+		t.Errorf("Error reading display name of added node. got: %v", res2.Results[1].Value)
+	}
 
-	if res3.Results[0].StatusCode.IsBad() {
-		t.Errorf("Error reading values for node '%s'. %s", req3.NodesToRead[0].NodeID, res3.Results[0].StatusCode)
+	res3, err := ch.AddReferences(ctx, &ua.AddReferencesRequest{
+		ReferencesToAdd: []ua.AddReferencesItem{
+			{
+				SourceNodeID:    ua.ObjectIDObjectsFolder,
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				IsForward:       true,
+				TargetNodeID:    ua.NewExpandedNodeID(objectID),
+				TargetNodeClass: ua.NodeClassObject,
+			},
+			{
+				SourceNodeID:    ua.ObjectIDObjectsFolder,
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				IsForward:       true,
+				TargetNodeID:    ua.NewExpandedNodeID(objectID),
+				TargetNodeClass: ua.NodeClassObject,
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error adding references"))
 		ch.Abort(ctx)
 		return
 	}
-
-	if historyData, ok := res3.Results[0].HistoryData.(ua.HistoryData); ok {
-		t.Logf("Found %d average value(s) for node '%s':", len(historyData.DataValues), req3.NodesToRead[0].NodeID)
-		for _, result := range historyData.DataValues {
-			t.Logf("Read %v, q: %#X, ts: %s", result.Value, uint32(result.StatusCode), result.SourceTimestamp)
-		}
+	if res3.Results[0] != ua.Good || res3.Results[1] != ua.BadDuplicateReferenceNotAllowed {
+		t.Errorf("Error adding references. got: %v", res3.Results)
 	}
 
-	t.Logf("Stop logging of data...")
-	req4 := &ua.CallRequest{
-		MethodsToCall: []ua.CallMethodRequest{{
-			ObjectID:       ua.ParseNodeID("ns=2;s=Demo.History"), // parent node
-			MethodID:       ua.ParseNodeID("ns=2;s=Demo.History.StopLogging"),
-			InputArguments: []ua.Variant{}},
+	res4, err := ch.DeleteReferences(ctx, &ua.DeleteReferencesRequest{
+		ReferencesToDelete: []ua.DeleteReferencesItem{
+			{
+				SourceNodeID:        ua.ObjectIDObjectsFolder,
+				ReferenceTypeID:     ua.ReferenceTypeIDOrganizes,
+				IsForward:           true,
+				TargetNodeID:        ua.NewExpandedNodeID(objectID),
+				DeleteBidirectional: true,
+			},
+			{
+				SourceNodeID:        ua.ObjectIDObjectsFolder,
+				ReferenceTypeID:     ua.ReferenceTypeIDOrganizes,
+				IsForward:           true,
+				TargetNodeID:        ua.NewExpandedNodeID(objectID),
+				DeleteBidirectional: true,
+			},
 		},
-	}
-
-	res4, err := ch.Call(ctx, req4)
+	})
 	if err != nil {
-		t.Error(errors.Wrap(err, "Error calling method"))
+		t.Error(errors.Wrap(err, "Error deleting references"))
 		ch.Abort(ctx)
 		return
 	}
-	_ = res4
+	if res4.Results[0] != ua.Good || res4.Results[1] != ua.BadNotFound {
+		t.Errorf("Error deleting references. got: %v", res4.Results)
+	}
+
+	res5, err := ch.DeleteNodes(ctx, &ua.DeleteNodesRequest{
+		NodesToDelete: []ua.DeleteNodesItem{
+			// the parent keeps its reference to the child, until the parent is deleted.
+			{NodeID: variableID, DeleteTargetReferences: false},
+			{NodeID: objectID, DeleteTargetReferences: true},
+			{NodeID: ua.ObjectIDServer, DeleteTargetReferences: true},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error deleting nodes"))
+		ch.Abort(ctx)
+		return
+	}
+	if res5.Results[0] != ua.Good || res5.Results[1] != ua.Good || res5.Results[2] != ua.BadNoDeleteRights {
+		t.Errorf("Error deleting nodes. got: %v", res5.Results)
+	}
+
+	res6, err := ch.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{
+			{NodeID: variableID, AttributeID: ua.AttributeIDValue},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading"))
+		ch.Abort(ctx)
+		return
+	}
+	if res6.Results[0].StatusCode != ua.BadNodeIDUnknown {
+		t.Errorf("Error deleting child node. got: %s", res6.Results[0].StatusCode)
+	}
+
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+// TestAddNodesAccessDenied tests that users without the ConfigureAdmin role may not add nodes.
+func TestAddNodesAccessDenied(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("user1", "password"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	res, err := ch.AddNodes(ctx, &ua.AddNodesRequest{
+		NodesToAdd: []ua.AddNodesItem{
+			{
+				ParentNodeID:    ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Demo")),
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				BrowseName:      ua.ParseQualifiedName("2:Denied"),
+				NodeClass:       ua.NodeClassObject,
+				NodeAttributes:  ua.ObjectAttributes{},
+				TypeDefinition:  ua.NewExpandedNodeID(ua.ObjectTypeIDFolderType),
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error adding nodes"))
+		ch.Abort(ctx)
+		return
+	}
+	if res.Results[0].StatusCode != ua.BadUserAccessDenied {
+		t.Errorf("Error adding nodes. want: %s, got: %s", ua.BadUserAccessDenied, res.Results[0].StatusCode)
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+// TestHistoryUpdate tests inserting, replacing and deleting historical values.
+func TestHistoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	t.Logf("Success connecting to server: %s", ch.EndpointURL())
+
+	nodeID := ua.ParseNodeID("ns=2;s=Demo.History.Double")
+	t0 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	t2 := t0.Add(2 * time.Second)
+	res, err := ch.HistoryUpdate(ctx, &ua.HistoryUpdateRequest{
+		HistoryUpdateDetails: []ua.ExtensionObject{
+			ua.UpdateDataDetails{
+				NodeID:               nodeID,
+				PerformInsertReplace: ua.PerformUpdateTypeInsert,
+				UpdateValues: []ua.DataValue{
+					ua.NewDataValue(float64(1.0), 0, t0, 0, t0, 0),
+					ua.NewDataValue(float64(2.0), 0, t1, 0, t1, 0),
+					ua.NewDataValue(float64(3.0), 0, t2, 0, t2, 0),
+				},
+			},
+			ua.UpdateDataDetails{
+				NodeID:               nodeID,
+				PerformInsertReplace: ua.PerformUpdateTypeReplace,
+				UpdateValues: []ua.DataValue{
+					ua.NewDataValue(float64(20.0), 0, t1, 0, t1, 0),
+					ua.NewDataValue(float64(40.0), 0, t2.Add(time.Second), 0, t2, 0),
+				},
+			},
+			ua.DeleteAtTimeDetails{
+				NodeID:   nodeID,
+				ReqTimes: []time.Time{t0},
+			},
+			ua.UpdateDataDetails{
+				NodeID:               ua.ParseNodeID("ns=2;s=Demo.Static.Scalar.Double"),
+				PerformInsertReplace: ua.PerformUpdateTypeInsert,
+				UpdateValues: []ua.DataValue{
+					ua.NewDataValue(float64(1.0), 0, t0, 0, t0, 0),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error updating history"))
+		ch.Abort(ctx)
+		return
+	}
+	expected := [][]ua.StatusCode{
+		{ua.GoodEntryInserted, ua.GoodEntryInserted, ua.GoodEntryInserted},
+		{ua.GoodEntryReplaced, ua.BadNoEntryExists},
+		{ua.Good},
+	}
+	for i, ops := range expected {
+		if res.Results[i].StatusCode.IsBad() {
+			t.Errorf("Error updating history %d. got: %s", i, res.Results[i].StatusCode)
+			continue
+		}
+		for j, op := range ops {
+			if res.Results[i].OperationResults[j] != op {
+				t.Errorf("Error updating history %d.%d. want: %s, got: %s", i, j, op, res.Results[i].OperationResults[j])
+			}
+		}
+	}
+	if res.Results[3].StatusCode != ua.BadHistoryOperationUnsupported {
+		t.Errorf("Error updating history of node without HistoryWrite access. got: %s", res.Results[3].StatusCode)
+	}
+
+	res2, err := ch.HistoryRead(ctx, &ua.HistoryReadRequest{
+		HistoryReadDetails: ua.ReadRawModifiedDetails{
+			StartTime: t0,
+			EndTime:   t2.Add(time.Second),
+		},
+		TimestampsToReturn: ua.TimestampsToReturnSource,
+		NodesToRead:        []ua.HistoryReadValueID{{NodeID: nodeID}},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading history"))
+		ch.Abort(ctx)
+		return
+	}
+	if data, ok := res2.Results[0].HistoryData.(ua.HistoryData); !ok || len(data.DataValues) != 2 || data.DataValues[0].Value != float64(20.0) {
+		t.Errorf("Error reading history. got: %v", res2.Results[0].HistoryData)
+	}
+
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+
+	// anonymous users may read but not update the history.
+	ch, err = client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	res3, err := ch.HistoryUpdate(ctx, &ua.HistoryUpdateRequest{
+		HistoryUpdateDetails: []ua.ExtensionObject{
+			ua.DeleteRawModifiedDetails{
+				NodeID:    nodeID,
+				StartTime: t0,
+				EndTime:   t2.Add(time.Second),
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error updating history"))
+		ch.Abort(ctx)
+		return
+	}
+	if res3.Results[0].StatusCode != ua.BadUserAccessDenied {
+		t.Errorf("Error updating history. want: %s, got: %s", ua.BadUserAccessDenied, res3.Results[0].StatusCode)
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+// TestPercentDeadband tests filtering data changes with a percent deadband of the EURange.
+func TestPercentDeadband(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	nodeID := ua.ParseNodeID("ns=2;s=Demo.Analog.Double")
+	write := func(value float64) error {
+		res, err := ch.Write(ctx, &ua.WriteRequest{
+			NodesToWrite: []ua.WriteValue{
+				{NodeID: nodeID, AttributeID: ua.AttributeIDValue, Value: ua.NewDataValue(value, 0, time.Time{}, 0, time.Time{}, 0)},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if res.Results[0].IsBad() {
+			return res.Results[0]
+		}
+		return nil
+	}
+	if err := write(0.0); err != nil {
+		t.Error(errors.Wrap(err, "Error writing"))
+		ch.Abort(ctx)
+		return
+	}
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 100.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	item := func(nodeID ua.NodeID, percent float64) ua.MonitoredItemCreateRequest {
+		return ua.MonitoredItemCreateRequest{
+			ItemToMonitor:  ua.ReadValueID{NodeID: nodeID, AttributeID: ua.AttributeIDValue},
+			MonitoringMode: ua.MonitoringModeReporting,
+			RequestedParameters: ua.MonitoringParameters{
+				ClientHandle: 42, QueueSize: 10, DiscardOldest: true, SamplingInterval: 100.0,
+				Filter: ua.DataChangeFilter{
+					Trigger:       ua.DataChangeTriggerStatusValue,
+					DeadbandType:  uint32(ua.DeadbandTypePercent),
+					DeadbandValue: percent,
+				},
+			},
+		}
+	}
+	res2, err := ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			item(nodeID, 10.0),
+			item(ua.ParseNodeID("ns=2;s=Demo.History.Double"), 10.0),
+			item(nodeID, 150.0),
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating items"))
+		ch.Abort(ctx)
+		return
+	}
+	expected := []ua.StatusCode{ua.Good, ua.BadDeadbandFilterInvalid, ua.BadDeadbandFilterInvalid}
+	for i, r := range res2.Results {
+		if r.StatusCode != expected[i] {
+			t.Errorf("Error creating item %d. want: %s, got: %s", i, expected[i], r.StatusCode)
+		}
+	}
+	// with EURange 0..100, a change of 5 is within the 10 percent deadband, a change of 20 is not.
+	for _, v := range []float64{5.0, 20.0} {
+		time.Sleep(300 * time.Millisecond)
+		if err := write(v); err != nil {
+			t.Error(errors.Wrap(err, "Error writing"))
+			ch.Abort(ctx)
+			return
+		}
+	}
+	values := []float64{}
+	req := &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}}
+	for len(values) == 0 || values[len(values)-1] != 20.0 {
+		res3, err := ch.Publish(ctx, req)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error publishing"))
+			ch.Abort(ctx)
+			return
+		}
+		for _, data := range res3.NotificationMessage.NotificationData {
+			if body, ok := data.(ua.DataChangeNotification); ok {
+				for _, z := range body.MonitoredItems {
+					values = append(values, z.Value.Value.(float64))
+				}
+			}
+		}
+		req = &ua.PublishRequest{
+			RequestHeader: ua.RequestHeader{TimeoutHint: 60000},
+			SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{
+				{SequenceNumber: res3.NotificationMessage.SequenceNumber, SubscriptionID: res3.SubscriptionID},
+			},
+		}
+	}
+	if len(values) != 2 || values[0] != 0.0 {
+		t.Errorf("Error filtering data changes. want: [0 20], got: %v", values)
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 100.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	severity := ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDBaseEventType, BrowsePath: ua.ParseBrowsePath("Severity"), AttributeID: ua.AttributeIDValue}
+	sourceName := ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDBaseEventType, BrowsePath: ua.ParseBrowsePath("SourceName"), AttributeID: ua.AttributeIDValue}
+	item := func(handle uint32, whereClause ua.ContentFilter) ua.MonitoredItemCreateRequest {
+		return ua.MonitoredItemCreateRequest{
+			ItemToMonitor:  ua.ReadValueID{NodeID: ua.ParseNodeID("ns=2;s=Area1"), AttributeID: ua.AttributeIDEventNotifier},
+			MonitoringMode: ua.MonitoringModeReporting,
+			RequestedParameters: ua.MonitoringParameters{
+				ClientHandle: handle, QueueSize: 100, DiscardOldest: true, SamplingInterval: 0.0,
+				Filter: ua.EventFilter{SelectClauses: ua.BaseEventSelectClauses, WhereClause: whereClause},
+			},
+		}
+	}
+	// a chain of elements, where each refers to the next one twice, ending with Severity > 300.
+	chain := make([]ua.ContentFilterElement, 64)
+	for i := range chain[:len(chain)-1] {
+		next := ua.ElementOperand{Index: uint32(i + 1)}
+		chain[i] = ua.ContentFilterElement{FilterOperator: ua.FilterOperatorAnd, FilterOperands: []ua.ExtensionObject{next, next}}
+	}
+	chain[len(chain)-1] = ua.ContentFilterElement{FilterOperator: ua.FilterOperatorGreaterThan, FilterOperands: []ua.ExtensionObject{severity, ua.LiteralOperand{Value: int32(300)}}}
+	res2, err := ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			// Severity > 300 AND SourceName LIKE 'Area_'
+			item(42, ua.ContentFilter{Elements: []ua.ContentFilterElement{
+				{FilterOperator: ua.FilterOperatorAnd, FilterOperands: []ua.ExtensionObject{ua.ElementOperand{Index: 1}, ua.ElementOperand{Index: 2}}},
+				{FilterOperator: ua.FilterOperatorGreaterThan, FilterOperands: []ua.ExtensionObject{severity, ua.LiteralOperand{Value: int32(300)}}},
+				{FilterOperator: ua.FilterOperatorLike, FilterOperands: []ua.ExtensionObject{sourceName, ua.LiteralOperand{Value: "Area_"}}},
+			}}),
+			// an element that refers to itself, and an element with missing operands.
+			item(43, ua.ContentFilter{Elements: []ua.ContentFilterElement{
+				{FilterOperator: ua.FilterOperatorEquals, FilterOperands: []ua.ExtensionObject{ua.ElementOperand{Index: 0}, ua.LiteralOperand{Value: true}}},
+				{FilterOperator: ua.FilterOperatorNot, FilterOperands: []ua.ExtensionObject{}},
+			}}),
+			// each element is evaluated once per event.
+			item(44, ua.ContentFilter{Elements: chain}),
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating items"))
+		ch.Abort(ctx)
+		return
+	}
+	if r := res2.Results[0]; r.StatusCode != ua.Good || r.FilterResult != nil {
+		t.Errorf("Error creating item. want: Good, got: %s, %v", r.StatusCode, r.FilterResult)
+	}
+	if r := res2.Results[1]; r.StatusCode != ua.BadMonitoredItemFilterInvalid {
+		t.Errorf("Error creating item. want: %s, got: %s", ua.BadMonitoredItemFilterInvalid, r.StatusCode)
+	} else if fr, ok := r.FilterResult.(ua.EventFilterResult); !ok || len(fr.WhereClauseResult.ElementResults) != 2 ||
+		fr.WhereClauseResult.ElementResults[0].StatusCode != ua.BadFilterOperandInvalid ||
+		fr.WhereClauseResult.ElementResults[1].StatusCode != ua.BadFilterOperandCountMismatch {
+		t.Errorf("Error validating where clause. got: %v", r.FilterResult)
+	}
+	if r := res2.Results[2]; r.StatusCode != ua.Good {
+		t.Errorf("Error creating item. want: Good, got: %s", r.StatusCode)
+	}
+	severities := []uint16{}
+	req := &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}}
+	for len(severities) < 4 {
+		res3, err := ch.Publish(ctx, req)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error publishing"))
+			ch.Abort(ctx)
+			return
+		}
+		for _, data := range res3.NotificationMessage.NotificationData {
+			if body, ok := data.(ua.EventNotificationList); ok {
+				for _, z := range body.Events {
+					severities = append(severities, z.EventFields[7].(uint16))
+				}
+			}
+		}
+		req = &ua.PublishRequest{
+			RequestHeader: ua.RequestHeader{TimeoutHint: 60000},
+			SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{
+				{SequenceNumber: res3.NotificationMessage.SequenceNumber, SubscriptionID: res3.SubscriptionID},
+			},
+		}
+	}
+	for _, s := range severities {
+		if s <= 300 {
+			t.Errorf("Error filtering events. want: severity > 300, got: %v", severities)
+			break
+		}
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+func TestAggregateFilter(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	nodeID := ua.ParseNodeID("ns=2;s=Demo.Analog.Double")
+	res0, err := ch.Write(ctx, &ua.WriteRequest{
+		NodesToWrite: []ua.WriteValue{
+			{NodeID: nodeID, AttributeID: ua.AttributeIDValue, Value: ua.NewDataValue(42.0, 0, time.Time{}, 0, time.Time{}, 0)},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error writing"))
+		ch.Abort(ctx)
+		return
+	}
+	if res0.Results[0].IsBad() {
+		t.Error(errors.Wrap(res0.Results[0], "Error writing"))
+		ch.Abort(ctx)
+		return
+	}
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 100.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	item := func(aggregateType ua.NodeID) ua.MonitoredItemCreateRequest {
+		return ua.MonitoredItemCreateRequest{
+			ItemToMonitor:  ua.ReadValueID{NodeID: nodeID, AttributeID: ua.AttributeIDValue},
+			MonitoringMode: ua.MonitoringModeReporting,
+			RequestedParameters: ua.MonitoringParameters{
+				ClientHandle: 42, QueueSize: 10, DiscardOldest: true, SamplingInterval: 100.0,
+				Filter: ua.AggregateFilter{
+					AggregateType:          aggregateType,
+					ProcessingInterval:     500.0,
+					AggregateConfiguration: ua.AggregateConfiguration{UseServerCapabilitiesDefaults: true},
+				},
+			},
+		}
+	}
+	res2, err := ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			item(ua.ObjectIDAggregateFunctionAverage),
+			item(ua.ObjectIDAggregateFunctionTotal),
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating items"))
+		ch.Abort(ctx)
+		return
+	}
+	if r := res2.Results[0]; r.StatusCode != ua.Good {
+		t.Errorf("Error creating item. want: Good, got: %s", r.StatusCode)
+	} else if fr, ok := r.FilterResult.(ua.AggregateFilterResult); !ok || fr.RevisedProcessingInterval != 500.0 || fr.RevisedStartTime.IsZero() {
+		t.Errorf("Error revising filter. got: %v", r.FilterResult)
+	}
+	if r := res2.Results[1]; r.StatusCode != ua.BadAggregateNotSupported {
+		t.Errorf("Error creating item. want: %s, got: %s", ua.BadAggregateNotSupported, r.StatusCode)
+	}
+	values := []ua.DataValue{}
+	req := &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}}
+	for len(values) < 3 {
+		res3, err := ch.Publish(ctx, req)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error publishing"))
+			ch.Abort(ctx)
+			return
+		}
+		for _, data := range res3.NotificationMessage.NotificationData {
+			if body, ok := data.(ua.DataChangeNotification); ok {
+				for _, z := range body.MonitoredItems {
+					values = append(values, z.Value)
+				}
+			}
+		}
+		req = &ua.PublishRequest{
+			RequestHeader: ua.RequestHeader{TimeoutHint: 60000},
+			SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{
+				{SequenceNumber: res3.NotificationMessage.SequenceNumber, SubscriptionID: res3.SubscriptionID},
+			},
+		}
+	}
+	// after the first interval, each interval is covered by good samples of the constant value.
+	for _, v := range values[1:] {
+		if v.Value != 42.0 || !v.StatusCode.IsGood() || uint32(v.StatusCode)&ua.HistorianBitsMask != ua.HistorianBitsCalculated {
+			t.Errorf("Error calculating average. want: 42 (Good, Calculated), got: %v (%#x)", v.Value, uint32(v.StatusCode))
+		}
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+func TestConditionRefresh(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 100.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	res2, err := ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			{
+				ItemToMonitor:  ua.ReadValueID{NodeID: ua.ParseNodeID("ns=2;s=Area2"), AttributeID: ua.AttributeIDEventNotifier},
+				MonitoringMode: ua.MonitoringModeReporting,
+				RequestedParameters: ua.MonitoringParameters{
+					ClientHandle: 42, QueueSize: 100, DiscardOldest: true, SamplingInterval: 0.0,
+					Filter: ua.EventFilter{SelectClauses: ua.AlarmConditionSelectClauses},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating items"))
+		ch.Abort(ctx)
+		return
+	}
+	if r := res2.Results[0]; r.StatusCode != ua.Good {
+		t.Errorf("Error creating item. want: Good, got: %s", r.StatusCode)
+	}
+	res3, err := ch.Call(ctx, &ua.CallRequest{
+		MethodsToCall: []ua.CallMethodRequest{{
+			ObjectID:       ua.ObjectTypeIDConditionType,
+			MethodID:       ua.MethodIDConditionTypeConditionRefresh,
+			InputArguments: []ua.Variant{res.SubscriptionID},
+		}},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling method"))
+		ch.Abort(ctx)
+		return
+	}
+	if res3.Results[0].StatusCode.IsBad() {
+		t.Error(errors.Wrap(res3.Results[0].StatusCode, "Error calling ConditionRefresh"))
+		ch.Abort(ctx)
+		return
+	}
+	alarmID := ua.ParseNodeID("ns=2;s=Area2.HighLevel")
+	req := &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}}
+	// publish until an event of the alarm satisfies the predicate.
+	nextAlarmEvent := func(pred func(fields []ua.Variant) bool) ([]ua.Variant, []ua.NodeID, error) {
+		eventTypes := []ua.NodeID{}
+		for {
+			res4, err := ch.Publish(ctx, req)
+			if err != nil {
+				return nil, nil, err
+			}
+			req = &ua.PublishRequest{
+				RequestHeader: ua.RequestHeader{TimeoutHint: 60000},
+				SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{
+					{SequenceNumber: res4.NotificationMessage.SequenceNumber, SubscriptionID: res4.SubscriptionID},
+				},
+			}
+			for _, data := range res4.NotificationMessage.NotificationData {
+				if body, ok := data.(ua.EventNotificationList); ok {
+					for _, z := range body.Events {
+						eventTypes = append(eventTypes, z.EventFields[1].(ua.NodeID))
+						if z.EventFields[8] == alarmID && pred(z.EventFields) {
+							return z.EventFields, eventTypes, nil
+						}
+					}
+				}
+			}
+		}
+	}
+	fields, eventTypes, err := nextAlarmEvent(func(fields []ua.Variant) bool { return true })
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error publishing"))
+		ch.Abort(ctx)
+		return
+	}
+	if len(eventTypes) != 2 || eventTypes[0] != ua.ObjectTypeIDRefreshStartEventType || eventTypes[1] != ua.ObjectTypeIDAlarmConditionType {
+		t.Errorf("Error refreshing conditions. want: [RefreshStart, AlarmCondition], got: %v", eventTypes)
+	}
+	if fields[11] != true || fields[14] != true {
+		t.Errorf("Error refreshing conditions. want: Retain and Active, got: %v, %v", fields[11], fields[14])
+	}
+	acknowledge := func(eventID ua.Variant) (ua.StatusCode, error) {
+		res5, err := ch.Call(ctx, &ua.CallRequest{
+			MethodsToCall: []ua.CallMethodRequest{{
+				ObjectID:       alarmID,
+				MethodID:       ua.MethodIDAcknowledgeableConditionTypeAcknowledge,
+				InputArguments: []ua.Variant{eventID, ua.LocalizedText{Text: "Acknowledged by test"}},
+			}},
+		})
+		if err != nil {
+			return ua.Good, err
+		}
+		return res5.Results[0].StatusCode, nil
+	}
+	if fields[12] != true {
+		sc, err := acknowledge(fields[0])
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error calling method"))
+			ch.Abort(ctx)
+			return
+		}
+		if sc != ua.Good {
+			t.Errorf("Error acknowledging alarm. want: Good, got: %s", sc)
+		}
+		fields, _, err = nextAlarmEvent(func(fields []ua.Variant) bool { return fields[12] == true })
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error publishing"))
+			ch.Abort(ctx)
+			return
+		}
+	}
+	sc, err := acknowledge(fields[0])
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling method"))
+		ch.Abort(ctx)
+		return
+	}
+	if sc != ua.BadConditionBranchAlreadyAcked {
+		t.Errorf("Error acknowledging alarm. want: %s, got: %s", ua.BadConditionBranchAlreadyAcked, sc)
+	}
+	sc, err = acknowledge(ua.ByteString("unknown"))
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling method"))
+		ch.Abort(ctx)
+		return
+	}
+	if sc != ua.BadEventIDUnknown {
+		t.Errorf("Error acknowledging alarm. want: %s, got: %s", ua.BadEventIDUnknown, sc)
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+func TestLimitAlarm(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 100.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	selectClauses := append([]ua.SimpleAttributeOperand{}, ua.AlarmConditionSelectClauses...)
+	selectClauses = append(selectClauses,
+		ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDExclusiveLimitAlarmType, BrowsePath: ua.ParseBrowsePath("LimitState/CurrentState/Id"), AttributeID: ua.AttributeIDValue},
+		ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDNonExclusiveLimitAlarmType, BrowsePath: ua.ParseBrowsePath("HighHighState/Id"), AttributeID: ua.AttributeIDValue},
+		ua.SimpleAttributeOperand{TypeDefinitionID: ua.ObjectTypeIDNonExclusiveLimitAlarmType, BrowsePath: ua.ParseBrowsePath("HighState/Id"), AttributeID: ua.AttributeIDValue},
+	)
+	res2, err := ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			{
+				ItemToMonitor:  ua.ReadValueID{NodeID: ua.ParseNodeID("ns=2;s=Tank1"), AttributeID: ua.AttributeIDEventNotifier},
+				MonitoringMode: ua.MonitoringModeReporting,
+				RequestedParameters: ua.MonitoringParameters{
+					ClientHandle: 42, QueueSize: 100, DiscardOldest: true, SamplingInterval: 0.0,
+					Filter: ua.EventFilter{SelectClauses: selectClauses},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating items"))
+		ch.Abort(ctx)
+		return
+	}
+	if r := res2.Results[0]; r.StatusCode != ua.Good {
+		t.Errorf("Error creating item. want: Good, got: %s", r.StatusCode)
+	}
+	exclusiveID := ua.ParseNodeID("ns=2;s=Tank1.LevelAlarm")
+	nonExclusiveID := ua.ParseNodeID("ns=2;s=Tank1.LevelLimits")
+	req := &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}}
+	// writes the level and returns the next events of the exclusive and non-exclusive alarm.
+	setLevel := func(level float64) (exclusive, nonExclusive []ua.Variant, err error) {
+		res3, err := ch.Write(ctx, &ua.WriteRequest{
+			NodesToWrite: []ua.WriteValue{
+				{NodeID: ua.ParseNodeID("ns=2;s=Tank1.Level"), AttributeID: ua.AttributeIDValue, Value: ua.NewDataValue(level, 0, time.Time{}, 0, time.Time{}, 0)},
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if res3.Results[0].IsBad() {
+			return nil, nil, res3.Results[0]
+		}
+		for i := 0; i < 10 && (exclusive == nil || nonExclusive == nil); i++ {
+			res4, err := ch.Publish(ctx, req)
+			if err != nil {
+				return nil, nil, err
+			}
+			req = &ua.PublishRequest{
+				RequestHeader: ua.RequestHeader{TimeoutHint: 60000},
+				SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{
+					{SequenceNumber: res4.NotificationMessage.SequenceNumber, SubscriptionID: res4.SubscriptionID},
+				},
+			}
+			for _, data := range res4.NotificationMessage.NotificationData {
+				if body, ok := data.(ua.EventNotificationList); ok {
+					for _, z := range body.Events {
+						switch z.EventFields[8] {
+						case exclusiveID:
+							exclusive = z.EventFields
+						case nonExclusiveID:
+							nonExclusive = z.EventFields
+						}
+					}
+				}
+			}
+		}
+		return exclusive, nonExclusive, nil
+	}
+	cases := []struct {
+		level         float64
+		active        bool
+		limitState    ua.Variant
+		highHighState ua.Variant
+		highState     ua.Variant
+	}{
+		{75, true, ua.ObjectIDExclusiveLimitStateMachineTypeHigh, false, true},
+		{95, true, ua.ObjectIDExclusiveLimitStateMachineTypeHighHigh, true, true},
+		// the high limit remains exceeded within the deadband, after the high high limit clears.
+		{69, true, ua.ObjectIDExclusiveLimitStateMachineTypeHigh, false, true},
+		{50, false, nil, false, false},
+	}
+	for _, c := range cases {
+		exclusive, nonExclusive, err := setLevel(c.level)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error setting level"))
+			ch.Abort(ctx)
+			return
+		}
+		if exclusive == nil || nonExclusive == nil {
+			t.Errorf("Error evaluating limits of level %v. want: events of both alarms", c.level)
+			continue
+		}
+		if exclusive[14] != c.active || exclusive[15] != c.limitState || exclusive[1] != ua.ObjectTypeIDExclusiveLimitAlarmType {
+			t.Errorf("Error evaluating exclusive limits of level %v. want: %v, %v, got: %v, %v", c.level, c.active, c.limitState, exclusive[14], exclusive[15])
+		}
+		if nonExclusive[14] != c.active || nonExclusive[16] != c.highHighState || nonExclusive[17] != c.highState {
+			t.Errorf("Error evaluating non-exclusive limits of level %v. want: %v, %v, %v, got: %v, %v, %v", c.level, c.active, c.highHighState, c.highState, nonExclusive[14], nonExclusive[16], nonExclusive[17])
+		}
+		if !c.active && exclusive[7] != uint16(100) {
+			t.Errorf("Error evaluating severity of level %v. want: %v, got: %v", c.level, 100, exclusive[7])
+		}
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+func TestShelving(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	res, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 100.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	selectClauses := append([]ua.SimpleAttributeOperand{}, ua.AlarmConditionSelectClauses...)
+	selectClauses = append(selectClauses, ua.AlarmConditionSelectClauseShelvingStateCurrentState)
+	item := func(handle uint32, whereClause ua.ContentFilter) ua.MonitoredItemCreateRequest {
+		return ua.MonitoredItemCreateRequest{
+			ItemToMonitor:  ua.ReadValueID{NodeID: ua.ParseNodeID("ns=2;s=Area2"), AttributeID: ua.AttributeIDEventNotifier},
+			MonitoringMode: ua.MonitoringModeReporting,
+			RequestedParameters: ua.MonitoringParameters{
+				ClientHandle: handle, QueueSize: 100, DiscardOldest: true, SamplingInterval: 0.0,
+				Filter: ua.EventFilter{SelectClauses: selectClauses, WhereClause: whereClause},
+			},
+		}
+	}
+	res2, err := ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     res.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			item(1, ua.ContentFilter{}),
+			// ShelvingState/CurrentState = 'Unshelved'
+			item(2, ua.ContentFilter{Elements: []ua.ContentFilterElement{
+				{FilterOperator: ua.FilterOperatorEquals, FilterOperands: []ua.ExtensionObject{ua.AlarmConditionSelectClauseShelvingStateCurrentState, ua.LiteralOperand{Value: "Unshelved"}}},
+			}}),
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating items"))
+		ch.Abort(ctx)
+		return
+	}
+	for _, r := range res2.Results {
+		if r.StatusCode != ua.Good {
+			t.Errorf("Error creating item. want: Good, got: %s", r.StatusCode)
+		}
+	}
+	alarmID := ua.ParseNodeID("ns=2;s=Area2.HighLevel")
+	shelvingStateID := ua.ParseNodeID("ns=2;s=Area2.HighLevel.ShelvingState")
+	call := func(methodID ua.NodeID, args ...ua.Variant) (ua.StatusCode, error) {
+		res3, err := ch.Call(ctx, &ua.CallRequest{
+			MethodsToCall: []ua.CallMethodRequest{{ObjectID: shelvingStateID, MethodID: methodID, InputArguments: args}},
+		})
+		if err != nil {
+			return ua.Good, err
+		}
+		return res3.Results[0].StatusCode, nil
+	}
+	req := &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}}
+	// publish until the first item receives an event of the alarm with the given shelving state.
+	// returns the shelving states of the events of the alarm that the second item received meanwhile.
+	waitFor := func(state string) ([]string, error) {
+		filtered := []string{}
+		for i := 0; i < 20; i++ {
+			res4, err := ch.Publish(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			req = &ua.PublishRequest{
+				RequestHeader: ua.RequestHeader{TimeoutHint: 60000},
+				SubscriptionAcknowledgements: []ua.SubscriptionAcknowledgement{
+					{SequenceNumber: res4.NotificationMessage.SequenceNumber, SubscriptionID: res4.SubscriptionID},
+				},
+			}
+			found := false
+			for _, data := range res4.NotificationMessage.NotificationData {
+				if body, ok := data.(ua.EventNotificationList); ok {
+					for _, z := range body.Events {
+						if z.EventFields[8] != alarmID {
+							continue
+						}
+						text := z.EventFields[15].(ua.LocalizedText).Text
+						switch z.ClientHandle {
+						case 1:
+							found = found || text == state
+						case 2:
+							filtered = append(filtered, text)
+						}
+					}
+				}
+			}
+			if found {
+				return filtered, nil
+			}
+		}
+		return nil, errors.Errorf("Error waiting for shelving state '%s'", state)
+	}
+	sc, err := call(ua.MethodIDShelvedStateMachineTypeOneShotShelve)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling method"))
+		ch.Abort(ctx)
+		return
+	}
+	if sc != ua.Good {
+		t.Errorf("Error shelving alarm. want: Good, got: %s", sc)
+	}
+	filtered, err := waitFor("OneShotShelved")
+	if err != nil {
+		t.Error(err)
+		ch.Abort(ctx)
+		return
+	}
+	if len(filtered) > 0 {
+		t.Errorf("Error filtering shelved alarm. want: no events, got: %v", filtered)
+	}
+	if sc, _ := call(ua.MethodIDShelvedStateMachineTypeOneShotShelve); sc != ua.BadConditionAlreadyShelved {
+		t.Errorf("Error shelving alarm. want: %s, got: %s", ua.BadConditionAlreadyShelved, sc)
+	}
+	if sc, _ := call(ua.MethodIDShelvedStateMachineTypeTimedShelve, float64(-1)); sc != ua.BadShelvingTimeOutOfRange {
+		t.Errorf("Error shelving alarm. want: %s, got: %s", ua.BadShelvingTimeOutOfRange, sc)
+	}
+	// the timed shelf expires after 500 ms.
+	if sc, _ := call(ua.MethodIDShelvedStateMachineTypeTimedShelve, float64(500)); sc != ua.Good {
+		t.Errorf("Error shelving alarm. want: Good, got: %s", sc)
+	}
+	filtered, err = waitFor("Unshelved")
+	if err != nil {
+		t.Error(err)
+		ch.Abort(ctx)
+		return
+	}
+	if len(filtered) != 1 || filtered[0] != "Unshelved" {
+		t.Errorf("Error unshelving alarm. want: [Unshelved], got: %v", filtered)
+	}
+	if sc, _ := call(ua.MethodIDShelvedStateMachineTypeUnshelve); sc != ua.BadConditionNotShelved {
+		t.Errorf("Error unshelving alarm. want: %s, got: %s", ua.BadConditionNotShelved, sc)
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	browse := func(nodeID ua.NodeID) ([]string, error) {
+		res, err := ch.Browse(ctx, &ua.BrowseRequest{
+			NodesToBrowse: []ua.BrowseDescription{{
+				NodeID:          nodeID,
+				BrowseDirection: ua.BrowseDirectionForward,
+				ReferenceTypeID: ua.ReferenceTypeIDHierarchicalReferences,
+				IncludeSubtypes: true,
+				ResultMask:      uint32(ua.BrowseResultMaskAll),
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if res.Results[0].StatusCode.IsBad() {
+			return nil, res.Results[0].StatusCode
+		}
+		names := []string{}
+		for _, r := range res.Results[0].References {
+			names = append(names, r.BrowseName.Name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	cases := []struct {
+		nodeID ua.NodeID
+		names  []string
+	}{
+		// the methods of FileType are referenced.
+		{ua.ParseNodeID("ns=2;s=Demo.FileInstance"), []string{"Close", "GetPosition", "Open", "OpenCount", "Read", "SetPosition", "Size", "UserWritable", "Writable", "Write"}},
+		// the children of ServerStatusType, and of the BuildInfo declaration and BuildInfoType.
+		{ua.ParseNodeID("ns=2;s=Demo.StatusInstance"), []string{"BuildInfo", "CurrentTime", "SecondsTillShutdown", "ShutdownReason", "StartTime", "State"}},
+		{ua.ParseNodeID("ns=2;s=Demo.StatusInstance.BuildInfo"), []string{"BuildDate", "BuildNumber", "ManufacturerName", "ProductName", "ProductUri", "SoftwareVersion"}},
+	}
+	for _, c := range cases {
+		names, err := browse(c.nodeID)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error browsing"))
+			continue
+		}
+		if !reflect.DeepEqual(names, c.names) {
+			t.Errorf("Error instantiating type. want: %v, got: %v", c.names, names)
+		}
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+func TestNodeManager(t *testing.T) {
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		endpointURL,
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(),
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	res, err := ch.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{{NodeID: ua.VariableIDServerNamespaceArray, AttributeID: ua.AttributeIDValue}},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading"))
+		ch.Abort(ctx)
+		return
+	}
+	nsIndex, linksIndex := uint16(0), uint16(0)
+	for i, nsu := range res.Results[0].Value.([]string) {
+		switch nsu {
+		case "http://github.com/awcullen/opcua/testserver/tags":
+			nsIndex = uint16(i)
+		case "http://github.com/awcullen/opcua/testserver/links":
+			linksIndex = uint16(i)
+		}
+	}
+	if nsIndex == 0 || linksIndex == 0 {
+		t.Error("Error finding namespaces of tags and links")
+		ch.Abort(ctx)
+		return
+	}
+	tag := func(i int) ua.NodeID {
+		return ua.NewNodeIDString(nsIndex, fmt.Sprintf("Tag%d", i))
+	}
+	res2, err := ch.Write(ctx, &ua.WriteRequest{
+		NodesToWrite: []ua.WriteValue{
+			{NodeID: tag(123457), AttributeID: ua.AttributeIDValue, Value: ua.NewDataValue(int32(7), 0, time.Time{}, 0, time.Time{}, 0)},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error writing"))
+		ch.Abort(ctx)
+		return
+	}
+	if res2.Results[0] != ua.Good {
+		t.Errorf("Error writing tag. want: Good, got: %s", res2.Results[0])
+	}
+	res3, err := ch.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{
+			{NodeID: tag(123456), AttributeID: ua.AttributeIDValue},
+			{NodeID: tag(123457), AttributeID: ua.AttributeIDValue},
+			{NodeID: tag(1000000), AttributeID: ua.AttributeIDValue},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading"))
+		ch.Abort(ctx)
+		return
+	}
+	if v := res3.Results[0].Value; v != int32(123456) {
+		t.Errorf("Error reading tag. want: 123456, got: %v", v)
+	}
+	if v := res3.Results[1].Value; v != int32(7) {
+		t.Errorf("Error reading tag. want: 7, got: %v", v)
+	}
+	if sc := res3.Results[2].StatusCode; sc != ua.BadNodeIDUnknown {
+		t.Errorf("Error reading tag. want: %s, got: %s", ua.BadNodeIDUnknown, sc)
+	}
+	res4, err := ch.Browse(ctx, &ua.BrowseRequest{
+		NodesToBrowse: []ua.BrowseDescription{{
+			NodeID:          ua.NewNodeIDString(nsIndex, "Tags"),
+			BrowseDirection: ua.BrowseDirectionForward,
+			ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+			IncludeSubtypes: true,
+			ResultMask:      uint32(ua.BrowseResultMaskAll),
+		}},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error browsing"))
+		ch.Abort(ctx)
+		return
+	}
+	if n := len(res4.Results[0].References); n != 10 {
+		t.Errorf("Error browsing tags. want: 10, got: %d", n)
+	}
+	// the links are browsed without the node of the folder.
+	res4, err = ch.Browse(ctx, &ua.BrowseRequest{
+		NodesToBrowse: []ua.BrowseDescription{{
+			NodeID:          ua.NewNodeIDString(linksIndex, "Links"),
+			BrowseDirection: ua.BrowseDirectionForward,
+			ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+			IncludeSubtypes: true,
+			ResultMask:      uint32(ua.BrowseResultMaskAll),
+		}},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error browsing"))
+		ch.Abort(ctx)
+		return
+	}
+	if r := res4.Results[0]; r.StatusCode != ua.Good || len(r.References) != 2 || r.References[0].BrowseName.Name != "Server" {
+		t.Errorf("Error browsing links. want: Good, 2 references to Server and Types, got: %s, %v", r.StatusCode, r.References)
+	}
+	res5, err := ch.TranslateBrowsePathsToNodeIDs(ctx, &ua.TranslateBrowsePathsToNodeIDsRequest{
+		BrowsePaths: []ua.BrowsePath{
+			{
+				StartingNode: ua.ObjectIDObjectsFolder,
+				RelativePath: ua.RelativePath{
+					Elements: []ua.RelativePathElement{
+						{TargetName: ua.NewQualifiedName(nsIndex, "Tags")},
+						{TargetName: ua.NewQualifiedName(nsIndex, "Tag5")},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error translating browse paths"))
+		ch.Abort(ctx)
+		return
+	}
+	if r := res5.Results[0]; r.StatusCode != ua.Good || len(r.Targets) != 1 || ua.ToNodeID(r.Targets[0].TargetID, nil) != tag(5) {
+		t.Errorf("Error translating browse path. want: %v, got: %s, %v", tag(5), r.StatusCode, r.Targets)
+	}
+	sub, err := ch.CreateSubscription(ctx, &ua.CreateSubscriptionRequest{
+		RequestedPublishingInterval: 100.0,
+		RequestedMaxKeepAliveCount:  30,
+		RequestedLifetimeCount:      30 * 3,
+		PublishingEnabled:           true,
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating subscription"))
+		ch.Abort(ctx)
+		return
+	}
+	res6, err := ch.CreateMonitoredItems(ctx, &ua.CreateMonitoredItemsRequest{
+		SubscriptionID:     sub.SubscriptionID,
+		TimestampsToReturn: ua.TimestampsToReturnBoth,
+		ItemsToCreate: []ua.MonitoredItemCreateRequest{
+			{
+				ItemToMonitor:  ua.ReadValueID{NodeID: tag(42), AttributeID: ua.AttributeIDValue},
+				MonitoringMode: ua.MonitoringModeReporting,
+				RequestedParameters: ua.MonitoringParameters{
+					ClientHandle: 42, QueueSize: 1, DiscardOldest: true, SamplingInterval: 100.0,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating items"))
+		ch.Abort(ctx)
+		return
+	}
+	if sc := res6.Results[0].StatusCode; sc != ua.Good {
+		t.Errorf("Error creating item. want: Good, got: %s", sc)
+	}
+	res7, err := ch.Publish(ctx, &ua.PublishRequest{RequestHeader: ua.RequestHeader{TimeoutHint: 60000}})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error publishing"))
+		ch.Abort(ctx)
+		return
+	}
+	for _, data := range res7.NotificationMessage.NotificationData {
+		if body, ok := data.(ua.DataChangeNotification); ok {
+			if v := body.MonitoredItems[0].Value.Value; v != int32(42) {
+				t.Errorf("Error monitoring tag. want: 42, got: %v", v)
+			}
+		}
+	}
+	err = ch.Close(ctx)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+		ch.Abort(ctx)
+		return
+	}
+}
+
+// TestNodeManagerRolePermissions tests that browsing a node that a NodeManager provides without the node
+// is permitted by the RolePermissions of the NodeManager.
+func TestNodeManagerRolePermissions(t *testing.T) {
+	ctx := context.Background()
+	browse := func(userName, password, folder string) (ua.BrowseResult, error) {
+		ch, err := client.Dial(
+			ctx,
+			endpointURL,
+			client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+			client.WithInsecureSkipVerify(),
+			client.WithUserNameIdentity(userName, password),
+		)
+		if err != nil {
+			return ua.BrowseResult{}, err
+		}
+		defer ch.Close(ctx)
+		linksIndex := uint16(0)
+		for i, nsu := range ch.GetNamespaceURIs() {
+			if nsu == "http://github.com/awcullen/opcua/testserver/links" {
+				linksIndex = uint16(i)
+			}
+		}
+		if linksIndex == 0 {
+			return ua.BrowseResult{}, errors.New("Error finding namespace of links")
+		}
+		res, err := ch.Browse(ctx, &ua.BrowseRequest{
+			NodesToBrowse: []ua.BrowseDescription{{
+				NodeID:          ua.NewNodeIDString(linksIndex, folder),
+				BrowseDirection: ua.BrowseDirectionForward,
+				ReferenceTypeID: ua.ReferenceTypeIDOrganizes,
+				IncludeSubtypes: true,
+				ResultMask:      uint32(ua.BrowseResultMaskAll),
+			}},
+		})
+		if err != nil {
+			return ua.BrowseResult{}, err
+		}
+		return res.Results[0], nil
+	}
+	cases := []struct {
+		userName, password, folder string
+		want                       ua.StatusCode
+	}{
+		{"root", "secret", "AdminLinks", ua.Good},
+		{"user1", "password", "AdminLinks", ua.BadNodeIDUnknown},
+		{"user1", "password", "Links", ua.Good},
+	}
+	for _, c := range cases {
+		r, err := browse(c.userName, c.password, c.folder)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error browsing"))
+			return
+		}
+		if r.StatusCode != c.want {
+			t.Errorf("Error browsing %s as %s. want: %s, got: %s", c.folder, c.userName, c.want, r.StatusCode)
+		}
+	}
+}
+
+// TestExportNodeSet tests exporting a namespace to a UANodeSet and loading it into another server.
+func TestExportNodeSet(t *testing.T) {
+	newServer := func() (*server.Server, error) {
+		return server.New(
+			ua.ApplicationDescription{
+				ApplicationURI:  fmt.Sprintf("urn:%s:exportserver", host),
+				ApplicationName: ua.LocalizedText{Text: "exportserver"},
+				ApplicationType: ua.ApplicationTypeServer,
+			},
+			"./pki/server.crt",
+			"./pki/server.key",
+			fmt.Sprintf("opc.tcp://%s:%d", host, port+1),
+		)
+	}
+	nsu := "http://github.com/awcullen/opcua/testserver/"
+	srv1, err := newServer()
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	nm1 := srv1.NamespaceManager()
+	if err := nm1.LoadNodeSetFromBuffer(testnodeset); err != nil {
+		t.Error(errors.Wrap(err, "Error loading nodeset"))
+		return
+	}
+	ns := uint16(nm1.Add(nsu))
+	if err := nm1.AddNodes(
+		server.NewDataTypeNode(
+			srv1,
+			ua.NewNodeIDString(ns, "Demo.Point"),
+			ua.NewQualifiedName(ns, "Point"),
+			ua.NewLocalizedText("Point", ""),
+			ua.NewLocalizedText("A point in the plane", ""),
+			nil,
+			[]ua.Reference{ua.NewReference(ua.ReferenceTypeIDHasSubtype, true, ua.NewExpandedNodeID(ua.DataTypeIDStructure))},
+			false,
+			ua.StructureDefinition{
+				BaseDataType:  ua.DataTypeIDStructure,
+				StructureType: ua.StructureTypeStructure,
+				Fields: []ua.StructureField{
+					{Name: "X", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+					{Name: "Y", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+					{Name: "Tags", DataType: ua.DataTypeIDString, ValueRank: ua.ValueRankOneDimension, IsOptional: true},
+				},
+			},
+		),
+	); err != nil {
+		t.Error(errors.Wrap(err, "Error adding nodes"))
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := nm1.ExportNodeSet(buf, nsu); err != nil {
+		t.Error(errors.Wrap(err, "Error exporting nodeset"))
+		return
+	}
+	set := &ua.UANodeSet{}
+	if err := xml.Unmarshal(buf.Bytes(), set); err != nil {
+		t.Error(errors.Wrap(err, "Error decoding exported nodeset"))
+		return
+	}
+	if len(set.NamespaceUris) == 0 || set.NamespaceUris[0] != nsu {
+		t.Errorf("NamespaceUris = %v, want %s first", set.NamespaceUris, nsu)
+		return
+	}
+	var point *ua.UANode
+	for i := range set.Nodes {
+		if set.Nodes[i].NodeID == "ns=1;s=Demo.Point" {
+			point = &set.Nodes[i]
+		}
+	}
+	if point == nil || point.XMLName.Local != "UADataType" || point.Definition == nil || len(point.Definition.Field) != 3 {
+		t.Errorf("Expected definition of UADataType ns=1;s=Demo.Point, got %+v", point)
+		return
+	}
+	if f := point.Definition.Field[2]; f.Name != "Tags" || f.DataType != "String" || f.ValueRank != 1 || !f.IsOptional {
+		t.Errorf("Unexpected field %+v", f)
+	}
+
+	if err := nm1.ExportNodeSet(buf, "urn:unknown"); err != ua.BadInvalidArgument {
+		t.Errorf("Error = %v, want BadInvalidArgument", err)
+	}
+
+	// load the exported nodeset into another server and compare.
+	srv2, err := newServer()
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	nm2 := srv2.NamespaceManager()
+	if err := nm2.LoadNodeSetFromBuffer(buf.Bytes()); err != nil {
+		t.Error(errors.Wrap(err, "Error loading exported nodeset"))
+		return
+	}
+	names := []string{"Boolean", "SByte", "Byte", "Int16", "UInt16", "Int32", "UInt32", "Int64", "UInt64", "Float", "Double",
+		"String", "DateTime", "Guid", "ByteString", "XmlElement", "LocalizedText", "QualifiedName"}
+	for _, kind := range []string{"Scalar", "Arrays"} {
+		for _, name := range names {
+			id := ua.NewNodeIDString(ns, "Demo.Static."+kind+"."+name)
+			n1, ok := nm1.FindVariable(id)
+			if !ok {
+				t.Errorf("Node %s not found in source", id)
+				continue
+			}
+			n2, ok := nm2.FindVariable(id)
+			if !ok {
+				t.Errorf("Node %s not found in exported nodeset", id)
+				continue
+			}
+			if !reflect.DeepEqual(n1.Value().Value, n2.Value().Value) {
+				t.Errorf("Value of %s = %v, want %v", id, n2.Value().Value, n1.Value().Value)
+			}
+			if n1.DataType() != n2.DataType() || n1.ValueRank() != n2.ValueRank() || n1.AccessLevel() != n2.AccessLevel() {
+				t.Errorf("Attributes of %s differ", id)
+			}
+			if !reflect.DeepEqual(n1.References(), n2.References()) {
+				t.Errorf("References of %s = %v, want %v", id, n2.References(), n1.References())
+			}
+		}
+	}
+	args1, _ := nm1.FindVariable(ua.NewNodeIDString(ns, "Demo.Methods.MethodIO.InputArguments"))
+	args2, ok := nm2.FindVariable(ua.NewNodeIDString(ns, "Demo.Methods.MethodIO.InputArguments"))
+	if !ok || !reflect.DeepEqual(args1.Value().Value, args2.Value().Value) {
+		t.Errorf("Expected InputArguments of Demo.Methods.MethodIO in exported nodeset")
+	}
+	if _, ok := nm2.FindNode(ua.NewNodeIDString(ns, "Demo.Point")); !ok {
+		t.Errorf("Node Demo.Point not found in exported nodeset")
+	}
+}
+
+const structureNodeSet = `<?xml version="1.0" encoding="utf-8"?>
+<UANodeSet xmlns="http://opcfoundation.org/UA/2011/03/UANodeSet.xsd">
+  <NamespaceUris>
+    <Uri>http://github.com/awcullen/opcua/structuretest/</Uri>
+  </NamespaceUris>
+  <Aliases>
+    <Alias Alias="Double">i=11</Alias>
+    <Alias Alias="String">i=12</Alias>
+    <Alias Alias="HasEncoding">i=38</Alias>
+    <Alias Alias="HasSubtype">i=45</Alias>
+    <Alias Alias="HasTypeDefinition">i=40</Alias>
+  </Aliases>
+  <UADataType NodeId="ns=1;i=1" BrowseName="1:Point">
+    <DisplayName>Point</DisplayName>
+    <References>
+      <Reference ReferenceType="HasSubtype" IsForward="false">i=22</Reference>
+      <Reference ReferenceType="HasEncoding">ns=1;i=2</Reference>
+      <Reference ReferenceType="HasEncoding">ns=1;i=6</Reference>
+    </References>
+    <Definition Name="1:Point">
+      <Field Name="X" DataType="Double" />
+      <Field Name="Y" DataType="Double" />
+    </Definition>
+  </UADataType>
+  <UAObject NodeId="ns=1;i=2" BrowseName="Default Binary">
+    <DisplayName>Default Binary</DisplayName>
+    <References>
+      <Reference ReferenceType="HasTypeDefinition">i=76</Reference>
+    </References>
+  </UAObject>
+  <UADataType NodeId="ns=1;i=3" BrowseName="1:Color">
+    <DisplayName>Color</DisplayName>
+    <References>
+      <Reference ReferenceType="HasSubtype" IsForward="false">i=29</Reference>
+    </References>
+    <Definition Name="1:Color">
+      <Field Name="Red" Value="0" />
+      <Field Name="Green" Value="1" />
+    </Definition>
+  </UADataType>
+  <UADataType NodeId="ns=1;i=4" BrowseName="1:Marker">
+    <DisplayName>Marker</DisplayName>
+    <References>
+      <Reference ReferenceType="HasSubtype" IsForward="false">i=22</Reference>
+      <Reference ReferenceType="HasEncoding">ns=1;i=5</Reference>
+      <Reference ReferenceType="HasEncoding">ns=1;i=7</Reference>
+    </References>
+    <Definition Name="1:Marker">
+      <Field Name="Position" DataType="ns=1;i=1" />
+      <Field Name="Color" DataType="ns=1;i=3" IsOptional="true" />
+      <Field Name="Labels" DataType="String" ValueRank="1" />
+    </Definition>
+  </UADataType>
+  <UAObject NodeId="ns=1;i=5" BrowseName="Default Binary">
+    <DisplayName>Default Binary</DisplayName>
+    <References>
+      <Reference ReferenceType="HasTypeDefinition">i=76</Reference>
+    </References>
+  </UAObject>
+  <UAObject NodeId="ns=1;i=6" BrowseName="Default XML">
+    <DisplayName>Default XML</DisplayName>
+    <References>
+      <Reference ReferenceType="HasTypeDefinition">i=76</Reference>
+    </References>
+  </UAObject>
+  <UAObject NodeId="ns=1;i=7" BrowseName="Default XML">
+    <DisplayName>Default XML</DisplayName>
+    <References>
+      <Reference ReferenceType="HasTypeDefinition">i=76</Reference>
+    </References>
+  </UAObject>
+  <UAVariable NodeId="ns=1;i=8" BrowseName="1:DefaultMarker" DataType="ns=1;i=4">
+    <DisplayName>DefaultMarker</DisplayName>
+    <References>
+      <Reference ReferenceType="HasTypeDefinition">i=63</Reference>
+    </References>
+    <Value>
+      <ExtensionObject xmlns="http://opcfoundation.org/UA/2008/02/Types.xsd">
+        <TypeId>
+          <Identifier>ns=1;i=7</Identifier>
+        </TypeId>
+        <Body>
+          <Marker>
+            <EncodingMask>1</EncodingMask>
+            <Position>
+              <X>1</X>
+              <Y>2</Y>
+            </Position>
+            <Color>Green_1</Color>
+            <Labels>
+              <String>a</String>
+              <String>b</String>
+            </Labels>
+          </Marker>
+        </Body>
+      </ExtensionObject>
+    </Value>
+  </UAVariable>
+</UANodeSet>`
+
+type namespaceURIs []string
+
+func (uris namespaceURIs) NamespaceURIs() []string { return uris }
+
+func TestLoadDataTypeDefinition(t *testing.T) {
+	srv, err := server.New(
+		ua.ApplicationDescription{
+			ApplicationURI:  fmt.Sprintf("urn:%s:structureserver", host),
+			ApplicationName: ua.LocalizedText{Text: "structureserver"},
+			ApplicationType: ua.ApplicationTypeServer,
+		},
+		"./pki/server.crt",
+		"./pki/server.key",
+		fmt.Sprintf("opc.tcp://%s:%d", host, port+2),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	nm := srv.NamespaceManager()
+	if err := nm.LoadNodeSetFromBuffer([]byte(structureNodeSet)); err != nil {
+		t.Error(errors.Wrap(err, "Error loading nodeset"))
+		return
+	}
+	nsu := "http://github.com/awcullen/opcua/structuretest/"
+	var ns uint16
+	for i, uri := range nm.NamespaceUris() {
+		if uri == nsu {
+			ns = uint16(i)
+		}
+	}
+	n, ok := nm.FindNode(ua.NewNodeIDNumeric(ns, 4))
+	if !ok {
+		t.Error(errors.New("Error finding Marker"))
+		return
+	}
+	def, ok := n.(*server.DataTypeNode).DataTypeDefinition().(ua.StructureDefinition)
+	if !ok {
+		t.Error(errors.Errorf("Error reading definition of Marker. %v", n.(*server.DataTypeNode).DataTypeDefinition()))
+		return
+	}
+	if def.StructureType != ua.StructureTypeStructureWithOptionalFields || def.DefaultEncodingID != ua.NewNodeIDNumeric(ns, 5) || len(def.Fields) != 3 {
+		t.Error(errors.Errorf("Error reading definition of Marker. %v", def))
+	}
+	if f := def.Fields[2]; f.DataType != ua.DataTypeIDString || f.ValueRank != ua.ValueRankOneDimension || f.IsOptional {
+		t.Error(errors.Errorf("Error reading field Labels. %v", f))
+	}
+	// a field without a ValueRank is a scalar.
+	if f := def.Fields[0]; f.ValueRank != ua.ValueRankScalar {
+		t.Error(errors.Errorf("Error reading field Position. %v", f))
+	}
+	n, _ = nm.FindNode(ua.NewNodeIDNumeric(ns, 3))
+	if def, ok := n.(*server.DataTypeNode).DataTypeDefinition().(ua.EnumDefinition); !ok || len(def.Fields) != 2 || def.Fields[1].Value != 1 {
+		t.Error(errors.Errorf("Error reading definition of Color. %v", n.(*server.DataTypeNode).DataTypeDefinition()))
+	}
+
+	// encode and decode a value of Marker.
+	point, ok := ua.NewStructure(ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 1), NamespaceURI: nsu})
+	if !ok {
+		t.Error(errors.New("Error finding structure Point"))
+		return
+	}
+	point.SetField("X", 1.0)
+	point.SetField("Y", 2.0)
+	marker, _ := ua.NewStructure(ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 4), NamespaceURI: nsu})
+	marker.SetField("Position", point)
+	marker.SetField("Color", int32(1))
+	marker.SetField("Labels", []string{"a", "b"})
+	ec := namespaceURIs(nm.NamespaceUris())
+	buf := &bytes.Buffer{}
+	if err := ua.NewBinaryEncoder(buf, ec).WriteVariant(marker); err != nil {
+		t.Error(errors.Wrap(err, "Error encoding Marker"))
+		return
+	}
+	var out ua.Variant
+	if err := ua.NewBinaryDecoder(buf, ec).ReadVariant(&out); err != nil {
+		t.Error(errors.Wrap(err, "Error decoding Marker"))
+		return
+	}
+	if !reflect.DeepEqual(out, ua.Variant(marker)) {
+		t.Error(errors.Errorf("Error decoding Marker. got: %v, want: %v", out, marker))
+	}
+
+	// read the value of a variable of Marker, and write it to an exported nodeset.
+	v, ok := nm.FindVariable(ua.NewNodeIDNumeric(ns, 8))
+	if !ok {
+		t.Error(errors.New("Error finding DefaultMarker"))
+		return
+	}
+	if !reflect.DeepEqual(v.Value().Value, ua.Variant(marker)) {
+		t.Error(errors.Errorf("Error reading DefaultMarker. got: %v, want: %v", v.Value().Value, marker))
+	}
+	buf.Reset()
+	if err := nm.ExportNodeSet(buf, nsu); err != nil {
+		t.Error(errors.Wrap(err, "Error exporting nodeset"))
+		return
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<Marker><EncodingMask>1</EncodingMask><Position><X>1</X><Y>2</Y></Position><Color>1</Color>")) {
+		t.Error(errors.Errorf("Error exporting DefaultMarker. %s", buf))
+	}
+}
+
+// TestDiscoveryServer tests registering servers with a discovery server, and finding them.
+func TestDiscoveryServer(t *testing.T) {
+	ldsURL := fmt.Sprintf("opc.tcp://%s:%d", host, port+6)
+	lds, err := server.New(
+		ua.ApplicationDescription{
+			ApplicationURI:  fmt.Sprintf("urn:%s:lds", host),
+			ApplicationName: ua.LocalizedText{Text: "lds"},
+			ApplicationType: ua.ApplicationTypeDiscoveryServer,
+			DiscoveryURLs:   []string{ldsURL},
+		},
+		"./pki/server.crt",
+		"./pki/server.key",
+		ldsURL,
+		server.WithAnonymousIdentity(true),
+		server.WithSecurityPolicyNone(true),
+		server.WithInsecureSkipVerify(),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing discovery server"))
+		return
+	}
+	go lds.ListenAndServe()
+	defer lds.Close()
+
+	// register with the certificate of the testserver.
+	ctx := context.Background()
+	serverURI := fmt.Sprintf("urn:%s:testserver", host)
+	var ch *client.Client
+	for i := 0; i < 50; i++ {
+		ch, err = client.Dial(
+			ctx,
+			ldsURL,
+			client.WithSecurityPolicyURI(ua.SecurityPolicyURIBasic256Sha256, ua.MessageSecurityModeSignAndEncrypt),
+			client.WithClientCertificatePaths("./pki/server.crt", "./pki/server.key"),
+			client.WithInsecureSkipVerify(),
+		)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to discovery server"))
+		return
+	}
+	defer ch.Close(ctx)
+	registered := ua.RegisteredServer{
+		ServerURI:     serverURI,
+		ServerNames:   []ua.LocalizedText{{Text: "Testserver", Locale: "en"}, {Text: "Serveur de test", Locale: "fr"}},
+		ServerType:    ua.ApplicationTypeServer,
+		DiscoveryURLs: []string{"opc.tcp://localhost:4841", "opc.tcp://localhost:4842"},
+		IsOnline:      true,
+	}
+	res, err := ch.RegisterServer2(ctx, &ua.RegisterServer2Request{
+		Server: registered,
+		DiscoveryConfiguration: []ua.ExtensionObject{
+			ua.MdnsDiscoveryConfiguration{MdnsServerName: "testserver", ServerCapabilities: []string{"DA"}},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error registering server"))
+		return
+	}
+	if len(res.ConfigurationResults) != 1 || res.ConfigurationResults[0] != ua.Good {
+		t.Errorf("ConfigurationResults = %v, want [Good]", res.ConfigurationResults)
+	}
+
+	// a server may only register itself.
+	other := registered
+	other.ServerURI = "urn:other"
+	if _, err := ch.RegisterServer(ctx, &ua.RegisterServerRequest{Server: other}); err != ua.BadServerURIInvalid {
+		t.Errorf("RegisterServer error = %v, want %v", err, ua.BadServerURIInvalid)
+	}
+
+	// find the server with the french name.
+	found, err := client.FindServers(ctx, &ua.FindServersRequest{EndpointURL: ldsURL, ServerURIs: []string{serverURI}, LocaleIDs: []string{"fr-FR"}})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error finding servers"))
+		return
+	}
+	if len(found.Servers) != 1 || found.Servers[0].ApplicationName.Text != "Serveur de test" || len(found.Servers[0].DiscoveryURLs) != 2 {
+		t.Errorf("FindServers = %v, want the french name of %s", found.Servers, serverURI)
+	}
+
+	// find a record for each discovery url, filtered by capabilities.
+	onNetwork, err := ch.FindServersOnNetwork(ctx, &ua.FindServersOnNetworkRequest{ServerCapabilityFilter: []string{"DA"}})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error finding servers on network"))
+		return
+	}
+	if len(onNetwork.Servers) != 2 || onNetwork.Servers[0].ServerName != "testserver" || onNetwork.Servers[1].DiscoveryURL != "opc.tcp://localhost:4842" {
+		t.Errorf("FindServersOnNetwork = %v, want 2 records", onNetwork.Servers)
+	}
+	onNetwork, err = ch.FindServersOnNetwork(ctx, &ua.FindServersOnNetworkRequest{StartingRecordID: onNetwork.Servers[1].RecordID})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error finding servers on network"))
+		return
+	}
+	if len(onNetwork.Servers) != 1 {
+		t.Errorf("FindServersOnNetwork = %v, want 1 record", onNetwork.Servers)
+	}
+	onNetwork, err = ch.FindServersOnNetwork(ctx, &ua.FindServersOnNetworkRequest{ServerCapabilityFilter: []string{"HD"}})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error finding servers on network"))
+		return
+	}
+	if len(onNetwork.Servers) != 0 {
+		t.Errorf("FindServersOnNetwork = %v, want no records", onNetwork.Servers)
+	}
+
+	// the registration is removed when the semaphore file is deleted.
+	semaphore, err := os.CreateTemp("", "semaphore")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating semaphore file"))
+		return
+	}
+	semaphore.Close()
+	registered.SemaphoreFilePath = semaphore.Name()
+	if _, err := ch.RegisterServer(ctx, &ua.RegisterServerRequest{Server: registered}); err != nil {
+		t.Error(errors.Wrap(err, "Error registering server"))
+		return
+	}
+	if n := len(lds.DiscoveryManager().RegisteredServers()); n != 1 {
+		t.Errorf("RegisteredServers = %d, want 1", n)
+	}
+	os.Remove(semaphore.Name())
+	if n := len(lds.DiscoveryManager().RegisteredServers()); n != 0 {
+		t.Errorf("RegisteredServers = %d after deleting semaphore file, want 0", n)
+	}
+	if _, err := ch.RegisterServer(ctx, &ua.RegisterServerRequest{Server: registered}); err != ua.BadSempahoreFileMissing {
+		t.Errorf("RegisterServer error = %v, want %v", err, ua.BadSempahoreFileMissing)
+	}
+
+	// a server registers itself periodically, until it closes.
+	srv, err := server.New(
+		ua.ApplicationDescription{
+			ApplicationURI:  serverURI,
+			ApplicationName: ua.LocalizedText{Text: "registeringserver"},
+			ApplicationType: ua.ApplicationTypeServer,
+		},
+		"./pki/server.crt",
+		"./pki/server.key",
+		fmt.Sprintf("opc.tcp://%s:%d", host, port+7),
+		server.WithInsecureSkipVerify(),
+		server.WithDiscoveryInsecureSkipVerify(),
+		server.WithDiscoveryRegistration(ldsURL, time.Second),
+		// an interval that is not positive is set to the default.
+		server.WithDiscoveryRegistration(ldsURL, 0),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	go srv.ListenAndServe()
+	registeredServers := []ua.RegisteredServer{}
+	for i := 0; i < 50 && len(registeredServers) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+		registeredServers = lds.DiscoveryManager().RegisteredServers()
+	}
+	if len(registeredServers) != 1 || registeredServers[0].ServerNames[0].Text != "registeringserver" || registeredServers[0].DiscoveryURLs[0] != srv.EndpointURL() {
+		t.Errorf("RegisteredServers = %v, want registeringserver", registeredServers)
+	}
+	srv.Close()
+	for i := 0; i < 50 && len(registeredServers) != 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+		registeredServers = lds.DiscoveryManager().RegisteredServers()
+	}
+	if len(registeredServers) != 0 {
+		t.Errorf("RegisteredServers = %v after closing server, want none", registeredServers)
+	}
+}
+
+// TestServerConfigurationUpdateCertificate pushes a new certificate to the server, signed by a test CA.
+func TestServerConfigurationUpdateCertificate(t *testing.T) {
+	// copy the certificate of the testserver, since the new certificate is stored in its place.
+	dir := t.TempDir()
+	certPath, keyPath := dir+"/server.crt", dir+"/server.key"
+	for src, dst := range map[string]string{"./pki/server.crt": certPath, "./pki/server.key": keyPath} {
+		buf, err := os.ReadFile(src)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error reading certificate"))
+			return
+		}
+		if err := os.WriteFile(dst, buf, 0600); err != nil {
+			t.Error(errors.Wrap(err, "Error writing certificate"))
+			return
+		}
+	}
+	configURL := fmt.Sprintf("opc.tcp://%s:%d", host, port+8)
+	srv, err := newSecurityAdminServer(configURL, certPath, keyPath)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	go srv.ListenAndServe()
+	defer srv.Close()
+
+	ctx := context.Background()
+	dial := func(userName string) (*client.Client, error) {
+		return dialWithUserName(ctx, configURL, userName)
+	}
+	call := func(ch *client.Client, methodID ua.NodeID, args ...ua.Variant) (ua.CallMethodResult, error) {
+		return callMethod(ctx, ch, ua.ObjectIDServerConfiguration, methodID, args...)
+	}
+
+	// only the SecurityAdmin role may call the methods.
+	user, err := dial("user")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	if res, err := call(user, ua.MethodIDServerConfigurationGetRejectedList); err != nil || res.StatusCode != ua.BadUserAccessDenied {
+		t.Errorf("GetRejectedList = %v, %v, want %v", res.StatusCode, err, ua.BadUserAccessDenied)
+	}
+	user.Close(ctx)
+
+	admin, err := dial("admin")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	defer admin.Close(ctx)
+	if res, err := call(admin, ua.MethodIDServerConfigurationGetRejectedList); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("GetRejectedList = %v, %v, want %v", res.StatusCode, err, ua.Good)
+	}
+
+	// create a signing request with a new private key.
+	res, err := call(admin, ua.MethodIDServerConfigurationCreateSigningRequest,
+		ua.ObjectIDServerConfigurationCertificateGroupsDefaultApplicationGroup,
+		ua.ObjectTypeIDRsaSha256ApplicationCertificateType,
+		"CN=configurationserver,O=test",
+		true,
+		ua.ByteString(make([]byte, 32)),
+	)
+	if err != nil || res.StatusCode != ua.Good {
+		t.Errorf("CreateSigningRequest = %v, %v, want %v", res.StatusCode, err, ua.Good)
+		return
+	}
+	csr, err := x509.ParseCertificateRequest([]byte(res.OutputArguments[0].(ua.ByteString)))
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error parsing certificate request"))
+		return
+	}
+	if err := csr.CheckSignature(); err != nil || csr.Subject.CommonName != "configurationserver" || len(csr.URIs) != 1 {
+		t.Errorf("CertificateRequest = %v, %v, want signed request for configurationserver", csr.Subject, err)
+	}
+
+	// sign the request with a test CA.
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error generating key"))
+		return
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "testca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caCert, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating CA certificate"))
+		return
+	}
+	newCert, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      csr.Subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageContentCommitment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     csr.DNSNames,
+		URIs:         csr.URIs,
+	}, caTemplate, csr.PublicKey, caKey)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating certificate"))
+		return
+	}
+
+	// a certificate for another key is rejected.
+	if res, err := call(admin, ua.MethodIDServerConfigurationUpdateCertificate, nil, nil, ua.ByteString(caCert), nil, "", nil); err != nil || res.StatusCode == ua.Good {
+		t.Errorf("UpdateCertificate = %v, %v, want bad status", res.StatusCode, err)
+	}
+	// a certificate that may not be used by a server is rejected.
+	clientCert, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      csr.Subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment | x509.KeyUsageContentCommitment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		DNSNames:     csr.DNSNames,
+		URIs:         csr.URIs,
+	}, caTemplate, csr.PublicKey, caKey)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating certificate"))
+		return
+	}
+	if res, err := call(admin, ua.MethodIDServerConfigurationUpdateCertificate, nil, nil, ua.ByteString(clientCert), []ua.ByteString{ua.ByteString(caCert)}, "", nil); err != nil || res.StatusCode != ua.BadCertificateUseNotAllowed {
+		t.Errorf("UpdateCertificate = %v, %v, want %v", res.StatusCode, err, ua.BadCertificateUseNotAllowed)
+	}
+	res, err = call(admin, ua.MethodIDServerConfigurationUpdateCertificate, nil, nil, ua.ByteString(newCert), []ua.ByteString{ua.ByteString(caCert)}, "", nil)
+	if err != nil || res.StatusCode != ua.Good || res.OutputArguments[0] != true {
+		t.Errorf("UpdateCertificate = %v, %v, want %v", res.StatusCode, err, ua.Good)
+		return
+	}
+	if bytes.HasPrefix(srv.LocalCertificate(), newCert) {
+		t.Error("LocalCertificate changed before ApplyChanges")
+	}
+	if res, err := call(admin, ua.MethodIDServerConfigurationApplyChanges); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("ApplyChanges = %v, %v, want %v", res.StatusCode, err, ua.Good)
+		return
+	}
+
+	// the existing channel continues, while new channels use the new certificate.
+	if _, err := admin.Read(ctx, &ua.ReadRequest{NodesToRead: []ua.ReadValueID{{NodeID: ua.VariableIDServerServerStatus, AttributeID: ua.AttributeIDValue}}}); err != nil {
+		t.Error(errors.Wrap(err, "Error reading with existing channel"))
+	}
+	if !bytes.HasPrefix(srv.LocalCertificate(), newCert) {
+		t.Error("LocalCertificate did not change after ApplyChanges")
+	}
+	eps, err := client.GetEndpoints(ctx, &ua.GetEndpointsRequest{EndpointURL: configURL})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling GetEndpoints"))
+		return
+	}
+	for _, ep := range eps.Endpoints {
+		if ep.SecurityMode != ua.MessageSecurityModeNone && !strings.HasPrefix(string(ep.ServerCertificate), string(newCert)) {
+			t.Errorf("ServerCertificate of %s is not the new certificate", ep.SecurityPolicyURI)
+		}
+	}
+	ch, err := dial("admin")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server with new certificate"))
+		return
+	}
+	ch.Close(ctx)
+
+	// the new certificate is stored in place of the old.
+	buf, err := os.ReadFile(certPath)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading certificate"))
+		return
+	}
+	if block, _ := pem.Decode(buf); block == nil || !bytes.Equal(block.Bytes, newCert) {
+		t.Error("Stored certificate is not the new certificate")
+	}
+}
+
+// TestTrustList reads and updates the trust list of the server, with the methods of the TrustListType.
+func TestTrustList(t *testing.T) {
+	dir := t.TempDir()
+	trustedPath, issuerPath := dir+"/trusted", dir+"/issuer"
+	trustListURL := fmt.Sprintf("opc.tcp://%s:%d", host, port+9)
+	srv, err := newSecurityAdminServer(trustListURL, "./pki/server.crt", "./pki/server.key",
+		server.WithTrustedCertificatesPaths(trustedPath+"/certs", trustedPath+"/crl"),
+		server.WithIssuerCertificatesPaths(issuerPath+"/certs", issuerPath+"/crl"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	go srv.ListenAndServe()
+	defer srv.Close()
+
+	ctx := context.Background()
+	ch, err := dialWithUserName(ctx, trustListURL, "admin")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	defer ch.Close(ctx)
+	trustList := ua.ObjectIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustList
+	call := func(methodID ua.NodeID, args ...ua.Variant) (ua.CallMethodResult, error) {
+		return callMethod(ctx, ch, trustList, methodID, args...)
+	}
+	readTrustList := func(masks uint32) (ua.TrustListDataType, error) {
+		var value ua.TrustListDataType
+		res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpenWithMasks, masks)
+		if err != nil || res.StatusCode != ua.Good {
+			return value, errors.Errorf("OpenWithMasks = %v, %v", res.StatusCode, err)
+		}
+		handle := res.OutputArguments[0].(uint32)
+		defer call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListClose, handle)
+		var buf bytes.Buffer
+		for {
+			res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListRead, handle, int32(256))
+			if err != nil || res.StatusCode != ua.Good {
+				return value, errors.Errorf("Read = %v, %v", res.StatusCode, err)
+			}
+			data := res.OutputArguments[0].(ua.ByteString)
+			if len(data) == 0 {
+				break
+			}
+			buf.WriteString(string(data))
+		}
+		err = ua.NewBinaryDecoder(&buf, ua.NewEncodingContext()).Decode(&value)
+		return value, err
+	}
+
+	// add the client certificate to the trusted certificates.
+	buf, err := os.ReadFile("./pki/client.crt")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading certificate"))
+		return
+	}
+	block, _ := pem.Decode(buf)
+	clientCert := ua.ByteString(block.Bytes)
+	if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListAddCertificate, clientCert, true); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("AddCertificate = %v, %v, want %v", res.StatusCode, err, ua.Good)
+		return
+	}
+	value, err := readTrustList(uint32(ua.TrustListMasksAll))
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading trust list"))
+		return
+	}
+	if value.SpecifiedLists != uint32(ua.TrustListMasksAll) || len(value.TrustedCertificates) != 1 || value.TrustedCertificates[0] != clientCert {
+		t.Errorf("TrustList = %v, want the client certificate", value)
+	}
+	value, err = readTrustList(uint32(ua.TrustListMasksIssuerCertificates))
+	if err != nil || value.SpecifiedLists != uint32(ua.TrustListMasksIssuerCertificates) || len(value.TrustedCertificates) != 0 {
+		t.Errorf("TrustList = %v, %v, want only the issuer certificates", value, err)
+	}
+
+	// while open for reading, the trust list may only be opened again with the same masks.
+	if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpenWithMasks, uint32(ua.TrustListMasksIssuerCertificates)); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("OpenWithMasks = %v, %v, want %v", res.StatusCode, err, ua.Good)
+	} else {
+		if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpen, byte(ua.OpenFileModeRead)); err != nil || res.StatusCode != ua.BadInvalidState {
+			t.Errorf("Open = %v, %v, want %v", res.StatusCode, err, ua.BadInvalidState)
+		}
+		call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListClose, res.OutputArguments[0].(uint32))
+	}
+
+	// replace the trusted and issuer certificates with a CA certificate.
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error generating key"))
+		return
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "testca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error creating CA certificate"))
+		return
+	}
+	caCert := ua.ByteString(caDER)
+	res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpen, byte(ua.OpenFileModeWrite|ua.OpenFileModeEraseExisting))
+	if err != nil || res.StatusCode != ua.Good {
+		t.Errorf("Open = %v, %v, want %v", res.StatusCode, err, ua.Good)
+		return
+	}
+	handle := res.OutputArguments[0].(uint32)
+
+	// the trust list is locked while open for writing.
+	if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpen, byte(ua.OpenFileModeRead)); err != nil || res.StatusCode != ua.BadInvalidState {
+		t.Errorf("Open = %v, %v, want %v", res.StatusCode, err, ua.BadInvalidState)
+	}
+	if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListAddCertificate, caCert, false); err != nil || res.StatusCode != ua.BadInvalidState {
+		t.Errorf("AddCertificate = %v, %v, want %v", res.StatusCode, err, ua.BadInvalidState)
+	}
+
+	var data bytes.Buffer
+	if err := ua.NewBinaryEncoder(&data, ua.NewEncodingContext()).Encode(&ua.TrustListDataType{
+		SpecifiedLists:      uint32(ua.TrustListMasksTrustedCertificates | ua.TrustListMasksIssuerCertificates),
+		TrustedCertificates: []ua.ByteString{caCert},
+		IssuerCertificates:  []ua.ByteString{caCert},
+	}); err != nil {
+		t.Error(errors.Wrap(err, "Error encoding trust list"))
+		return
+	}
+	for b := data.Bytes(); len(b) > 0; b = b[min(len(b), 256):] {
+		if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListWrite, handle, ua.ByteString(b[:min(len(b), 256)])); err != nil || res.StatusCode != ua.Good {
+			t.Errorf("Write = %v, %v, want %v", res.StatusCode, err, ua.Good)
+			return
+		}
+	}
+	res, err = call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListCloseAndUpdate, handle)
+	if err != nil || res.StatusCode != ua.Good || res.OutputArguments[0] != false {
+		t.Errorf("CloseAndUpdate = %v, %v, want %v", res.StatusCode, err, ua.Good)
+		return
+	}
+	value, err = readTrustList(uint32(ua.TrustListMasksAll))
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading trust list"))
+		return
+	}
+	if len(value.TrustedCertificates) != 1 || value.TrustedCertificates[0] != caCert || len(value.IssuerCertificates) != 1 || value.IssuerCertificates[0] != caCert {
+		t.Errorf("TrustList = %v, want the CA certificate", value)
+	}
+
+	// remove the CA certificate from the trusted certificates.
+	thumbprint := fmt.Sprintf("%x", sha1.Sum(caDER))
+	if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListRemoveCertificate, thumbprint, true); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("RemoveCertificate = %v, %v, want %v", res.StatusCode, err, ua.Good)
+	}
+	if res, err := call(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListRemoveCertificate, thumbprint, true); err != nil || res.StatusCode != ua.BadInvalidArgument {
+		t.Errorf("RemoveCertificate = %v, %v, want %v", res.StatusCode, err, ua.BadInvalidArgument)
+	}
+	if entries, err := os.ReadDir(trustedPath + "/certs"); err != nil || len(entries) != 0 {
+		t.Errorf("Trusted certificates = %v, %v, want none", entries, err)
+	}
+}
+
+func TestFileNode(t *testing.T) {
+	fileURL := fmt.Sprintf("opc.tcp://%s:%d", host, port+10)
+	srv, err := newSecurityAdminServer(fileURL, "./pki/server.crt", "./pki/server.key")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	go srv.ListenAndServe()
+	defer srv.Close()
+
+	// add a writable file and a read-only file to the Objects folder.
+	rolePermissions := []ua.RolePermissionType{
+		{RoleID: ua.ObjectIDWellKnownRoleAuthenticatedUser, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead | ua.PermissionTypeWrite | ua.PermissionTypeCall},
+	}
+	storage := server.NewMemoryFile([]byte("hello"))
+	file := server.NewFileNode(
+		srv,
+		ua.ParseNodeID("ns=1;s=Test.File"),
+		ua.NewQualifiedName(1, "File"),
+		ua.NewLocalizedText("File", ""),
+		ua.NewLocalizedText("A writable file.", ""),
+		rolePermissions,
+		[]ua.Reference{ua.NewReference(ua.ReferenceTypeIDOrganizes, true, ua.NewExpandedNodeID(ua.ObjectIDObjectsFolder))},
+		storage,
+		true,
+	)
+	readOnlyFile := server.NewFileNode(
+		srv,
+		ua.ParseNodeID("ns=1;s=Test.ReadOnlyFile"),
+		ua.NewQualifiedName(1, "ReadOnlyFile"),
+		ua.NewLocalizedText("ReadOnlyFile", ""),
+		ua.NewLocalizedText("A read-only file.", ""),
+		rolePermissions,
+		[]ua.Reference{ua.NewReference(ua.ReferenceTypeIDOrganizes, true, ua.NewExpandedNodeID(ua.ObjectIDObjectsFolder))},
+		server.NewMemoryFile([]byte("read only")),
+		false,
+	)
+	if err := srv.NamespaceManager().AddNodes(append(file.Nodes(), readOnlyFile.Nodes()...)...); err != nil {
+		t.Error(errors.Wrap(err, "Error adding nodes"))
+		return
+	}
+
+	ctx := context.Background()
+	ch, err := dialWithUserName(ctx, fileURL, "user")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	call := func(method string, args ...ua.Variant) (ua.CallMethodResult, error) {
+		return callMethod(ctx, ch, file.NodeID(), ua.ParseNodeID("ns=1;s=Test.File."+method), args...)
+	}
+
+	// append to the file, then read it from the start.
+	res, err := call("Open", byte(ua.OpenFileModeRead|ua.OpenFileModeWrite|ua.OpenFileModeAppend))
+	if err != nil || res.StatusCode != ua.Good {
+		t.Errorf("Open = %v, %v, want %v", res.StatusCode, err, ua.Good)
+		ch.Abort(ctx)
+		return
+	}
+	handle := res.OutputArguments[0].(uint32)
+	if res, err := call("Open", byte(ua.OpenFileModeRead)); err != nil || res.StatusCode != ua.BadNotReadable {
+		t.Errorf("Open = %v, %v, want %v", res.StatusCode, err, ua.BadNotReadable)
+	}
+	if res, err := call("Write", handle, ua.ByteString(", world")); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("Write = %v, %v, want %v", res.StatusCode, err, ua.Good)
+	}
+	file.SetMaxSize(16)
+	if res, err := call("Write", handle, ua.ByteString("!!!!!")); err != nil || res.StatusCode != ua.BadEncodingLimitsExceeded {
+		t.Errorf("Write = %v, %v, want %v", res.StatusCode, err, ua.BadEncodingLimitsExceeded)
+	}
+	if res, err := call("GetPosition", handle); err != nil || res.StatusCode != ua.Good || res.OutputArguments[0] != uint64(12) {
+		t.Errorf("GetPosition = %v, %v, want %v", res.OutputArguments, err, uint64(12))
+	}
+	if res, err := call("SetPosition", handle, uint64(0)); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("SetPosition = %v, %v, want %v", res.StatusCode, err, ua.Good)
+	}
+	if res, err := call("Read", handle, int32(100)); err != nil || res.StatusCode != ua.Good || res.OutputArguments[0] != ua.ByteString("hello, world") {
+		t.Errorf("Read = %v, %v, want %q", res.OutputArguments, err, "hello, world")
+	}
+	if res, err := call("Read", handle, int32(100)); err != nil || res.StatusCode != ua.Good || res.OutputArguments[0] != ua.ByteString("") {
+		t.Errorf("Read = %v, %v, want end of file", res.OutputArguments, err)
+	}
+	if res, err := call("Close", handle); err != nil || res.StatusCode != ua.Good {
+		t.Errorf("Close = %v, %v, want %v", res.StatusCode, err, ua.Good)
+	}
+	if res, err := call("Close", handle); err != nil || res.StatusCode != ua.BadInvalidArgument {
+		t.Errorf("Close = %v, %v, want %v", res.StatusCode, err, ua.BadInvalidArgument)
+	}
+	if string(storage.Bytes()) != "hello, world" {
+		t.Errorf("Bytes = %q, want %q", storage.Bytes(), "hello, world")
+	}
+
+	// the properties report the size and the open handles.
+	if _, err := call("Open", byte(ua.OpenFileModeRead)); err != nil {
+		t.Error(errors.Wrap(err, "Error opening file"))
+	}
+	readRes, err := ch.Read(ctx, &ua.ReadRequest{
+		NodesToRead: []ua.ReadValueID{
+			{NodeID: ua.ParseNodeID("ns=1;s=Test.File.Size"), AttributeID: ua.AttributeIDValue},
+			{NodeID: ua.ParseNodeID("ns=1;s=Test.File.OpenCount"), AttributeID: ua.AttributeIDValue},
+			{NodeID: ua.ParseNodeID("ns=1;s=Test.File.UserWritable"), AttributeID: ua.AttributeIDValue},
+			{NodeID: ua.ParseNodeID("ns=1;s=Test.ReadOnlyFile.Writable"), AttributeID: ua.AttributeIDValue},
+		},
+	})
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading"))
+		ch.Abort(ctx)
+		return
+	}
+	if v := readRes.Results[0].Value; v != uint64(12) {
+		t.Errorf("Size = %v, want %v", v, uint64(12))
+	}
+	if v := readRes.Results[1].Value; v != uint16(1) {
+		t.Errorf("OpenCount = %v, want %v", v, uint16(1))
+	}
+	if v := readRes.Results[2].Value; v != true {
+		t.Errorf("UserWritable = %v, want %v", v, true)
+	}
+	if v := readRes.Results[3].Value; v != false {
+		t.Errorf("Writable = %v, want %v", v, false)
+	}
+
+	// the read-only file may not be opened for writing.
+	if res, err := callMethod(ctx, ch, readOnlyFile.NodeID(), ua.ParseNodeID("ns=1;s=Test.ReadOnlyFile.Open"), byte(ua.OpenFileModeWrite)); err != nil || res.StatusCode != ua.BadNotWritable {
+		t.Errorf("Open = %v, %v, want %v", res.StatusCode, err, ua.BadNotWritable)
+	}
+
+	// the handles are closed when the session closes.
+	if err := ch.Close(ctx); err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+	}
+	if n := file.OpenCount(); n != 0 {
+		t.Errorf("OpenCount = %v, want %v", n, 0)
+	}
+}
+
+func TestCertificateStore(t *testing.T) {
+	storeURL := fmt.Sprintf("opc.tcp://%s:%d", host, port+11)
+	store := ua.NewMemoryCertificateStore()
+	srv, err := server.New(
+		ua.ApplicationDescription{
+			ApplicationURI:  fmt.Sprintf("urn:%s:testserver", host),
+			ApplicationName: ua.LocalizedText{Text: "storeserver"},
+			ApplicationType: ua.ApplicationTypeServer,
+			DiscoveryURLs:   []string{storeURL},
+		},
+		"./pki/server.crt",
+		"./pki/server.key",
+		storeURL,
+		server.WithCertificateStore(store),
+		server.WithSecurityPolicyNone(true),
+		server.WithAnonymousIdentity(true),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error constructing server"))
+		return
+	}
+	go srv.ListenAndServe()
+	defer srv.Close()
+	for i := 0; i < 50; i++ {
+		if _, err := client.FindServers(context.Background(), &ua.FindServersRequest{EndpointURL: storeURL}); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	ctx := context.Background()
+	dial := func() (*client.Client, error) {
+		return client.Dial(
+			ctx,
+			storeURL,
+			client.WithSecurityPolicyURI(ua.SecurityPolicyURIBasic256Sha256, ua.MessageSecurityModeSignAndEncrypt),
+			client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+			client.WithInsecureSkipVerify(),
+		)
+	}
+
+	// the client certificate is rejected, until it is added to the trusted certificates.
+	if ch, err := dial(); err == nil {
+		ch.Close(ctx)
+		t.Error("Dial succeeded, want the client certificate rejected")
+		return
+	}
+	buf, err := os.ReadFile("./pki/client.crt")
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading certificate"))
+		return
+	}
+	block, _ := pem.Decode(buf)
+	if rejected, err := store.Rejected(); err != nil || len(rejected) != 1 || !bytes.Equal(rejected[0], block.Bytes) {
+		t.Errorf("Rejected = %d certificates, %v, want the client certificate", len(rejected), err)
+	}
+	if err := store.Add(ua.TrustListMasksTrustedCertificates, block.Bytes); err != nil {
+		t.Error(errors.Wrap(err, "Error adding certificate"))
+		return
+	}
+	ch, err := dial()
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	if err := ch.Close(ctx); err != nil {
+		t.Error(errors.Wrap(err, "Error closing client"))
+	}
+
+	// clients may share a validator, that rejects the server certificate to its store.
+	validator := ua.NewCertificateValidator(ua.NewMemoryCertificateStore())
+	for i := 0; i < 2; i++ {
+		if ch, err := client.Dial(
+			ctx,
+			storeURL,
+			client.WithSecurityPolicyURI(ua.SecurityPolicyURIBasic256Sha256, ua.MessageSecurityModeSignAndEncrypt),
+			client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+			client.WithCertificateValidator(validator),
+		); err == nil {
+			ch.Close(ctx)
+			t.Error("Dial succeeded, want the server certificate rejected")
+		}
+	}
+	if rejected, err := validator.Store().Rejected(); err != nil || len(rejected) != 1 {
+		t.Errorf("Rejected = %d certificates, %v, want the server certificate", len(rejected), err)
+	}
+
+	// the client certificate is rejected again, after it is removed.
+	if err := store.Remove(ua.TrustListMasksTrustedCertificates, block.Bytes); err != nil {
+		t.Error(errors.Wrap(err, "Error removing certificate"))
+		return
+	}
+	if ch, err := dial(); err == nil {
+		ch.Close(ctx)
+		t.Error("Dial succeeded, want the client certificate rejected")
+	}
+}
+
+/*
+// TestReadHistory demonstrates reading history from the UaCPPServer available from https://www.unified-automation.com/
+func TestReadHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping long running test")
+	}
+	ctx := context.Background()
+	ch, err := client.Dial(
+		ctx,
+		"opc.tcp://localhost:48010",
+		client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+		client.WithInsecureSkipVerify(), // skips verification of server certificate
+		client.WithUserNameIdentity("root", "secret"),
+	)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error connecting to server"))
+		return
+	}
+	t.Logf("Success connecting to server: %s", ch.EndpointURL())
+
+	t.Logf("Start logging of data...")
+	req := &ua.CallRequest{
+		MethodsToCall: []ua.CallMethodRequest{
+			{
+				ObjectID:       ua.ParseNodeID("ns=2;s=Demo.History"), // parent node
+				MethodID:       ua.ParseNodeID("ns=2;s=Demo.History.StartLogging"),
+				InputArguments: []ua.Variant{},
+			},
+		},
+	}
+
+	res, err := ch.Call(ctx, req)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling method"))
+		ch.Abort(ctx)
+		return
+	}
+	_ = res
+
+	t.Logf("Collecting 10 seconds of data...")
+	time.Sleep(10 * time.Second)
+
+	t.Log("Reading history for last 10 seconds")
+	var cp ua.ByteString
+	for {
+		req2 := &ua.HistoryReadRequest{
+			HistoryReadDetails: ua.ReadRawModifiedDetails{
+				StartTime:        time.Now().Add(-1 * time.Minute),
+				EndTime:          time.Now(),
+				NumValuesPerNode: 100,
+				ReturnBounds:     false,
+			},
+			TimestampsToReturn:        ua.TimestampsToReturnBoth,
+			ReleaseContinuationPoints: false,
+			NodesToRead: []ua.HistoryReadValueID{
+				{NodeID: ua.ParseNodeID("ns=2;s=Demo.History.DoubleWithHistory"), ContinuationPoint: cp},
+			},
+		}
+
+		res2, err := ch.HistoryRead(ctx, req2)
+		if err != nil {
+			t.Error(errors.Wrap(err, "Error reading"))
+			ch.Abort(ctx)
+			return
+		}
+
+		if res2.Results[0].StatusCode.IsBad() {
+			t.Errorf("Error reading values for node '%s'. %s", req2.NodesToRead[0].NodeID, res2.Results[0].StatusCode)
+			ch.Abort(ctx)
+			return
+		}
+
+		if historyData, ok := res2.Results[0].HistoryData.(ua.HistoryData); ok {
+			t.Logf("Found %d value(s) for node '%s':", len(historyData.DataValues), req2.NodesToRead[0].NodeID)
+			for _, result := range historyData.DataValues {
+				t.Logf("Read %v, q: %#X, ts: %s", result.Value, uint32(result.StatusCode), result.SourceTimestamp)
+			}
+		}
+
+		cp = res2.Results[0].ContinuationPoint
+		if cp == "" {
+			break
+		}
+	}
+	t.Log("Now read the 1 sec average of the last 10 seconds...")
+
+	req3 := &ua.HistoryReadRequest{
+		HistoryReadDetails: ua.ReadProcessedDetails{
+			StartTime:          time.Now().Add(-10 * time.Second),
+			EndTime:            time.Now(),
+			ProcessingInterval: 1000.0,
+			AggregateType:      []ua.NodeID{ua.ObjectIDAggregateFunctionAverage},
+		},
+		TimestampsToReturn:        ua.TimestampsToReturnBoth,
+		ReleaseContinuationPoints: false,
+		NodesToRead: []ua.HistoryReadValueID{
+			{NodeID: ua.ParseNodeID("ns=2;s=Demo.History.DoubleWithHistory")},
+		},
+	}
+
+	res3, err := ch.HistoryRead(ctx, req3)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error reading"))
+		ch.Abort(ctx)
+		return
+	}
+
+	if res3.Results[0].StatusCode.IsBad() {
+		t.Errorf("Error reading values for node '%s'. %s", req3.NodesToRead[0].NodeID, res3.Results[0].StatusCode)
+		ch.Abort(ctx)
+		return
+	}
+
+	if historyData, ok := res3.Results[0].HistoryData.(ua.HistoryData); ok {
+		t.Logf("Found %d average value(s) for node '%s':", len(historyData.DataValues), req3.NodesToRead[0].NodeID)
+		for _, result := range historyData.DataValues {
+			t.Logf("Read %v, q: %#X, ts: %s", result.Value, uint32(result.StatusCode), result.SourceTimestamp)
+		}
+	}
+
+	t.Logf("Stop logging of data...")
+	req4 := &ua.CallRequest{
+		MethodsToCall: []ua.CallMethodRequest{{
+
+			ObjectID:       ua.ParseNodeID("ns=2;s=Demo.History"), // parent node
+			MethodID:       ua.ParseNodeID("ns=2;s=Demo.History.StopLogging"),
+			InputArguments: []ua.Variant{}},
+		},
+	}
+
+	res4, err := ch.Call(ctx, req4)
+	if err != nil {
+		t.Error(errors.Wrap(err, "Error calling method"))
+		ch.Abort(ctx)
+		return
+	}
+	_ = res4
 
 	ch.Close(ctx)
 }
 */
 
+// newSecurityAdminServer returns a server where the user 'admin' has the SecurityAdmin role.
+// Every user is authenticated with the password 'secret'.
+func newSecurityAdminServer(endpointURL, certPath, keyPath string, opts ...server.Option) (*server.Server, error) {
+	return server.New(
+		ua.ApplicationDescription{
+			ApplicationURI:  fmt.Sprintf("urn:%s:testserver", host),
+			ApplicationName: ua.LocalizedText{Text: "configurationserver"},
+			ApplicationType: ua.ApplicationTypeServer,
+			DiscoveryURLs:   []string{endpointURL},
+		},
+		certPath,
+		keyPath,
+		endpointURL,
+		append([]server.Option{
+			server.WithInsecureSkipVerify(),
+			server.WithAuthenticateUserNameIdentityFunc(func(userIdentity ua.UserNameIdentity, applicationURI string, endpointURL string) error {
+				if userIdentity.Password != "secret" {
+					return ua.BadUserAccessDenied
+				}
+				return nil
+			}),
+			server.WithRolesProvider(server.GetRolesFunc(func(userIdentity any, applicationURI string, endpointURL string) ([]ua.NodeID, error) {
+				if id, ok := userIdentity.(ua.UserNameIdentity); ok && id.UserName == "admin" {
+					return []ua.NodeID{ua.ObjectIDWellKnownRoleAuthenticatedUser, ua.ObjectIDWellKnownRoleSecurityAdmin}, nil
+				}
+				return []ua.NodeID{ua.ObjectIDWellKnownRoleAuthenticatedUser}, nil
+			})),
+		}, opts...)...,
+	)
+}
+
+// dialWithUserName connects to the server with an encrypted channel, retrying until the server is listening.
+func dialWithUserName(ctx context.Context, endpointURL, userName string) (*client.Client, error) {
+	var ch *client.Client
+	var err error
+	for i := 0; i < 50; i++ {
+		ch, err = client.Dial(
+			ctx,
+			endpointURL,
+			client.WithSecurityPolicyURI(ua.SecurityPolicyURIBasic256Sha256, ua.MessageSecurityModeSignAndEncrypt),
+			client.WithClientCertificatePaths("./pki/client.crt", "./pki/client.key"),
+			client.WithUserNameIdentity(userName, "secret"),
+			client.WithInsecureSkipVerify(),
+		)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return ch, err
+}
+
+// callMethod calls the method of the object, and returns the result.
+func callMethod(ctx context.Context, ch *client.Client, objectID, methodID ua.NodeID, args ...ua.Variant) (ua.CallMethodResult, error) {
+	res, err := ch.Call(ctx, &ua.CallRequest{
+		MethodsToCall: []ua.CallMethodRequest{{
+			ObjectID:       objectID,
+			MethodID:       methodID,
+			InputArguments: args,
+		}},
+	})
+	if err != nil {
+		return ua.CallMethodResult{}, err
+	}
+	return res.Results[0], nil
+}
+
 func createNewCertificate(appName, certFile, keyFile string) error {
 
 	// Create a keypair.
@@ -114,6 +114,7 @@ type Session struct {
 	clientUserIdOfSession                   string
 	authenticationMechanism                 string
 	clientUserIdHistory                     []string
+	deleteHandlers                          map[any]func()
 }
 
 func NewSession(server *Server, sessionId ua.NodeID, sessionName string, authenticationToken ua.NodeID, sessionNonce ua.ByteString, timeout float64, clientDescription ua.ApplicationDescription, serverUri string, endpointUrl string, clientCertificate ua.ByteString, maxResponseMessageSize uint32) *Session {
@@ -150,10 +151,9 @@ func (s *Session) IsExpired() bool {
 
 func (s *Session) delete() {
 	s.Lock()
-	defer s.Unlock()
 	//s.sessionId = nil  // need to keep to look up diagnostics node
 	s.authenticationToken = nil
-	s.userIdentity = nil
+	//s.userIdentity = nil  // need to keep to validate transfer of subscriptions
 	s.channelId = 0
 	s.securityMode = ua.MessageSecurityModeNone
 	s.securityPolicyURI = ua.SecurityPolicyURINone
@@ -169,6 +169,22 @@ func (s *Session) delete() {
 	}
 	s.historyCPs = nil
 	s.clientUserIdHistory = nil
+	handlers := s.deleteHandlers
+	s.deleteHandlers = nil
+	s.Unlock()
+	for _, f := range handlers {
+		f()
+	}
+}
+
+// onDelete sets a func that is called when the session is deleted. A func set with the same key is replaced.
+func (s *Session) onDelete(key any, f func()) {
+	s.Lock()
+	defer s.Unlock()
+	if s.deleteHandlers == nil {
+		s.deleteHandlers = make(map[any]func())
+	}
+	s.deleteHandlers[key] = f
 }
 
 func (s *Session) SessionId() ua.NodeID {
@@ -0,0 +1,290 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// WithMaxTimeShelved sets the maximum time that an alarm may be shelved. A one-shot shelf also ends
+// after this time. Default is unlimited.
+func WithMaxTimeShelved(value time.Duration) ConditionOption {
+	return func(c *Condition) {
+		c.maxTimeShelved = value
+	}
+}
+
+// ShelvingState returns the NodeID of the current state of the ShelvedStateMachine, or nil if the condition is not an alarm.
+func (c *Condition) ShelvingState() ua.NodeID {
+	c.RLock()
+	defer c.RUnlock()
+	return c.shelvingState
+}
+
+// UnshelveTime returns the time remaining until the alarm is unshelved automatically, or zero if there is no such time.
+func (c *Condition) UnshelveTime() time.Duration {
+	c.RLock()
+	defer c.RUnlock()
+	return c.remainingShelveTime()
+}
+
+// TimedShelve shelves the alarm for the given time. The alarm is unshelved automatically when the time expires.
+func (c *Condition) TimedShelve(shelvingTime time.Duration) error {
+	c.Lock()
+	if !c.alarm {
+		c.Unlock()
+		return ua.BadNotSupported
+	}
+	if c.shelvingState == ua.ObjectIDShelvedStateMachineTypeTimedShelved {
+		c.Unlock()
+		return ua.BadConditionAlreadyShelved
+	}
+	if shelvingTime <= 0 || (c.maxTimeShelved > 0 && shelvingTime > c.maxTimeShelved) {
+		c.Unlock()
+		return ua.BadShelvingTimeOutOfRange
+	}
+	c.setShelvingState(ua.ObjectIDShelvedStateMachineTypeTimedShelved, shelvingTime)
+	evts := c.update(&c.state, true)
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// OneShotShelve shelves the alarm until it returns to normal.
+func (c *Condition) OneShotShelve() error {
+	c.Lock()
+	if !c.alarm {
+		c.Unlock()
+		return ua.BadNotSupported
+	}
+	if c.shelvingState == ua.ObjectIDShelvedStateMachineTypeOneShotShelved {
+		c.Unlock()
+		return ua.BadConditionAlreadyShelved
+	}
+	c.setShelvingState(ua.ObjectIDShelvedStateMachineTypeOneShotShelved, c.maxTimeShelved)
+	evts := c.update(&c.state, true)
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// Unshelve unshelves the alarm.
+func (c *Condition) Unshelve() error {
+	c.Lock()
+	if !c.alarm {
+		c.Unlock()
+		return ua.BadNotSupported
+	}
+	if c.shelvingState == ua.ObjectIDShelvedStateMachineTypeUnshelved {
+		c.Unlock()
+		return ua.BadConditionNotShelved
+	}
+	c.setShelvingState(ua.ObjectIDShelvedStateMachineTypeUnshelved, 0)
+	evts := c.update(&c.state, true)
+	c.Unlock()
+	c.emit(evts...)
+	return nil
+}
+
+// setShelvingState sets the state of the ShelvedStateMachine. If the duration is positive, the alarm is
+// unshelved when it expires. Must be called while holding the lock of the condition.
+func (c *Condition) setShelvingState(state ua.NodeID, d time.Duration) {
+	c.shelvingState = state
+	if c.shelveTimer != nil {
+		c.shelveTimer.Stop()
+		c.shelveTimer = nil
+	}
+	c.unshelveTime = time.Time{}
+	if d <= 0 {
+		return
+	}
+	c.unshelveTime = time.Now().Add(d)
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		c.Lock()
+		if c.shelveTimer != timer {
+			c.Unlock()
+			return
+		}
+		c.shelveTimer = nil
+		c.setShelvingState(ua.ObjectIDShelvedStateMachineTypeUnshelved, 0)
+		evts := c.update(&c.state, true)
+		c.Unlock()
+		c.emit(evts...)
+	})
+	c.shelveTimer = timer
+}
+
+// remainingShelveTime returns the time until the alarm is unshelved. Must be called while holding the lock of the condition.
+func (c *Condition) remainingShelveTime() time.Duration {
+	if c.unshelveTime.IsZero() {
+		return 0
+	}
+	if d := time.Until(c.unshelveTime); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// shelvingStateText returns the name of the state of the ShelvedStateMachine.
+func shelvingStateText(state ua.NodeID) ua.LocalizedText {
+	switch state {
+	case ua.ObjectIDShelvedStateMachineTypeTimedShelved:
+		return ua.NewLocalizedText("TimedShelved", "")
+	case ua.ObjectIDShelvedStateMachineTypeOneShotShelved:
+		return ua.NewLocalizedText("OneShotShelved", "")
+	default:
+		return ua.NewLocalizedText("Unshelved", "")
+	}
+}
+
+// addShelvingFields adds the fields of the ShelvingState to the event. Must be called while holding the lock of the condition.
+func (c *Condition) addShelvingFields(f map[string]ua.Variant) {
+	f["ShelvingState"] = shelvingStateText(c.shelvingState)
+	f["ShelvingState/CurrentState"] = shelvingStateText(c.shelvingState)
+	f["ShelvingState/CurrentState/Id"] = c.shelvingState
+	f["ShelvingState/UnshelveTime"] = float64(c.remainingShelveTime()) / float64(time.Millisecond)
+	f["SuppressedOrShelved"] = c.shelvingState != ua.ObjectIDShelvedStateMachineTypeUnshelved
+	if c.maxTimeShelved > 0 {
+		f["MaxTimeShelved"] = float64(c.maxTimeShelved) / float64(time.Millisecond)
+	}
+}
+
+// addShelvingState adds the ShelvingState component of an alarm, with its CurrentState and UnshelveTime variables.
+func (m *ConditionManager) addShelvingState(c *Condition) error {
+	nm := m.server.NamespaceManager()
+	shelvingID := childNodeID(c.NodeID(), "ShelvingState")
+	n := NewObjectNode(
+		m.server,
+		shelvingID,
+		ua.NewQualifiedName(0, "ShelvingState"),
+		ua.NewLocalizedText("ShelvingState", ""),
+		ua.NewLocalizedText("", ""),
+		nil,
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.ObjectTypeIDShelvedStateMachineType)),
+			ua.NewReference(ua.ReferenceTypeIDHasComponent, true, ua.NewExpandedNodeID(c.NodeID())),
+		},
+		ua.EventNotifierNone,
+	)
+	currentState := NewVariableNode(
+		m.server,
+		childNodeID(shelvingID, "CurrentState"),
+		ua.NewQualifiedName(0, "CurrentState"),
+		ua.NewLocalizedText("CurrentState", ""),
+		ua.NewLocalizedText("", ""),
+		nil,
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDFiniteStateVariableType)),
+			ua.NewReference(ua.ReferenceTypeIDHasComponent, true, ua.NewExpandedNodeID(shelvingID)),
+		},
+		ua.NewDataValue(shelvingStateText(ua.ObjectIDShelvedStateMachineTypeUnshelved), 0, time.Now(), 0, time.Now(), 0),
+		ua.DataTypeIDLocalizedText,
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead,
+		0,
+		false,
+		nil,
+	)
+	currentState.SetReadValueHandler(func(session *Session, req ua.ReadValueID) ua.DataValue {
+		return ua.NewDataValue(shelvingStateText(c.ShelvingState()), 0, time.Now(), 0, time.Now(), 0)
+	})
+	unshelveTime := NewVariableNode(
+		m.server,
+		childNodeID(shelvingID, "UnshelveTime"),
+		ua.NewQualifiedName(0, "UnshelveTime"),
+		ua.NewLocalizedText("UnshelveTime", ""),
+		ua.NewLocalizedText("", ""),
+		nil,
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDPropertyType)),
+			ua.NewReference(ua.ReferenceTypeIDHasProperty, true, ua.NewExpandedNodeID(shelvingID)),
+		},
+		ua.NewDataValue(float64(0), 0, time.Now(), 0, time.Now(), 0),
+		ua.DataTypeIDDuration,
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead,
+		0,
+		false,
+		nil,
+	)
+	unshelveTime.SetReadValueHandler(func(session *Session, req ua.ReadValueID) ua.DataValue {
+		return ua.NewDataValue(float64(c.UnshelveTime())/float64(time.Millisecond), 0, time.Now(), 0, time.Now(), 0)
+	})
+	if err := nm.AddNodes(n, currentState, unshelveTime); err != nil {
+		return err
+	}
+	c.shelvingNode = n
+	m.Lock()
+	m.shelvingStates[shelvingID] = c
+	m.Unlock()
+	return nil
+}
+
+// findShelvingCondition returns the alarm whose ShelvingState is the object of the method call.
+// The alarm itself is accepted as the object too.
+func (m *ConditionManager) findShelvingCondition(req ua.CallMethodRequest) (*Condition, ua.StatusCode) {
+	m.RLock()
+	c, ok := m.shelvingStates[req.ObjectID]
+	m.RUnlock()
+	if ok {
+		return c, ua.Good
+	}
+	if c, ok := m.FindCondition(req.ObjectID); ok && c.alarm {
+		return c, ua.Good
+	}
+	return nil, ua.BadNodeIDInvalid
+}
+
+func (m *ConditionManager) onTimedShelve(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) < 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+	}
+	if len(req.InputArguments) > 1 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	shelvingTime, ok := req.InputArguments[0].(float64)
+	if !ok {
+		return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+	}
+	c, sc := m.findShelvingCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.TimedShelve(time.Duration(shelvingTime * float64(time.Millisecond))); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+func (m *ConditionManager) onOneShotShelve(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) > 0 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	c, sc := m.findShelvingCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.OneShotShelve(); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
+
+func (m *ConditionManager) onUnshelve(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+	if len(req.InputArguments) > 0 {
+		return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+	}
+	c, sc := m.findShelvingCondition(req)
+	if sc != ua.Good {
+		return ua.CallMethodResult{StatusCode: sc}
+	}
+	if err := c.Unshelve(); err != nil {
+		return ua.CallMethodResult{StatusCode: err.(ua.StatusCode)}
+	}
+	return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+}
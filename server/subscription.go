@@ -42,7 +42,7 @@ type Subscription struct {
 	moreNotifications            bool
 	isLate                       bool
 	resend                       bool
-	session                      *Session
+	session                      atomic.Pointer[Session]
 	manager                      *SubscriptionManager
 	retransmissionQueue          *list.List
 	diagnosticsNodeId            ua.NodeID
@@ -60,13 +60,15 @@ type Subscription struct {
 	monitoredItemCount           uint32
 	disabledMonitoredItemCount   uint32
 	monitoringQueueOverflowCount uint32
+	transferRequestCount         uint32
+	transferredToAltClientCount  uint32
+	transferredToSameClientCount uint32
 }
 
 // NewSubscription instantiates a new Subscription.
 func NewSubscription(manager *SubscriptionManager, session *Session, publishingInterval float64, lifetimeCount uint32, maxKeepAliveCount uint32, maxNotificationsPerPublish uint32, publishingEnabled bool, priority byte) *Subscription {
 	s := &Subscription{
 		manager:             manager,
-		session:             session,
 		id:                  atomic.AddUint32(&subscriptionID, 1),
 		publishingEnabled:   publishingEnabled,
 		priority:            priority,
@@ -76,6 +78,7 @@ func NewSubscription(manager *SubscriptionManager, session *Session, publishingI
 		diagnosticsNodeId:   ua.NewNodeIDGUID(1, uuid.New()),
 		sessionId:           session.sessionId,
 	}
+	s.session.Store(session)
 	s.setPublishingInterval(publishingInterval)
 	s.setMaxKeepAliveCount(maxKeepAliveCount)
 	s.setLifetimeCount(lifetimeCount)
@@ -110,7 +113,7 @@ func (s *Subscription) deleteImpl() {
 		e.Value = nil
 	}
 	s.retransmissionQueue = nil
-	s.session = nil
+	s.session.Store(nil)
 	s.manager = nil
 }
 
@@ -315,7 +318,7 @@ func (s *Subscription) publish(tn time.Time) error {
 	s.resend = false
 	switch {
 	case notificationsAvailable && s.publishingEnabled:
-		sess := s.session
+		sess := s.session.Load()
 		if sess == nil {
 			log.Printf("Subscription '%d' session in nil.\n", s.id)
 			return nil
@@ -348,7 +351,7 @@ func (s *Subscription) publish(tn time.Time) error {
 					}
 					more = more || more1
 					maxN = maxN - len(encs)
-				case *DataChangeMonitoredItem:
+				case *DataChangeMonitoredItem, *AggregateMonitoredItem:
 					encs, more1 := mi.notifications(maxN)
 					for _, enc := range encs {
 						if dv, ok := enc.(ua.DataValue); ok {
@@ -422,7 +425,7 @@ func (s *Subscription) publish(tn time.Time) error {
 				PublishTime:      time.Now(),
 				NotificationData: []ua.ExtensionObject{ua.StatusChangeNotification{Status: ua.BadTimeout}},
 			}
-			s.session.stateChanges <- &stateChangeOp{subscriptionId: s.id, message: nm}
+			s.session.Load().stateChanges <- &stateChangeOp{subscriptionId: s.id, message: nm}
 			s.nextSequenceNumber++
 			s.manager.Delete(s)
 			s.deleteImpl()
@@ -432,7 +435,7 @@ func (s *Subscription) publish(tn time.Time) error {
 	default:
 		s.keepAliveCount++
 		if s.keepAliveCount >= s.maxKeepAliveCount || s.publishRequestCount == 0 {
-			sess := s.session
+			sess := s.session.Load()
 			if sess == nil {
 				log.Printf("Subscription '%d' session in nil.\n", s.id)
 				return nil
@@ -488,7 +491,7 @@ func (s *Subscription) publish(tn time.Time) error {
 					PublishTime:      time.Now(),
 					NotificationData: []ua.ExtensionObject{ua.StatusChangeNotification{Status: ua.BadTimeout}},
 				}
-				s.session.stateChanges <- &stateChangeOp{subscriptionId: s.id, message: nm}
+				s.session.Load().stateChanges <- &stateChangeOp{subscriptionId: s.id, message: nm}
 				s.nextSequenceNumber++
 				s.manager.Delete(s)
 				s.deleteImpl()
@@ -538,7 +541,7 @@ func (s *Subscription) handleLatePublishRequest(ch *serverSecureChannel, request
 				}
 				more = more || more1
 				maxN = maxN - len(encs)
-			case *DataChangeMonitoredItem:
+			case *DataChangeMonitoredItem, *AggregateMonitoredItem:
 				encs, more1 := mi.notifications(maxN)
 				for _, enc := range encs {
 					if dv, ok := enc.(ua.DataValue); ok {
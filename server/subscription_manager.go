@@ -87,13 +87,109 @@ func (m *SubscriptionManager) GetBySession(session *Session) []*Subscription {
 	defer m.RUnlock()
 	subs := make([]*Subscription, 0, 4)
 	for _, sub := range m.subscriptionsByID {
-		if sub.session == session {
+		if sub.session.Load() == session {
 			subs = append(subs, sub)
 		}
 	}
 	return subs
 }
 
+// Transfer reassigns the subscription to the session. The user of the session must be the same
+// user that owns the subscription. The retransmission queue is kept, so the client may republish
+// any unacknowledged messages. The previous session is notified that the subscription was transferred.
+func (m *SubscriptionManager) Transfer(id uint32, session *Session, sendInitialValues bool) ua.TransferResult {
+	s, ok := m.Get(id)
+	if !ok {
+		return ua.TransferResult{StatusCode: ua.BadSubscriptionIDInvalid}
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.transferRequestCount++
+	old := s.session.Load()
+	if old == nil {
+		return ua.TransferResult{StatusCode: ua.BadSubscriptionIDInvalid}
+	}
+	if old != session {
+		if !isSameUser(old.UserIdentity(), session.UserIdentity()) {
+			return ua.TransferResult{StatusCode: ua.BadUserAccessDenied}
+		}
+		if _, ok := session.UserIdentity().(ua.AnonymousIdentity); ok && old.clientDescription.ApplicationURI != session.clientDescription.ApplicationURI {
+			return ua.TransferResult{StatusCode: ua.BadUserAccessDenied}
+		}
+		if old.clientDescription.ApplicationURI == session.clientDescription.ApplicationURI {
+			s.transferredToSameClientCount++
+		} else {
+			s.transferredToAltClientCount++
+		}
+		s.session.Store(session)
+		s.sessionId = session.sessionId
+		if m.server.serverDiagnostics {
+			m.removeDiagnosticsNode(s)
+			m.addDiagnosticsNode(s)
+		}
+		// notify previous session.
+		nm := ua.NotificationMessage{
+			SequenceNumber:   s.nextSequenceNumber,
+			PublishTime:      time.Now(),
+			NotificationData: []ua.ExtensionObject{ua.StatusChangeNotification{Status: ua.GoodSubscriptionTransferred}},
+		}
+		ch, requestid, req, results, ok, err := old.removePublishRequest()
+		if err == nil && ok {
+			ch.Write(
+				&ua.PublishResponse{
+					ResponseHeader: ua.ResponseHeader{
+						Timestamp:     time.Now(),
+						RequestHandle: req.RequestHeader.RequestHandle,
+					},
+					SubscriptionID:           s.id,
+					AvailableSequenceNumbers: []uint32{},
+					MoreNotifications:        false,
+					NotificationMessage:      nm,
+					Results:                  results,
+					DiagnosticInfos:          nil,
+				},
+				requestid,
+			)
+		} else {
+			select {
+			case old.stateChanges <- &stateChangeOp{subscriptionId: s.id, message: nm}:
+			default:
+			}
+		}
+	}
+	if sendInitialValues {
+		s.resend = true
+	}
+	s.lifetimeCount = 0
+	avail := make([]uint32, 0, 4)
+	q := s.retransmissionQueue
+	for e := q.Front(); e != nil; e = e.Next() {
+		if nm, ok := e.Value.(ua.NotificationMessage); ok {
+			avail = append(avail, nm.SequenceNumber)
+		}
+	}
+	return ua.TransferResult{AvailableSequenceNumbers: avail}
+}
+
+// isSameUser returns true if the user identities identify the same user.
+func isSameUser(a, b any) bool {
+	switch a := a.(type) {
+	case ua.AnonymousIdentity:
+		_, ok := b.(ua.AnonymousIdentity)
+		return ok
+	case ua.UserNameIdentity:
+		b, ok := b.(ua.UserNameIdentity)
+		return ok && a.UserName == b.UserName
+	case ua.X509Identity:
+		b, ok := b.(ua.X509Identity)
+		return ok && a.Certificate == b.Certificate
+	case ua.IssuedIdentity:
+		b, ok := b.(ua.IssuedIdentity)
+		return ok && a.TokenData == b.TokenData
+	}
+	return false
+}
+
 func (m *SubscriptionManager) checkForExpiredSubscriptions() {
 	m.Lock()
 	defer m.Unlock()
@@ -173,9 +269,9 @@ func (m *SubscriptionManager) addDiagnosticsNode(s *Subscription) {
 			RepublishRequestCount:        s.republishRequestCount,
 			RepublishMessageRequestCount: s.republishMessageRequestCount,
 			RepublishMessageCount:        s.republishMessageCount,
-			// TransferRequestCount:         uint32(0),
-			// TransferredToAltClientCount:  uint32(0),
-			// TransferredToSameClientCount: uint32(0),
+			TransferRequestCount:         s.transferRequestCount,
+			TransferredToAltClientCount:  s.transferredToAltClientCount,
+			TransferredToSameClientCount: s.transferredToSameClientCount,
 			PublishRequestCount:          s.publishRequestCount,
 			DataChangeNotificationsCount: s.dataChangeNotificationsCount,
 			EventNotificationsCount:      s.eventNotificationsCount,
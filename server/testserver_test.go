@@ -3,10 +3,15 @@
 package server_test
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/x509"
 	_ "embed"
 	"fmt"
 	"os"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/awcullen/opcua/server"
 	"github.com/awcullen/opcua/ua"
@@ -15,7 +20,7 @@ import (
 
 var (
 	host, _         = os.Hostname()
-	port            = 46010
+	port            = 46011
 	SoftwareVersion = "1.0.0"
 	//go:embed testnodeset_test.xml
 	testnodeset []byte
@@ -86,8 +91,20 @@ func NewTestServer() (*server.Server, error) {
 			// log.Printf("Login %s from %s\n", cert.Subject, applicationURI)
 			return nil
 		}),
+		server.WithGetRolesFunc(func(userIdentity any, applicationURI string, endpointURL string) ([]ua.NodeID, error) {
+			roles, err := server.DefaultRolesProvider.GetRoles(userIdentity, applicationURI, endpointURL)
+			if err != nil {
+				return nil, err
+			}
+			// root may configure the address space.
+			if id, ok := userIdentity.(ua.UserNameIdentity); ok && id.UserName == "root" {
+				roles = append(roles, ua.ObjectIDWellKnownRoleConfigureAdmin)
+			}
+			return roles, nil
+		}),
 		server.WithSecurityPolicyNone(true),
 		server.WithInsecureSkipVerify(),
+		server.WithHistorian(newMemoryHistorian()),
 	)
 	if err != nil {
 		return nil, err
@@ -168,5 +185,467 @@ func NewTestServer() (*server.Server, error) {
 			return ua.CallMethodResult{OutputArguments: []ua.Variant{uint32(result)}}
 		})
 	}
+	// add a variable that records history and allows authenticated users to update it.
+	historyNode := server.NewVariableNode(
+		srv,
+		ua.ParseNodeID("ns=2;s=Demo.History.Double"),
+		ua.ParseQualifiedName("2:HistoryDouble"),
+		ua.NewLocalizedText("HistoryDouble", ""),
+		ua.NewLocalizedText("", ""),
+		[]ua.RolePermissionType{
+			{RoleID: ua.ObjectIDWellKnownRoleAuthenticatedUser, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead | ua.PermissionTypeReadHistory | ua.PermissionTypeInsertHistory | ua.PermissionTypeModifyHistory | ua.PermissionTypeDeleteHistory},
+			{RoleID: ua.ObjectIDWellKnownRoleAnonymous, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead | ua.PermissionTypeReadHistory},
+		},
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDOrganizes, true, ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Demo"))),
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDBaseDataVariableType)),
+		},
+		ua.NewDataValue(float64(0), 0, time.Now(), 0, time.Now(), 0),
+		ua.DataTypeIDDouble,
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead|ua.AccessLevelsHistoryRead|ua.AccessLevelsHistoryWrite,
+		0,
+		false,
+		nil,
+	)
+	if err := nm.AddNode(historyNode); err != nil {
+		return nil, err
+	}
+	// add an analog item with an engineering unit range, for testing percent deadband.
+	analogNode := server.NewVariableNode(
+		srv,
+		ua.ParseNodeID("ns=2;s=Demo.Analog.Double"),
+		ua.ParseQualifiedName("2:AnalogDouble"),
+		ua.NewLocalizedText("AnalogDouble", ""),
+		ua.NewLocalizedText("", ""),
+		[]ua.RolePermissionType{
+			{RoleID: ua.ObjectIDWellKnownRoleAuthenticatedUser, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead | ua.PermissionTypeWrite},
+			{RoleID: ua.ObjectIDWellKnownRoleAnonymous, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead},
+		},
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDOrganizes, true, ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Demo"))),
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDAnalogItemType)),
+		},
+		ua.NewDataValue(float64(0), 0, time.Now(), 0, time.Now(), 0),
+		ua.DataTypeIDDouble,
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead|ua.AccessLevelsCurrentWrite,
+		0,
+		false,
+		nil,
+	)
+	euRangeNode := server.NewVariableNode(
+		srv,
+		ua.ParseNodeID("ns=2;s=Demo.Analog.Double.EURange"),
+		ua.ParseQualifiedName("EURange"),
+		ua.NewLocalizedText("EURange", ""),
+		ua.NewLocalizedText("", ""),
+		nil,
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasProperty, true, ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Demo.Analog.Double"))),
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDPropertyType)),
+		},
+		ua.NewDataValue(ua.Range{Low: 0, High: 100}, 0, time.Now(), 0, time.Now(), 0),
+		ua.DataTypeIDRange,
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead,
+		0,
+		false,
+		nil,
+	)
+	if err := nm.AddNodes(analogNode, euRangeNode); err != nil {
+		return nil, err
+	}
+
+	// add instances of a standard ObjectType and VariableType, for testing Instantiate.
+	if _, err := nm.Instantiate(ua.ObjectTypeIDFileType, ua.ParseNodeID("ns=2;s=Demo"), ua.ParseQualifiedName("2:FileInstance"), nil, server.WithParentReferenceType(ua.ReferenceTypeIDOrganizes)); err != nil {
+		return nil, err
+	}
+	if _, err := nm.Instantiate(ua.VariableTypeIDServerStatusType, ua.ParseNodeID("ns=2;s=Demo"), ua.ParseQualifiedName("2:StatusInstance"), nil, server.WithParentReferenceType(ua.ReferenceTypeIDOrganizes)); err != nil {
+		return nil, err
+	}
+
+	// add a namespace of tags that are provided by a NodeManager, for testing NodeManager.
+	tagsIndex := nm.Add("http://github.com/awcullen/opcua/testserver/tags")
+	if err := nm.RegisterNodeManager(tagsIndex, &tagNodeManager{srv: srv, nsIndex: tagsIndex, values: map[int32]ua.DataValue{}}); err != nil {
+		return nil, err
+	}
+	if objects, ok := nm.FindObject(ua.ObjectIDObjectsFolder); ok {
+		if err := nm.AddReference(objects, ua.NewReference(ua.ReferenceTypeIDOrganizes, false, ua.NewExpandedNodeID(ua.NewNodeIDString(tagsIndex, "Tags")))); err != nil {
+			return nil, err
+		}
+	}
+
+	// add a namespace of links that are provided by a NodeManager without nodes, for testing NodeManager.
+	linksIndex := nm.Add("http://github.com/awcullen/opcua/testserver/links")
+	if err := nm.RegisterNodeManager(linksIndex, &linkNodeManager{nsIndex: linksIndex}); err != nil {
+		return nil, err
+	}
+
+	// add a tank with a level and limit alarms, for testing alarms.
+	tankNode := server.NewObjectNode(
+		srv,
+		ua.ParseNodeID("ns=2;s=Tank1"),
+		ua.ParseQualifiedName("2:Tank1"),
+		ua.NewLocalizedText("Tank1", ""),
+		ua.NewLocalizedText("Alarms of Tank1", ""),
+		nil,
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasNotifier, true, ua.NewExpandedNodeID(ua.ObjectIDServer)),
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.ObjectTypeIDBaseObjectType)),
+		},
+		ua.EventNotifierSubscribeToEvents,
+	)
+	levelNode := server.NewVariableNode(
+		srv,
+		ua.ParseNodeID("ns=2;s=Tank1.Level"),
+		ua.ParseQualifiedName("2:Level"),
+		ua.NewLocalizedText("Level", ""),
+		ua.NewLocalizedText("", ""),
+		[]ua.RolePermissionType{
+			{RoleID: ua.ObjectIDWellKnownRoleAuthenticatedUser, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead | ua.PermissionTypeWrite},
+			{RoleID: ua.ObjectIDWellKnownRoleAnonymous, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead},
+		},
+		[]ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasComponent, true, ua.NewExpandedNodeID(ua.ParseNodeID("ns=2;s=Tank1"))),
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDBaseDataVariableType)),
+		},
+		ua.NewDataValue(float64(50), 0, time.Now(), 0, time.Now(), 0),
+		ua.DataTypeIDDouble,
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead|ua.AccessLevelsCurrentWrite,
+		0,
+		false,
+		nil,
+	)
+	if err := nm.AddNodes(tankNode, levelNode); err != nil {
+		return nil, err
+	}
+	if _, err := srv.ConditionManager().AddExclusiveLimitAlarm(
+		ua.ParseNodeID("ns=2;s=Tank1.LevelAlarm"),
+		ua.ParseQualifiedName("2:LevelAlarm"),
+		tankNode,
+		levelNode,
+		server.WithHighHighLimit(90),
+		server.WithHighLimit(70),
+		server.WithLowLimit(30),
+		server.WithLowLowLimit(10),
+		server.WithLimitDeadband(2),
+	); err != nil {
+		return nil, err
+	}
+	if _, err := srv.ConditionManager().AddNonExclusiveLimitAlarm(
+		ua.ParseNodeID("ns=2;s=Tank1.LevelLimits"),
+		ua.ParseQualifiedName("2:LevelLimits"),
+		tankNode,
+		levelNode,
+		server.WithHighHighLimit(90),
+		server.WithHighLimit(70),
+		server.WithLimitDeadband(2),
+	); err != nil {
+		return nil, err
+	}
+
+	// add an active alarm in Area2, for testing conditions.
+	area2, _ := nm.FindObject(ua.ParseNodeID("ns=2;s=Area2"))
+	alarm, err := srv.ConditionManager().AddCondition(
+		ua.ParseNodeID("ns=2;s=Area2.HighLevel"),
+		ua.ObjectTypeIDAlarmConditionType,
+		ua.NewQualifiedName(2, "HighLevel"),
+		area2,
+	)
+	if err != nil {
+		return nil, err
+	}
+	alarm.SetActive(true, 700, ua.LocalizedText{Text: "High level in Area2"})
+
+	// emit events in Area1 with a cycling severity, for testing event filters.
+	go func() {
+		source, _ := nm.FindObject(ua.ParseNodeID("ns=2;s=Area1"))
+		severities := []uint16{100, 500, 900}
+		ticker := time.NewTicker(200 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			select {
+			case <-ticker.C:
+				evt := &ua.BaseEvent{
+					EventID:     getNextEventID(),
+					EventType:   ua.ObjectTypeIDBaseEventType,
+					SourceNode:  source.NodeID(),
+					SourceName:  "Area1",
+					Time:        time.Now(),
+					ReceiveTime: time.Now(),
+					Message:     ua.LocalizedText{Text: "Event in Area1"},
+					Severity:    severities[i%len(severities)],
+				}
+				nm.OnEvent(source, evt)
+			case <-srv.Closing():
+				return
+			}
+		}
+	}()
+
 	return srv, nil
 }
+
+// getNextEventID gets next random eventID.
+func getNextEventID() ua.ByteString {
+	var nonce = make([]byte, 16)
+	rand.Read(nonce)
+	return ua.ByteString(nonce)
+}
+
+// tagNodeManager provides a folder of a million tags, creating the nodes on demand. The nodes are
+// not kept, only the values that are written, as if the tags were stored in a database.
+type tagNodeManager struct {
+	sync.Mutex
+	srv     *server.Server
+	nsIndex uint16
+	values  map[int32]ua.DataValue
+}
+
+func (m *tagNodeManager) FindNode(id ua.NodeID) (server.Node, bool) {
+	refs, ok := m.References(id)
+	if !ok {
+		return nil, false
+	}
+	name := id.(ua.NodeIDString).ID
+	i, ok := m.tag(id)
+	if !ok {
+		return server.NewObjectNode(m.srv, id, ua.NewQualifiedName(m.nsIndex, name), ua.NewLocalizedText(name, ""), ua.NewLocalizedText("", ""), nil, refs, ua.EventNotifierNone), true
+	}
+	n := server.NewVariableNode(
+		m.srv,
+		id,
+		ua.NewQualifiedName(m.nsIndex, name),
+		ua.NewLocalizedText(name, ""),
+		ua.NewLocalizedText("", ""),
+		[]ua.RolePermissionType{
+			{RoleID: ua.ObjectIDWellKnownRoleAuthenticatedUser, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead | ua.PermissionTypeWrite},
+			{RoleID: ua.ObjectIDWellKnownRoleAnonymous, Permissions: ua.PermissionTypeBrowse | ua.PermissionTypeRead},
+		},
+		refs,
+		ua.NewDataValue(i, 0, time.Now(), 0, time.Now(), 0),
+		ua.DataTypeIDInt32,
+		ua.ValueRankScalar,
+		[]uint32{},
+		ua.AccessLevelsCurrentRead|ua.AccessLevelsCurrentWrite,
+		0,
+		false,
+		nil,
+	)
+	n.SetReadValueHandler(func(session *server.Session, req ua.ReadValueID) ua.DataValue {
+		m.Lock()
+		defer m.Unlock()
+		if v, ok := m.values[i]; ok {
+			return v
+		}
+		return ua.NewDataValue(i, 0, time.Now(), 0, time.Now(), 0)
+	})
+	n.SetWriteValueHandler(func(session *server.Session, req ua.WriteValue) (ua.DataValue, ua.StatusCode) {
+		m.Lock()
+		defer m.Unlock()
+		v := ua.NewDataValue(req.Value.Value, req.Value.StatusCode, time.Now(), 0, time.Now(), 0)
+		m.values[i] = v
+		return v, ua.Good
+	})
+	return n, true
+}
+
+func (m *tagNodeManager) References(id ua.NodeID) ([]ua.Reference, bool) {
+	if id == ua.NewNodeIDString(m.nsIndex, "Tags") {
+		// the folder references the first ten tags.
+		refs := []ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.ObjectTypeIDFolderType)),
+			ua.NewReference(ua.ReferenceTypeIDOrganizes, true, ua.NewExpandedNodeID(ua.ObjectIDObjectsFolder)),
+		}
+		for i := 0; i < 10; i++ {
+			refs = append(refs, ua.NewReference(ua.ReferenceTypeIDOrganizes, false, ua.NewExpandedNodeID(ua.NewNodeIDString(m.nsIndex, fmt.Sprintf("Tag%d", i)))))
+		}
+		return refs, true
+	}
+	if _, ok := m.tag(id); !ok {
+		return nil, false
+	}
+	return []ua.Reference{
+		ua.NewReference(ua.ReferenceTypeIDHasTypeDefinition, false, ua.NewExpandedNodeID(ua.VariableTypeIDBaseDataVariableType)),
+		ua.NewReference(ua.ReferenceTypeIDOrganizes, true, ua.NewExpandedNodeID(ua.NewNodeIDString(m.nsIndex, "Tags"))),
+	}, true
+}
+
+// tag returns the number of the tag with the given NodeID.
+func (m *tagNodeManager) tag(id ua.NodeID) (int32, bool) {
+	sid, ok := id.(ua.NodeIDString)
+	if !ok || sid.NamespaceIndex != m.nsIndex {
+		return 0, false
+	}
+	var i int32
+	if _, err := fmt.Sscanf(sid.ID, "Tag%d", &i); err != nil || i < 0 || i >= 1000000 || sid.ID != fmt.Sprintf("Tag%d", i) {
+		return 0, false
+	}
+	return i, true
+}
+
+// linkNodeManager provides the references of a folder of links to standard nodes, but not the
+// folder itself, as if the links were the only data of an external source.
+type linkNodeManager struct {
+	nsIndex uint16
+}
+
+func (m *linkNodeManager) FindNode(id ua.NodeID) (server.Node, bool) {
+	return nil, false
+}
+
+func (m *linkNodeManager) References(id ua.NodeID) ([]ua.Reference, bool) {
+	switch id {
+	case ua.NewNodeIDString(m.nsIndex, "Links"):
+		return []ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDOrganizes, false, ua.NewExpandedNodeID(ua.ObjectIDServer)),
+			ua.NewReference(ua.ReferenceTypeIDOrganizes, false, ua.NewExpandedNodeID(ua.ObjectIDTypesFolder)),
+		}, true
+	case ua.NewNodeIDString(m.nsIndex, "AdminLinks"):
+		return []ua.Reference{
+			ua.NewReference(ua.ReferenceTypeIDOrganizes, false, ua.NewExpandedNodeID(ua.ObjectIDServerConfiguration)),
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// RolePermissions permits only the ConfigureAdmin role to browse the admin links. The links use the
+// RolePermissions of the server.
+func (m *linkNodeManager) RolePermissions(id ua.NodeID) []ua.RolePermissionType {
+	if id == ua.NewNodeIDString(m.nsIndex, "AdminLinks") {
+		return []ua.RolePermissionType{
+			{RoleID: ua.ObjectIDWellKnownRoleConfigureAdmin, Permissions: ua.PermissionTypeBrowse},
+		}
+	}
+	return nil
+}
+
+// memoryHistorian stores historical data values in memory.
+type memoryHistorian struct {
+	sync.Mutex
+	values map[ua.NodeID][]ua.DataValue
+}
+
+func newMemoryHistorian() *memoryHistorian {
+	return &memoryHistorian{values: make(map[ua.NodeID][]ua.DataValue)}
+}
+
+func (h *memoryHistorian) WriteEvent(ctx context.Context, nodeID ua.NodeID, eventFields []ua.Variant) error {
+	return nil
+}
+
+func (h *memoryHistorian) WriteValue(ctx context.Context, nodeID ua.NodeID, value ua.DataValue) error {
+	h.Lock()
+	defer h.Unlock()
+	h.values[nodeID] = append(h.values[nodeID], value)
+	return nil
+}
+
+func (h *memoryHistorian) ReadEvent(ctx context.Context, nodesToRead []ua.HistoryReadValueID, details ua.ReadEventDetails,
+	timestampsToReturn ua.TimestampsToReturn, releaseContinuationPoints bool) ([]ua.HistoryReadResult, ua.StatusCode) {
+	return nil, ua.BadHistoryOperationUnsupported
+}
+
+func (h *memoryHistorian) ReadRawModified(ctx context.Context, nodesToRead []ua.HistoryReadValueID, details ua.ReadRawModifiedDetails,
+	timestampsToReturn ua.TimestampsToReturn, releaseContinuationPoints bool) ([]ua.HistoryReadResult, ua.StatusCode) {
+	h.Lock()
+	defer h.Unlock()
+	results := make([]ua.HistoryReadResult, len(nodesToRead))
+	for i, n := range nodesToRead {
+		values := []ua.DataValue{}
+		for _, v := range h.values[n.NodeID] {
+			if !v.SourceTimestamp.Before(details.StartTime) && v.SourceTimestamp.Before(details.EndTime) {
+				values = append(values, v)
+			}
+		}
+		results[i] = ua.HistoryReadResult{HistoryData: ua.HistoryData{DataValues: values}}
+	}
+	return results, ua.Good
+}
+
+func (h *memoryHistorian) ReadProcessed(ctx context.Context, nodesToRead []ua.HistoryReadValueID, details ua.ReadProcessedDetails,
+	timestampsToReturn ua.TimestampsToReturn, releaseContinuationPoints bool) ([]ua.HistoryReadResult, ua.StatusCode) {
+	return nil, ua.BadHistoryOperationUnsupported
+}
+
+func (h *memoryHistorian) ReadAtTime(ctx context.Context, nodesToRead []ua.HistoryReadValueID, details ua.ReadAtTimeDetails,
+	timestampsToReturn ua.TimestampsToReturn, releaseContinuationPoints bool) ([]ua.HistoryReadResult, ua.StatusCode) {
+	return nil, ua.BadHistoryOperationUnsupported
+}
+
+func (h *memoryHistorian) UpdateData(ctx context.Context, details ua.UpdateDataDetails) ua.HistoryUpdateResult {
+	h.Lock()
+	defer h.Unlock()
+	values := h.values[details.NodeID]
+	results := make([]ua.StatusCode, len(details.UpdateValues))
+	for i, v := range details.UpdateValues {
+		j := sort.Search(len(values), func(k int) bool { return !values[k].SourceTimestamp.Before(v.SourceTimestamp) })
+		exists := j < len(values) && values[j].SourceTimestamp.Equal(v.SourceTimestamp)
+		switch {
+		case exists && details.PerformInsertReplace == ua.PerformUpdateTypeInsert:
+			results[i] = ua.BadEntryExists
+		case !exists && details.PerformInsertReplace == ua.PerformUpdateTypeReplace:
+			results[i] = ua.BadNoEntryExists
+		case exists:
+			values[j] = v
+			results[i] = ua.GoodEntryReplaced
+		default:
+			values = append(values, ua.DataValue{})
+			copy(values[j+1:], values[j:])
+			values[j] = v
+			results[i] = ua.GoodEntryInserted
+		}
+	}
+	h.values[details.NodeID] = values
+	return ua.HistoryUpdateResult{OperationResults: results}
+}
+
+func (h *memoryHistorian) UpdateEvent(ctx context.Context, details ua.UpdateEventDetails) ua.HistoryUpdateResult {
+	return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+}
+
+func (h *memoryHistorian) DeleteRawModified(ctx context.Context, details ua.DeleteRawModifiedDetails) ua.HistoryUpdateResult {
+	h.Lock()
+	defer h.Unlock()
+	values := []ua.DataValue{}
+	for _, v := range h.values[details.NodeID] {
+		if !v.SourceTimestamp.Before(details.StartTime) && v.SourceTimestamp.Before(details.EndTime) {
+			continue
+		}
+		values = append(values, v)
+	}
+	if len(values) == len(h.values[details.NodeID]) {
+		return ua.HistoryUpdateResult{StatusCode: ua.BadNoData}
+	}
+	h.values[details.NodeID] = values
+	return ua.HistoryUpdateResult{}
+}
+
+func (h *memoryHistorian) DeleteAtTime(ctx context.Context, details ua.DeleteAtTimeDetails) ua.HistoryUpdateResult {
+	h.Lock()
+	defer h.Unlock()
+	results := make([]ua.StatusCode, len(details.ReqTimes))
+	for i, t := range details.ReqTimes {
+		values := h.values[details.NodeID]
+		results[i] = ua.BadNoEntryExists
+		for j, v := range values {
+			if v.SourceTimestamp.Equal(t) {
+				h.values[details.NodeID] = append(values[:j], values[j+1:]...)
+				results[i] = ua.Good
+				break
+			}
+		}
+	}
+	return ua.HistoryUpdateResult{OperationResults: results}
+}
+
+func (h *memoryHistorian) DeleteEvent(ctx context.Context, details ua.DeleteEventDetails) ua.HistoryUpdateResult {
+	return ua.HistoryUpdateResult{StatusCode: ua.BadHistoryOperationUnsupported}
+}
@@ -0,0 +1,517 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package server
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// the file modes that a trust list may be opened with.
+const (
+	trustListModeRead  = byte(ua.OpenFileModeRead)
+	trustListModeWrite = byte(ua.OpenFileModeWrite | ua.OpenFileModeEraseExisting)
+)
+
+// the lists of the trust list, in the order of the fields of the TrustListDataType.
+var trustListMasks = [4]ua.TrustListMasks{
+	ua.TrustListMasksTrustedCertificates,
+	ua.TrustListMasksTrustedCrls,
+	ua.TrustListMasksIssuerCertificates,
+	ua.TrustListMasksIssuerCrls,
+}
+
+// trustList is the trust list of the DefaultApplicationGroup. A GDS or admin client reads and replaces
+// the trusted and issuer certificates and CRLs, that are kept in the certificate store of the server.
+// The methods of the FileType are those of a FileNode, over an in-memory file that holds the encoded lists.
+// See https://reference.opcfoundation.org/v104/GDS/docs/7.5/
+type trustList struct {
+	server         *Server
+	file           *fileType
+	data           *MemoryFile
+	masks          uint32
+	lastUpdateTime time.Time
+}
+
+// newTrustList instantiates a new trustList. Only the SecurityAdmin role may open it for writing.
+func newTrustList(server *Server) *trustList {
+	tl := &trustList{
+		server: server,
+		data:   NewMemoryFile(nil),
+	}
+	tl.file = newFileType(tl.data, tl.writable, func(session *Session) bool {
+		return tl.writable() && server.checkSecurityAdmin(session) == ua.Good
+	})
+	return tl
+}
+
+// initializeTrustList installs the properties and methods of the TrustList object.
+func (srv *Server) initializeTrustList() {
+	nm := srv.NamespaceManager()
+	tl := newTrustList(srv)
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListSize); ok {
+		n.SetReadValueHandler(func(session *Session, req ua.ReadValueID) ua.DataValue {
+			data, err := tl.encode(uint32(ua.TrustListMasksAll))
+			if err != nil {
+				return ua.NewDataValue(nil, ua.BadResourceUnavailable, time.Time{}, 0, time.Now(), 0)
+			}
+			return ua.NewDataValue(uint64(len(data)), 0, time.Now(), 0, time.Now(), 0)
+		})
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpenCount); ok {
+		n.SetReadValueHandler(func(session *Session, req ua.ReadValueID) ua.DataValue {
+			return ua.NewDataValue(tl.file.openCount(), 0, time.Now(), 0, time.Now(), 0)
+		})
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListWritable); ok {
+		n.SetReadValueHandler(func(session *Session, req ua.ReadValueID) ua.DataValue {
+			return ua.NewDataValue(tl.writable(), 0, time.Now(), 0, time.Now(), 0)
+		})
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListUserWritable); ok {
+		n.SetReadValueHandler(func(session *Session, req ua.ReadValueID) ua.DataValue {
+			return ua.NewDataValue(tl.file.userWritable(session), 0, time.Now(), 0, time.Now(), 0)
+		})
+	}
+	if n, ok := nm.FindVariable(ua.VariableIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListLastUpdateTime); ok {
+		n.SetReadValueHandler(func(session *Session, req ua.ReadValueID) ua.DataValue {
+			tl.file.Lock()
+			defer tl.file.Unlock()
+			return ua.NewDataValue(tl.lastUpdateTime, 0, time.Now(), 0, time.Now(), 0)
+		})
+	}
+
+	// only the SecurityAdmin role may call the methods.
+	rolePermissions := srv.securityAdminRolePermissions()
+	for _, id := range []ua.NodeID{
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpen,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpenWithMasks,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListRead,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListWrite,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListGetPosition,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListSetPosition,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListClose,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListCloseAndUpdate,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListAddCertificate,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListRemoveCertificate,
+	} {
+		if n, ok := nm.FindMethod(id); ok {
+			n.rolePermissions = rolePermissions
+		}
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpen); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) < 1 {
+				return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+			}
+			if len(req.InputArguments) > 1 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			mode, ok := req.InputArguments[0].(byte)
+			if !ok {
+				return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+			}
+			handle, err := tl.open(session, mode, uint32(ua.TrustListMasksAll))
+			if err != nil {
+				return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+			}
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{handle}}
+		})
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListOpenWithMasks); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) < 1 {
+				return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+			}
+			if len(req.InputArguments) > 1 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			masks, ok := req.InputArguments[0].(uint32)
+			if !ok {
+				return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+			}
+			handle, err := tl.open(session, trustListModeRead, masks)
+			if err != nil {
+				return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+			}
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{handle}}
+		})
+	}
+
+	// the methods of the FileType are shared with the FileNode.
+	for id, handler := range map[ua.NodeID]func(*Session, ua.CallMethodRequest) ua.CallMethodResult{
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListRead:        tl.file.callRead,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListWrite:       tl.file.callWrite,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListGetPosition: tl.file.callGetPosition,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListSetPosition: tl.file.callSetPosition,
+		ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListClose:       tl.file.callClose,
+	} {
+		if n, ok := nm.FindMethod(id); ok {
+			n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+				if result := srv.checkSecurityAdmin(session); result != ua.Good {
+					return ua.CallMethodResult{StatusCode: result}
+				}
+				return handler(session, req)
+			})
+		}
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListCloseAndUpdate); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) < 1 {
+				return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+			}
+			if len(req.InputArguments) > 1 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			handle, ok := req.InputArguments[0].(uint32)
+			if !ok {
+				return ua.CallMethodResult{StatusCode: ua.BadInvalidArgument, InputArgumentResults: []ua.StatusCode{ua.BadTypeMismatch}}
+			}
+			if err := tl.closeAndUpdate(session, handle); err != nil {
+				return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+			}
+			// the certificates are validated with the updated trust list at once, so ApplyChanges is not required.
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{false}}
+		})
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListAddCertificate); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) < 2 {
+				return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+			}
+			if len(req.InputArguments) > 2 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			opResult := ua.Good
+			argsResults := make([]ua.StatusCode, 2)
+			certificate, ok := req.InputArguments[0].(ua.ByteString)
+			if !ok {
+				opResult = ua.BadInvalidArgument
+				argsResults[0] = ua.BadTypeMismatch
+			}
+			isTrustedCertificate, ok := req.InputArguments[1].(bool)
+			if !ok {
+				opResult = ua.BadInvalidArgument
+				argsResults[1] = ua.BadTypeMismatch
+			}
+			if opResult == ua.BadInvalidArgument {
+				return ua.CallMethodResult{StatusCode: opResult, InputArgumentResults: argsResults}
+			}
+			if err := tl.addCertificate([]byte(certificate), isTrustedCertificate); err != nil {
+				return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+			}
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+		})
+	}
+
+	if n, ok := nm.FindMethod(ua.MethodIDServerConfigurationCertificateGroupsDefaultApplicationGroupTrustListRemoveCertificate); ok {
+		n.SetCallMethodHandler(func(session *Session, req ua.CallMethodRequest) ua.CallMethodResult {
+			if result := srv.checkSecurityAdmin(session); result != ua.Good {
+				return ua.CallMethodResult{StatusCode: result}
+			}
+			if len(req.InputArguments) < 2 {
+				return ua.CallMethodResult{StatusCode: ua.BadArgumentsMissing}
+			}
+			if len(req.InputArguments) > 2 {
+				return ua.CallMethodResult{StatusCode: ua.BadTooManyArguments}
+			}
+			opResult := ua.Good
+			argsResults := make([]ua.StatusCode, 2)
+			thumbprint, ok := req.InputArguments[0].(string)
+			if !ok {
+				opResult = ua.BadInvalidArgument
+				argsResults[0] = ua.BadTypeMismatch
+			}
+			isTrustedCertificate, ok := req.InputArguments[1].(bool)
+			if !ok {
+				opResult = ua.BadInvalidArgument
+				argsResults[1] = ua.BadTypeMismatch
+			}
+			if opResult == ua.BadInvalidArgument {
+				return ua.CallMethodResult{StatusCode: opResult, InputArgumentResults: argsResults}
+			}
+			if err := tl.removeCertificate(thumbprint, isTrustedCertificate); err != nil {
+				return ua.CallMethodResult{StatusCode: statusCodeOf(err)}
+			}
+			return ua.CallMethodResult{OutputArguments: []ua.Variant{}}
+		})
+	}
+}
+
+// open opens the trust list for reading the lists of the masks, or for writing. The lists are encoded
+// to the file when the first handle is opened for reading, so while the trust list is open for reading,
+// it may only be opened again with the same masks. Only one session may open the trust list for writing,
+// while no other handles are open.
+func (tl *trustList) open(session *Session, mode byte, masks uint32) (uint32, error) {
+	if mode != trustListModeRead && mode != trustListModeWrite {
+		return 0, ua.BadInvalidArgument
+	}
+	f := tl.file
+	f.Lock()
+	defer f.Unlock()
+	for _, h := range f.handles {
+		if h.mode == trustListModeWrite || mode == trustListModeWrite || masks != tl.masks {
+			return 0, ua.BadInvalidState
+		}
+	}
+	if mode == trustListModeRead && len(f.handles) == 0 {
+		data, err := tl.encode(masks)
+		if err != nil {
+			return 0, err
+		}
+		if err := tl.data.Truncate(0); err != nil {
+			return 0, ua.BadResourceUnavailable
+		}
+		if _, err := tl.data.Seek(0, io.SeekStart); err != nil {
+			return 0, ua.BadResourceUnavailable
+		}
+		if _, err := tl.data.Write(data); err != nil {
+			return 0, ua.BadResourceUnavailable
+		}
+		tl.masks = masks
+	}
+	return f.openLocked(session, mode)
+}
+
+// closeAndUpdate closes the handle, then replaces the lists that were written. The lists are replaced
+// while the trust list is locked, and are restored if any change to the store fails.
+func (tl *trustList) closeAndUpdate(session *Session, handle uint32) error {
+	f := tl.file
+	f.Lock()
+	defer f.Unlock()
+	h, err := f.handle(session, handle)
+	if err != nil {
+		return err
+	}
+	delete(f.handles, handle)
+	if h.mode != trustListModeWrite {
+		return ua.BadInvalidState
+	}
+	var value ua.TrustListDataType
+	if err := ua.NewBinaryDecoder(bytes.NewReader(tl.data.Bytes()), ua.NewEncodingContext()).Decode(&value); err != nil {
+		return ua.BadDecodingError
+	}
+	lists := [][]ua.ByteString{value.TrustedCertificates, value.TrustedCrls, value.IssuerCertificates, value.IssuerCrls}
+	for i, list := range lists {
+		if value.SpecifiedLists&uint32(trustListMasks[i]) == 0 {
+			continue
+		}
+		for _, b := range list {
+			if err := parseTrustListEntry(trustListMasks[i], []byte(b)); err != nil {
+				return ua.BadCertificateInvalid
+			}
+		}
+	}
+
+	// find the entries to add and to remove, before changing the store.
+	store := tl.server.certificateStore
+	type change struct {
+		list ua.TrustListMasks
+		der  []byte
+	}
+	var adds, removes []change
+	for i, list := range lists {
+		if value.SpecifiedLists&uint32(trustListMasks[i]) == 0 {
+			continue
+		}
+		old, err := store.List(trustListMasks[i])
+		if err != nil {
+			return ua.BadNotWritable
+		}
+		ders := make([][]byte, len(list))
+		for j, der := range list {
+			ders[j] = []byte(der)
+			if !containsDER(old, ders[j]) {
+				adds = append(adds, change{trustListMasks[i], ders[j]})
+			}
+		}
+		for _, der := range old {
+			if !containsDER(ders, der) {
+				removes = append(removes, change{trustListMasks[i], der})
+			}
+		}
+	}
+
+	// add the new entries first, then remove the old. If a change fails, undo the changes made.
+	var added, removed []change
+	rollback := func() {
+		for _, c := range removed {
+			store.Add(c.list, c.der)
+		}
+		for _, c := range added {
+			store.Remove(c.list, c.der)
+		}
+	}
+	for _, c := range adds {
+		if err := store.Add(c.list, c.der); err != nil {
+			rollback()
+			return statusCodeOf(err)
+		}
+		added = append(added, c)
+	}
+	for _, c := range removes {
+		if err := store.Remove(c.list, c.der); err != nil {
+			rollback()
+			return statusCodeOf(err)
+		}
+		removed = append(removed, c)
+	}
+	tl.lastUpdateTime = time.Now()
+	return nil
+}
+
+// addCertificate adds a certificate to the trusted certificates, or to the issuer certificates.
+// A certificate may not be added while the trust list is open for writing.
+func (tl *trustList) addCertificate(certificate []byte, isTrustedCertificate bool) error {
+	crt, err := x509.ParseCertificate(certificate)
+	if err != nil {
+		return ua.BadCertificateInvalid
+	}
+	if !isTrustedCertificate && !crt.IsCA {
+		return ua.BadCertificateInvalid
+	}
+	tl.file.Lock()
+	defer tl.file.Unlock()
+	for _, h := range tl.file.handles {
+		if h.mode == trustListModeWrite {
+			return ua.BadInvalidState
+		}
+	}
+	list := ua.TrustListMasksIssuerCertificates
+	if isTrustedCertificate {
+		list = ua.TrustListMasksTrustedCertificates
+	}
+	if err := tl.server.certificateStore.Add(list, crt.Raw); err != nil {
+		return statusCodeOf(err)
+	}
+	tl.lastUpdateTime = time.Now()
+	return nil
+}
+
+// removeCertificate removes the certificate with the thumbprint from the trusted certificates,
+// or from the issuer certificates. The CRLs issued by the certificate are removed too.
+func (tl *trustList) removeCertificate(thumbprint string, isTrustedCertificate bool) error {
+	tl.file.Lock()
+	defer tl.file.Unlock()
+	for _, h := range tl.file.handles {
+		if h.mode == trustListModeWrite {
+			return ua.BadInvalidState
+		}
+	}
+	store := tl.server.certificateStore
+	certs, crls := ua.TrustListMasksIssuerCertificates, ua.TrustListMasksIssuerCrls
+	if isTrustedCertificate {
+		certs, crls = ua.TrustListMasksTrustedCertificates, ua.TrustListMasksTrustedCrls
+	}
+	ders, err := store.List(certs)
+	if err != nil {
+		return ua.BadInvalidArgument
+	}
+	for _, der := range ders {
+		if !strings.EqualFold(fmt.Sprintf("%x", sha1.Sum(der)), thumbprint) {
+			continue
+		}
+		if err := store.Remove(certs, der); err != nil {
+			return statusCodeOf(err)
+		}
+		if crt, err := x509.ParseCertificate(der); err == nil && crt.IsCA {
+			if ders, err := store.List(crls); err == nil {
+				for _, der := range ders {
+					if crl, err := x509.ParseRevocationList(der); err == nil && bytes.Equal(crl.RawIssuer, crt.RawSubject) {
+						store.Remove(crls, der)
+					}
+				}
+			}
+		}
+		tl.lastUpdateTime = time.Now()
+		return nil
+	}
+	return ua.BadInvalidArgument
+}
+
+// writable returns true if the store of the trust list may be changed.
+func (tl *trustList) writable() bool {
+	if s, ok := tl.server.certificateStore.(interface{ Writable() bool }); ok {
+		return s.Writable()
+	}
+	return true
+}
+
+// encode returns the lists of the masks, encoded as a TrustListDataType.
+func (tl *trustList) encode(masks uint32) ([]byte, error) {
+	value := ua.TrustListDataType{
+		SpecifiedLists:      masks & uint32(ua.TrustListMasksAll),
+		TrustedCertificates: []ua.ByteString{},
+		TrustedCrls:         []ua.ByteString{},
+		IssuerCertificates:  []ua.ByteString{},
+		IssuerCrls:          []ua.ByteString{},
+	}
+	for i, list := range []*[]ua.ByteString{&value.TrustedCertificates, &value.TrustedCrls, &value.IssuerCertificates, &value.IssuerCrls} {
+		if value.SpecifiedLists&uint32(trustListMasks[i]) == 0 {
+			continue
+		}
+		ders, err := tl.server.certificateStore.List(trustListMasks[i])
+		if err != nil {
+			return nil, ua.BadResourceUnavailable
+		}
+		for _, der := range ders {
+			*list = append(*list, ua.ByteString(der))
+		}
+	}
+	var buf bytes.Buffer
+	if err := ua.NewBinaryEncoder(&buf, ua.NewEncodingContext()).Encode(&value); err != nil {
+		return nil, ua.BadEncodingError
+	}
+	return buf.Bytes(), nil
+}
+
+// containsDER returns true if the list contains the DER encoded data.
+func containsDER(list [][]byte, der []byte) bool {
+	for _, b := range list {
+		if bytes.Equal(b, der) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustListEntry returns an error if the DER encoded data is not a certificate, or a CRL for the lists of CRLs.
+func parseTrustListEntry(list ua.TrustListMasks, der []byte) error {
+	if list == ua.TrustListMasksTrustedCrls || list == ua.TrustListMasksIssuerCrls {
+		_, err := x509.ParseRevocationList(der)
+		return err
+	}
+	_, err := x509.ParseCertificate(der)
+	return err
+}
+
+// statusCodeOf returns the StatusCode of the error, or BadUnexpectedError.
+func statusCodeOf(err error) ua.StatusCode {
+	if code, ok := err.(ua.StatusCode); ok {
+		return code
+	}
+	return ua.BadUnexpectedError
+}
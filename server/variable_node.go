@@ -28,6 +28,7 @@ type VariableNode struct {
 	historian               HistoryReadWriter
 	readValueHandler        func(*Session, ua.ReadValueID) ua.DataValue
 	writeValueHandler       func(*Session, ua.WriteValue) (ua.DataValue, ua.StatusCode)
+	listeners               map[ValueListener]struct{}
 }
 
 var _ Node = (*VariableNode)(nil)
@@ -129,11 +130,40 @@ func (n *VariableNode) Value() ua.DataValue {
 // SetValue sets the value of the Variable.
 func (n *VariableNode) SetValue(value ua.DataValue) {
 	n.Lock()
-	defer n.Unlock()
 	n.value = value
 	if n.historizing {
 		n.historian.WriteValue(context.Background(), n.nodeId, value)
 	}
+	listeners := make([]ValueListener, 0, len(n.listeners))
+	for listener := range n.listeners {
+		listeners = append(listeners, listener)
+	}
+	n.Unlock()
+	for _, listener := range listeners {
+		listener.OnValueChanged(n, value)
+	}
+}
+
+// ValueListener is notified when the value of a node is set.
+type ValueListener interface {
+	OnValueChanged(*VariableNode, ua.DataValue)
+}
+
+// AddValueListener adds a listener that is notified when the value of this node is set.
+func (n *VariableNode) AddValueListener(listener ValueListener) {
+	n.Lock()
+	defer n.Unlock()
+	if n.listeners == nil {
+		n.listeners = map[ValueListener]struct{}{}
+	}
+	n.listeners[listener] = struct{}{}
+}
+
+// RemoveValueListener removes a listener that was added with AddValueListener.
+func (n *VariableNode) RemoveValueListener(listener ValueListener) {
+	n.Lock()
+	defer n.Unlock()
+	delete(n.listeners, listener)
 }
 
 // DataType returns the DataType attribute of this node.
@@ -167,7 +197,7 @@ func (n *VariableNode) UserAccessLevel(userIdentity any) byte {
 	if rolePermissions == nil {
 		rolePermissions = n.server.RolePermissions()
 	}
-	var currentRead, currentWrite, historyRead bool
+	var currentRead, currentWrite, historyRead, historyWrite bool
 	for _, role := range roles {
 		for _, rp := range rolePermissions {
 			if rp.RoleID == role {
@@ -180,6 +210,9 @@ func (n *VariableNode) UserAccessLevel(userIdentity any) byte {
 				if rp.Permissions&ua.PermissionTypeReadHistory != 0 {
 					historyRead = true
 				}
+				if rp.Permissions&(ua.PermissionTypeInsertHistory|ua.PermissionTypeModifyHistory|ua.PermissionTypeDeleteHistory) != 0 {
+					historyWrite = true
+				}
 			}
 		}
 	}
@@ -192,6 +225,9 @@ func (n *VariableNode) UserAccessLevel(userIdentity any) byte {
 	if !historyRead {
 		accessLevel &^= ua.AccessLevelsHistoryRead
 	}
+	if !historyWrite {
+		accessLevel &^= ua.AccessLevelsHistoryWrite
+	}
 	return accessLevel
 }
 
@@ -112,3 +112,12 @@ var AlarmConditionSelectClauses []SimpleAttributeOperand = []SimpleAttributeOper
 }
 
 var AlarmConditionSelectClauseActiveStateEffectiveDisplayName SimpleAttributeOperand = SimpleAttributeOperand{TypeDefinitionID: ObjectTypeIDAlarmConditionType, BrowsePath: ParseBrowsePath("ActiveState/EffectiveDisplayName"), AttributeID: AttributeIDValue}
+
+// AlarmConditionSelectClauseShelvingStateCurrentState selects the name of the current state of the ShelvedStateMachine.
+var AlarmConditionSelectClauseShelvingStateCurrentState SimpleAttributeOperand = SimpleAttributeOperand{TypeDefinitionID: ObjectTypeIDAlarmConditionType, BrowsePath: ParseBrowsePath("ShelvingState/CurrentState"), AttributeID: AttributeIDValue}
+
+// AlarmConditionSelectClauseShelvingStateCurrentStateID selects the NodeID of the current state of the ShelvedStateMachine.
+var AlarmConditionSelectClauseShelvingStateCurrentStateID SimpleAttributeOperand = SimpleAttributeOperand{TypeDefinitionID: ObjectTypeIDAlarmConditionType, BrowsePath: ParseBrowsePath("ShelvingState/CurrentState/Id"), AttributeID: AttributeIDValue}
+
+// AlarmConditionSelectClauseShelvingStateUnshelveTime selects the time remaining until the alarm is unshelved.
+var AlarmConditionSelectClauseShelvingStateUnshelveTime SimpleAttributeOperand = SimpleAttributeOperand{TypeDefinitionID: ObjectTypeIDAlarmConditionType, BrowsePath: ParseBrowsePath("ShelvingState/UnshelveTime"), AttributeID: AttributeIDValue}
@@ -3,6 +3,7 @@
 package ua
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -134,6 +135,12 @@ func getDecoder(typ reflect.Type) (decoderFunc, error) {
 }
 
 func getStructDecoder(typ reflect.Type) (decoderFunc, error) {
+	if isUnion(typ) {
+		return getUnionDecoder(typ)
+	}
+	if hasOptionalFields(typ) {
+		return getStructWithOptionalFieldsDecoder(typ)
+	}
 	decoders := []decoderFunc{}
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
@@ -155,18 +162,112 @@ func getStructDecoder(typ reflect.Type) (decoderFunc, error) {
 		return nil
 	}, nil
 }
-func getStructPtrDecoder(typ reflect.Type) (decoderFunc, error) {
+
+// getOptionalFieldDecoder returns a decoder that allocates the pointer field, then decodes the field.
+func getOptionalFieldDecoder(field reflect.StructField) (decoderFunc, error) {
+	elemDecoder, err := getDecoder(field.Type.Elem())
+	if err != nil {
+		return nil, err
+	}
+	typ := field.Type
+	offset := field.Offset
+	return func(buf *BinaryDecoder, p unsafe.Pointer) error {
+		v := reflect.New(typ.Elem())
+		reflect.NewAt(typ, unsafe.Pointer(uintptr(p)+offset)).Elem().Set(v)
+		return elemDecoder(buf, v.UnsafePointer())
+	}, nil
+}
+
+func getStructWithOptionalFieldsDecoder(typ reflect.Type) (decoderFunc, error) {
 	decoders := []decoderFunc{}
+	bits := []uint32{} // the bit of the encoding mask for each field, or zero if required.
+	offsets := []uintptr{}
+	var bit uint32 = 1
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
+		offset := field.Offset
+		if field.Type.Kind() == reflect.Ptr {
+			dec, err := getOptionalFieldDecoder(field)
+			if err != nil {
+				return nil, err
+			}
+			if bit == 0 {
+				return nil, fmt.Errorf("too many optional fields: %s", typ)
+			}
+			decoders = append(decoders, dec)
+			bits = append(bits, bit)
+			offsets = append(offsets, offset)
+			bit <<= 1
+			continue
+		}
 		dec, err := getDecoder(field.Type)
 		if err != nil {
 			return nil, err
 		}
-		offset := field.Offset
 		decoders = append(decoders, func(buf *BinaryDecoder, p unsafe.Pointer) error {
 			return dec(buf, unsafe.Pointer(uintptr(p)+offset))
 		})
+		bits = append(bits, 0)
+		offsets = append(offsets, offset)
+	}
+	return func(buf *BinaryDecoder, p unsafe.Pointer) error {
+		var mask uint32
+		if err := buf.ReadUInt32(&mask); err != nil {
+			return err
+		}
+		for i, dec := range decoders {
+			if bits[i] != 0 && mask&bits[i] == 0 {
+				*(*unsafe.Pointer)(unsafe.Pointer(uintptr(p) + offsets[i])) = nilPtr
+				continue
+			}
+			if err := dec(buf, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+func getUnionDecoder(typ reflect.Type) (decoderFunc, error) {
+	offsets := []uintptr{}
+	decoders := []decoderFunc{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type == typeUnion {
+			continue
+		}
+		if field.Type.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("unsupported union field: %s.%s", typ, field.Name)
+		}
+		dec, err := getOptionalFieldDecoder(field)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, field.Offset)
+		decoders = append(decoders, dec)
+	}
+	return func(buf *BinaryDecoder, p unsafe.Pointer) error {
+		var sw uint32
+		if err := buf.ReadUInt32(&sw); err != nil {
+			return err
+		}
+		if sw > uint32(len(decoders)) {
+			return BadDecodingError
+		}
+		for _, offset := range offsets {
+			*(*unsafe.Pointer)(unsafe.Pointer(uintptr(p) + offset)) = nilPtr
+		}
+		if sw == 0 {
+			return nil
+		}
+		return decoders[sw-1](buf, p)
+	}, nil
+}
+
+func getStructPtrDecoder(typ reflect.Type) (decoderFunc, error) {
+	dec, err := getStructDecoder(typ)
+	if err != nil {
+		return nil, err
 	}
 	return func(buf *BinaryDecoder, p unsafe.Pointer) error {
 		p2 := unsafe.Pointer(*(**struct{})(p))
@@ -175,12 +276,7 @@ func getStructPtrDecoder(typ reflect.Type) (decoderFunc, error) {
 			reflect.NewAt(v.Type(), p).Elem().Set(v)
 			p2 = unsafe.Pointer(*(**struct{})(p))
 		}
-		for _, dec := range decoders {
-			if err := dec(buf, p2); err != nil {
-				return err
-			}
-		}
-		return nil
+		return dec(buf, p2)
 	}, nil
 }
 
@@ -799,6 +895,13 @@ func (dec *BinaryDecoder) ReadExtensionObject(value *ExtensionObject) error {
 		if err != nil {
 			return BadDecodingError
 		}
+		// lookup structure definition
+		if st, ok := findStructureType(id); ok {
+			var s Structure
+			if err := NewBinaryDecoder(bytes.NewReader(body), dec.ec).readStructure(st, &s); err == nil {
+				*value = s
+			}
+		}
 		return nil
 	case 0x02:
 		var body XMLElement
@@ -31,6 +31,7 @@ var (
 	typeVariant         = reflect.TypeOf((*Variant)(nil)).Elem()
 	typeDiagnosticInfo  = reflect.TypeOf((*DiagnosticInfo)(nil)).Elem()
 	typeSliceOfByte     = reflect.TypeOf((*[]byte)(nil)).Elem()
+	typeUnion           = reflect.TypeOf((*Union)(nil)).Elem()
 	nilPtr              = unsafe.Pointer(nil)
 )
 
@@ -169,6 +170,12 @@ func getEncoder(typ reflect.Type) (encoderFunc, error) {
 }
 
 func getStructEncoder(typ reflect.Type) (encoderFunc, error) {
+	if isUnion(typ) {
+		return getUnionEncoder(typ)
+	}
+	if hasOptionalFields(typ) {
+		return getStructWithOptionalFieldsEncoder(typ)
+	}
 	encoders := []encoderFunc{}
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
@@ -191,23 +198,70 @@ func getStructEncoder(typ reflect.Type) (encoderFunc, error) {
 	}, nil
 }
 
-func getStructPtrEncoder(typ reflect.Type) (encoderFunc, error) {
+// isUnion returns true if the struct embeds the Union type. Each other field of
+// the struct must be a pointer, and at most one field may be set.
+func isUnion(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.Anonymous && field.Type == typeUnion {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOptionalFields returns true if the struct has a pointer field. Pointer fields
+// are optional, and are encoded only if not nil.
+func hasOptionalFields(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Type.Kind() == reflect.Ptr {
+			return true
+		}
+	}
+	return false
+}
+
+func getStructWithOptionalFieldsEncoder(typ reflect.Type) (encoderFunc, error) {
 	encoders := []encoderFunc{}
+	optionals := []uintptr{}
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
+		offset := field.Offset
+		if field.Type.Kind() == reflect.Ptr {
+			enc, err := getEncoder(field.Type.Elem())
+			if err != nil {
+				return nil, err
+			}
+			optionals = append(optionals, offset)
+			encoders = append(encoders, func(buf *BinaryEncoder, p unsafe.Pointer) error {
+				p2 := *(*unsafe.Pointer)(unsafe.Pointer(uintptr(p) + offset))
+				if p2 == nilPtr {
+					return nil
+				}
+				return enc(buf, p2)
+			})
+			continue
+		}
 		enc, err := getEncoder(field.Type)
 		if err != nil {
 			return nil, err
 		}
-		offset := field.Offset
 		encoders = append(encoders, func(buf *BinaryEncoder, p unsafe.Pointer) error {
 			return enc(buf, unsafe.Pointer(uintptr(p)+offset))
 		})
 	}
+	if len(optionals) > 32 {
+		return nil, fmt.Errorf("too many optional fields: %s", typ)
+	}
 	return func(buf *BinaryEncoder, p unsafe.Pointer) error {
-		p = unsafe.Pointer(*(**struct{})(p))
-		if p == nilPtr {
-			return BadEncodingError
+		// write the encoding mask, with a bit set for each optional field that is present.
+		var mask uint32
+		for i, offset := range optionals {
+			if *(*unsafe.Pointer)(unsafe.Pointer(uintptr(p) + offset)) != nilPtr {
+				mask |= 1 << i
+			}
+		}
+		if err := buf.WriteUInt32(mask); err != nil {
+			return err
 		}
 		for _, enc := range encoders {
 			if err := enc(buf, p); err != nil {
@@ -218,6 +272,52 @@ func getStructPtrEncoder(typ reflect.Type) (encoderFunc, error) {
 	}, nil
 }
 
+func getUnionEncoder(typ reflect.Type) (encoderFunc, error) {
+	offsets := []uintptr{}
+	encoders := []encoderFunc{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type == typeUnion {
+			continue
+		}
+		if field.Type.Kind() != reflect.Ptr {
+			return nil, fmt.Errorf("unsupported union field: %s.%s", typ, field.Name)
+		}
+		enc, err := getEncoder(field.Type.Elem())
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, field.Offset)
+		encoders = append(encoders, enc)
+	}
+	return func(buf *BinaryEncoder, p unsafe.Pointer) error {
+		// write the switch field, the position of the first field that is set, then the field.
+		for i, offset := range offsets {
+			if p2 := *(*unsafe.Pointer)(unsafe.Pointer(uintptr(p) + offset)); p2 != nilPtr {
+				if err := buf.WriteUInt32(uint32(i + 1)); err != nil {
+					return err
+				}
+				return encoders[i](buf, p2)
+			}
+		}
+		return buf.WriteUInt32(0)
+	}, nil
+}
+
+func getStructPtrEncoder(typ reflect.Type) (encoderFunc, error) {
+	enc, err := getStructEncoder(typ)
+	if err != nil {
+		return nil, err
+	}
+	return func(buf *BinaryEncoder, p unsafe.Pointer) error {
+		p = unsafe.Pointer(*(**struct{})(p))
+		if p == nilPtr {
+			return BadEncodingError
+		}
+		return enc(buf, p)
+	}, nil
+}
+
 func getSliceEncoder(typ reflect.Type) (encoderFunc, error) {
 	elemType := typ.Elem()
 	elemSize := elemType.Size()
@@ -774,6 +874,9 @@ func (enc *BinaryEncoder) WriteExtensionObject(value ExtensionObject) error {
 		}
 		return nil
 	}
+	if s, ok := value.(Structure); ok {
+		return enc.writeStructureExtensionObject(s)
+	}
 	// lookup encoding id
 	typ := reflect.TypeOf(value)
 	if typ.Kind() == reflect.Ptr {
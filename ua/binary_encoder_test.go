@@ -4,6 +4,7 @@ package ua_test
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 	"time"
 
@@ -736,3 +737,267 @@ func TestMatrix(t *testing.T) {
 		assert.DeepEqual(t, out, c.in)
 	}
 }
+
+type testEncodingContext []string
+
+func (ec testEncodingContext) NamespaceURIs() []string { return ec }
+
+func TestStructure(t *testing.T) {
+	const nsu = "http://github.com/awcullen/opcua/ua/structure/"
+	ec := testEncodingContext{"http://opcfoundation.org/UA/", nsu}
+	ua.RegisterStructureDefinition(ua.NewNodeIDNumeric(1, 1), ua.StructureDefinition{
+		DefaultEncodingID: ua.NewNodeIDNumeric(1, 2),
+		BaseDataType:      ua.DataTypeIDStructure,
+		StructureType:     ua.StructureTypeStructure,
+		Fields: []ua.StructureField{
+			{Name: "X", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+			{Name: "Y", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+		},
+	}, ec)
+	ua.RegisterEnumDefinition(ua.NewNodeIDNumeric(1, 5), ua.EnumDefinition{}, ec)
+	ua.RegisterStructureDefinition(ua.NewNodeIDNumeric(1, 3), ua.StructureDefinition{
+		DefaultEncodingID: ua.NewNodeIDNumeric(1, 4),
+		BaseDataType:      ua.DataTypeIDStructure,
+		StructureType:     ua.StructureTypeStructureWithOptionalFields,
+		Fields: []ua.StructureField{
+			{Name: "Name", DataType: ua.DataTypeIDString, ValueRank: ua.ValueRankScalar},
+			{Name: "Center", DataType: ua.NewNodeIDNumeric(1, 1), ValueRank: ua.ValueRankScalar, IsOptional: true},
+			{Name: "Tags", DataType: ua.DataTypeIDString, ValueRank: ua.ValueRankOneDimension, IsOptional: true},
+			{Name: "Kind", DataType: ua.NewNodeIDNumeric(1, 5), ValueRank: ua.ValueRankScalar},
+		},
+	}, ec)
+	ua.RegisterStructureDefinition(ua.NewNodeIDNumeric(1, 6), ua.StructureDefinition{
+		DefaultEncodingID: ua.NewNodeIDNumeric(1, 7),
+		BaseDataType:      ua.DataTypeIDUnion,
+		StructureType:     ua.StructureTypeUnion,
+		Fields: []ua.StructureField{
+			{Name: "Number", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+			{Name: "Text", DataType: ua.DataTypeIDString, ValueRank: ua.ValueRankScalar},
+		},
+	}, ec)
+
+	point := ua.Structure{
+		TypeID: ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 1), NamespaceURI: nsu},
+		Fields: []ua.StructureFieldValue{{"X", 1.0}, {"Y", 2.0}},
+	}
+	cases := []struct {
+		in    ua.Structure
+		bytes []byte
+	}{
+		{
+			point,
+			[]byte{
+				0x01, 0x01, 0x02, 0x00, 0x01, // encoding id
+				0x10, 0x00, 0x00, 0x00, // len
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+			},
+		},
+		{
+			ua.Structure{
+				TypeID: ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 3), NamespaceURI: nsu},
+				Fields: []ua.StructureFieldValue{{"Name", "a"}, {"Center", nil}, {"Tags", []string{"x"}}, {"Kind", int32(2)}},
+			},
+			[]byte{
+				0x01, 0x01, 0x04, 0x00, 0x01, // encoding id
+				0x16, 0x00, 0x00, 0x00, // len
+				0x02, 0x00, 0x00, 0x00, // mask
+				0x01, 0x00, 0x00, 0x00, 0x61,
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x78,
+				0x02, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			ua.Structure{
+				TypeID: ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 3), NamespaceURI: nsu},
+				Fields: []ua.StructureFieldValue{{"Name", "a"}, {"Center", point}, {"Tags", nil}, {"Kind", int32(0)}},
+			},
+			[]byte{
+				0x01, 0x01, 0x04, 0x00, 0x01, // encoding id
+				0x1d, 0x00, 0x00, 0x00, // len
+				0x01, 0x00, 0x00, 0x00, // mask
+				0x01, 0x00, 0x00, 0x00, 0x61,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			ua.Structure{
+				TypeID: ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 6), NamespaceURI: nsu},
+				Fields: []ua.StructureFieldValue{{"Number", nil}, {"Text", "hi"}},
+			},
+			[]byte{
+				0x01, 0x01, 0x07, 0x00, 0x01, // encoding id
+				0x0a, 0x00, 0x00, 0x00, // len
+				0x02, 0x00, 0x00, 0x00, // switch
+				0x02, 0x00, 0x00, 0x00, 0x68, 0x69,
+			},
+		},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		enc := ua.NewBinaryEncoder(buf, ec)
+		if err := enc.WriteExtensionObject(c.in); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, buf.Bytes(), c.bytes)
+
+		dec := ua.NewBinaryDecoder(buf, ec)
+		var out ua.ExtensionObject
+		if err := dec.ReadExtensionObject(&out); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, out, ua.ExtensionObject(c.in))
+	}
+}
+
+type testOptionalStruct struct {
+	A int32
+	B *float64
+	C *string
+}
+
+type testUnion struct {
+	ua.Union
+	A *int32
+	B *string
+}
+
+func TestStructWithOptionalFields(t *testing.T) {
+	b, c := 2.0, "x"
+	cases := []struct {
+		in    testOptionalStruct
+		bytes []byte
+	}{
+		{
+			testOptionalStruct{A: 1, B: &b},
+			[]byte{
+				0x01, 0x00, 0x00, 0x00, // mask
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+			},
+		},
+		{
+			testOptionalStruct{A: 1, C: &c},
+			[]byte{
+				0x02, 0x00, 0x00, 0x00, // mask
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x78,
+			},
+		},
+		{
+			testOptionalStruct{A: 1},
+			[]byte{
+				0x00, 0x00, 0x00, 0x00, // mask
+				0x01, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		enc := ua.NewBinaryEncoder(buf, ua.NewEncodingContext())
+		if err := enc.Encode(c.in); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, buf.Bytes(), c.bytes)
+
+		dec := ua.NewBinaryDecoder(buf, ua.NewEncodingContext())
+		out := testOptionalStruct{B: new(float64), C: new(string)}
+		if err := dec.Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, out, c.in)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	a, b := int32(7), "hi"
+	cases := []struct {
+		in    testUnion
+		bytes []byte
+	}{
+		{
+			testUnion{A: &a},
+			[]byte{
+				0x01, 0x00, 0x00, 0x00, // switch
+				0x07, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			testUnion{B: &b},
+			[]byte{
+				0x02, 0x00, 0x00, 0x00, // switch
+				0x02, 0x00, 0x00, 0x00, 0x68, 0x69,
+			},
+		},
+		{
+			testUnion{},
+			[]byte{
+				0x00, 0x00, 0x00, 0x00, // switch
+			},
+		},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		enc := ua.NewBinaryEncoder(buf, ua.NewEncodingContext())
+		if err := enc.Encode(c.in); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, buf.Bytes(), c.bytes)
+
+		dec := ua.NewBinaryDecoder(buf, ua.NewEncodingContext())
+		out := testUnion{A: new(int32), B: new(string)}
+		if err := dec.Decode(&out); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, out, c.in)
+	}
+}
+
+func TestExtensionObjectWithOptionalFields(t *testing.T) {
+	const nsu = "http://github.com/awcullen/opcua/ua/optional/"
+	ec := testEncodingContext{"http://opcfoundation.org/UA/", nsu}
+	ua.RegisterBinaryEncodingID(reflect.TypeOf(testOptionalStruct{}), ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 1), NamespaceURI: nsu})
+	ua.RegisterBinaryEncodingID(reflect.TypeOf(testUnion{}), ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 2), NamespaceURI: nsu})
+	b, s := 2.0, "hi"
+	cases := []struct {
+		in    ua.ExtensionObject
+		bytes []byte
+	}{
+		{
+			testOptionalStruct{A: 1, B: &b},
+			[]byte{
+				0x01, 0x01, 0x01, 0x00, 0x01, // encoding id
+				0x10, 0x00, 0x00, 0x00, // len
+				0x01, 0x00, 0x00, 0x00, // mask
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40,
+			},
+		},
+		{
+			testUnion{B: &s},
+			[]byte{
+				0x01, 0x01, 0x02, 0x00, 0x01, // encoding id
+				0x0a, 0x00, 0x00, 0x00, // len
+				0x02, 0x00, 0x00, 0x00, // switch
+				0x02, 0x00, 0x00, 0x00, 0x68, 0x69,
+			},
+		},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		enc := ua.NewBinaryEncoder(buf, ec)
+		if err := enc.WriteExtensionObject(c.in); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, buf.Bytes(), c.bytes)
+
+		dec := ua.NewBinaryDecoder(buf, ec)
+		var out ua.ExtensionObject
+		if err := dec.ReadExtensionObject(&out); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, out, c.in)
+	}
+}
@@ -4,6 +4,7 @@ package ua
 
 import (
 	"encoding/base64"
+	"strings"
 )
 
 // ByteString is stored as a string.
@@ -14,6 +15,17 @@ func (b ByteString) String() string {
 	return base64.StdEncoding.EncodeToString([]byte(b))
 }
 
+// MarshalText returns ByteString as a base64-encoded string.
 func (b ByteString) MarshalText() ([]byte, error) {
 	return []byte(b.String()), nil
 }
+
+// UnmarshalText sets ByteString from a base64-encoded string. Whitespace in the string is ignored.
+func (b *ByteString) UnmarshalText(text []byte) error {
+	buf, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(text)), ""))
+	if err != nil {
+		return err
+	}
+	*b = ByteString(buf)
+	return nil
+}
@@ -10,13 +10,55 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
-// ValidateCertificate validates the leaf certificate.
+// ValidateCertificate validates the leaf certificate, with the certificates and revocation lists found in the paths.
+// Path may be to a file, comma-separated list of files, or directory.
 func ValidateCertificate(certificates []*x509.Certificate, keyUsages []x509.ExtKeyUsage, hostname, trustedPath, trustedCRLPath, issuersPath, issuersCRLPath, rejectedCertsPath string,
+	suppressCertificateHostNameInvalid, suppressCertificateTimeInvalid, suppressCertificateChainIncomplete, suppressCertificateRevocationUnknown bool) error {
+	store := NewDirectoryCertificateStore(trustedPath, trustedCRLPath, issuersPath, issuersCRLPath, rejectedCertsPath)
+	return NewCertificateValidator(store).Validate(certificates, keyUsages, hostname,
+		suppressCertificateHostNameInvalid, suppressCertificateTimeInvalid, suppressCertificateChainIncomplete, suppressCertificateRevocationUnknown)
+}
+
+// the maximum number of chains that a CertificateValidator caches.
+const maxCachedChains = 1024
+
+// CertificateValidator validates certificates with the lists of a CertificateStore.
+// The parsed lists and the chains that are built are cached, until the version of the store changes.
+type CertificateValidator struct {
+	sync.Mutex
+	store         CertificateStore
+	loaded        bool
+	version       uint64
+	trusted       []*x509.Certificate
+	roots         *x509.CertPool
+	intermediates *x509.CertPool
+	crls          []*x509.RevocationList
+	chains        map[string][][]*x509.Certificate
+	generation    uint64
+}
+
+// NewCertificateValidator instantiates a new CertificateValidator.
+func NewCertificateValidator(store CertificateStore) *CertificateValidator {
+	return &CertificateValidator{
+		store:  store,
+		chains: make(map[string][][]*x509.Certificate),
+	}
+}
+
+// Store returns the CertificateStore of the validator.
+func (v *CertificateValidator) Store() CertificateStore {
+	return v.store
+}
+
+// Validate validates the leaf certificate, that is the first of the certificates. The rest of the certificates
+// may be the issuers of the leaf certificate. A certificate that fails validation is rejected to the store.
+func (v *CertificateValidator) Validate(certificates []*x509.Certificate, keyUsages []x509.ExtKeyUsage, hostname string,
 	suppressCertificateHostNameInvalid, suppressCertificateTimeInvalid, suppressCertificateChainIncomplete, suppressCertificateRevocationUnknown bool) error {
 	if len(certificates) == 0 {
 		return BadCertificateInvalid
@@ -27,10 +69,12 @@ func ValidateCertificate(certificates []*x509.Certificate, keyUsages []x509.ExtK
 	certificate := certificates[0]
 	certificates = certificates[1:]
 
-	intermediates := x509.NewCertPool()
-	roots := x509.NewCertPool()
-	trusted := []*x509.Certificate{}
-	crls := []*x509.RevocationList{}
+	trusted, roots, intermediates, crls, generation := v.load()
+	if len(certificates) > 0 || suppressCertificateChainIncomplete {
+		// the cached pools are shared, so add to a copy.
+		roots = roots.Clone()
+		intermediates = intermediates.Clone()
+	}
 
 	for _, crt := range certificates {
 		if isSelfSigned(crt) {
@@ -40,35 +84,6 @@ func ValidateCertificate(certificates []*x509.Certificate, keyUsages []x509.ExtK
 		}
 	}
 
-	if crts, err := readCertificates(issuersPath); err == nil {
-		for _, crt := range crts {
-			if isSelfSigned(crt) {
-				roots.AddCert(crt)
-			} else {
-				intermediates.AddCert(crt)
-			}
-		}
-	}
-
-	if lists, err := readRevocationLists(issuersCRLPath); err == nil {
-		crls = append(crls, lists...)
-	}
-
-	if crts, err := readCertificates(trustedPath); err == nil {
-		trusted = crts
-		for _, crt := range crts {
-			if isSelfSigned(crt) {
-				roots.AddCert(crt)
-			} else {
-				intermediates.AddCert(crt)
-			}
-		}
-	}
-
-	if lists, err := readRevocationLists(trustedCRLPath); err == nil {
-		crls = append(crls, lists...)
-	}
-
 	opts := x509.VerifyOptions{
 		Roots:         roots,
 		Intermediates: intermediates,
@@ -85,15 +100,20 @@ func ValidateCertificate(certificates []*x509.Certificate, keyUsages []x509.ExtK
 	}
 
 	if suppressCertificateChainIncomplete {
-		if opts.Roots == nil {
-			opts.Roots = x509.NewCertPool()
-		}
 		opts.Roots.AddCert(certificate)
-		trusted = append(trusted, certificate)
+		trusted = append(trusted[:len(trusted):len(trusted)], certificate)
 	}
 
-	// build chain
-	chains, err := certificate.Verify(opts)
+	// build chain, or reuse the chains built before.
+	key := chainKey(certificate, certificates, opts, suppressCertificateChainIncomplete)
+	chains, ok := v.cachedChains(key, generation, opts.CurrentTime)
+	var err error
+	if !ok {
+		chains, err = certificate.Verify(opts)
+		if err == nil {
+			v.cacheChains(key, generation, chains)
+		}
+	}
 	switch se := err.(type) {
 	case x509.CertificateInvalidError:
 		switch se.Reason {
@@ -214,20 +234,133 @@ func ValidateCertificate(certificates []*x509.Certificate, keyUsages []x509.ExtK
 	}
 	if err != nil {
 		// log.Printf("Error verifying remote certificate. %s\n", err)
-		if len(rejectedCertsPath) > 0 {
-			os.MkdirAll(rejectedCertsPath, os.ModeDir|0755)
-			block := &pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}
-			thumbprint := sha1.Sum(certificate.Raw)
-			if f, err := os.Create(filepath.Join(rejectedCertsPath, fmt.Sprintf("%x.crt", thumbprint))); err == nil {
-				pem.Encode(f, block)
-				defer f.Close()
-			}
-		}
+		v.store.Reject(certificate.Raw)
 		return err
 	}
 	return nil
 }
 
+// load returns the trusted certificates, the pools of the trusted and issuer certificates, the CRLs, and
+// the generation of the lists. The lists are parsed again when the version of the store changes.
+func (v *CertificateValidator) load() ([]*x509.Certificate, *x509.CertPool, *x509.CertPool, []*x509.RevocationList, uint64) {
+	v.Lock()
+	defer v.Unlock()
+	version, err := v.store.Version()
+	if v.loaded && err == nil && version == v.version {
+		return v.trusted, v.roots, v.intermediates, v.crls, v.generation
+	}
+	v.generation++
+	v.roots = x509.NewCertPool()
+	v.intermediates = x509.NewCertPool()
+	v.crls = []*x509.RevocationList{}
+	v.chains = make(map[string][][]*x509.Certificate)
+
+	for _, crt := range parseCertificates(v.store.List(TrustListMasksIssuerCertificates)) {
+		if isSelfSigned(crt) {
+			v.roots.AddCert(crt)
+		} else {
+			v.intermediates.AddCert(crt)
+		}
+	}
+
+	v.crls = append(v.crls, parseRevocationLists(v.store.List(TrustListMasksIssuerCrls))...)
+
+	v.trusted = parseCertificates(v.store.List(TrustListMasksTrustedCertificates))
+	for _, crt := range v.trusted {
+		if isSelfSigned(crt) {
+			v.roots.AddCert(crt)
+		} else {
+			v.intermediates.AddCert(crt)
+		}
+	}
+
+	v.crls = append(v.crls, parseRevocationLists(v.store.List(TrustListMasksTrustedCrls))...)
+
+	// if the store has no version, the lists are parsed every time.
+	v.loaded = err == nil
+	v.version = version
+	return v.trusted, v.roots, v.intermediates, v.crls, v.generation
+}
+
+// cachedChains returns the chains built before for the key, if the lists are of the generation, and every
+// certificate of the chains is valid at the time.
+func (v *CertificateValidator) cachedChains(key string, generation uint64, t time.Time) ([][]*x509.Certificate, bool) {
+	v.Lock()
+	defer v.Unlock()
+	chains, ok := v.chains[key]
+	if !ok || generation != v.generation {
+		return nil, false
+	}
+	if t.IsZero() {
+		t = time.Now()
+	}
+	for _, chain := range chains {
+		for _, c := range chain {
+			if t.Before(c.NotBefore) || t.After(c.NotAfter) {
+				delete(v.chains, key)
+				return nil, false
+			}
+		}
+	}
+	return chains, true
+}
+
+// cacheChains stores the chains built for the key. The chains are not stored if the lists were
+// loaded again, since the chains were built with the lists of the generation.
+func (v *CertificateValidator) cacheChains(key string, generation uint64, chains [][]*x509.Certificate) {
+	v.Lock()
+	defer v.Unlock()
+	if generation != v.generation {
+		return
+	}
+	if len(v.chains) >= maxCachedChains {
+		v.chains = make(map[string][][]*x509.Certificate)
+	}
+	v.chains[key] = chains
+}
+
+// chainKey returns a key of the certificates and the options used to build the chains.
+func chainKey(certificate *x509.Certificate, certificates []*x509.Certificate, opts x509.VerifyOptions, suppressCertificateChainIncomplete bool) string {
+	h := sha1.New()
+	h.Write(certificate.Raw)
+	for _, crt := range certificates {
+		h.Write(crt.Raw)
+	}
+	for _, ku := range opts.KeyUsages {
+		fmt.Fprintf(h, "|%d", ku)
+	}
+	fmt.Fprintf(h, "|%s|%d|%t", opts.DNSName, opts.CurrentTime.UnixNano(), suppressCertificateChainIncomplete)
+	return string(h.Sum(nil))
+}
+
+// parseCertificates returns the certificates that parse without error.
+func parseCertificates(ders [][]byte, err error) []*x509.Certificate {
+	list := []*x509.Certificate{}
+	if err != nil {
+		return list
+	}
+	for _, der := range ders {
+		if crt, err := x509.ParseCertificate(der); err == nil {
+			list = append(list, crt)
+		}
+	}
+	return list
+}
+
+// parseRevocationLists returns the revocation lists that parse without error.
+func parseRevocationLists(ders [][]byte, err error) []*x509.RevocationList {
+	list := []*x509.RevocationList{}
+	if err != nil {
+		return list
+	}
+	for _, der := range ders {
+		if crl, err := x509.ParseRevocationList(der); err == nil {
+			list = append(list, crl)
+		}
+	}
+	return list
+}
+
 // readCertificates reads certificates from path.
 // Path may be to a file, comma-separated list of files, or directory.
 func readCertificates(path string) ([]*x509.Certificate, error) {
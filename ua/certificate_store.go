@@ -0,0 +1,381 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// directoryVersionInterval is the time that the Version of a DirectoryCertificateStore is reused,
+// before the files are checked again.
+const directoryVersionInterval = time.Second
+
+// CertificateStore stores the certificates and certificate revocation lists (CRLs) used to validate the
+// certificates of remote applications, and stores the certificates that are rejected.
+// The lists are selected by a single TrustListMasks value, e.g. TrustListMasksTrustedCertificates.
+// The certificates and CRLs are DER encoded.
+type CertificateStore interface {
+	// List returns the certificates or CRLs of the list.
+	List(list TrustListMasks) ([][]byte, error)
+	// Add adds the certificate or CRL to the list.
+	Add(list TrustListMasks, der []byte) error
+	// Remove removes the certificate or CRL from the list. Returns BadNotFound if not in the list.
+	Remove(list TrustListMasks, der []byte) error
+	// Reject stores a certificate that failed validation.
+	Reject(der []byte) error
+	// Rejected returns the certificates that failed validation.
+	Rejected() ([][]byte, error)
+	// Version returns a value that changes whenever the trusted or issuer lists change.
+	Version() (uint64, error)
+}
+
+// DirectoryCertificateStore is a CertificateStore that keeps the lists in files of PEM encoded data.
+// Each path may be to a file, comma-separated list of files, or directory. Only the lists with a path
+// to a directory may be changed. Added certificates and CRLs are stored in files named by their thumbprint.
+type DirectoryCertificateStore struct {
+	trustedCertsPath  string
+	trustedCRLsPath   string
+	issuerCertsPath   string
+	issuerCRLsPath    string
+	rejectedCertsPath string
+	versionLock       sync.Mutex
+	version           uint64
+	versionTime       time.Time
+}
+
+// NewDirectoryCertificateStore instantiates a new DirectoryCertificateStore.
+func NewDirectoryCertificateStore(trustedCertsPath, trustedCRLsPath, issuerCertsPath, issuerCRLsPath, rejectedCertsPath string) *DirectoryCertificateStore {
+	return &DirectoryCertificateStore{
+		trustedCertsPath:  trustedCertsPath,
+		trustedCRLsPath:   trustedCRLsPath,
+		issuerCertsPath:   issuerCertsPath,
+		issuerCRLsPath:    issuerCRLsPath,
+		rejectedCertsPath: rejectedCertsPath,
+	}
+}
+
+// List returns the certificates or CRLs of the list.
+func (s *DirectoryCertificateStore) List(list TrustListMasks) ([][]byte, error) {
+	path, isCRL, err := s.path(list)
+	if err != nil {
+		return nil, err
+	}
+	return readDERs(path, isCRL)
+}
+
+// Add adds the certificate or CRL to the list.
+func (s *DirectoryCertificateStore) Add(list TrustListMasks, der []byte) error {
+	path, isCRL, err := s.path(list)
+	if err != nil {
+		return err
+	}
+	defer s.resetVersion()
+	if isCRL {
+		return writePEMFile(path, "X509 CRL", ".crl", der)
+	}
+	return writePEMFile(path, "CERTIFICATE", ".crt", der)
+}
+
+// Remove removes the certificate or CRL from the list. Returns BadNotFound if not in the list.
+func (s *DirectoryCertificateStore) Remove(list TrustListMasks, der []byte) error {
+	path, isCRL, err := s.path(list)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return BadNotFound
+	}
+	if fi, err := os.Stat(path); err != nil || !fi.IsDir() {
+		if os.IsNotExist(err) {
+			return BadNotFound
+		}
+		return BadNotWritable
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return BadNotWritable
+	}
+	defer s.resetVersion()
+	found := false
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		file := filepath.Join(path, entry.Name())
+		ders, err := readDERs(file, isCRL)
+		if err != nil || len(ders) != 1 || !bytes.Equal(ders[0], der) {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return BadNotWritable
+		}
+		found = true
+	}
+	if !found {
+		return BadNotFound
+	}
+	return nil
+}
+
+// Reject stores a certificate that failed validation. The certificate is discarded if the path of
+// the rejected certificates is empty.
+func (s *DirectoryCertificateStore) Reject(der []byte) error {
+	if s.rejectedCertsPath == "" {
+		return nil
+	}
+	return writePEMFile(s.rejectedCertsPath, "CERTIFICATE", ".crt", der)
+}
+
+// Rejected returns the certificates that failed validation.
+func (s *DirectoryCertificateStore) Rejected() ([][]byte, error) {
+	return readDERs(s.rejectedCertsPath, false)
+}
+
+// Version returns a hash of the names, sizes and modification times of the files of the trusted and issuer lists.
+// The files are checked at most once per second, so a change to the files may be seen a second later.
+// A change made with Add or Remove is seen at once.
+func (s *DirectoryCertificateStore) Version() (uint64, error) {
+	s.versionLock.Lock()
+	defer s.versionLock.Unlock()
+	if !s.versionTime.IsZero() && time.Since(s.versionTime) < directoryVersionInterval {
+		return s.version, nil
+	}
+	h := fnv.New64a()
+	for _, path := range []string{s.trustedCertsPath, s.trustedCRLsPath, s.issuerCertsPath, s.issuerCRLsPath} {
+		for _, file := range listFiles(path) {
+			fi, err := os.Stat(file)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return 0, err
+			}
+			h.Write([]byte(file))
+			binary.Write(h, binary.LittleEndian, fi.Size())
+			binary.Write(h, binary.LittleEndian, fi.ModTime().UnixNano())
+		}
+		h.Write([]byte{0})
+	}
+	s.version, s.versionTime = h.Sum64(), time.Now()
+	return s.version, nil
+}
+
+// resetVersion checks the files again at the next call of Version.
+func (s *DirectoryCertificateStore) resetVersion() {
+	s.versionLock.Lock()
+	defer s.versionLock.Unlock()
+	s.versionTime = time.Time{}
+}
+
+// Writable returns true if the path of each of the trusted and issuer lists is to a directory.
+func (s *DirectoryCertificateStore) Writable() bool {
+	for _, path := range []string{s.trustedCertsPath, s.trustedCRLsPath, s.issuerCertsPath, s.issuerCRLsPath} {
+		if path == "" {
+			return false
+		}
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// path returns the path of the list, and true if the list is of CRLs.
+func (s *DirectoryCertificateStore) path(list TrustListMasks) (string, bool, error) {
+	switch list {
+	case TrustListMasksTrustedCertificates:
+		return s.trustedCertsPath, false, nil
+	case TrustListMasksTrustedCrls:
+		return s.trustedCRLsPath, true, nil
+	case TrustListMasksIssuerCertificates:
+		return s.issuerCertsPath, false, nil
+	case TrustListMasksIssuerCrls:
+		return s.issuerCRLsPath, true, nil
+	default:
+		return "", false, BadInvalidArgument
+	}
+}
+
+// readDERs reads the DER encoded certificates or CRLs from path. A path that is empty or not found has none.
+func readDERs(path string, isCRL bool) ([][]byte, error) {
+	ders := [][]byte{}
+	if path == "" {
+		return ders, nil
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) && !strings.Contains(path, ",") {
+		return ders, nil
+	}
+	if isCRL {
+		crls, err := readRevocationLists(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, crl := range crls {
+			ders = append(ders, crl.Raw)
+		}
+		return ders, nil
+	}
+	crts, err := readCertificates(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, crt := range crts {
+		ders = append(ders, crt.Raw)
+	}
+	return ders, nil
+}
+
+// listFiles returns the files of the path. Path may be to a file, comma-separated list of files, or directory.
+func listFiles(path string) []string {
+	if path == "" {
+		return nil
+	}
+	if isDir(path) {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil
+		}
+		files := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+		return files
+	}
+	files := strings.Split(path, ",")
+	for i := range files {
+		files[i] = strings.TrimSpace(files[i])
+	}
+	return files
+}
+
+// isDir returns true if the path is to a directory.
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+// writePEMFile stores the DER encoded data in the directory, in a file named by its thumbprint.
+// The directory is created if not found.
+func writePEMFile(path, blockType, ext string, der []byte) error {
+	if path == "" {
+		return BadNotWritable
+	}
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+		return BadNotWritable
+	}
+	if err := os.MkdirAll(path, os.ModeDir|0755); err != nil {
+		return BadNotWritable
+	}
+	file := filepath.Join(path, fmt.Sprintf("%x%s", sha1.Sum(der), ext))
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0644); err != nil {
+		return BadNotWritable
+	}
+	return nil
+}
+
+// MemoryCertificateStore is a CertificateStore that keeps the lists in memory.
+// Use it to load certificates from a database or secret store, or for testing.
+type MemoryCertificateStore struct {
+	sync.RWMutex
+	lists    map[TrustListMasks][][]byte
+	rejected [][]byte
+	version  uint64
+}
+
+// NewMemoryCertificateStore instantiates a new, empty MemoryCertificateStore.
+func NewMemoryCertificateStore() *MemoryCertificateStore {
+	return &MemoryCertificateStore{
+		lists: make(map[TrustListMasks][][]byte),
+	}
+}
+
+// List returns the certificates or CRLs of the list.
+func (s *MemoryCertificateStore) List(list TrustListMasks) ([][]byte, error) {
+	if !isSingleList(list) {
+		return nil, BadInvalidArgument
+	}
+	s.RLock()
+	defer s.RUnlock()
+	return append([][]byte{}, s.lists[list]...), nil
+}
+
+// Add adds the certificate or CRL to the list.
+func (s *MemoryCertificateStore) Add(list TrustListMasks, der []byte) error {
+	if !isSingleList(list) {
+		return BadInvalidArgument
+	}
+	s.Lock()
+	defer s.Unlock()
+	for _, b := range s.lists[list] {
+		if bytes.Equal(b, der) {
+			return nil
+		}
+	}
+	s.lists[list] = append(s.lists[list], bytes.Clone(der))
+	s.version++
+	return nil
+}
+
+// Remove removes the certificate or CRL from the list. Returns BadNotFound if not in the list.
+func (s *MemoryCertificateStore) Remove(list TrustListMasks, der []byte) error {
+	if !isSingleList(list) {
+		return BadInvalidArgument
+	}
+	s.Lock()
+	defer s.Unlock()
+	for i, b := range s.lists[list] {
+		if bytes.Equal(b, der) {
+			s.lists[list] = append(s.lists[list][:i:i], s.lists[list][i+1:]...)
+			s.version++
+			return nil
+		}
+	}
+	return BadNotFound
+}
+
+// Reject stores a certificate that failed validation.
+func (s *MemoryCertificateStore) Reject(der []byte) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, b := range s.rejected {
+		if bytes.Equal(b, der) {
+			return nil
+		}
+	}
+	s.rejected = append(s.rejected, bytes.Clone(der))
+	return nil
+}
+
+// Rejected returns the certificates that failed validation.
+func (s *MemoryCertificateStore) Rejected() ([][]byte, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return append([][]byte{}, s.rejected...), nil
+}
+
+// Version returns a counter that is incremented whenever the lists change.
+func (s *MemoryCertificateStore) Version() (uint64, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.version, nil
+}
+
+// isSingleList returns true if the mask selects exactly one list.
+func isSingleList(list TrustListMasks) bool {
+	switch list {
+	case TrustListMasksTrustedCertificates, TrustListMasksTrustedCrls, TrustListMasksIssuerCertificates, TrustListMasksIssuerCrls:
+		return true
+	default:
+		return false
+	}
+}
@@ -6,4 +6,11 @@ package ua
 // Register the struct type and id with the BinaryEncoder using
 //
 //	func RegisterBinaryEncodingID(typ reflect.Type, id ExpandedNodeID)
+//
+// Pointer fields of the struct are optional, and encoded as a StructureWithOptionalFields.
+// A struct that embeds Union is encoded as a union, with each field a pointer and at most one not nil.
+//
+// Or store a Structure, and register the DataTypeDefinition using
+//
+//	func RegisterStructureDefinition(dataTypeID NodeID, definition StructureDefinition, namespaceURIs []string)
 type ExtensionObject any
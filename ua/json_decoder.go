@@ -0,0 +1,698 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// variantGoTypes maps the builtin type of a Variant to the Go type of a scalar value.
+var variantGoTypes = [...]reflect.Type{
+	VariantTypeBoolean:         reflect.TypeOf(false),
+	VariantTypeSByte:           reflect.TypeOf(int8(0)),
+	VariantTypeByte:            reflect.TypeOf(uint8(0)),
+	VariantTypeInt16:           reflect.TypeOf(int16(0)),
+	VariantTypeUInt16:          reflect.TypeOf(uint16(0)),
+	VariantTypeInt32:           reflect.TypeOf(int32(0)),
+	VariantTypeUInt32:          reflect.TypeOf(uint32(0)),
+	VariantTypeInt64:           reflect.TypeOf(int64(0)),
+	VariantTypeUInt64:          reflect.TypeOf(uint64(0)),
+	VariantTypeFloat:           reflect.TypeOf(float32(0)),
+	VariantTypeDouble:          reflect.TypeOf(float64(0)),
+	VariantTypeString:          reflect.TypeOf(""),
+	VariantTypeDateTime:        typeDateTime,
+	VariantTypeGUID:            typeGUID,
+	VariantTypeByteString:      typeByteString,
+	VariantTypeXMLElement:      typeXMLElement,
+	VariantTypeNodeID:          typeNodeID,
+	VariantTypeExpandedNodeID:  typeExpandedNodeID,
+	VariantTypeStatusCode:      typeStatusCode,
+	VariantTypeQualifiedName:   typeQualifiedName,
+	VariantTypeLocalizedText:   typeLocalizedText,
+	VariantTypeExtensionObject: typeExtensionObject,
+	VariantTypeDataValue:       typeDataValue,
+	VariantTypeVariant:         typeVariant,
+	VariantTypeDiagnosticInfo:  typeDiagnosticInfo,
+}
+
+// JSONDecoder decodes the reversible form of the UA JSON encoding, described in Part 6 section 5.4.
+type JSONDecoder struct {
+	d  *json.Decoder
+	ec EncodingContext
+}
+
+// NewJSONDecoder returns a new decoder that reads from an io.Reader.
+func NewJSONDecoder(r io.Reader, ec EncodingContext) *JSONDecoder {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	return &JSONDecoder{d, ec}
+}
+
+// Decode reads the next JSON value and stores it in the value pointed to by v.
+func (dec *JSONDecoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return BadDecodingError
+	}
+	var j any
+	if err := dec.d.Decode(&j); err != nil {
+		return BadDecodingError
+	}
+	if err := dec.decodeValue(j, rv.Elem()); err != nil {
+		return BadDecodingError
+	}
+	return nil
+}
+
+// ReadVariant reads a Variant.
+func (dec *JSONDecoder) ReadVariant(value *Variant) error {
+	return dec.Decode(value)
+}
+
+// ReadExtensionObject reads an ExtensionObject.
+func (dec *JSONDecoder) ReadExtensionObject(value *ExtensionObject) error {
+	return dec.Decode(value)
+}
+
+func (dec *JSONDecoder) decodeValue(j any, rv reflect.Value) error {
+	switch rv.Type() {
+	case typeVariant:
+		v, err := dec.decodeVariant(j)
+		if err != nil {
+			return err
+		}
+		setInterface(rv, v)
+		return nil
+	case typeExtensionObject:
+		v, err := dec.decodeExtensionObject(j)
+		if err != nil {
+			return err
+		}
+		setInterface(rv, v)
+		return nil
+	case typeNodeID:
+		v, err := dec.decodeNodeID(j)
+		if err != nil {
+			return err
+		}
+		setInterface(rv, v)
+		return nil
+	case typeDateTime:
+		s, ok := j.(string)
+		if !ok {
+			return BadDecodingError
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.Set(reflect.ValueOf(t.UTC()))
+		return nil
+	case typeGUID:
+		s, ok := j.(string)
+		if !ok {
+			return BadDecodingError
+		}
+		g, err := uuid.Parse(s)
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.Set(reflect.ValueOf(g))
+		return nil
+	case typeByteString, typeSliceOfByte:
+		if j == nil {
+			rv.SetZero()
+			return nil
+		}
+		s, ok := j.(string)
+		if !ok {
+			if rv.Type() == typeSliceOfByte {
+				break // array of Byte
+			}
+			return BadDecodingError
+		}
+		buf, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return BadDecodingError
+		}
+		if rv.Type() == typeByteString {
+			rv.SetString(string(buf))
+		} else {
+			rv.SetBytes(buf)
+		}
+		return nil
+	case typeExpandedNodeID:
+		if j == nil {
+			rv.SetZero()
+			return nil
+		}
+		s, ok := j.(string)
+		if !ok {
+			return BadDecodingError
+		}
+		rv.Set(reflect.ValueOf(ParseExpandedNodeID(s)))
+		return nil
+	case typeStatusCode:
+		if m, ok := j.(map[string]any); ok {
+			j = m["Code"]
+		}
+	case typeQualifiedName:
+		m, ok := j.(map[string]any)
+		if !ok {
+			return BadDecodingError
+		}
+		var name QualifiedName
+		name.Name, _ = m["Name"].(string)
+		switch uri := m["Uri"].(type) {
+		case json.Number:
+			ns, err := strconv.ParseUint(uri.String(), 10, 16)
+			if err != nil {
+				return BadDecodingError
+			}
+			name.NamespaceIndex = uint16(ns)
+		case string:
+			if i := indexOfURI(dec.ec.NamespaceURIs(), uri); i > 0 {
+				name.NamespaceIndex = uint16(i)
+			}
+		}
+		rv.Set(reflect.ValueOf(name))
+		return nil
+	case typeLocalizedText:
+		var text LocalizedText
+		switch j := j.(type) {
+		case string:
+			text.Text = j
+		case map[string]any:
+			text.Text, _ = j["Text"].(string)
+			text.Locale, _ = j["Locale"].(string)
+		case nil:
+		default:
+			return BadDecodingError
+		}
+		rv.Set(reflect.ValueOf(text))
+		return nil
+	case typeDataValue:
+		return dec.decodeDataValue(j, rv)
+	case typeDiagnosticInfo:
+		return dec.decodeDiagnosticInfo(j, rv)
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		b, ok := j.(bool)
+		if !ok && j != nil {
+			return BadDecodingError
+		}
+		rv.SetBool(b)
+		return nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s, ok := numberString(j)
+		if !ok {
+			return BadDecodingError
+		}
+		i, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.SetInt(i)
+		return nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s, ok := numberString(j)
+		if !ok {
+			return BadDecodingError
+		}
+		i, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.SetUint(i)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch s, _ := numberString(j); s {
+		case "NaN":
+			f = math.NaN()
+		case "Infinity":
+			f = math.Inf(1)
+		case "-Infinity":
+			f = math.Inf(-1)
+		default:
+			var err error
+			if f, err = strconv.ParseFloat(s, rv.Type().Bits()); err != nil {
+				return BadDecodingError
+			}
+		}
+		rv.SetFloat(f)
+		return nil
+	case reflect.String:
+		s, ok := j.(string)
+		if !ok && j != nil {
+			return BadDecodingError
+		}
+		rv.SetString(s)
+		return nil
+	case reflect.Slice:
+		if j == nil {
+			rv.SetZero()
+			return nil
+		}
+		a, ok := j.([]any)
+		if !ok {
+			return BadDecodingError
+		}
+		s := reflect.MakeSlice(rv.Type(), len(a), len(a))
+		for i, e := range a {
+			if err := dec.decodeValue(e, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+		return nil
+	case reflect.Ptr:
+		if j == nil {
+			rv.SetZero()
+			return nil
+		}
+		v := reflect.New(rv.Type().Elem())
+		if err := dec.decodeValue(j, v.Elem()); err != nil {
+			return err
+		}
+		rv.Set(v)
+		return nil
+	case reflect.Struct:
+		if rv.Type().Implements(typeNodeID) {
+			v, err := dec.decodeNodeID(j)
+			if err != nil || v == nil || reflect.TypeOf(v) != rv.Type() {
+				return BadDecodingError
+			}
+			rv.Set(reflect.ValueOf(v))
+			return nil
+		}
+		return dec.decodeStruct(j, rv)
+	}
+	return BadDecodingError
+}
+
+// decodeStruct decodes the fields of a struct from a JSON object. Pointer fields are optional.
+func (dec *JSONDecoder) decodeStruct(j any, rv reflect.Value) error {
+	m, ok := j.(map[string]any)
+	if !ok {
+		if j == nil {
+			rv.SetZero()
+			return nil
+		}
+		return BadDecodingError
+	}
+	typ := rv.Type()
+	if isUnion(typ) {
+		sw, _ := numberString(m["SwitchField"])
+		n, _ := strconv.Atoi(sw)
+		for i, pos := 0, 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Anonymous && field.Type == typeUnion {
+				continue
+			}
+			pos++
+			if pos != n {
+				rv.Field(i).SetZero()
+				continue
+			}
+			if err := dec.decodeValue(m["Value"], rv.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if err := dec.decodeValue(m[field.Name], rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dec *JSONDecoder) decodeVariant(j any) (Variant, error) {
+	if j == nil {
+		return nil, nil
+	}
+	m, ok := j.(map[string]any)
+	if !ok {
+		return nil, BadDecodingError
+	}
+	s, _ := numberString(m["Type"])
+	vt, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || vt == 0 || vt >= uint64(len(variantGoTypes)) {
+		return nil, BadDecodingError
+	}
+	typ := variantGoTypes[vt]
+	a, ok := m["Body"].([]any)
+	if !ok {
+		if byte(vt) == VariantTypeExtensionObject {
+			return dec.decodeExtensionObject(m["Body"])
+		}
+		v := reflect.New(typ).Elem()
+		if err := dec.decodeValue(m["Body"], v); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	}
+	s2 := reflect.MakeSlice(reflect.SliceOf(typ), len(a), len(a))
+	for i, e := range a {
+		if err := dec.decodeValue(e, s2.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	var dims []int32
+	if err := dec.decodeValue(m["Dimensions"], reflect.ValueOf(&dims).Elem()); err != nil {
+		return nil, err
+	}
+	return reshapeArray(s2, dims)
+}
+
+// reshapeArray returns the flattened array as a multidimensional array with the given dimensions.
+// Without dimensions, the array is one-dimensional.
+func reshapeArray(flat reflect.Value, dims []int32) (any, error) {
+	if len(dims) > 0 {
+		if err := checkDimensions(dims, flat.Len()); err != nil {
+			return nil, err
+		}
+	}
+	switch len(dims) {
+	case 0, 1:
+		return flat.Interface(), nil
+	case 2:
+		res := reflect.MakeSlice(reflect.SliceOf(flat.Type()), int(dims[0]), int(dims[0]))
+		for i := 0; i < int(dims[0]); i++ {
+			res.Index(i).Set(flat.Slice(i*int(dims[1]), (i+1)*int(dims[1])))
+		}
+		return res.Interface(), nil
+	case 3:
+		res := reflect.MakeSlice(reflect.SliceOf(reflect.SliceOf(flat.Type())), int(dims[0]), int(dims[0]))
+		for i := 0; i < int(dims[0]); i++ {
+			res2 := reflect.MakeSlice(reflect.SliceOf(flat.Type()), int(dims[1]), int(dims[1]))
+			for k := 0; k < int(dims[1]); k++ {
+				start := (i*int(dims[1]) + k) * int(dims[2])
+				res2.Index(k).Set(flat.Slice(start, start+int(dims[2])))
+			}
+			res.Index(i).Set(res2)
+		}
+		return res.Interface(), nil
+	default:
+		return nil, BadDecodingError
+	}
+}
+
+// checkDimensions returns BadDecodingError unless each dimension is greater than zero, and the product
+// of the dimensions equals the length of the array.
+func checkDimensions(dims []int32, length int) error {
+	n := 1
+	for _, d := range dims {
+		// the product may not exceed the length, so it cannot overflow.
+		if d <= 0 || n > length/int(d) {
+			return BadDecodingError
+		}
+		n *= int(d)
+	}
+	if n != length {
+		return BadDecodingError
+	}
+	return nil
+}
+
+// decodeExtensionObject decodes the body of the ExtensionObject to the type registered for the TypeId.
+// A JSON body is identified by the id of the DefaultJson encoding or of the DataType, and a ByteString
+// body by the id of the DefaultBinary encoding.
+// Returns BadDecodingError if the TypeId is of an unknown type.
+func (dec *JSONDecoder) decodeExtensionObject(j any) (ExtensionObject, error) {
+	if j == nil {
+		return nil, nil
+	}
+	m, ok := j.(map[string]any)
+	if !ok {
+		return nil, BadDecodingError
+	}
+	nodeID, err := dec.decodeNodeID(m["TypeId"])
+	if err != nil || nodeID == nil {
+		return nil, BadDecodingError
+	}
+	id := ToExpandedNodeID(nodeID, dec.ec.NamespaceURIs())
+	s, _ := numberString(m["Encoding"])
+	switch s {
+	case "", "0":
+		if typ, ok := FindTypeForJSONEncodingID(id); ok {
+			v := reflect.New(typ).Elem()
+			if err := dec.decodeValue(m["Body"], v); err != nil {
+				return nil, err
+			}
+			return v.Interface(), nil
+		}
+		if st, ok := findStructureType(id); ok {
+			return dec.decodeStructure(m["Body"], st)
+		}
+		return nil, BadDecodingError
+	case "1":
+		var body []byte
+		if err := dec.decodeValue(m["Body"], reflect.ValueOf(&body).Elem()); err != nil {
+			return nil, err
+		}
+		bd := NewBinaryDecoder(bytes.NewReader(body), dec.ec)
+		if typ, ok := FindTypeForBinaryEncodingID(id); ok {
+			obj := reflect.New(typ).Elem().Interface()
+			if err := bd.Decode(obj); err != nil {
+				return nil, BadDecodingError
+			}
+			return obj, nil
+		}
+		if st, ok := findStructureType(id); ok {
+			var s Structure
+			if err := bd.readStructure(st, &s); err != nil {
+				return nil, BadDecodingError
+			}
+			return s, nil
+		}
+		return nil, BadDecodingError
+	default:
+		return nil, BadDecodingError
+	}
+}
+
+// decodeStructure decodes the fields of a Structure from a JSON object, using the registered definition.
+func (dec *JSONDecoder) decodeStructure(j any, st *structureType) (Structure, error) {
+	m, ok := j.(map[string]any)
+	if !ok {
+		return Structure{}, BadDecodingError
+	}
+	s := Structure{TypeID: st.dataTypeID, Fields: make([]StructureFieldValue, len(st.fields))}
+	for i, f := range st.fields {
+		s.Fields[i].Name = f.name
+	}
+	if st.structureType == StructureTypeUnion {
+		sw, _ := numberString(m["SwitchField"])
+		n, _ := strconv.Atoi(sw)
+		if n < 0 || n > len(st.fields) {
+			return Structure{}, BadDecodingError
+		}
+		if n > 0 {
+			v, err := dec.decodeFieldValue(m["Value"], st.fields[n-1])
+			if err != nil {
+				return Structure{}, err
+			}
+			s.Fields[n-1].Value = v
+		}
+		return s, nil
+	}
+	for i, f := range st.fields {
+		fj, ok := m[f.name]
+		if !ok && f.isOptional {
+			continue
+		}
+		v, err := dec.decodeFieldValue(fj, f)
+		if err != nil {
+			return Structure{}, err
+		}
+		s.Fields[i].Value = v
+	}
+	return s, nil
+}
+
+// decodeFieldValue decodes the value of a field of a Structure.
+func (dec *JSONDecoder) decodeFieldValue(j any, f structureFieldType) (Variant, error) {
+	ft, ok := findFieldType(f.dataTypeID)
+	if !ok {
+		return nil, BadDecodingError
+	}
+	elem := func(j any) (Variant, error) {
+		switch {
+		case ft.structure != nil:
+			return dec.decodeStructure(j, ft.structure)
+		case ft.goType != nil:
+			v := reflect.New(ft.goType).Elem()
+			if err := dec.decodeValue(j, v); err != nil {
+				return nil, err
+			}
+			return v.Interface(), nil
+		case ft.builtin == VariantTypeVariant:
+			return dec.decodeVariant(j)
+		case ft.builtin == VariantTypeExtensionObject:
+			return dec.decodeExtensionObject(j)
+		default:
+			v := reflect.New(variantGoTypes[ft.builtin]).Elem()
+			if err := dec.decodeValue(j, v); err != nil {
+				return nil, err
+			}
+			return v.Interface(), nil
+		}
+	}
+	if f.valueRank == ValueRankScalar {
+		return elem(j)
+	}
+	if j == nil {
+		return nil, nil
+	}
+	if ft.builtin != 0 {
+		v := reflect.New(reflect.SliceOf(variantGoTypes[ft.builtin])).Elem()
+		if err := dec.decodeValue(j, v); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	}
+	a, ok := j.([]any)
+	if !ok {
+		return nil, BadDecodingError
+	}
+	list := make([]ExtensionObject, len(a))
+	for i, e := range a {
+		v, err := elem(e)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+// decodeNodeID decodes a NodeID from its string form. Strings of the form "nsu=uri;i=1"
+// are resolved using the NamespaceURIs of the EncodingContext.
+func (dec *JSONDecoder) decodeNodeID(j any) (NodeID, error) {
+	if j == nil {
+		return nil, nil
+	}
+	s, ok := j.(string)
+	if !ok {
+		return nil, BadDecodingError
+	}
+	if strings.HasPrefix(s, "nsu=") {
+		id := ToNodeID(ParseExpandedNodeID(s), dec.ec.NamespaceURIs())
+		if id == nil {
+			return nil, BadDecodingError
+		}
+		return id, nil
+	}
+	id := ParseNodeID(s)
+	if id == nil {
+		return nil, BadDecodingError
+	}
+	return id, nil
+}
+
+func (dec *JSONDecoder) decodeDataValue(j any, rv reflect.Value) error {
+	m, ok := j.(map[string]any)
+	if !ok {
+		return BadDecodingError
+	}
+	var dv DataValue
+	fields := []struct {
+		name string
+		ptr  any
+	}{
+		{"Value", &dv.Value},
+		{"Status", &dv.StatusCode},
+		{"SourceTimestamp", &dv.SourceTimestamp},
+		{"SourcePicoseconds", &dv.SourcePicoseconds},
+		{"ServerTimestamp", &dv.ServerTimestamp},
+		{"ServerPicoseconds", &dv.ServerPicoseconds},
+	}
+	for _, f := range fields {
+		if fj, ok := m[f.name]; ok {
+			if err := dec.decodeValue(fj, reflect.ValueOf(f.ptr).Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	rv.Set(reflect.ValueOf(dv))
+	return nil
+}
+
+func (dec *JSONDecoder) decodeDiagnosticInfo(j any, rv reflect.Value) error {
+	m, ok := j.(map[string]any)
+	if !ok {
+		return BadDecodingError
+	}
+	var info DiagnosticInfo
+	fields := []struct {
+		name string
+		ptr  any
+	}{
+		{"SymbolicId", &info.SymbolicID},
+		{"NamespaceUri", &info.NamespaceURI},
+		{"Locale", &info.Locale},
+		{"LocalizedText", &info.LocalizedText},
+		{"AdditionalInfo", &info.AdditionalInfo},
+		{"InnerStatusCode", &info.InnerStatusCode},
+		{"InnerDiagnosticInfo", &info.InnerDiagnosticInfo},
+	}
+	for _, f := range fields {
+		if fj, ok := m[f.name]; ok {
+			if err := dec.decodeValue(fj, reflect.ValueOf(f.ptr).Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	rv.Set(reflect.ValueOf(info))
+	return nil
+}
+
+// setInterface sets the interface value, or clears it if v is nil.
+func setInterface(rv reflect.Value, v any) {
+	if v == nil {
+		rv.SetZero()
+		return
+	}
+	rv.Set(reflect.ValueOf(v))
+}
+
+// numberString returns the string form of a JSON number, or a string, e.g. an Int64 or "NaN".
+func numberString(j any) (string, bool) {
+	switch j := j.(type) {
+	case json.Number:
+		return j.String(), true
+	case string:
+		return j, true
+	case nil:
+		return "0", true
+	default:
+		return "", false
+	}
+}
+
+func indexOfURI(uris []string, uri string) int {
+	for i, u := range uris {
+		if u == uri {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,701 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	typeStatusCode = reflect.TypeOf((*StatusCode)(nil)).Elem()
+	typeByteString = reflect.TypeOf((*ByteString)(nil)).Elem()
+	typeXMLElement = reflect.TypeOf((*XMLElement)(nil)).Elem()
+	typeStructure  = reflect.TypeOf((*Structure)(nil)).Elem()
+
+	// variantTypes maps the Go type of a scalar value to the builtin type of the Variant.
+	variantTypes = map[reflect.Type]byte{
+		reflect.TypeOf(false):           VariantTypeBoolean,
+		reflect.TypeOf(int8(0)):         VariantTypeSByte,
+		reflect.TypeOf(uint8(0)):        VariantTypeByte,
+		reflect.TypeOf(int16(0)):        VariantTypeInt16,
+		reflect.TypeOf(uint16(0)):       VariantTypeUInt16,
+		reflect.TypeOf(int32(0)):        VariantTypeInt32,
+		reflect.TypeOf(uint32(0)):       VariantTypeUInt32,
+		reflect.TypeOf(int64(0)):        VariantTypeInt64,
+		reflect.TypeOf(uint64(0)):       VariantTypeUInt64,
+		reflect.TypeOf(float32(0)):      VariantTypeFloat,
+		reflect.TypeOf(float64(0)):      VariantTypeDouble,
+		reflect.TypeOf(""):              VariantTypeString,
+		typeDateTime:                    VariantTypeDateTime,
+		typeGUID:                        VariantTypeGUID,
+		typeByteString:                  VariantTypeByteString,
+		typeXMLElement:                  VariantTypeXMLElement,
+		typeNodeID:                      VariantTypeNodeID,
+		typeExpandedNodeID:              VariantTypeExpandedNodeID,
+		typeStatusCode:                  VariantTypeStatusCode,
+		typeQualifiedName:               VariantTypeQualifiedName,
+		typeLocalizedText:               VariantTypeLocalizedText,
+		typeExtensionObject:             VariantTypeExtensionObject,
+		typeDataValue:                   VariantTypeDataValue,
+		typeVariant:                     VariantTypeVariant,
+		typeDiagnosticInfo:              VariantTypeDiagnosticInfo,
+		reflect.TypeOf(NodeIDNumeric{}): VariantTypeNodeID,
+		reflect.TypeOf(NodeIDString{}):  VariantTypeNodeID,
+		reflect.TypeOf(NodeIDGUID{}):    VariantTypeNodeID,
+		reflect.TypeOf(NodeIDOpaque{}):  VariantTypeNodeID,
+	}
+)
+
+// JSONEncoder encodes the UA JSON encoding, described in Part 6 section 5.4.
+//
+// The reversible form, the default, may be decoded by the JSONDecoder. The non-reversible form
+// is intended for consumers that do not know OPC UA, e.g. web services: Variants and ExtensionObjects
+// are encoded as their body, namespace indexes are replaced by NamespaceURIs, LocalizedText is encoded as
+// its text, and StatusCode is encoded with its symbolic name.
+//
+// ExtensionObjects are identified by the json encoding id that was registered with
+//
+//	func RegisterJSONEncodingID(typ reflect.Type, id ExpandedNodeID)
+//
+// Structures of a registered StructureDefinition are identified by the id of their DataType.
+type JSONEncoder struct {
+	w          io.Writer
+	ec         EncodingContext
+	reversible bool
+}
+
+// NewJSONEncoder returns a new encoder that writes the reversible form to an io.Writer.
+func NewJSONEncoder(w io.Writer, ec EncodingContext) *JSONEncoder {
+	return &JSONEncoder{w, ec, true}
+}
+
+// SetReversible sets whether the encoder writes the reversible or non-reversible form.
+func (enc *JSONEncoder) SetReversible(reversible bool) {
+	enc.reversible = reversible
+}
+
+// Encode writes the JSON encoding of the value. To encode a Variant or ExtensionObject,
+// pass a pointer to the Variant or ExtensionObject.
+func (enc *JSONEncoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	b, err := enc.appendValue(nil, rv)
+	if err != nil {
+		return BadEncodingError
+	}
+	if _, err := enc.w.Write(b); err != nil {
+		return BadEncodingError
+	}
+	return nil
+}
+
+// WriteVariant writes the JSON encoding of the Variant.
+func (enc *JSONEncoder) WriteVariant(value Variant) error {
+	return enc.Encode(&value)
+}
+
+// WriteExtensionObject writes the JSON encoding of the ExtensionObject.
+func (enc *JSONEncoder) WriteExtensionObject(value ExtensionObject) error {
+	return enc.Encode(&value)
+}
+
+func (enc *JSONEncoder) appendValue(b []byte, rv reflect.Value) ([]byte, error) {
+	if !rv.IsValid() {
+		return append(b, "null"...), nil
+	}
+	switch rv.Type() {
+	case typeVariant:
+		return enc.appendVariant(b, rv.Interface())
+	case typeExtensionObject:
+		return enc.appendExtensionObject(b, rv.Interface())
+	case typeNodeID:
+		if rv.IsNil() {
+			return append(b, "null"...), nil
+		}
+		return enc.appendNodeID(b, rv.Interface().(NodeID)), nil
+	case typeDateTime:
+		return appendDateTime(b, rv.Interface().(time.Time)), nil
+	case typeGUID:
+		return appendString(b, rv.Interface().(uuid.UUID).String()), nil
+	case typeByteString:
+		return appendString(b, base64.StdEncoding.EncodeToString([]byte(rv.String()))), nil
+	case typeSliceOfByte:
+		if rv.IsNil() {
+			return append(b, "null"...), nil
+		}
+		return appendString(b, base64.StdEncoding.EncodeToString(rv.Bytes())), nil
+	case typeXMLElement:
+		return appendString(b, rv.String()), nil
+	case typeExpandedNodeID:
+		return enc.appendExpandedNodeID(b, rv.Interface().(ExpandedNodeID)), nil
+	case typeStatusCode:
+		return enc.appendStatusCode(b, StatusCode(rv.Uint())), nil
+	case typeQualifiedName:
+		return enc.appendQualifiedName(b, rv.Interface().(QualifiedName)), nil
+	case typeLocalizedText:
+		return enc.appendLocalizedText(b, rv.Interface().(LocalizedText)), nil
+	case typeDataValue:
+		return enc.appendDataValue(b, rv.Interface().(DataValue))
+	case typeDiagnosticInfo:
+		return enc.appendDiagnosticInfo(b, rv.Interface().(DiagnosticInfo)), nil
+	case typeStructure:
+		return enc.appendStructure(b, rv.Interface().(Structure))
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(b, rv.Bool()), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return strconv.AppendInt(b, rv.Int(), 10), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return strconv.AppendUint(b, rv.Uint(), 10), nil
+	case reflect.Int64:
+		return appendString(b, strconv.FormatInt(rv.Int(), 10)), nil
+	case reflect.Uint64:
+		return appendString(b, strconv.FormatUint(rv.Uint(), 10)), nil
+	case reflect.Float32:
+		return appendFloat(b, rv.Float(), 32), nil
+	case reflect.Float64:
+		return appendFloat(b, rv.Float(), 64), nil
+	case reflect.String:
+		return appendString(b, rv.String()), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return append(b, "null"...), nil
+		}
+		b = append(b, '[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			var err error
+			if b, err = enc.appendValue(b, rv.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, ']'), nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return append(b, "null"...), nil
+		}
+		return enc.appendValue(b, rv.Elem())
+	case reflect.Interface:
+		if rv.IsNil() {
+			return append(b, "null"...), nil
+		}
+		return enc.appendValue(b, rv.Elem())
+	case reflect.Struct:
+		if id, ok := rv.Interface().(NodeID); ok {
+			return enc.appendNodeID(b, id), nil
+		}
+		return enc.appendStruct(b, rv)
+	}
+	return nil, BadEncodingError
+}
+
+// appendStruct writes the fields of the struct as a JSON object. Nil pointer fields are optional, and omitted.
+func (enc *JSONEncoder) appendStruct(b []byte, rv reflect.Value) ([]byte, error) {
+	typ := rv.Type()
+	var err error
+	if isUnion(typ) {
+		// the switch field is the position of the first field that is set.
+		for i, pos := 0, 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Anonymous && field.Type == typeUnion {
+				continue
+			}
+			pos++
+			if fv := rv.Field(i); !fv.IsNil() {
+				if !enc.reversible {
+					return enc.appendValue(b, fv)
+				}
+				b = append(b, `{"SwitchField":`...)
+				b = strconv.AppendInt(b, int64(pos), 10)
+				b = append(b, `,"Value":`...)
+				if b, err = enc.appendValue(b, fv); err != nil {
+					return nil, err
+				}
+				return append(b, '}'), nil
+			}
+		}
+		if !enc.reversible {
+			return append(b, "null"...), nil
+		}
+		return append(b, `{"SwitchField":0}`...), nil
+	}
+	b = append(b, '{')
+	comma := false
+	if enc.reversible && hasOptionalFields(typ) {
+		var mask uint32
+		for i, bit := 0, uint32(1); i < typ.NumField(); i++ {
+			if typ.Field(i).Type.Kind() == reflect.Ptr {
+				if !rv.Field(i).IsNil() {
+					mask |= bit
+				}
+				bit <<= 1
+			}
+		}
+		b = append(b, `"EncodingMask":`...)
+		b = strconv.AppendUint(b, uint64(mask), 10)
+		comma = true
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		if field.Type.Kind() == reflect.Ptr && fv.IsNil() {
+			continue
+		}
+		if comma {
+			b = append(b, ',')
+		}
+		comma = true
+		b = appendString(b, field.Name)
+		b = append(b, ':')
+		if b, err = enc.appendValue(b, fv); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// appendStructure writes the fields of the Structure as a JSON object, using the registered definition.
+func (enc *JSONEncoder) appendStructure(b []byte, s Structure) ([]byte, error) {
+	st, ok := findStructureType(canonicalID(s.TypeID, enc.ec))
+	if !ok {
+		return nil, BadEncodingError
+	}
+	var err error
+	if st.structureType == StructureTypeUnion {
+		for i, f := range st.fields {
+			if v, _ := s.Field(f.name); v != nil {
+				if !enc.reversible {
+					return enc.appendFieldValue(b, f, v)
+				}
+				b = append(b, `{"SwitchField":`...)
+				b = strconv.AppendInt(b, int64(i+1), 10)
+				b = append(b, `,"Value":`...)
+				if b, err = enc.appendFieldValue(b, f, v); err != nil {
+					return nil, err
+				}
+				return append(b, '}'), nil
+			}
+		}
+		if !enc.reversible {
+			return append(b, "null"...), nil
+		}
+		return append(b, `{"SwitchField":0}`...), nil
+	}
+	b = append(b, '{')
+	comma := false
+	if enc.reversible && st.structureType == StructureTypeStructureWithOptionalFields {
+		var mask uint32
+		var bit uint32 = 1
+		for _, f := range st.fields {
+			if f.isOptional {
+				if v, _ := s.Field(f.name); v != nil {
+					mask |= bit
+				}
+				bit <<= 1
+			}
+		}
+		b = append(b, `"EncodingMask":`...)
+		b = strconv.AppendUint(b, uint64(mask), 10)
+		comma = true
+	}
+	for _, f := range st.fields {
+		v, _ := s.Field(f.name)
+		if f.isOptional && v == nil {
+			continue
+		}
+		if comma {
+			b = append(b, ',')
+		}
+		comma = true
+		b = appendString(b, f.name)
+		b = append(b, ':')
+		if b, err = enc.appendFieldValue(b, f, v); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// appendFieldValue writes the value of a field of a Structure. Fields of abstract data types, e.g. BaseDataType
+// or Structure, are written as Variant or ExtensionObject. Fields of structured data types are written inline.
+func (enc *JSONEncoder) appendFieldValue(b []byte, f structureFieldType, v any) ([]byte, error) {
+	ft, ok := findFieldType(f.dataTypeID)
+	if !ok {
+		return nil, BadEncodingError
+	}
+	elem := func(b []byte, v any) ([]byte, error) {
+		switch ft.builtin {
+		case VariantTypeVariant:
+			return enc.appendVariant(b, v)
+		case VariantTypeExtensionObject:
+			return enc.appendExtensionObject(b, v)
+		default:
+			return enc.appendValue(b, reflect.ValueOf(v))
+		}
+	}
+	if f.valueRank == ValueRankScalar || v == nil {
+		return elem(b, v)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, BadEncodingError
+	}
+	if ft.builtin != 0 && ft.builtin != VariantTypeVariant && ft.builtin != VariantTypeExtensionObject {
+		return enc.appendValue(b, rv)
+	}
+	b = append(b, '[')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = elem(b, rv.Index(i).Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, ']'), nil
+}
+
+func (enc *JSONEncoder) appendVariant(b []byte, value Variant) ([]byte, error) {
+	if value == nil {
+		return append(b, "null"...), nil
+	}
+	if !enc.reversible {
+		return enc.appendValue(b, reflect.ValueOf(value))
+	}
+	rv := reflect.ValueOf(value)
+	var dims []int32
+	if rv.Kind() == reflect.Slice {
+		// flatten a multidimensional array, and record the dimensions.
+		dims = []int32{int32(rv.Len())}
+		for rv.Type().Elem().Kind() == reflect.Slice {
+			var inner int32
+			if rv.Len() > 0 {
+				inner = int32(rv.Index(0).Len())
+			}
+			dims = append(dims, inner)
+			flat := reflect.MakeSlice(rv.Type().Elem(), 0, rv.Len()*int(inner))
+			for i := 0; i < rv.Len(); i++ {
+				flat = reflect.AppendSlice(flat, rv.Index(i))
+			}
+			rv = flat
+		}
+		if len(dims) == 1 {
+			dims = nil
+		}
+	}
+	vt, ok := variantTypeOf(rv)
+	if !ok {
+		return nil, BadEncodingError
+	}
+	b = append(b, `{"Type":`...)
+	b = strconv.AppendUint(b, uint64(vt), 10)
+	b = append(b, `,"Body":`...)
+	var err error
+	if rv.Kind() == reflect.Slice {
+		// arrays of structs are arrays of ExtensionObjects, arrays of Byte are arrays of numbers.
+		b = append(b, '[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			ev := rv.Index(i)
+			switch vt {
+			case VariantTypeExtensionObject:
+				b, err = enc.appendExtensionObject(b, ev.Interface())
+			case VariantTypeByte:
+				b = strconv.AppendUint(b, ev.Uint(), 10)
+			default:
+				b, err = enc.appendValue(b, ev)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	} else if vt == VariantTypeExtensionObject {
+		if b, err = enc.appendExtensionObject(b, value); err != nil {
+			return nil, err
+		}
+	} else {
+		if b, err = enc.appendValue(b, rv); err != nil {
+			return nil, err
+		}
+	}
+	if dims != nil {
+		b = append(b, `,"Dimensions":`...)
+		if b, err = enc.appendValue(b, reflect.ValueOf(dims)); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+// variantTypeOf returns the builtin type of the scalar value or one-dimensional array.
+func variantTypeOf(rv reflect.Value) (byte, bool) {
+	typ := rv.Type()
+	if vt, ok := variantTypes[typ]; ok {
+		return vt, true
+	}
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+		if vt, ok := variantTypes[typ]; ok {
+			return vt, true
+		}
+	}
+	if typ.Kind() == reflect.Struct {
+		return VariantTypeExtensionObject, true
+	}
+	return 0, false
+}
+
+func (enc *JSONEncoder) appendExtensionObject(b []byte, value ExtensionObject) ([]byte, error) {
+	if value == nil {
+		return append(b, "null"...), nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return append(b, "null"...), nil
+		}
+		rv = rv.Elem()
+	}
+	if !enc.reversible {
+		return enc.appendValue(b, rv)
+	}
+	var id ExpandedNodeID
+	if s, ok := value.(Structure); ok {
+		st, ok := findStructureType(canonicalID(s.TypeID, enc.ec))
+		if !ok {
+			return nil, BadEncodingError
+		}
+		id = st.dataTypeID
+	} else {
+		var ok bool
+		if id, ok = FindJSONEncodingIDForType(rv.Type()); !ok {
+			return nil, BadEncodingError
+		}
+	}
+	b = append(b, `{"TypeId":`...)
+	b = enc.appendNodeID(b, ToNodeID(id, enc.ec.NamespaceURIs()))
+	b = append(b, `,"Body":`...)
+	var err error
+	if b, err = enc.appendValue(b, rv); err != nil {
+		return nil, err
+	}
+	return append(b, '}'), nil
+}
+
+// appendNodeID writes the NodeID as a string, e.g. "ns=2;s=Demo". In the non-reversible form, the namespace
+// index is replaced by the NamespaceURI, e.g. "nsu=http://github.com/awcullen/opcua/testserver/;s=Demo".
+func (enc *JSONEncoder) appendNodeID(b []byte, id NodeID) []byte {
+	if id == nil {
+		return append(b, "null"...)
+	}
+	if !enc.reversible {
+		if x := ToExpandedNodeID(id, enc.ec.NamespaceURIs()); x.NodeID != nil {
+			return appendString(b, x.String())
+		}
+	}
+	return appendString(b, nodeIDString(id))
+}
+
+func nodeIDString(id NodeID) string {
+	switch id := id.(type) {
+	case NodeIDNumeric:
+		return id.String()
+	case NodeIDString:
+		return id.String()
+	case NodeIDGUID:
+		return id.String()
+	case NodeIDOpaque:
+		return id.String()
+	default:
+		return ""
+	}
+}
+
+func (enc *JSONEncoder) appendExpandedNodeID(b []byte, id ExpandedNodeID) []byte {
+	if id.NodeID == nil {
+		return append(b, "null"...)
+	}
+	if !enc.reversible && id.NamespaceURI == "" {
+		if x := ToExpandedNodeID(id.NodeID, enc.ec.NamespaceURIs()); x.NodeID != nil {
+			id.NamespaceURI, id.NodeID = x.NamespaceURI, x.NodeID
+		}
+	}
+	return appendString(b, id.String())
+}
+
+func (enc *JSONEncoder) appendStatusCode(b []byte, code StatusCode) []byte {
+	if enc.reversible {
+		return strconv.AppendUint(b, uint64(code), 10)
+	}
+	b = append(b, `{"Code":`...)
+	b = strconv.AppendUint(b, uint64(code), 10)
+	if sym := code.Symbol(); sym != "" {
+		b = append(b, `,"Symbol":`...)
+		b = appendString(b, sym)
+	}
+	return append(b, '}')
+}
+
+func (enc *JSONEncoder) appendQualifiedName(b []byte, name QualifiedName) []byte {
+	b = append(b, `{"Name":`...)
+	b = appendString(b, name.Name)
+	if name.NamespaceIndex > 0 {
+		b = append(b, `,"Uri":`...)
+		uris := enc.ec.NamespaceURIs()
+		if !enc.reversible && name.NamespaceIndex > 1 && int(name.NamespaceIndex) < len(uris) {
+			b = appendString(b, uris[name.NamespaceIndex])
+		} else {
+			b = strconv.AppendUint(b, uint64(name.NamespaceIndex), 10)
+		}
+	}
+	return append(b, '}')
+}
+
+func (enc *JSONEncoder) appendLocalizedText(b []byte, text LocalizedText) []byte {
+	if !enc.reversible {
+		return appendString(b, text.Text)
+	}
+	b = append(b, '{')
+	if text.Locale != "" {
+		b = append(b, `"Locale":`...)
+		b = appendString(b, text.Locale)
+		if text.Text != "" {
+			b = append(b, ',')
+		}
+	}
+	if text.Text != "" {
+		b = append(b, `"Text":`...)
+		b = appendString(b, text.Text)
+	}
+	return append(b, '}')
+}
+
+// appendDataValue writes the DataValue as a JSON object, omitting the fields with default values.
+func (enc *JSONEncoder) appendDataValue(b []byte, dv DataValue) ([]byte, error) {
+	b = append(b, '{')
+	comma := false
+	field := func(name string) {
+		if comma {
+			b = append(b, ',')
+		}
+		comma = true
+		b = appendString(b, name)
+		b = append(b, ':')
+	}
+	if dv.Value != nil {
+		field("Value")
+		var err error
+		if b, err = enc.appendVariant(b, dv.Value); err != nil {
+			return nil, err
+		}
+	}
+	if dv.StatusCode != Good {
+		field("Status")
+		b = enc.appendStatusCode(b, dv.StatusCode)
+	}
+	if !dv.SourceTimestamp.IsZero() {
+		field("SourceTimestamp")
+		b = appendDateTime(b, dv.SourceTimestamp)
+	}
+	if dv.SourcePicoseconds != 0 {
+		field("SourcePicoseconds")
+		b = strconv.AppendUint(b, uint64(dv.SourcePicoseconds), 10)
+	}
+	if !dv.ServerTimestamp.IsZero() {
+		field("ServerTimestamp")
+		b = appendDateTime(b, dv.ServerTimestamp)
+	}
+	if dv.ServerPicoseconds != 0 {
+		field("ServerPicoseconds")
+		b = strconv.AppendUint(b, uint64(dv.ServerPicoseconds), 10)
+	}
+	return append(b, '}'), nil
+}
+
+// appendDiagnosticInfo writes the DiagnosticInfo as a JSON object, omitting the fields that are not set.
+func (enc *JSONEncoder) appendDiagnosticInfo(b []byte, info DiagnosticInfo) []byte {
+	b = append(b, '{')
+	comma := false
+	field := func(name string) {
+		if comma {
+			b = append(b, ',')
+		}
+		comma = true
+		b = appendString(b, name)
+		b = append(b, ':')
+	}
+	if info.SymbolicID != nil {
+		field("SymbolicId")
+		b = strconv.AppendInt(b, int64(*info.SymbolicID), 10)
+	}
+	if info.NamespaceURI != nil {
+		field("NamespaceUri")
+		b = strconv.AppendInt(b, int64(*info.NamespaceURI), 10)
+	}
+	if info.Locale != nil {
+		field("Locale")
+		b = strconv.AppendInt(b, int64(*info.Locale), 10)
+	}
+	if info.LocalizedText != nil {
+		field("LocalizedText")
+		b = strconv.AppendInt(b, int64(*info.LocalizedText), 10)
+	}
+	if info.AdditionalInfo != nil {
+		field("AdditionalInfo")
+		b = appendString(b, *info.AdditionalInfo)
+	}
+	if info.InnerStatusCode != nil {
+		field("InnerStatusCode")
+		b = enc.appendStatusCode(b, *info.InnerStatusCode)
+	}
+	if info.InnerDiagnosticInfo != nil {
+		field("InnerDiagnosticInfo")
+		b = enc.appendDiagnosticInfo(b, *info.InnerDiagnosticInfo)
+	}
+	return append(b, '}')
+}
+
+func appendString(b []byte, s string) []byte {
+	buf, _ := json.Marshal(s)
+	return append(b, buf...)
+}
+
+// appendDateTime writes the time in ISO 8601 format, in UTC.
+func appendDateTime(b []byte, t time.Time) []byte {
+	b = append(b, '"')
+	b = t.UTC().AppendFormat(b, time.RFC3339Nano)
+	return append(b, '"')
+}
+
+// appendFloat writes the number, or the strings "NaN", "Infinity" and "-Infinity".
+func appendFloat(b []byte, f float64, bitSize int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(b, `"NaN"`...)
+	case math.IsInf(f, 1):
+		return append(b, `"Infinity"`...)
+	case math.IsInf(f, -1):
+		return append(b, `"-Infinity"`...)
+	}
+	return strconv.AppendFloat(b, f, 'g', -1, bitSize)
+}
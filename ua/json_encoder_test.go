@@ -0,0 +1,156 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+	"gotest.tools/assert"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestJSON(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/", "urn:test:ns1", "http://github.com/awcullen/opcua/testserver/"}
+	cases := []struct {
+		in            any
+		reversible    string
+		nonReversible string
+	}{
+		{
+			ptr(ua.Variant(true)),
+			`{"Type":1,"Body":true}`,
+			`true`,
+		},
+		{
+			ptr(ua.Variant(int64(-5))),
+			`{"Type":8,"Body":"-5"}`,
+			`"-5"`,
+		},
+		{
+			ptr(ua.Variant(1.5)),
+			`{"Type":11,"Body":1.5}`,
+			`1.5`,
+		},
+		{
+			ptr(ua.Variant(ua.ByteString("abc"))),
+			`{"Type":15,"Body":"YWJj"}`,
+			`"YWJj"`,
+		},
+		{
+			ptr(ua.Variant(ua.NewNodeIDString(2, "Demo"))),
+			`{"Type":17,"Body":"ns=2;s=Demo"}`,
+			`"nsu=http://github.com/awcullen/opcua/testserver/;s=Demo"`,
+		},
+		{
+			ptr(ua.Variant(ua.NewLocalizedText("Hello", "en"))),
+			`{"Type":21,"Body":{"Locale":"en","Text":"Hello"}}`,
+			`"Hello"`,
+		},
+		{
+			ptr(ua.Variant(ua.NewQualifiedName(2, "Demo"))),
+			`{"Type":20,"Body":{"Name":"Demo","Uri":2}}`,
+			`{"Name":"Demo","Uri":"http://github.com/awcullen/opcua/testserver/"}`,
+		},
+		{
+			ptr(ua.Variant([][]int32{{1, 2}, {3, 4}})),
+			`{"Type":6,"Body":[1,2,3,4],"Dimensions":[2,2]}`,
+			`[[1,2],[3,4]]`,
+		},
+		{
+			ptr(ua.Variant([]ua.Variant{int32(1), "a"})),
+			`{"Type":24,"Body":[{"Type":6,"Body":1},{"Type":12,"Body":"a"}]}`,
+			`[1,"a"]`,
+		},
+		{
+			ptr(ua.Variant(ua.Range{Low: 0, High: 100})),
+			`{"Type":22,"Body":{"TypeId":"i=15375","Body":{"Low":0,"High":100}}}`,
+			`{"Low":0,"High":100}`,
+		},
+		{
+			ptr(ua.DataValue{Value: 1.5, StatusCode: ua.BadNodeIDUnknown, SourceTimestamp: time.Date(2021, time.January, 2, 3, 4, 5, 0, time.UTC)}),
+			`{"Value":{"Type":11,"Body":1.5},"Status":2150891520,"SourceTimestamp":"2021-01-02T03:04:05Z"}`,
+			`{"Value":1.5,"Status":{"Code":2150891520,"Symbol":"BadNodeIdUnknown"},"SourceTimestamp":"2021-01-02T03:04:05Z"}`,
+		},
+		{
+			ptr(ua.DiagnosticInfo{SymbolicID: ptr(int32(1)), InnerStatusCode: ptr(ua.BadNodeIDUnknown)}),
+			`{"SymbolicId":1,"InnerStatusCode":2150891520}`,
+			`{"SymbolicId":1,"InnerStatusCode":{"Code":2150891520,"Symbol":"BadNodeIdUnknown"}}`,
+		},
+		{
+			ptr(testOptionalStruct{A: 1, B: ptr(2.0)}),
+			`{"EncodingMask":1,"A":1,"B":2}`,
+			`{"A":1,"B":2}`,
+		},
+		{
+			ptr(testUnion{B: ptr("hi")}),
+			`{"SwitchField":2,"Value":"hi"}`,
+			`"hi"`,
+		},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		enc := ua.NewJSONEncoder(buf, ec)
+		if err := enc.Encode(c.in); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, buf.String(), c.reversible)
+
+		dec := ua.NewJSONDecoder(buf, ec)
+		out := reflect.New(reflect.TypeOf(c.in).Elem())
+		if err := dec.Decode(out.Interface()); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, out.Interface(), c.in)
+
+		buf.Reset()
+		enc.SetReversible(false)
+		if err := enc.Encode(c.in); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, buf.String(), c.nonReversible)
+	}
+}
+
+func TestJSONInvalidDimensions(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/"}
+	for _, in := range []string{
+		`{"Type":6,"Body":[1,2],"Dimensions":[-1,-2]}`,
+		`{"Type":6,"Body":[],"Dimensions":[0,5]}`,
+		`{"Type":6,"Body":[1,2],"Dimensions":[2,1,-1]}`,
+		`{"Type":6,"Body":[1,2],"Dimensions":[65536,65536,65536]}`,
+		`{"Type":6,"Body":[1,2],"Dimensions":[3]}`,
+		`{"Type":6,"Body":[1,2],"Dimensions":[0]}`,
+	} {
+		var out ua.Variant
+		if err := ua.NewJSONDecoder(strings.NewReader(in), ec).ReadVariant(&out); err != ua.BadDecodingError {
+			t.Errorf("ReadVariant(%s) = %v, want %v", in, err, ua.BadDecodingError)
+		}
+	}
+}
+
+func TestJSONExtensionObjectDataTypeID(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/"}
+	in := `{"Type":22,"Body":{"TypeId":"i=884","Body":{"Low":0,"High":100}}}`
+	var out ua.Variant
+	if err := ua.NewJSONDecoder(strings.NewReader(in), ec).ReadVariant(&out); err != nil {
+		t.Fatal(err)
+	}
+	assert.DeepEqual(t, out, ua.Variant(ua.Range{Low: 0, High: 100}))
+}
+
+func TestJSONUnknownExtensionObject(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/"}
+	in := `{"Type":22,"Body":{"TypeId":"ns=0;i=999999","Body":{"X":1}}}`
+	var out ua.Variant
+	if err := ua.NewJSONDecoder(strings.NewReader(in), ec).ReadVariant(&out); err != ua.BadDecodingError {
+		t.Errorf("ReadVariant(%s) = %v, want %v", in, err, ua.BadDecodingError)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	jsonEncodingTypes sync.Map // map[ExpandedNodeID]reflect.Type
+	jsonEncodingIDs   sync.Map // map[reflect.Type]ExpandedNodeID
+)
+
+// RegisterJSONEncodingID registers the type and id with the JSONEncoder. A type may be registered with
+// more than one id, e.g. the id of its DefaultJson encoding and the id of its DataType. The JSONDecoder
+// accepts each id, and the JSONEncoder writes the id that was registered first.
+func RegisterJSONEncodingID(typ reflect.Type, id ExpandedNodeID) {
+	jsonEncodingTypes.LoadOrStore(id, typ)
+	jsonEncodingIDs.LoadOrStore(typ, id)
+}
+
+// FindJSONEncodingIDForType finds the JSONEncodingID given the type.
+func FindJSONEncodingIDForType(typ reflect.Type) (ExpandedNodeID, bool) {
+	if val, ok := jsonEncodingIDs.Load(typ); ok {
+		if id, ok := val.(ExpandedNodeID); ok {
+			return id, ok
+		}
+	}
+	return NilExpandedNodeID, false
+}
+
+// FindTypeForJSONEncodingID finds the Type given the JSONEncodingID.
+func FindTypeForJSONEncodingID(id ExpandedNodeID) (reflect.Type, bool) {
+	if val, ok := jsonEncodingTypes.Load(id); ok {
+		if typ, ok := val.(reflect.Type); ok {
+			return typ, ok
+		}
+	}
+	return nil, false
+}
@@ -992,3 +992,501 @@ func (c StatusCode) Error() string {
         return "An unknown error occurred."
     }
 }
+
+// Symbol returns the symbolic name of the StatusCode, e.g. "BadNodeIdUnknown".
+func (c StatusCode) Symbol() string {
+    switch c {
+    case Good:
+        return "Good"
+    case BadUnexpectedError:
+        return "BadUnexpectedError"
+    case BadInternalError:
+        return "BadInternalError"
+    case BadOutOfMemory:
+        return "BadOutOfMemory"
+    case BadResourceUnavailable:
+        return "BadResourceUnavailable"
+    case BadCommunicationError:
+        return "BadCommunicationError"
+    case BadEncodingError:
+        return "BadEncodingError"
+    case BadDecodingError:
+        return "BadDecodingError"
+    case BadEncodingLimitsExceeded:
+        return "BadEncodingLimitsExceeded"
+    case BadRequestTooLarge:
+        return "BadRequestTooLarge"
+    case BadResponseTooLarge:
+        return "BadResponseTooLarge"
+    case BadUnknownResponse:
+        return "BadUnknownResponse"
+    case BadTimeout:
+        return "BadTimeout"
+    case BadServiceUnsupported:
+        return "BadServiceUnsupported"
+    case BadShutdown:
+        return "BadShutdown"
+    case BadServerNotConnected:
+        return "BadServerNotConnected"
+    case BadServerHalted:
+        return "BadServerHalted"
+    case BadNothingToDo:
+        return "BadNothingToDo"
+    case BadTooManyOperations:
+        return "BadTooManyOperations"
+    case BadTooManyMonitoredItems:
+        return "BadTooManyMonitoredItems"
+    case BadDataTypeIDUnknown:
+        return "BadDataTypeIdUnknown"
+    case BadCertificateInvalid:
+        return "BadCertificateInvalid"
+    case BadSecurityChecksFailed:
+        return "BadSecurityChecksFailed"
+    case BadCertificatePolicyCheckFailed:
+        return "BadCertificatePolicyCheckFailed"
+    case BadCertificateTimeInvalid:
+        return "BadCertificateTimeInvalid"
+    case BadCertificateIssuerTimeInvalid:
+        return "BadCertificateIssuerTimeInvalid"
+    case BadCertificateHostNameInvalid:
+        return "BadCertificateHostNameInvalid"
+    case BadCertificateURIInvalid:
+        return "BadCertificateUriInvalid"
+    case BadCertificateUseNotAllowed:
+        return "BadCertificateUseNotAllowed"
+    case BadCertificateIssuerUseNotAllowed:
+        return "BadCertificateIssuerUseNotAllowed"
+    case BadCertificateUntrusted:
+        return "BadCertificateUntrusted"
+    case BadCertificateRevocationUnknown:
+        return "BadCertificateRevocationUnknown"
+    case BadCertificateIssuerRevocationUnknown:
+        return "BadCertificateIssuerRevocationUnknown"
+    case BadCertificateRevoked:
+        return "BadCertificateRevoked"
+    case BadCertificateIssuerRevoked:
+        return "BadCertificateIssuerRevoked"
+    case BadCertificateChainIncomplete:
+        return "BadCertificateChainIncomplete"
+    case BadUserAccessDenied:
+        return "BadUserAccessDenied"
+    case BadIdentityTokenInvalid:
+        return "BadIdentityTokenInvalid"
+    case BadIdentityTokenRejected:
+        return "BadIdentityTokenRejected"
+    case BadSecureChannelIDInvalid:
+        return "BadSecureChannelIdInvalid"
+    case BadInvalidTimestamp:
+        return "BadInvalidTimestamp"
+    case BadNonceInvalid:
+        return "BadNonceInvalid"
+    case BadSessionIDInvalid:
+        return "BadSessionIdInvalid"
+    case BadSessionClosed:
+        return "BadSessionClosed"
+    case BadSessionNotActivated:
+        return "BadSessionNotActivated"
+    case BadSubscriptionIDInvalid:
+        return "BadSubscriptionIdInvalid"
+    case BadRequestHeaderInvalid:
+        return "BadRequestHeaderInvalid"
+    case BadTimestampsToReturnInvalid:
+        return "BadTimestampsToReturnInvalid"
+    case BadRequestCancelledByClient:
+        return "BadRequestCancelledByClient"
+    case BadTooManyArguments:
+        return "BadTooManyArguments"
+    case BadLicenseExpired:
+        return "BadLicenseExpired"
+    case BadLicenseLimitsExceeded:
+        return "BadLicenseLimitsExceeded"
+    case BadLicenseNotAvailable:
+        return "BadLicenseNotAvailable"
+    case GoodSubscriptionTransferred:
+        return "GoodSubscriptionTransferred"
+    case GoodCompletesAsynchronously:
+        return "GoodCompletesAsynchronously"
+    case GoodOverload:
+        return "GoodOverload"
+    case GoodClamped:
+        return "GoodClamped"
+    case BadNoCommunication:
+        return "BadNoCommunication"
+    case BadWaitingForInitialData:
+        return "BadWaitingForInitialData"
+    case BadNodeIDInvalid:
+        return "BadNodeIdInvalid"
+    case BadNodeIDUnknown:
+        return "BadNodeIdUnknown"
+    case BadAttributeIDInvalid:
+        return "BadAttributeIdInvalid"
+    case BadIndexRangeInvalid:
+        return "BadIndexRangeInvalid"
+    case BadIndexRangeNoData:
+        return "BadIndexRangeNoData"
+    case BadDataEncodingInvalid:
+        return "BadDataEncodingInvalid"
+    case BadDataEncodingUnsupported:
+        return "BadDataEncodingUnsupported"
+    case BadNotReadable:
+        return "BadNotReadable"
+    case BadNotWritable:
+        return "BadNotWritable"
+    case BadOutOfRange:
+        return "BadOutOfRange"
+    case BadNotSupported:
+        return "BadNotSupported"
+    case BadNotFound:
+        return "BadNotFound"
+    case BadObjectDeleted:
+        return "BadObjectDeleted"
+    case BadNotImplemented:
+        return "BadNotImplemented"
+    case BadMonitoringModeInvalid:
+        return "BadMonitoringModeInvalid"
+    case BadMonitoredItemIDInvalid:
+        return "BadMonitoredItemIdInvalid"
+    case BadMonitoredItemFilterInvalid:
+        return "BadMonitoredItemFilterInvalid"
+    case BadMonitoredItemFilterUnsupported:
+        return "BadMonitoredItemFilterUnsupported"
+    case BadFilterNotAllowed:
+        return "BadFilterNotAllowed"
+    case BadStructureMissing:
+        return "BadStructureMissing"
+    case BadEventFilterInvalid:
+        return "BadEventFilterInvalid"
+    case BadContentFilterInvalid:
+        return "BadContentFilterInvalid"
+    case BadFilterOperatorInvalid:
+        return "BadFilterOperatorInvalid"
+    case BadFilterOperatorUnsupported:
+        return "BadFilterOperatorUnsupported"
+    case BadFilterOperandCountMismatch:
+        return "BadFilterOperandCountMismatch"
+    case BadFilterOperandInvalid:
+        return "BadFilterOperandInvalid"
+    case BadFilterElementInvalid:
+        return "BadFilterElementInvalid"
+    case BadFilterLiteralInvalid:
+        return "BadFilterLiteralInvalid"
+    case BadContinuationPointInvalid:
+        return "BadContinuationPointInvalid"
+    case BadNoContinuationPoints:
+        return "BadNoContinuationPoints"
+    case BadReferenceTypeIDInvalid:
+        return "BadReferenceTypeIdInvalid"
+    case BadBrowseDirectionInvalid:
+        return "BadBrowseDirectionInvalid"
+    case BadNodeNotInView:
+        return "BadNodeNotInView"
+    case BadNumericOverflow:
+        return "BadNumericOverflow"
+    case BadServerURIInvalid:
+        return "BadServerUriInvalid"
+    case BadServerNameMissing:
+        return "BadServerNameMissing"
+    case BadDiscoveryURLMissing:
+        return "BadDiscoveryUrlMissing"
+    case BadSempahoreFileMissing:
+        return "BadSempahoreFileMissing"
+    case BadRequestTypeInvalid:
+        return "BadRequestTypeInvalid"
+    case BadSecurityModeRejected:
+        return "BadSecurityModeRejected"
+    case BadSecurityPolicyRejected:
+        return "BadSecurityPolicyRejected"
+    case BadTooManySessions:
+        return "BadTooManySessions"
+    case BadUserSignatureInvalid:
+        return "BadUserSignatureInvalid"
+    case BadApplicationSignatureInvalid:
+        return "BadApplicationSignatureInvalid"
+    case BadNoValidCertificates:
+        return "BadNoValidCertificates"
+    case BadIdentityChangeNotSupported:
+        return "BadIdentityChangeNotSupported"
+    case BadRequestCancelledByRequest:
+        return "BadRequestCancelledByRequest"
+    case BadParentNodeIDInvalid:
+        return "BadParentNodeIdInvalid"
+    case BadReferenceNotAllowed:
+        return "BadReferenceNotAllowed"
+    case BadNodeIDRejected:
+        return "BadNodeIdRejected"
+    case BadNodeIDExists:
+        return "BadNodeIdExists"
+    case BadNodeClassInvalid:
+        return "BadNodeClassInvalid"
+    case BadBrowseNameInvalid:
+        return "BadBrowseNameInvalid"
+    case BadBrowseNameDuplicated:
+        return "BadBrowseNameDuplicated"
+    case BadNodeAttributesInvalid:
+        return "BadNodeAttributesInvalid"
+    case BadTypeDefinitionInvalid:
+        return "BadTypeDefinitionInvalid"
+    case BadSourceNodeIDInvalid:
+        return "BadSourceNodeIdInvalid"
+    case BadTargetNodeIDInvalid:
+        return "BadTargetNodeIdInvalid"
+    case BadDuplicateReferenceNotAllowed:
+        return "BadDuplicateReferenceNotAllowed"
+    case BadInvalidSelfReference:
+        return "BadInvalidSelfReference"
+    case BadReferenceLocalOnly:
+        return "BadReferenceLocalOnly"
+    case BadNoDeleteRights:
+        return "BadNoDeleteRights"
+    case UncertainReferenceNotDeleted:
+        return "UncertainReferenceNotDeleted"
+    case BadServerIndexInvalid:
+        return "BadServerIndexInvalid"
+    case BadViewIDUnknown:
+        return "BadViewIdUnknown"
+    case BadViewTimestampInvalid:
+        return "BadViewTimestampInvalid"
+    case BadViewParameterMismatch:
+        return "BadViewParameterMismatch"
+    case BadViewVersionInvalid:
+        return "BadViewVersionInvalid"
+    case UncertainNotAllNodesAvailable:
+        return "UncertainNotAllNodesAvailable"
+    case GoodResultsMayBeIncomplete:
+        return "GoodResultsMayBeIncomplete"
+    case BadNotTypeDefinition:
+        return "BadNotTypeDefinition"
+    case UncertainReferenceOutOfServer:
+        return "UncertainReferenceOutOfServer"
+    case BadTooManyMatches:
+        return "BadTooManyMatches"
+    case BadQueryTooComplex:
+        return "BadQueryTooComplex"
+    case BadNoMatch:
+        return "BadNoMatch"
+    case BadMaxAgeInvalid:
+        return "BadMaxAgeInvalid"
+    case BadSecurityModeInsufficient:
+        return "BadSecurityModeInsufficient"
+    case BadHistoryOperationInvalid:
+        return "BadHistoryOperationInvalid"
+    case BadHistoryOperationUnsupported:
+        return "BadHistoryOperationUnsupported"
+    case BadInvalidTimestampArgument:
+        return "BadInvalidTimestampArgument"
+    case BadWriteNotSupported:
+        return "BadWriteNotSupported"
+    case BadTypeMismatch:
+        return "BadTypeMismatch"
+    case BadMethodInvalid:
+        return "BadMethodInvalid"
+    case BadArgumentsMissing:
+        return "BadArgumentsMissing"
+    case BadNotExecutable:
+        return "BadNotExecutable"
+    case BadTooManySubscriptions:
+        return "BadTooManySubscriptions"
+    case BadTooManyPublishRequests:
+        return "BadTooManyPublishRequests"
+    case BadNoSubscription:
+        return "BadNoSubscription"
+    case BadSequenceNumberUnknown:
+        return "BadSequenceNumberUnknown"
+    case BadMessageNotAvailable:
+        return "BadMessageNotAvailable"
+    case BadInsufficientClientProfile:
+        return "BadInsufficientClientProfile"
+    case BadStateNotActive:
+        return "BadStateNotActive"
+    case BadAlreadyExists:
+        return "BadAlreadyExists"
+    case BadTCPServerTooBusy:
+        return "BadTcpServerTooBusy"
+    case BadTCPMessageTypeInvalid:
+        return "BadTcpMessageTypeInvalid"
+    case BadTCPSecureChannelUnknown:
+        return "BadTcpSecureChannelUnknown"
+    case BadTCPMessageTooLarge:
+        return "BadTcpMessageTooLarge"
+    case BadTCPNotEnoughResources:
+        return "BadTcpNotEnoughResources"
+    case BadTCPInternalError:
+        return "BadTcpInternalError"
+    case BadTCPEndpointURLInvalid:
+        return "BadTcpEndpointUrlInvalid"
+    case BadRequestInterrupted:
+        return "BadRequestInterrupted"
+    case BadRequestTimeout:
+        return "BadRequestTimeout"
+    case BadSecureChannelClosed:
+        return "BadSecureChannelClosed"
+    case BadSecureChannelTokenUnknown:
+        return "BadSecureChannelTokenUnknown"
+    case BadSequenceNumberInvalid:
+        return "BadSequenceNumberInvalid"
+    case BadProtocolVersionUnsupported:
+        return "BadProtocolVersionUnsupported"
+    case BadConfigurationError:
+        return "BadConfigurationError"
+    case BadNotConnected:
+        return "BadNotConnected"
+    case BadDeviceFailure:
+        return "BadDeviceFailure"
+    case BadSensorFailure:
+        return "BadSensorFailure"
+    case BadOutOfService:
+        return "BadOutOfService"
+    case BadDeadbandFilterInvalid:
+        return "BadDeadbandFilterInvalid"
+    case UncertainNoCommunicationLastUsableValue:
+        return "UncertainNoCommunicationLastUsableValue"
+    case UncertainLastUsableValue:
+        return "UncertainLastUsableValue"
+    case UncertainSubstituteValue:
+        return "UncertainSubstituteValue"
+    case UncertainInitialValue:
+        return "UncertainInitialValue"
+    case UncertainSensorNotAccurate:
+        return "UncertainSensorNotAccurate"
+    case UncertainEngineeringUnitsExceeded:
+        return "UncertainEngineeringUnitsExceeded"
+    case UncertainSubNormal:
+        return "UncertainSubNormal"
+    case GoodLocalOverride:
+        return "GoodLocalOverride"
+    case BadRefreshInProgress:
+        return "BadRefreshInProgress"
+    case BadConditionAlreadyDisabled:
+        return "BadConditionAlreadyDisabled"
+    case BadConditionAlreadyEnabled:
+        return "BadConditionAlreadyEnabled"
+    case BadConditionDisabled:
+        return "BadConditionDisabled"
+    case BadEventIDUnknown:
+        return "BadEventIdUnknown"
+    case BadEventNotAcknowledgeable:
+        return "BadEventNotAcknowledgeable"
+    case BadDialogNotActive:
+        return "BadDialogNotActive"
+    case BadDialogResponseInvalid:
+        return "BadDialogResponseInvalid"
+    case BadConditionBranchAlreadyAcked:
+        return "BadConditionBranchAlreadyAcked"
+    case BadConditionBranchAlreadyConfirmed:
+        return "BadConditionBranchAlreadyConfirmed"
+    case BadConditionAlreadyShelved:
+        return "BadConditionAlreadyShelved"
+    case BadConditionNotShelved:
+        return "BadConditionNotShelved"
+    case BadShelvingTimeOutOfRange:
+        return "BadShelvingTimeOutOfRange"
+    case BadNoData:
+        return "BadNoData"
+    case BadBoundNotFound:
+        return "BadBoundNotFound"
+    case BadBoundNotSupported:
+        return "BadBoundNotSupported"
+    case BadDataLost:
+        return "BadDataLost"
+    case BadDataUnavailable:
+        return "BadDataUnavailable"
+    case BadEntryExists:
+        return "BadEntryExists"
+    case BadNoEntryExists:
+        return "BadNoEntryExists"
+    case BadTimestampNotSupported:
+        return "BadTimestampNotSupported"
+    case GoodEntryInserted:
+        return "GoodEntryInserted"
+    case GoodEntryReplaced:
+        return "GoodEntryReplaced"
+    case UncertainDataSubNormal:
+        return "UncertainDataSubNormal"
+    case GoodNoData:
+        return "GoodNoData"
+    case GoodMoreData:
+        return "GoodMoreData"
+    case BadAggregateListMismatch:
+        return "BadAggregateListMismatch"
+    case BadAggregateNotSupported:
+        return "BadAggregateNotSupported"
+    case BadAggregateInvalidInputs:
+        return "BadAggregateInvalidInputs"
+    case BadAggregateConfigurationRejected:
+        return "BadAggregateConfigurationRejected"
+    case GoodDataIgnored:
+        return "GoodDataIgnored"
+    case BadRequestNotAllowed:
+        return "BadRequestNotAllowed"
+    case BadRequestNotComplete:
+        return "BadRequestNotComplete"
+    case GoodEdited:
+        return "GoodEdited"
+    case GoodPostActionFailed:
+        return "GoodPostActionFailed"
+    case UncertainDominantValueChanged:
+        return "UncertainDominantValueChanged"
+    case GoodDependentValueChanged:
+        return "GoodDependentValueChanged"
+    case BadDominantValueChanged:
+        return "BadDominantValueChanged"
+    case UncertainDependentValueChanged:
+        return "UncertainDependentValueChanged"
+    case BadDependentValueChanged:
+        return "BadDependentValueChanged"
+    case GoodEditedDependentValueChanged:
+        return "GoodEditedDependentValueChanged"
+    case GoodEditedDominantValueChanged:
+        return "GoodEditedDominantValueChanged"
+    case GoodEditedDominantValueChangedDependentValueChanged:
+        return "GoodEditedDominantValueChangedDependentValueChanged"
+    case BadEditedOutOfRange:
+        return "BadEditedOutOfRange"
+    case BadInitialValueOutOfRange:
+        return "BadInitialValueOutOfRange"
+    case BadOutOfRangeDominantValueChanged:
+        return "BadOutOfRangeDominantValueChanged"
+    case BadEditedOutOfRangeDominantValueChanged:
+        return "BadEditedOutOfRangeDominantValueChanged"
+    case BadOutOfRangeDominantValueChangedDependentValueChanged:
+        return "BadOutOfRangeDominantValueChangedDependentValueChanged"
+    case BadEditedOutOfRangeDominantValueChangedDependentValueChanged:
+        return "BadEditedOutOfRangeDominantValueChangedDependentValueChanged"
+    case GoodCommunicationEvent:
+        return "GoodCommunicationEvent"
+    case GoodShutdownEvent:
+        return "GoodShutdownEvent"
+    case GoodCallAgain:
+        return "GoodCallAgain"
+    case GoodNonCriticalTimeout:
+        return "GoodNonCriticalTimeout"
+    case BadInvalidArgument:
+        return "BadInvalidArgument"
+    case BadConnectionRejected:
+        return "BadConnectionRejected"
+    case BadDisconnect:
+        return "BadDisconnect"
+    case BadConnectionClosed:
+        return "BadConnectionClosed"
+    case BadInvalidState:
+        return "BadInvalidState"
+    case BadEndOfStream:
+        return "BadEndOfStream"
+    case BadNoDataAvailable:
+        return "BadNoDataAvailable"
+    case BadWaitingForResponse:
+        return "BadWaitingForResponse"
+    case BadOperationAbandoned:
+        return "BadOperationAbandoned"
+    case BadExpectedStreamToBlock:
+        return "BadExpectedStreamToBlock"
+    case BadWouldBlock:
+        return "BadWouldBlock"
+    case BadSyntaxError:
+        return "BadSyntaxError"
+    case BadMaxConnectionsReached:
+        return "BadMaxConnectionsReached"
+    default:
+        return ""
+    }
+}
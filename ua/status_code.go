@@ -70,12 +70,20 @@ const (
 	LimitBitsConstant uint32 = 0x00000300
 	// Overflow - .
 	Overflow uint32 = 0x00000080
-	// HistorianBitsMask - the mask of bits that pertain to the Historian.
+	// HistorianBitsMask - the mask of bits that pertain to the Historian. The HistorianBits have the values of
+	// https://reference.opcfoundation.org/v104/Core/docs/Part4/7.34.1/ (Breaking change: HistorianBitsInterpolated
+	// was 0x10 and HistorianBitsPartial was 0x100, which are the MultiValue and LimitLow bits.)
 	HistorianBitsMask uint32 = 0x0000001F
+	// HistorianBitsRaw - A raw data value.
+	HistorianBitsRaw uint32 = 0x00000000
 	// HistorianBitsCalculated - A data value which was calculated.
 	HistorianBitsCalculated uint32 = 0x00000001
 	// HistorianBitsInterpolated - A data value which was interpolated.
-	HistorianBitsInterpolated uint32 = 0x00000010
+	HistorianBitsInterpolated uint32 = 0x00000002
 	// HistorianBitsPartial - A data value which was calculated with an incomplete interval.
-	HistorianBitsPartial uint32 = 0x00000100
+	HistorianBitsPartial uint32 = 0x00000004
+	// HistorianBitsExtraData - A raw data value that hides other data at the same timestamp.
+	HistorianBitsExtraData uint32 = 0x00000008
+	// HistorianBitsMultiValue - Multiple values match the aggregate criteria.
+	HistorianBitsMultiValue uint32 = 0x00000010
 )
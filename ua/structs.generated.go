@@ -2771,4 +2771,856 @@ func init() {
         RegisterBinaryEncodingID(reflect.TypeOf((*ProgramDiagnosticDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDProgramDiagnosticDataTypeEncodingDefaultBinary))
         RegisterBinaryEncodingID(reflect.TypeOf((*ProgramDiagnostic2DataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDProgramDiagnostic2DataTypeEncodingDefaultBinary))
         RegisterBinaryEncodingID(reflect.TypeOf((*Annotation)(nil)).Elem(), NewExpandedNodeID(ObjectIDAnnotationEncodingDefaultBinary))
+        RegisterXMLEncodingID(reflect.TypeOf((*KeyValuePair)(nil)).Elem(), NewExpandedNodeID(ObjectIDKeyValuePairEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AdditionalParametersType)(nil)).Elem(), NewExpandedNodeID(ObjectIDAdditionalParametersTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EphemeralKeyType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEphemeralKeyTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EndpointType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RationalNumber)(nil)).Elem(), NewExpandedNodeID(ObjectIDRationalNumberEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*Vector)(nil)).Elem(), NewExpandedNodeID(ObjectIDVectorEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ThreeDVector)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDVectorEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CartesianCoordinates)(nil)).Elem(), NewExpandedNodeID(ObjectIDCartesianCoordinatesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ThreeDCartesianCoordinates)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDCartesianCoordinatesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*Orientation)(nil)).Elem(), NewExpandedNodeID(ObjectIDOrientationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ThreeDOrientation)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDOrientationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*Frame)(nil)).Elem(), NewExpandedNodeID(ObjectIDFrameEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ThreeDFrame)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDFrameEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*IdentityMappingRuleType)(nil)).Elem(), NewExpandedNodeID(ObjectIDIdentityMappingRuleTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CurrencyUnitType)(nil)).Elem(), NewExpandedNodeID(ObjectIDCurrencyUnitTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TrustListDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDTrustListDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DecimalDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDecimalDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataTypeSchemaHeader)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataTypeSchemaHeaderEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataTypeDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataTypeDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*StructureDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDStructureDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EnumDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SimpleTypeDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDSimpleTypeDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UABinaryFileDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUABinaryFileDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataSetMetaDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetMetaDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*FieldMetaData)(nil)).Elem(), NewExpandedNodeID(ObjectIDFieldMetaDataEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ConfigurationVersionDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDConfigurationVersionDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PublishedDataSetDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedDataSetDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PublishedDataSetSourceDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedDataSetSourceDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PublishedVariableDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedVariableDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PublishedDataItemsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedDataItemsDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PublishedEventsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedEventsDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataSetWriterDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetWriterDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataSetWriterTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetWriterTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetWriterMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PubSubGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPubSubGroupDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*WriterGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriterGroupDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*WriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriterGroupTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*WriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriterGroupMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PubSubConnectionDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPubSubConnectionDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDConnectionTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NetworkAddressDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDNetworkAddressDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NetworkAddressURLDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDNetworkAddressURLDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReaderGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDReaderGroupDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReaderGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDReaderGroupTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReaderGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDReaderGroupMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataSetReaderDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetReaderDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataSetReaderTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetReaderTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetReaderMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SubscribedDataSetDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscribedDataSetDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TargetVariablesDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDTargetVariablesDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*FieldTargetDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDFieldTargetDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SubscribedDataSetMirrorDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscribedDataSetMirrorDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PubSubConfigurationDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPubSubConfigurationDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UADPWriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUADPWriterGroupMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UADPDataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUADPDataSetWriterMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UADPDataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUADPDataSetReaderMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*JSONWriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDJSONWriterGroupMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*JSONDataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDJSONDataSetWriterMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*JSONDataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDJSONDataSetReaderMessageDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DatagramConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDatagramConnectionTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DatagramWriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDatagramWriterGroupTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrokerConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerConnectionTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrokerWriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerWriterGroupTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrokerDataSetWriterTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerDataSetWriterTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrokerDataSetReaderTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerDataSetReaderTransportDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AliasNameDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDAliasNameDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RolePermissionType)(nil)).Elem(), NewExpandedNodeID(ObjectIDRolePermissionTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*StructureField)(nil)).Elem(), NewExpandedNodeID(ObjectIDStructureFieldEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*StructureDefinition)(nil)).Elem(), NewExpandedNodeID(ObjectIDStructureDefinitionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EnumDefinition)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumDefinitionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*Argument)(nil)).Elem(), NewExpandedNodeID(ObjectIDArgumentEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EnumValueType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumValueTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EnumField)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumFieldEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*OptionSet)(nil)).Elem(), NewExpandedNodeID(ObjectIDOptionSetEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*Union)(nil)).Elem(), NewExpandedNodeID(ObjectIDUnionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TimeZoneDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDTimeZoneDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ApplicationDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDApplicationDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RequestHeader)(nil)).Elem(), NewExpandedNodeID(ObjectIDRequestHeaderEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ResponseHeader)(nil)).Elem(), NewExpandedNodeID(ObjectIDResponseHeaderEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ServiceFault)(nil)).Elem(), NewExpandedNodeID(ObjectIDServiceFaultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SessionlessInvokeRequestType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionlessInvokeRequestTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SessionlessInvokeResponseType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionlessInvokeResponseTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*FindServersRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*FindServersResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ServerOnNetwork)(nil)).Elem(), NewExpandedNodeID(ObjectIDServerOnNetworkEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*FindServersOnNetworkRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersOnNetworkRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*FindServersOnNetworkResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersOnNetworkResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UserTokenPolicy)(nil)).Elem(), NewExpandedNodeID(ObjectIDUserTokenPolicyEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EndpointDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*GetEndpointsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDGetEndpointsRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*GetEndpointsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDGetEndpointsResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RegisteredServer)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisteredServerEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RegisterServerRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServerRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RegisterServerResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServerResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DiscoveryConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDDiscoveryConfigurationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MdnsDiscoveryConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDMdnsDiscoveryConfigurationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RegisterServer2Request)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServer2RequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RegisterServer2Response)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServer2ResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ChannelSecurityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDChannelSecurityTokenEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*OpenSecureChannelRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDOpenSecureChannelRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*OpenSecureChannelResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDOpenSecureChannelResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CloseSecureChannelRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSecureChannelRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CloseSecureChannelResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSecureChannelResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SignedSoftwareCertificate)(nil)).Elem(), NewExpandedNodeID(ObjectIDSignedSoftwareCertificateEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SignatureData)(nil)).Elem(), NewExpandedNodeID(ObjectIDSignatureDataEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CreateSessionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSessionRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CreateSessionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSessionResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UserIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDUserIdentityTokenEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AnonymousIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDAnonymousIdentityTokenEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UserNameIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDUserNameIdentityTokenEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*X509IdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDX509IdentityTokenEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*IssuedIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDIssuedIdentityTokenEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ActivateSessionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDActivateSessionRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ActivateSessionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDActivateSessionResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CloseSessionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSessionRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CloseSessionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSessionResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CancelRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCancelRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CancelResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCancelResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NodeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDNodeAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ObjectAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDObjectAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*VariableAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDVariableAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MethodAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDMethodAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ObjectTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDObjectTypeAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*VariableTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDVariableTypeAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReferenceTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDReferenceTypeAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataTypeAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ViewAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDViewAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*GenericAttributeValue)(nil)).Elem(), NewExpandedNodeID(ObjectIDGenericAttributeValueEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*GenericAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDGenericAttributesEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AddNodesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesItemEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AddNodesResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AddNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AddNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AddReferencesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddReferencesItemEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AddReferencesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddReferencesRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AddReferencesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddReferencesResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteNodesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteNodesItemEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteNodesRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteNodesResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteReferencesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteReferencesItemEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteReferencesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteReferencesRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteReferencesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteReferencesResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ViewDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDViewDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowseDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReferenceDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDReferenceDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowseResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowseRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowseResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowseNextRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseNextRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowseNextResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseNextResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RelativePathElement)(nil)).Elem(), NewExpandedNodeID(ObjectIDRelativePathElementEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RelativePath)(nil)).Elem(), NewExpandedNodeID(ObjectIDRelativePathEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowsePath)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowsePathEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowsePathTarget)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowsePathTargetEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BrowsePathResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowsePathResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TranslateBrowsePathsToNodeIDsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDTranslateBrowsePathsToNodeIDsRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TranslateBrowsePathsToNodeIDsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDTranslateBrowsePathsToNodeIDsResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RegisterNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterNodesRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RegisterNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterNodesResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UnregisterNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDUnregisterNodesRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UnregisterNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDUnregisterNodesResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EndpointConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointConfigurationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*QueryDataDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryDataDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NodeTypeDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDNodeTypeDescriptionEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*QueryDataSet)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryDataSetEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NodeReference)(nil)).Elem(), NewExpandedNodeID(ObjectIDNodeReferenceEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ContentFilterElement)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterElementEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ContentFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*FilterOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDFilterOperandEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ElementOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDElementOperandEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*LiteralOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDLiteralOperandEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AttributeOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDAttributeOperandEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SimpleAttributeOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDSimpleAttributeOperandEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ContentFilterElementResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterElementResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ContentFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ParsingResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDParsingResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*QueryFirstRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryFirstRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*QueryFirstResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryFirstResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*QueryNextRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryNextRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*QueryNextResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryNextResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadValueID)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadValueIDEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryReadValueID)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadValueIDEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryReadResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryReadDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadEventDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadEventDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadRawModifiedDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadRawModifiedDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadProcessedDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadProcessedDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadAtTimeDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadAtTimeDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ReadAnnotationDataDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadAnnotationDataDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryData)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryDataEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ModificationInfo)(nil)).Elem(), NewExpandedNodeID(ObjectIDModificationInfoEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryModifiedData)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryModifiedDataEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryEvent)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryEventEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryReadRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryReadResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*WriteValue)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriteValueEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*WriteRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriteRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*WriteResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriteResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryUpdateDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UpdateDataDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDUpdateDataDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UpdateStructureDataDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDUpdateStructureDataDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*UpdateEventDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDUpdateEventDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteRawModifiedDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteRawModifiedDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteAtTimeDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteAtTimeDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteEventDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteEventDetailsEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryUpdateResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryUpdateRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryUpdateResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CallMethodRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallMethodRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CallMethodResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallMethodResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CallRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CallResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoringFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoringFilterEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataChangeFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataChangeFilterEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EventFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventFilterEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AggregateConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDAggregateConfigurationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AggregateFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDAggregateFilterEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoringFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoringFilterResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EventFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventFilterResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AggregateFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDAggregateFilterResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoringParameters)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoringParametersEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoredItemCreateRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemCreateRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoredItemCreateResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemCreateResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CreateMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateMonitoredItemsRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CreateMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateMonitoredItemsResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoredItemModifyRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemModifyRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoredItemModifyResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemModifyResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ModifyMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifyMonitoredItemsRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ModifyMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifyMonitoredItemsResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SetMonitoringModeRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetMonitoringModeRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SetMonitoringModeResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetMonitoringModeResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SetTriggeringRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetTriggeringRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SetTriggeringResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetTriggeringResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteMonitoredItemsRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteMonitoredItemsResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CreateSubscriptionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSubscriptionRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*CreateSubscriptionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSubscriptionResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ModifySubscriptionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifySubscriptionRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ModifySubscriptionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifySubscriptionResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SetPublishingModeRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetPublishingModeRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SetPublishingModeResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetPublishingModeResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NotificationMessage)(nil)).Elem(), NewExpandedNodeID(ObjectIDNotificationMessageEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NotificationData)(nil)).Elem(), NewExpandedNodeID(ObjectIDNotificationDataEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DataChangeNotification)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataChangeNotificationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*MonitoredItemNotification)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemNotificationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EventNotificationList)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventNotificationListEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EventFieldList)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventFieldListEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*HistoryEventFieldList)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryEventFieldListEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*StatusChangeNotification)(nil)).Elem(), NewExpandedNodeID(ObjectIDStatusChangeNotificationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SubscriptionAcknowledgement)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscriptionAcknowledgementEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PublishRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*PublishResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RepublishRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDRepublishRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RepublishResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDRepublishResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TransferResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDTransferResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TransferSubscriptionsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDTransferSubscriptionsRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*TransferSubscriptionsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDTransferSubscriptionsResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteSubscriptionsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteSubscriptionsRequestEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DeleteSubscriptionsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteSubscriptionsResponseEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*BuildInfo)(nil)).Elem(), NewExpandedNodeID(ObjectIDBuildInfoEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*RedundantServerDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDRedundantServerDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EndpointURLListDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointURLListDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*NetworkGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDNetworkGroupDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SamplingIntervalDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSamplingIntervalDiagnosticsDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ServerDiagnosticsSummaryDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDServerDiagnosticsSummaryDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ServerStatusDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDServerStatusDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SessionDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionDiagnosticsDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SessionSecurityDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionSecurityDiagnosticsDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ServiceCounterDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDServiceCounterDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*StatusResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDStatusResultEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SubscriptionDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscriptionDiagnosticsDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ModelChangeStructureDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDModelChangeStructureDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*SemanticChangeStructureDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSemanticChangeStructureDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*Range)(nil)).Elem(), NewExpandedNodeID(ObjectIDRangeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*EUInformation)(nil)).Elem(), NewExpandedNodeID(ObjectIDEUInformationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ComplexNumberType)(nil)).Elem(), NewExpandedNodeID(ObjectIDComplexNumberTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*DoubleComplexNumberType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDoubleComplexNumberTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*AxisInformation)(nil)).Elem(), NewExpandedNodeID(ObjectIDAxisInformationEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*XVType)(nil)).Elem(), NewExpandedNodeID(ObjectIDXVTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ProgramDiagnosticDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDProgramDiagnosticDataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*ProgramDiagnostic2DataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDProgramDiagnostic2DataTypeEncodingDefaultXML))
+        RegisterXMLEncodingID(reflect.TypeOf((*Annotation)(nil)).Elem(), NewExpandedNodeID(ObjectIDAnnotationEncodingDefaultXML))
+        RegisterJSONEncodingID(reflect.TypeOf((*KeyValuePair)(nil)).Elem(), NewExpandedNodeID(ObjectIDKeyValuePairEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*KeyValuePair)(nil)).Elem(), NewExpandedNodeID(DataTypeIDKeyValuePair))
+        RegisterJSONEncodingID(reflect.TypeOf((*AdditionalParametersType)(nil)).Elem(), NewExpandedNodeID(ObjectIDAdditionalParametersTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AdditionalParametersType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAdditionalParametersType))
+        RegisterJSONEncodingID(reflect.TypeOf((*EphemeralKeyType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEphemeralKeyTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EphemeralKeyType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEphemeralKeyType))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEndpointType))
+        RegisterJSONEncodingID(reflect.TypeOf((*RationalNumber)(nil)).Elem(), NewExpandedNodeID(ObjectIDRationalNumberEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RationalNumber)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRationalNumber))
+        RegisterJSONEncodingID(reflect.TypeOf((*Vector)(nil)).Elem(), NewExpandedNodeID(ObjectIDVectorEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*Vector)(nil)).Elem(), NewExpandedNodeID(DataTypeIDVector))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDVector)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDVectorEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDVector)(nil)).Elem(), NewExpandedNodeID(DataTypeIDThreeDVector))
+        RegisterJSONEncodingID(reflect.TypeOf((*CartesianCoordinates)(nil)).Elem(), NewExpandedNodeID(ObjectIDCartesianCoordinatesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CartesianCoordinates)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCartesianCoordinates))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDCartesianCoordinates)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDCartesianCoordinatesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDCartesianCoordinates)(nil)).Elem(), NewExpandedNodeID(DataTypeIDThreeDCartesianCoordinates))
+        RegisterJSONEncodingID(reflect.TypeOf((*Orientation)(nil)).Elem(), NewExpandedNodeID(ObjectIDOrientationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*Orientation)(nil)).Elem(), NewExpandedNodeID(DataTypeIDOrientation))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDOrientation)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDOrientationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDOrientation)(nil)).Elem(), NewExpandedNodeID(DataTypeIDThreeDOrientation))
+        RegisterJSONEncodingID(reflect.TypeOf((*Frame)(nil)).Elem(), NewExpandedNodeID(ObjectIDFrameEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*Frame)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFrame))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDFrame)(nil)).Elem(), NewExpandedNodeID(ObjectIDThreeDFrameEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ThreeDFrame)(nil)).Elem(), NewExpandedNodeID(DataTypeIDThreeDFrame))
+        RegisterJSONEncodingID(reflect.TypeOf((*IdentityMappingRuleType)(nil)).Elem(), NewExpandedNodeID(ObjectIDIdentityMappingRuleTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*IdentityMappingRuleType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDIdentityMappingRuleType))
+        RegisterJSONEncodingID(reflect.TypeOf((*CurrencyUnitType)(nil)).Elem(), NewExpandedNodeID(ObjectIDCurrencyUnitTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CurrencyUnitType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCurrencyUnitType))
+        RegisterJSONEncodingID(reflect.TypeOf((*TrustListDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDTrustListDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TrustListDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTrustListDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DecimalDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDecimalDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DecimalDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDecimalDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataTypeSchemaHeader)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataTypeSchemaHeaderEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataTypeSchemaHeader)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataTypeSchemaHeader))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataTypeDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataTypeDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataTypeDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataTypeDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*StructureDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDStructureDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*StructureDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDStructureDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEnumDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*SimpleTypeDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDSimpleTypeDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SimpleTypeDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSimpleTypeDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*UABinaryFileDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUABinaryFileDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UABinaryFileDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUABinaryFileDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetMetaDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetMetaDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetMetaDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataSetMetaDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*FieldMetaData)(nil)).Elem(), NewExpandedNodeID(ObjectIDFieldMetaDataEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*FieldMetaData)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFieldMetaData))
+        RegisterJSONEncodingID(reflect.TypeOf((*ConfigurationVersionDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDConfigurationVersionDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ConfigurationVersionDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDConfigurationVersionDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedDataSetDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedDataSetDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedDataSetDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPublishedDataSetDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedDataSetSourceDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedDataSetSourceDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedDataSetSourceDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPublishedDataSetSourceDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedVariableDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedVariableDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedVariableDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPublishedVariableDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedDataItemsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedDataItemsDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedDataItemsDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPublishedDataItemsDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedEventsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishedEventsDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishedEventsDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPublishedEventsDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetWriterDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetWriterDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetWriterDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataSetWriterDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetWriterTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetWriterTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetWriterTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataSetWriterTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetWriterMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataSetWriterMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PubSubGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPubSubGroupDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PubSubGroupDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPubSubGroupDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriterGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriterGroupDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriterGroupDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDWriterGroupDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriterGroupTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDWriterGroupTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriterGroupMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDWriterGroupMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PubSubConnectionDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPubSubConnectionDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PubSubConnectionDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPubSubConnectionDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDConnectionTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDConnectionTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*NetworkAddressDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDNetworkAddressDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NetworkAddressDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNetworkAddressDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*NetworkAddressURLDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDNetworkAddressURLDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NetworkAddressURLDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNetworkAddressURLDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReaderGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDReaderGroupDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReaderGroupDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReaderGroupDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReaderGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDReaderGroupTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReaderGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReaderGroupTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReaderGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDReaderGroupMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReaderGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReaderGroupMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetReaderDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetReaderDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetReaderDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataSetReaderDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetReaderTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetReaderTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetReaderTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataSetReaderTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataSetReaderMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataSetReaderMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscribedDataSetDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscribedDataSetDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscribedDataSetDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSubscribedDataSetDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*TargetVariablesDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDTargetVariablesDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TargetVariablesDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTargetVariablesDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*FieldTargetDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDFieldTargetDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*FieldTargetDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFieldTargetDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscribedDataSetMirrorDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscribedDataSetMirrorDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscribedDataSetMirrorDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSubscribedDataSetMirrorDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*PubSubConfigurationDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDPubSubConfigurationDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PubSubConfigurationDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPubSubConfigurationDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*UADPWriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUADPWriterGroupMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UADPWriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUADPWriterGroupMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*UADPDataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUADPDataSetWriterMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UADPDataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUADPDataSetWriterMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*UADPDataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDUADPDataSetReaderMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UADPDataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUADPDataSetReaderMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*JSONWriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDJSONWriterGroupMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*JSONWriterGroupMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDJSONWriterGroupMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*JSONDataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDJSONDataSetWriterMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*JSONDataSetWriterMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDJSONDataSetWriterMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*JSONDataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDJSONDataSetReaderMessageDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*JSONDataSetReaderMessageDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDJSONDataSetReaderMessageDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DatagramConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDatagramConnectionTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DatagramConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDatagramConnectionTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DatagramWriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDatagramWriterGroupTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DatagramWriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDatagramWriterGroupTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerConnectionTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerConnectionTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrokerConnectionTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerWriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerWriterGroupTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerWriterGroupTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrokerWriterGroupTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerDataSetWriterTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerDataSetWriterTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerDataSetWriterTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrokerDataSetWriterTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerDataSetReaderTransportDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrokerDataSetReaderTransportDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrokerDataSetReaderTransportDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrokerDataSetReaderTransportDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*AliasNameDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDAliasNameDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AliasNameDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAliasNameDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*RolePermissionType)(nil)).Elem(), NewExpandedNodeID(ObjectIDRolePermissionTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RolePermissionType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRolePermissionType))
+        RegisterJSONEncodingID(reflect.TypeOf((*StructureField)(nil)).Elem(), NewExpandedNodeID(ObjectIDStructureFieldEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*StructureField)(nil)).Elem(), NewExpandedNodeID(DataTypeIDStructureField))
+        RegisterJSONEncodingID(reflect.TypeOf((*StructureDefinition)(nil)).Elem(), NewExpandedNodeID(ObjectIDStructureDefinitionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*StructureDefinition)(nil)).Elem(), NewExpandedNodeID(DataTypeIDStructureDefinition))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumDefinition)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumDefinitionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumDefinition)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEnumDefinition))
+        RegisterJSONEncodingID(reflect.TypeOf((*Argument)(nil)).Elem(), NewExpandedNodeID(ObjectIDArgumentEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*Argument)(nil)).Elem(), NewExpandedNodeID(DataTypeIDArgument))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumValueType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumValueTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumValueType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEnumValueType))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumField)(nil)).Elem(), NewExpandedNodeID(ObjectIDEnumFieldEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EnumField)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEnumField))
+        RegisterJSONEncodingID(reflect.TypeOf((*OptionSet)(nil)).Elem(), NewExpandedNodeID(ObjectIDOptionSetEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*OptionSet)(nil)).Elem(), NewExpandedNodeID(DataTypeIDOptionSet))
+        RegisterJSONEncodingID(reflect.TypeOf((*Union)(nil)).Elem(), NewExpandedNodeID(ObjectIDUnionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*Union)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUnion))
+        RegisterJSONEncodingID(reflect.TypeOf((*TimeZoneDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDTimeZoneDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TimeZoneDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTimeZoneDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ApplicationDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDApplicationDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ApplicationDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDApplicationDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*RequestHeader)(nil)).Elem(), NewExpandedNodeID(ObjectIDRequestHeaderEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RequestHeader)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRequestHeader))
+        RegisterJSONEncodingID(reflect.TypeOf((*ResponseHeader)(nil)).Elem(), NewExpandedNodeID(ObjectIDResponseHeaderEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ResponseHeader)(nil)).Elem(), NewExpandedNodeID(DataTypeIDResponseHeader))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServiceFault)(nil)).Elem(), NewExpandedNodeID(ObjectIDServiceFaultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServiceFault)(nil)).Elem(), NewExpandedNodeID(DataTypeIDServiceFault))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionlessInvokeRequestType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionlessInvokeRequestTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionlessInvokeRequestType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSessionlessInvokeRequestType))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionlessInvokeResponseType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionlessInvokeResponseTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionlessInvokeResponseType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSessionlessInvokeResponseType))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFindServersRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFindServersResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServerOnNetwork)(nil)).Elem(), NewExpandedNodeID(ObjectIDServerOnNetworkEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServerOnNetwork)(nil)).Elem(), NewExpandedNodeID(DataTypeIDServerOnNetwork))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersOnNetworkRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersOnNetworkRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersOnNetworkRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFindServersOnNetworkRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersOnNetworkResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDFindServersOnNetworkResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*FindServersOnNetworkResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFindServersOnNetworkResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*UserTokenPolicy)(nil)).Elem(), NewExpandedNodeID(ObjectIDUserTokenPolicyEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UserTokenPolicy)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUserTokenPolicy))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEndpointDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*GetEndpointsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDGetEndpointsRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*GetEndpointsRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDGetEndpointsRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*GetEndpointsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDGetEndpointsResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*GetEndpointsResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDGetEndpointsResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisteredServer)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisteredServerEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisteredServer)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRegisteredServer))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServerRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServerRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServerRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRegisterServerRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServerResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServerResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServerResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRegisterServerResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*DiscoveryConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDDiscoveryConfigurationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DiscoveryConfiguration)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDiscoveryConfiguration))
+        RegisterJSONEncodingID(reflect.TypeOf((*MdnsDiscoveryConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDMdnsDiscoveryConfigurationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MdnsDiscoveryConfiguration)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMdnsDiscoveryConfiguration))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServer2Request)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServer2RequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServer2Request)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRegisterServer2Request))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServer2Response)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterServer2ResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterServer2Response)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRegisterServer2Response))
+        RegisterJSONEncodingID(reflect.TypeOf((*ChannelSecurityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDChannelSecurityTokenEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ChannelSecurityToken)(nil)).Elem(), NewExpandedNodeID(DataTypeIDChannelSecurityToken))
+        RegisterJSONEncodingID(reflect.TypeOf((*OpenSecureChannelRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDOpenSecureChannelRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*OpenSecureChannelRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDOpenSecureChannelRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*OpenSecureChannelResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDOpenSecureChannelResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*OpenSecureChannelResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDOpenSecureChannelResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSecureChannelRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSecureChannelRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSecureChannelRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCloseSecureChannelRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSecureChannelResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSecureChannelResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSecureChannelResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCloseSecureChannelResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*SignedSoftwareCertificate)(nil)).Elem(), NewExpandedNodeID(ObjectIDSignedSoftwareCertificateEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SignedSoftwareCertificate)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSignedSoftwareCertificate))
+        RegisterJSONEncodingID(reflect.TypeOf((*SignatureData)(nil)).Elem(), NewExpandedNodeID(ObjectIDSignatureDataEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SignatureData)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSignatureData))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSessionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSessionRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSessionRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCreateSessionRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSessionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSessionResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSessionResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCreateSessionResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*UserIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDUserIdentityTokenEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UserIdentityToken)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUserIdentityToken))
+        RegisterJSONEncodingID(reflect.TypeOf((*AnonymousIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDAnonymousIdentityTokenEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AnonymousIdentityToken)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAnonymousIdentityToken))
+        RegisterJSONEncodingID(reflect.TypeOf((*UserNameIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDUserNameIdentityTokenEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UserNameIdentityToken)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUserNameIdentityToken))
+        RegisterJSONEncodingID(reflect.TypeOf((*X509IdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDX509IdentityTokenEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*X509IdentityToken)(nil)).Elem(), NewExpandedNodeID(DataTypeIDX509IdentityToken))
+        RegisterJSONEncodingID(reflect.TypeOf((*IssuedIdentityToken)(nil)).Elem(), NewExpandedNodeID(ObjectIDIssuedIdentityTokenEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*IssuedIdentityToken)(nil)).Elem(), NewExpandedNodeID(DataTypeIDIssuedIdentityToken))
+        RegisterJSONEncodingID(reflect.TypeOf((*ActivateSessionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDActivateSessionRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ActivateSessionRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDActivateSessionRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*ActivateSessionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDActivateSessionResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ActivateSessionResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDActivateSessionResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSessionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSessionRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSessionRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCloseSessionRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSessionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCloseSessionResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CloseSessionResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCloseSessionResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*CancelRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCancelRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CancelRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCancelRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CancelResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCancelResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CancelResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCancelResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*NodeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDNodeAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NodeAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNodeAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*ObjectAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDObjectAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ObjectAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDObjectAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*VariableAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDVariableAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*VariableAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDVariableAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*MethodAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDMethodAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MethodAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMethodAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*ObjectTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDObjectTypeAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ObjectTypeAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDObjectTypeAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*VariableTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDVariableTypeAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*VariableTypeAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDVariableTypeAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReferenceTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDReferenceTypeAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReferenceTypeAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReferenceTypeAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataTypeAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataTypeAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataTypeAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataTypeAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*ViewAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDViewAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ViewAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDViewAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*GenericAttributeValue)(nil)).Elem(), NewExpandedNodeID(ObjectIDGenericAttributeValueEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*GenericAttributeValue)(nil)).Elem(), NewExpandedNodeID(DataTypeIDGenericAttributeValue))
+        RegisterJSONEncodingID(reflect.TypeOf((*GenericAttributes)(nil)).Elem(), NewExpandedNodeID(ObjectIDGenericAttributesEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*GenericAttributes)(nil)).Elem(), NewExpandedNodeID(DataTypeIDGenericAttributes))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesItemEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesItem)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAddNodesItem))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAddNodesResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAddNodesRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddNodesResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddNodesResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAddNodesResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddReferencesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddReferencesItemEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddReferencesItem)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAddReferencesItem))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddReferencesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddReferencesRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddReferencesRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAddReferencesRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddReferencesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDAddReferencesResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AddReferencesResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAddReferencesResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteNodesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteNodesItemEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteNodesItem)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteNodesItem))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteNodesRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteNodesRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteNodesRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteNodesResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteNodesResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteNodesResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteReferencesItem)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteReferencesItemEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteReferencesItem)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteReferencesItem))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteReferencesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteReferencesRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteReferencesRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteReferencesRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteReferencesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteReferencesResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteReferencesResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteReferencesResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*ViewDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDViewDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ViewDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDViewDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowseDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReferenceDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDReferenceDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReferenceDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReferenceDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowseResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowseRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowseResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseNextRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseNextRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseNextRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowseNextRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseNextResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowseNextResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowseNextResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowseNextResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*RelativePathElement)(nil)).Elem(), NewExpandedNodeID(ObjectIDRelativePathElementEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RelativePathElement)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRelativePathElement))
+        RegisterJSONEncodingID(reflect.TypeOf((*RelativePath)(nil)).Elem(), NewExpandedNodeID(ObjectIDRelativePathEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RelativePath)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRelativePath))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowsePath)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowsePathEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowsePath)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowsePath))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowsePathTarget)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowsePathTargetEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowsePathTarget)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowsePathTarget))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowsePathResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDBrowsePathResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BrowsePathResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBrowsePathResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*TranslateBrowsePathsToNodeIDsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDTranslateBrowsePathsToNodeIDsRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TranslateBrowsePathsToNodeIDsRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTranslateBrowsePathsToNodeIDsRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*TranslateBrowsePathsToNodeIDsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDTranslateBrowsePathsToNodeIDsResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TranslateBrowsePathsToNodeIDsResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTranslateBrowsePathsToNodeIDsResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterNodesRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterNodesRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRegisterNodesRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDRegisterNodesResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RegisterNodesResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRegisterNodesResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*UnregisterNodesRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDUnregisterNodesRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UnregisterNodesRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUnregisterNodesRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*UnregisterNodesResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDUnregisterNodesResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UnregisterNodesResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUnregisterNodesResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointConfigurationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointConfiguration)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEndpointConfiguration))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryDataDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryDataDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryDataDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDQueryDataDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*NodeTypeDescription)(nil)).Elem(), NewExpandedNodeID(ObjectIDNodeTypeDescriptionEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NodeTypeDescription)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNodeTypeDescription))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryDataSet)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryDataSetEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryDataSet)(nil)).Elem(), NewExpandedNodeID(DataTypeIDQueryDataSet))
+        RegisterJSONEncodingID(reflect.TypeOf((*NodeReference)(nil)).Elem(), NewExpandedNodeID(ObjectIDNodeReferenceEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NodeReference)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNodeReference))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilterElement)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterElementEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilterElement)(nil)).Elem(), NewExpandedNodeID(DataTypeIDContentFilterElement))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilter)(nil)).Elem(), NewExpandedNodeID(DataTypeIDContentFilter))
+        RegisterJSONEncodingID(reflect.TypeOf((*FilterOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDFilterOperandEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*FilterOperand)(nil)).Elem(), NewExpandedNodeID(DataTypeIDFilterOperand))
+        RegisterJSONEncodingID(reflect.TypeOf((*ElementOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDElementOperandEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ElementOperand)(nil)).Elem(), NewExpandedNodeID(DataTypeIDElementOperand))
+        RegisterJSONEncodingID(reflect.TypeOf((*LiteralOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDLiteralOperandEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*LiteralOperand)(nil)).Elem(), NewExpandedNodeID(DataTypeIDLiteralOperand))
+        RegisterJSONEncodingID(reflect.TypeOf((*AttributeOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDAttributeOperandEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AttributeOperand)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAttributeOperand))
+        RegisterJSONEncodingID(reflect.TypeOf((*SimpleAttributeOperand)(nil)).Elem(), NewExpandedNodeID(ObjectIDSimpleAttributeOperandEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SimpleAttributeOperand)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSimpleAttributeOperand))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilterElementResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterElementResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilterElementResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDContentFilterElementResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDContentFilterResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ContentFilterResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDContentFilterResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*ParsingResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDParsingResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ParsingResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDParsingResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryFirstRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryFirstRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryFirstRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDQueryFirstRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryFirstResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryFirstResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryFirstResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDQueryFirstResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryNextRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryNextRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryNextRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDQueryNextRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryNextResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDQueryNextResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*QueryNextResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDQueryNextResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadValueID)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadValueIDEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadValueID)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadValueID))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadValueID)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadValueIDEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadValueID)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryReadValueID))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryReadResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryReadDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadEventDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadEventDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadEventDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadEventDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadRawModifiedDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadRawModifiedDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadRawModifiedDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadRawModifiedDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadProcessedDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadProcessedDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadProcessedDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadProcessedDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadAtTimeDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadAtTimeDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadAtTimeDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadAtTimeDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadAnnotationDataDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDReadAnnotationDataDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ReadAnnotationDataDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDReadAnnotationDataDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryData)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryDataEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryData)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryData))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModificationInfo)(nil)).Elem(), NewExpandedNodeID(ObjectIDModificationInfoEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModificationInfo)(nil)).Elem(), NewExpandedNodeID(DataTypeIDModificationInfo))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryModifiedData)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryModifiedDataEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryModifiedData)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryModifiedData))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryEvent)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryEventEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryEvent)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryEvent))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryReadRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryReadResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryReadResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryReadResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriteValue)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriteValueEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriteValue)(nil)).Elem(), NewExpandedNodeID(DataTypeIDWriteValue))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriteRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriteRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriteRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDWriteRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriteResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDWriteResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*WriteResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDWriteResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryUpdateDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*UpdateDataDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDUpdateDataDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UpdateDataDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUpdateDataDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*UpdateStructureDataDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDUpdateStructureDataDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UpdateStructureDataDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUpdateStructureDataDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*UpdateEventDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDUpdateEventDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*UpdateEventDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDUpdateEventDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteRawModifiedDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteRawModifiedDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteRawModifiedDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteRawModifiedDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteAtTimeDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteAtTimeDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteAtTimeDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteAtTimeDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteEventDetails)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteEventDetailsEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteEventDetails)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteEventDetails))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryUpdateResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryUpdateRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryUpdateResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryUpdateResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryUpdateResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallMethodRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallMethodRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallMethodRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCallMethodRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallMethodResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallMethodResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallMethodResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCallMethodResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCallRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCallResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CallResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCallResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoringFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoringFilterEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoringFilter)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoringFilter))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataChangeFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataChangeFilterEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataChangeFilter)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataChangeFilter))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventFilterEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventFilter)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEventFilter))
+        RegisterJSONEncodingID(reflect.TypeOf((*AggregateConfiguration)(nil)).Elem(), NewExpandedNodeID(ObjectIDAggregateConfigurationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AggregateConfiguration)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAggregateConfiguration))
+        RegisterJSONEncodingID(reflect.TypeOf((*AggregateFilter)(nil)).Elem(), NewExpandedNodeID(ObjectIDAggregateFilterEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AggregateFilter)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAggregateFilter))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoringFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoringFilterResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoringFilterResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoringFilterResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventFilterResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventFilterResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEventFilterResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*AggregateFilterResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDAggregateFilterResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AggregateFilterResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAggregateFilterResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoringParameters)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoringParametersEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoringParameters)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoringParameters))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemCreateRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemCreateRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemCreateRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoredItemCreateRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemCreateResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemCreateResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemCreateResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoredItemCreateResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateMonitoredItemsRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCreateMonitoredItemsRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateMonitoredItemsResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCreateMonitoredItemsResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemModifyRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemModifyRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemModifyRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoredItemModifyRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemModifyResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemModifyResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemModifyResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoredItemModifyResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifyMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifyMonitoredItemsRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifyMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDModifyMonitoredItemsRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifyMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifyMonitoredItemsResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifyMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDModifyMonitoredItemsResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetMonitoringModeRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetMonitoringModeRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetMonitoringModeRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSetMonitoringModeRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetMonitoringModeResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetMonitoringModeResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetMonitoringModeResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSetMonitoringModeResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetTriggeringRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetTriggeringRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetTriggeringRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSetTriggeringRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetTriggeringResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetTriggeringResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetTriggeringResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSetTriggeringResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteMonitoredItemsRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteMonitoredItemsRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteMonitoredItemsRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteMonitoredItemsResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteMonitoredItemsResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteMonitoredItemsResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSubscriptionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSubscriptionRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSubscriptionRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCreateSubscriptionRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSubscriptionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDCreateSubscriptionResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*CreateSubscriptionResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDCreateSubscriptionResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifySubscriptionRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifySubscriptionRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifySubscriptionRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDModifySubscriptionRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifySubscriptionResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDModifySubscriptionResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModifySubscriptionResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDModifySubscriptionResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetPublishingModeRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetPublishingModeRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetPublishingModeRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSetPublishingModeRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetPublishingModeResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDSetPublishingModeResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SetPublishingModeResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSetPublishingModeResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*NotificationMessage)(nil)).Elem(), NewExpandedNodeID(ObjectIDNotificationMessageEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NotificationMessage)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNotificationMessage))
+        RegisterJSONEncodingID(reflect.TypeOf((*NotificationData)(nil)).Elem(), NewExpandedNodeID(ObjectIDNotificationDataEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NotificationData)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNotificationData))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataChangeNotification)(nil)).Elem(), NewExpandedNodeID(ObjectIDDataChangeNotificationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DataChangeNotification)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDataChangeNotification))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemNotification)(nil)).Elem(), NewExpandedNodeID(ObjectIDMonitoredItemNotificationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*MonitoredItemNotification)(nil)).Elem(), NewExpandedNodeID(DataTypeIDMonitoredItemNotification))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventNotificationList)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventNotificationListEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventNotificationList)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEventNotificationList))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventFieldList)(nil)).Elem(), NewExpandedNodeID(ObjectIDEventFieldListEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EventFieldList)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEventFieldList))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryEventFieldList)(nil)).Elem(), NewExpandedNodeID(ObjectIDHistoryEventFieldListEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*HistoryEventFieldList)(nil)).Elem(), NewExpandedNodeID(DataTypeIDHistoryEventFieldList))
+        RegisterJSONEncodingID(reflect.TypeOf((*StatusChangeNotification)(nil)).Elem(), NewExpandedNodeID(ObjectIDStatusChangeNotificationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*StatusChangeNotification)(nil)).Elem(), NewExpandedNodeID(DataTypeIDStatusChangeNotification))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscriptionAcknowledgement)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscriptionAcknowledgementEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscriptionAcknowledgement)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSubscriptionAcknowledgement))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPublishRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDPublishResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*PublishResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDPublishResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*RepublishRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDRepublishRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RepublishRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRepublishRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*RepublishResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDRepublishResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RepublishResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRepublishResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*TransferResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDTransferResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TransferResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTransferResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*TransferSubscriptionsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDTransferSubscriptionsRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TransferSubscriptionsRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTransferSubscriptionsRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*TransferSubscriptionsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDTransferSubscriptionsResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*TransferSubscriptionsResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDTransferSubscriptionsResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteSubscriptionsRequest)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteSubscriptionsRequestEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteSubscriptionsRequest)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteSubscriptionsRequest))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteSubscriptionsResponse)(nil)).Elem(), NewExpandedNodeID(ObjectIDDeleteSubscriptionsResponseEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DeleteSubscriptionsResponse)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDeleteSubscriptionsResponse))
+        RegisterJSONEncodingID(reflect.TypeOf((*BuildInfo)(nil)).Elem(), NewExpandedNodeID(ObjectIDBuildInfoEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*BuildInfo)(nil)).Elem(), NewExpandedNodeID(DataTypeIDBuildInfo))
+        RegisterJSONEncodingID(reflect.TypeOf((*RedundantServerDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDRedundantServerDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*RedundantServerDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRedundantServerDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointURLListDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDEndpointURLListDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EndpointURLListDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEndpointURLListDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*NetworkGroupDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDNetworkGroupDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*NetworkGroupDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDNetworkGroupDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*SamplingIntervalDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSamplingIntervalDiagnosticsDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SamplingIntervalDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSamplingIntervalDiagnosticsDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServerDiagnosticsSummaryDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDServerDiagnosticsSummaryDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServerDiagnosticsSummaryDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDServerDiagnosticsSummaryDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServerStatusDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDServerStatusDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServerStatusDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDServerStatusDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionDiagnosticsDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSessionDiagnosticsDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionSecurityDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSessionSecurityDiagnosticsDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SessionSecurityDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSessionSecurityDiagnosticsDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServiceCounterDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDServiceCounterDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ServiceCounterDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDServiceCounterDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*StatusResult)(nil)).Elem(), NewExpandedNodeID(ObjectIDStatusResultEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*StatusResult)(nil)).Elem(), NewExpandedNodeID(DataTypeIDStatusResult))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscriptionDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSubscriptionDiagnosticsDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SubscriptionDiagnosticsDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSubscriptionDiagnosticsDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModelChangeStructureDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDModelChangeStructureDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ModelChangeStructureDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDModelChangeStructureDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*SemanticChangeStructureDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDSemanticChangeStructureDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*SemanticChangeStructureDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDSemanticChangeStructureDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*Range)(nil)).Elem(), NewExpandedNodeID(ObjectIDRangeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*Range)(nil)).Elem(), NewExpandedNodeID(DataTypeIDRange))
+        RegisterJSONEncodingID(reflect.TypeOf((*EUInformation)(nil)).Elem(), NewExpandedNodeID(ObjectIDEUInformationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*EUInformation)(nil)).Elem(), NewExpandedNodeID(DataTypeIDEUInformation))
+        RegisterJSONEncodingID(reflect.TypeOf((*ComplexNumberType)(nil)).Elem(), NewExpandedNodeID(ObjectIDComplexNumberTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ComplexNumberType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDComplexNumberType))
+        RegisterJSONEncodingID(reflect.TypeOf((*DoubleComplexNumberType)(nil)).Elem(), NewExpandedNodeID(ObjectIDDoubleComplexNumberTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*DoubleComplexNumberType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDDoubleComplexNumberType))
+        RegisterJSONEncodingID(reflect.TypeOf((*AxisInformation)(nil)).Elem(), NewExpandedNodeID(ObjectIDAxisInformationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*AxisInformation)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAxisInformation))
+        RegisterJSONEncodingID(reflect.TypeOf((*XVType)(nil)).Elem(), NewExpandedNodeID(ObjectIDXVTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*XVType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDXVType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ProgramDiagnosticDataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDProgramDiagnosticDataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ProgramDiagnosticDataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDProgramDiagnosticDataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*ProgramDiagnostic2DataType)(nil)).Elem(), NewExpandedNodeID(ObjectIDProgramDiagnostic2DataTypeEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*ProgramDiagnostic2DataType)(nil)).Elem(), NewExpandedNodeID(DataTypeIDProgramDiagnostic2DataType))
+        RegisterJSONEncodingID(reflect.TypeOf((*Annotation)(nil)).Elem(), NewExpandedNodeID(ObjectIDAnnotationEncodingDefaultJSON))
+        RegisterJSONEncodingID(reflect.TypeOf((*Annotation)(nil)).Elem(), NewExpandedNodeID(DataTypeIDAnnotation))
 }
@@ -0,0 +1,712 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Structure is a value of a structured DataType that is encoded using the DataTypeDefinition of the DataType,
+// rather than a Go type. Register the definition with the encoders using
+//
+//	func RegisterStructureDefinition(dataTypeID NodeID, definition StructureDefinition, namespaceURIs []string)
+type Structure struct {
+	// TypeID is the NodeID of the DataType, e.g. "nsu=http://github.com/awcullen/opcua/testserver/;i=3002".
+	TypeID ExpandedNodeID
+	// Fields are the fields of the structure, in the order of the definition.
+	Fields []StructureFieldValue
+}
+
+// StructureFieldValue is the name and value of a field of a Structure.
+type StructureFieldValue struct {
+	Name  string
+	Value Variant
+}
+
+// NewStructure returns a Structure of the DataType with the fields of the registered definition set to nil.
+func NewStructure(dataTypeID ExpandedNodeID) (Structure, bool) {
+	st, ok := findStructureType(dataTypeID)
+	if !ok {
+		return Structure{}, false
+	}
+	s := Structure{TypeID: st.dataTypeID, Fields: make([]StructureFieldValue, len(st.fields))}
+	for i, f := range st.fields {
+		s.Fields[i].Name = f.name
+	}
+	return s, true
+}
+
+// Field returns the value of the field with the given name.
+func (s Structure) Field(name string) (Variant, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// SetField sets the value of the field with the given name, adding the field if not found.
+func (s *Structure) SetField(name string, value Variant) {
+	for i, f := range s.Fields {
+		if f.Name == name {
+			s.Fields[i].Value = value
+			return
+		}
+	}
+	s.Fields = append(s.Fields, StructureFieldValue{name, value})
+}
+
+var (
+	structureTypes sync.Map // map[ExpandedNodeID]*structureType, by DataType and by binary and xml encoding id
+	enumTypes      sync.Map // map[ExpandedNodeID]struct{}
+)
+
+// structureType is a StructureDefinition with the NodeIDs resolved to ExpandedNodeIDs.
+type structureType struct {
+	dataTypeID    ExpandedNodeID
+	encodingID    ExpandedNodeID
+	xmlEncodingID ExpandedNodeID
+	name          string
+	structureType StructureType
+	fields        []structureFieldType
+}
+
+type structureFieldType struct {
+	name       string
+	dataTypeID ExpandedNodeID
+	valueRank  int32
+	isOptional bool
+}
+
+// RegisterStructureDefinition registers the definition of a structured DataType with the encoders.
+// Values of the DataType are encoded and decoded as a Structure, unless a Go type is registered
+// with the same binary encoding id. The NodeIDs of the definition are resolved using the given table of namespaceURIs.
+func RegisterStructureDefinition(dataTypeID NodeID, definition StructureDefinition, namespaceURIs []string) {
+	st := &structureType{
+		dataTypeID:    ToExpandedNodeID(dataTypeID, namespaceURIs),
+		structureType: definition.StructureType,
+		fields:        make([]structureFieldType, len(definition.Fields)),
+	}
+	for i, f := range definition.Fields {
+		st.fields[i] = structureFieldType{
+			name:       f.Name,
+			dataTypeID: ToExpandedNodeID(f.DataType, namespaceURIs),
+			valueRank:  f.ValueRank,
+			isOptional: f.IsOptional,
+		}
+	}
+	structureTypes.Store(st.dataTypeID, st)
+	if definition.DefaultEncodingID != nil {
+		st.encodingID = ToExpandedNodeID(definition.DefaultEncodingID, namespaceURIs)
+		structureTypes.Store(st.encodingID, st)
+	}
+}
+
+// RegisterStructureXMLEncoding registers the name of a structured DataType, and the id of its
+// default XML encoding, with the XMLEncoder. The definition must be registered first.
+func RegisterStructureXMLEncoding(dataTypeID NodeID, name string, encodingID NodeID, namespaceURIs []string) {
+	st, ok := findStructureType(ToExpandedNodeID(dataTypeID, namespaceURIs))
+	if !ok {
+		return
+	}
+	st2 := *st
+	st2.name = name
+	st2.xmlEncodingID = ToExpandedNodeID(encodingID, namespaceURIs)
+	structureTypes.Store(st2.dataTypeID, &st2)
+	if st2.encodingID.NodeID != nil {
+		structureTypes.Store(st2.encodingID, &st2)
+	}
+	structureTypes.Store(st2.xmlEncodingID, &st2)
+}
+
+// RegisterEnumDefinition registers an enumerated DataType with the encoders, so that fields of the DataType
+// are encoded as Int32.
+func RegisterEnumDefinition(dataTypeID NodeID, definition EnumDefinition, namespaceURIs []string) {
+	enumTypes.Store(ToExpandedNodeID(dataTypeID, namespaceURIs), struct{}{})
+}
+
+// findStructureType finds the structure type given the DataType, or the binary or xml encoding id.
+func findStructureType(id ExpandedNodeID) (*structureType, bool) {
+	if val, ok := structureTypes.Load(id); ok {
+		if st, ok := val.(*structureType); ok {
+			return st, true
+		}
+	}
+	return nil, false
+}
+
+var (
+	// builtinTypes maps the DataTypes of the standard namespace to the builtin type used for encoding.
+	builtinTypes = map[NodeID]byte{
+		DataTypeIDBoolean:                        VariantTypeBoolean,
+		DataTypeIDSByte:                          VariantTypeSByte,
+		DataTypeIDByte:                           VariantTypeByte,
+		DataTypeIDInt16:                          VariantTypeInt16,
+		DataTypeIDUInt16:                         VariantTypeUInt16,
+		DataTypeIDInt32:                          VariantTypeInt32,
+		DataTypeIDUInt32:                         VariantTypeUInt32,
+		DataTypeIDInt64:                          VariantTypeInt64,
+		DataTypeIDUInt64:                         VariantTypeUInt64,
+		DataTypeIDFloat:                          VariantTypeFloat,
+		DataTypeIDDouble:                         VariantTypeDouble,
+		DataTypeIDString:                         VariantTypeString,
+		DataTypeIDDateTime:                       VariantTypeDateTime,
+		DataTypeIDGUID:                           VariantTypeGUID,
+		DataTypeIDByteString:                     VariantTypeByteString,
+		DataTypeIDXMLElement:                     VariantTypeXMLElement,
+		DataTypeIDNodeID:                         VariantTypeNodeID,
+		DataTypeIDExpandedNodeID:                 VariantTypeExpandedNodeID,
+		DataTypeIDStatusCode:                     VariantTypeStatusCode,
+		DataTypeIDQualifiedName:                  VariantTypeQualifiedName,
+		DataTypeIDLocalizedText:                  VariantTypeLocalizedText,
+		DataTypeIDStructure:                      VariantTypeExtensionObject,
+		DataTypeIDDataValue:                      VariantTypeDataValue,
+		DataTypeIDBaseDataType:                   VariantTypeVariant,
+		DataTypeIDDiagnosticInfo:                 VariantTypeDiagnosticInfo,
+		DataTypeIDNumber:                         VariantTypeVariant,
+		DataTypeIDInteger:                        VariantTypeVariant,
+		DataTypeIDUInteger:                       VariantTypeVariant,
+		DataTypeIDDecimal:                        VariantTypeExtensionObject,
+		DataTypeIDEnumeration:                    VariantTypeInt32,
+		DataTypeIDDuration:                       VariantTypeDouble,
+		DataTypeIDUtcTime:                        VariantTypeDateTime,
+		DataTypeIDDate:                           VariantTypeDateTime,
+		DataTypeIDTime:                           VariantTypeString,
+		DataTypeIDLocaleID:                       VariantTypeString,
+		DataTypeIDNumericRange:                   VariantTypeString,
+		DataTypeIDNormalizedString:               VariantTypeString,
+		DataTypeIDDecimalString:                  VariantTypeString,
+		DataTypeIDDurationString:                 VariantTypeString,
+		DataTypeIDTimeString:                     VariantTypeString,
+		DataTypeIDDateString:                     VariantTypeString,
+		DataTypeIDIntegerID:                      VariantTypeUInt32,
+		DataTypeIDCounter:                        VariantTypeUInt32,
+		DataTypeIDIndex:                          VariantTypeUInt32,
+		DataTypeIDVersionTime:                    VariantTypeUInt32,
+		DataTypeIDBitFieldMaskDataType:           VariantTypeUInt64,
+		DataTypeIDImage:                          VariantTypeByteString,
+		DataTypeIDImageBMP:                       VariantTypeByteString,
+		DataTypeIDImageGIF:                       VariantTypeByteString,
+		DataTypeIDImageJPG:                       VariantTypeByteString,
+		DataTypeIDImagePNG:                       VariantTypeByteString,
+		DataTypeIDAudioDataType:                  VariantTypeByteString,
+		DataTypeIDApplicationInstanceCertificate: VariantTypeByteString,
+		DataTypeIDContinuationPoint:              VariantTypeByteString,
+		DataTypeIDSessionAuthenticationToken:     VariantTypeNodeID,
+		DataTypeIDNamingRuleType:                 VariantTypeInt32,
+		DataTypeIDOpenFileMode:                   VariantTypeInt32,
+		DataTypeIDIdentityCriteriaType:           VariantTypeInt32,
+		DataTypeIDPubSubState:                    VariantTypeInt32,
+		DataTypeIDOverrideValueHandling:          VariantTypeInt32,
+		DataTypeIDDataSetOrderingType:            VariantTypeInt32,
+		DataTypeIDBrokerTransportQoS:             VariantTypeInt32,
+		DataTypeIDDiagnosticsLevel:               VariantTypeInt32,
+		DataTypeIDIDType:                         VariantTypeInt32,
+		DataTypeIDNodeClass:                      VariantTypeInt32,
+		DataTypeIDStructureType:                  VariantTypeInt32,
+		DataTypeIDApplicationType:                VariantTypeInt32,
+		DataTypeIDMessageSecurityMode:            VariantTypeInt32,
+		DataTypeIDUserTokenType:                  VariantTypeInt32,
+		DataTypeIDSecurityTokenRequestType:       VariantTypeInt32,
+		DataTypeIDBrowseDirection:                VariantTypeInt32,
+		DataTypeIDFilterOperator:                 VariantTypeInt32,
+		DataTypeIDTimestampsToReturn:             VariantTypeInt32,
+		DataTypeIDHistoryUpdateType:              VariantTypeInt32,
+		DataTypeIDPerformUpdateType:              VariantTypeInt32,
+		DataTypeIDMonitoringMode:                 VariantTypeInt32,
+		DataTypeIDDataChangeTrigger:              VariantTypeInt32,
+		DataTypeIDDeadbandType:                   VariantTypeInt32,
+		DataTypeIDRedundancySupport:              VariantTypeInt32,
+		DataTypeIDServerState:                    VariantTypeInt32,
+		DataTypeIDAxisScaleEnumeration:           VariantTypeInt32,
+		DataTypeIDExceptionDeviationFormat:       VariantTypeInt32,
+	}
+
+	// goStructureTypes maps the structured DataTypes of the standard namespace that may appear as fields
+	// of a Structure to their Go type.
+	goStructureTypes = map[NodeID]reflect.Type{
+		DataTypeIDRange:                   reflect.TypeOf(Range{}),
+		DataTypeIDEUInformation:           reflect.TypeOf(EUInformation{}),
+		DataTypeIDArgument:                reflect.TypeOf(Argument{}),
+		DataTypeIDEnumValueType:           reflect.TypeOf(EnumValueType{}),
+		DataTypeIDTimeZoneDataType:        reflect.TypeOf(TimeZoneDataType{}),
+		DataTypeIDXVType:                  reflect.TypeOf(XVType{}),
+		DataTypeIDComplexNumberType:       reflect.TypeOf(ComplexNumberType{}),
+		DataTypeIDDoubleComplexNumberType: reflect.TypeOf(DoubleComplexNumberType{}),
+		DataTypeIDAxisInformation:         reflect.TypeOf(AxisInformation{}),
+		DataTypeIDThreeDVector:            reflect.TypeOf(ThreeDVector{}),
+		DataTypeIDKeyValuePair:            reflect.TypeOf(KeyValuePair{}),
+	}
+)
+
+// fieldType describes how a field of the DataType is encoded. Exactly one of builtin, structure or goType is set.
+type fieldType struct {
+	builtin   byte
+	structure *structureType
+	goType    reflect.Type
+}
+
+// findFieldType returns how fields of the DataType are encoded.
+func findFieldType(id ExpandedNodeID) (fieldType, bool) {
+	if id.NamespaceURI == "" && id.ServerIndex == 0 {
+		if b, ok := builtinTypes[id.NodeID]; ok {
+			return fieldType{builtin: b}, true
+		}
+		if typ, ok := goStructureTypes[id.NodeID]; ok {
+			return fieldType{goType: typ}, true
+		}
+	}
+	if st, ok := findStructureType(id); ok {
+		// a registered Go type takes precedence over the definition.
+		if typ, ok := FindTypeForBinaryEncodingID(st.encodingID); ok {
+			return fieldType{goType: typ}, true
+		}
+		return fieldType{structure: st}, true
+	}
+	if _, ok := enumTypes.Load(id); ok {
+		return fieldType{builtin: VariantTypeInt32}, true
+	}
+	return fieldType{}, false
+}
+
+// WriteStructure writes the fields of a Structure, without the ExtensionObject header.
+func (enc *BinaryEncoder) WriteStructure(value Structure) error {
+	st, ok := findStructureType(canonicalID(value.TypeID, enc.ec))
+	if !ok {
+		return BadEncodingError
+	}
+	return enc.writeStructure(st, value)
+}
+
+// writeStructureExtensionObject writes a Structure as an ExtensionObject with the binary encoding id of its definition.
+func (enc *BinaryEncoder) writeStructureExtensionObject(value Structure) error {
+	st, ok := findStructureType(canonicalID(value.TypeID, enc.ec))
+	if !ok || st.encodingID.NodeID == nil {
+		return BadEncodingError
+	}
+	if err := enc.WriteNodeID(ToNodeID(st.encodingID, enc.ec.NamespaceURIs())); err != nil {
+		return BadEncodingError
+	}
+	if err := enc.WriteByte(0x01); err != nil {
+		return BadEncodingError
+	}
+	buf := *(bytesPool.Get().(*[]byte))
+	defer bytesPool.Put(&buf)
+	var writer = NewWriter(buf)
+	enc2 := NewBinaryEncoder(writer, enc.ec)
+	if err := enc2.writeStructure(st, value); err != nil {
+		return BadEncodingError
+	}
+	if err := enc.WriteByteArray(writer.Bytes()); err != nil {
+		return BadEncodingError
+	}
+	return nil
+}
+
+func (enc *BinaryEncoder) writeStructure(st *structureType, value Structure) error {
+	switch st.structureType {
+	case StructureTypeStructureWithOptionalFields:
+		var mask uint32
+		var bit uint32 = 1
+		for _, f := range st.fields {
+			if f.isOptional {
+				if v, _ := value.Field(f.name); v != nil {
+					mask |= bit
+				}
+				bit <<= 1
+			}
+		}
+		if err := enc.WriteUInt32(mask); err != nil {
+			return BadEncodingError
+		}
+		for _, f := range st.fields {
+			v, _ := value.Field(f.name)
+			if f.isOptional && v == nil {
+				continue
+			}
+			if err := enc.writeField(f, v); err != nil {
+				return BadEncodingError
+			}
+		}
+		return nil
+	case StructureTypeUnion:
+		// the switch field is the position of the first field with a value, or zero if none.
+		for i, f := range st.fields {
+			if v, _ := value.Field(f.name); v != nil {
+				if err := enc.WriteUInt32(uint32(i + 1)); err != nil {
+					return BadEncodingError
+				}
+				return enc.writeField(f, v)
+			}
+		}
+		return enc.WriteUInt32(0)
+	default:
+		for _, f := range st.fields {
+			v, _ := value.Field(f.name)
+			if err := enc.writeField(f, v); err != nil {
+				return BadEncodingError
+			}
+		}
+		return nil
+	}
+}
+
+// writeField writes the value of a field, as a scalar or a one-dimensional array.
+func (enc *BinaryEncoder) writeField(f structureFieldType, v Variant) error {
+	ft, ok := findFieldType(f.dataTypeID)
+	if !ok {
+		return BadEncodingError
+	}
+	switch f.valueRank {
+	case ValueRankScalar:
+		return enc.writeFieldValue(ft, v)
+	case ValueRankOneDimension, ValueRankOneOrMoreDimensions:
+		if v == nil {
+			return enc.WriteInt32(-1)
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return BadEncodingError
+		}
+		if err := enc.WriteInt32(int32(rv.Len())); err != nil {
+			return BadEncodingError
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.writeFieldValue(ft, rv.Index(i).Interface()); err != nil {
+				return BadEncodingError
+			}
+		}
+		return nil
+	default:
+		return BadEncodingError
+	}
+}
+
+func (enc *BinaryEncoder) writeFieldValue(ft fieldType, v Variant) error {
+	switch {
+	case ft.structure != nil:
+		s, ok := v.(Structure)
+		if !ok {
+			return BadEncodingError
+		}
+		return enc.writeStructure(ft.structure, s)
+	case ft.goType != nil:
+		if v == nil || reflect.TypeOf(v) != ft.goType {
+			return BadEncodingError
+		}
+		return enc.Encode(v)
+	}
+	switch ft.builtin {
+	case VariantTypeBoolean:
+		return writeAs(v, enc.WriteBoolean)
+	case VariantTypeSByte:
+		return writeAs(v, enc.WriteSByte)
+	case VariantTypeByte:
+		return writeAs(v, enc.WriteByte)
+	case VariantTypeInt16:
+		return writeAs(v, enc.WriteInt16)
+	case VariantTypeUInt16:
+		return writeAs(v, enc.WriteUInt16)
+	case VariantTypeInt32:
+		// enumerations may be given as a Go type based on int32.
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Int32 {
+			return enc.WriteInt32(int32(rv.Int()))
+		}
+		return BadEncodingError
+	case VariantTypeUInt32:
+		return writeAs(v, enc.WriteUInt32)
+	case VariantTypeInt64:
+		return writeAs(v, enc.WriteInt64)
+	case VariantTypeUInt64:
+		return writeAs(v, enc.WriteUInt64)
+	case VariantTypeFloat:
+		return writeAs(v, enc.WriteFloat)
+	case VariantTypeDouble:
+		return writeAs(v, enc.WriteDouble)
+	case VariantTypeString:
+		return writeAs(v, enc.WriteString)
+	case VariantTypeDateTime:
+		return writeAs(v, enc.WriteDateTime)
+	case VariantTypeGUID:
+		return writeAs(v, enc.WriteGUID)
+	case VariantTypeByteString:
+		return writeAs(v, enc.WriteByteString)
+	case VariantTypeXMLElement:
+		return writeAs(v, enc.WriteXMLElement)
+	case VariantTypeNodeID:
+		if v == nil {
+			return enc.WriteNodeID(nil)
+		}
+		return writeAs(v, enc.WriteNodeID)
+	case VariantTypeExpandedNodeID:
+		return writeAs(v, enc.WriteExpandedNodeID)
+	case VariantTypeStatusCode:
+		return writeAs(v, enc.WriteStatusCode)
+	case VariantTypeQualifiedName:
+		return writeAs(v, enc.WriteQualifiedName)
+	case VariantTypeLocalizedText:
+		return writeAs(v, enc.WriteLocalizedText)
+	case VariantTypeExtensionObject:
+		return enc.WriteExtensionObject(v)
+	case VariantTypeDataValue:
+		return writeAs(v, enc.WriteDataValue)
+	case VariantTypeVariant:
+		return enc.WriteVariant(v)
+	case VariantTypeDiagnosticInfo:
+		return writeAs(v, enc.WriteDiagnosticInfo)
+	default:
+		return BadEncodingError
+	}
+}
+
+// writeAs writes the value if it is of type T.
+func writeAs[T any](v Variant, write func(T) error) error {
+	t, ok := v.(T)
+	if !ok {
+		return BadEncodingError
+	}
+	return write(t)
+}
+
+// canonicalID returns the ExpandedNodeID with the NamespaceURI set, as used by the registry.
+func canonicalID(id ExpandedNodeID, ec EncodingContext) ExpandedNodeID {
+	if id.NamespaceURI != "" {
+		return id
+	}
+	return ToExpandedNodeID(id.NodeID, ec.NamespaceURIs())
+}
+
+// ReadStructure reads the fields of a Structure of the given DataType, without the ExtensionObject header.
+func (dec *BinaryDecoder) ReadStructure(dataTypeID ExpandedNodeID, value *Structure) error {
+	st, ok := findStructureType(canonicalID(dataTypeID, dec.ec))
+	if !ok {
+		return BadDecodingError
+	}
+	return dec.readStructure(st, value)
+}
+
+func (dec *BinaryDecoder) readStructure(st *structureType, value *Structure) error {
+	s := Structure{TypeID: st.dataTypeID, Fields: make([]StructureFieldValue, len(st.fields))}
+	for i, f := range st.fields {
+		s.Fields[i].Name = f.name
+	}
+	switch st.structureType {
+	case StructureTypeStructureWithOptionalFields:
+		var mask uint32
+		if err := dec.ReadUInt32(&mask); err != nil {
+			return BadDecodingError
+		}
+		var bit uint32 = 1
+		for i, f := range st.fields {
+			if f.isOptional {
+				present := mask&bit != 0
+				bit <<= 1
+				if !present {
+					continue
+				}
+			}
+			if err := dec.readField(f, &s.Fields[i].Value); err != nil {
+				return BadDecodingError
+			}
+		}
+	case StructureTypeUnion:
+		var sw uint32
+		if err := dec.ReadUInt32(&sw); err != nil {
+			return BadDecodingError
+		}
+		if sw > uint32(len(st.fields)) {
+			return BadDecodingError
+		}
+		if sw > 0 {
+			if err := dec.readField(st.fields[sw-1], &s.Fields[sw-1].Value); err != nil {
+				return BadDecodingError
+			}
+		}
+	default:
+		for i, f := range st.fields {
+			if err := dec.readField(f, &s.Fields[i].Value); err != nil {
+				return BadDecodingError
+			}
+		}
+	}
+	*value = s
+	return nil
+}
+
+// readField reads the value of a field, as a scalar or a one-dimensional array.
+func (dec *BinaryDecoder) readField(f structureFieldType, v *Variant) error {
+	ft, ok := findFieldType(f.dataTypeID)
+	if !ok {
+		return BadDecodingError
+	}
+	switch f.valueRank {
+	case ValueRankScalar:
+		return dec.readFieldValue(ft, v)
+	case ValueRankOneDimension, ValueRankOneOrMoreDimensions:
+		if ft.builtin != 0 {
+			return dec.readBuiltinArray(ft.builtin, v)
+		}
+		var n int32
+		if err := dec.ReadInt32(&n); err != nil {
+			return BadDecodingError
+		}
+		if n < 0 {
+			*v = nil
+			return nil
+		}
+		list := make([]ExtensionObject, n)
+		for i := range list {
+			var item Variant
+			if err := dec.readFieldValue(ft, &item); err != nil {
+				return BadDecodingError
+			}
+			list[i] = item
+		}
+		*v = list
+		return nil
+	default:
+		return BadDecodingError
+	}
+}
+
+func (dec *BinaryDecoder) readFieldValue(ft fieldType, v *Variant) error {
+	switch {
+	case ft.structure != nil:
+		var s Structure
+		if err := dec.readStructure(ft.structure, &s); err != nil {
+			return BadDecodingError
+		}
+		*v = s
+		return nil
+	case ft.goType != nil:
+		obj := reflect.New(ft.goType).Elem().Interface()
+		if err := dec.Decode(obj); err != nil {
+			return BadDecodingError
+		}
+		*v = obj
+		return nil
+	}
+	switch ft.builtin {
+	case VariantTypeBoolean:
+		return readAs(v, dec.ReadBoolean)
+	case VariantTypeSByte:
+		return readAs(v, dec.ReadSByte)
+	case VariantTypeByte:
+		return readAs(v, dec.ReadByte)
+	case VariantTypeInt16:
+		return readAs(v, dec.ReadInt16)
+	case VariantTypeUInt16:
+		return readAs(v, dec.ReadUInt16)
+	case VariantTypeInt32:
+		return readAs(v, dec.ReadInt32)
+	case VariantTypeUInt32:
+		return readAs(v, dec.ReadUInt32)
+	case VariantTypeInt64:
+		return readAs(v, dec.ReadInt64)
+	case VariantTypeUInt64:
+		return readAs(v, dec.ReadUInt64)
+	case VariantTypeFloat:
+		return readAs(v, dec.ReadFloat)
+	case VariantTypeDouble:
+		return readAs(v, dec.ReadDouble)
+	case VariantTypeString:
+		return readAs(v, dec.ReadString)
+	case VariantTypeDateTime:
+		return readAs(v, dec.ReadDateTime)
+	case VariantTypeGUID:
+		return readAs(v, dec.ReadGUID)
+	case VariantTypeByteString:
+		return readAs(v, dec.ReadByteString)
+	case VariantTypeXMLElement:
+		return readAs(v, dec.ReadXMLElement)
+	case VariantTypeNodeID:
+		return readAs(v, dec.ReadNodeID)
+	case VariantTypeExpandedNodeID:
+		return readAs(v, dec.ReadExpandedNodeID)
+	case VariantTypeStatusCode:
+		return readAs(v, dec.ReadStatusCode)
+	case VariantTypeQualifiedName:
+		return readAs(v, dec.ReadQualifiedName)
+	case VariantTypeLocalizedText:
+		return readAs(v, dec.ReadLocalizedText)
+	case VariantTypeExtensionObject:
+		return readAs(v, dec.ReadExtensionObject)
+	case VariantTypeDataValue:
+		return readAs(v, dec.ReadDataValue)
+	case VariantTypeVariant:
+		return dec.ReadVariant(v)
+	case VariantTypeDiagnosticInfo:
+		return readAs(v, dec.ReadDiagnosticInfo)
+	default:
+		return BadDecodingError
+	}
+}
+
+func (dec *BinaryDecoder) readBuiltinArray(builtin byte, v *Variant) error {
+	switch builtin {
+	case VariantTypeBoolean:
+		return readAs(v, dec.ReadBooleanArray)
+	case VariantTypeSByte:
+		return readAs(v, dec.ReadSByteArray)
+	case VariantTypeByte:
+		return readAs(v, dec.ReadByteArray)
+	case VariantTypeInt16:
+		return readAs(v, dec.ReadInt16Array)
+	case VariantTypeUInt16:
+		return readAs(v, dec.ReadUInt16Array)
+	case VariantTypeInt32:
+		return readAs(v, dec.ReadInt32Array)
+	case VariantTypeUInt32:
+		return readAs(v, dec.ReadUInt32Array)
+	case VariantTypeInt64:
+		return readAs(v, dec.ReadInt64Array)
+	case VariantTypeUInt64:
+		return readAs(v, dec.ReadUInt64Array)
+	case VariantTypeFloat:
+		return readAs(v, dec.ReadFloatArray)
+	case VariantTypeDouble:
+		return readAs(v, dec.ReadDoubleArray)
+	case VariantTypeString:
+		return readAs(v, dec.ReadStringArray)
+	case VariantTypeDateTime:
+		return readAs(v, dec.ReadDateTimeArray)
+	case VariantTypeGUID:
+		return readAs(v, dec.ReadGUIDArray)
+	case VariantTypeByteString:
+		return readAs(v, dec.ReadByteStringArray)
+	case VariantTypeXMLElement:
+		return readAs(v, dec.ReadXMLElementArray)
+	case VariantTypeNodeID:
+		return readAs(v, dec.ReadNodeIDArray)
+	case VariantTypeExpandedNodeID:
+		return readAs(v, dec.ReadExpandedNodeIDArray)
+	case VariantTypeStatusCode:
+		return readAs(v, dec.ReadStatusCodeArray)
+	case VariantTypeQualifiedName:
+		return readAs(v, dec.ReadQualifiedNameArray)
+	case VariantTypeLocalizedText:
+		return readAs(v, dec.ReadLocalizedTextArray)
+	case VariantTypeExtensionObject:
+		return readAs(v, dec.ReadExtensionObjectArray)
+	case VariantTypeDataValue:
+		return readAs(v, dec.ReadDataValueArray)
+	case VariantTypeVariant:
+		return readAs(v, dec.ReadVariantArray)
+	case VariantTypeDiagnosticInfo:
+		return readAs(v, dec.ReadDiagnosticInfoArray)
+	default:
+		return BadDecodingError
+	}
+}
+
+// readAs reads a value of type T.
+func readAs[T any](v *Variant, read func(*T) error) error {
+	var t T
+	if err := read(&t); err != nil {
+		return BadDecodingError
+	}
+	*v = t
+	return nil
+}
@@ -86,6 +86,17 @@ type UADataTypeField struct {
 	IsOptional  bool                 `xml:"IsOptional,attr"`
 }
 
+// UnmarshalXML decodes the field. If the ValueRank attribute is missing, the field is a scalar (-1).
+func (f *UADataTypeField) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type field UADataTypeField
+	v := field{ValueRank: int(ValueRankScalar)}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*f = UADataTypeField(v)
+	return nil
+}
+
 // ListOfBoolean supports reading UANodeSet from xml.
 type ListOfBoolean struct {
 	List []bool `xml:"Boolean,omitempty"`
@@ -256,6 +267,7 @@ type UAExpandedNodeID struct {
 // UAVariant supports reading UANodeSet from xml.
 type UAVariant struct {
 	XMLName         xml.Name
+	InnerXML        string             `xml:",innerxml"`
 	Bool            *bool              `xml:"Boolean"`
 	Byte            *uint8             `xml:"Byte"`
 	UInt16          *uint16            `xml:"UInt16"`
@@ -0,0 +1,351 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocketProtocol is the subprotocol of the WebSocket mapping that carries UA-SC chunks.
+// See https://reference.opcfoundation.org/v104/Core/docs/Part6/7.5.2/
+const WebSocketProtocol = "opcua+uacp"
+
+const (
+	// the guid appended to the client key to compute the accept key.
+	webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	// the frame opcodes.
+	opContinuation byte = 0x0
+	opBinary       byte = 0x2
+	opClose        byte = 0x8
+	opPing         byte = 0x9
+	opPong         byte = 0xA
+	// the close status when a frame violates the protocol.
+	closeProtocolError uint16 = 1002
+)
+
+// webSocketConn is a net.Conn that sends each Write as a binary message and
+// receives the payloads of binary messages as a stream.
+type webSocketConn struct {
+	net.Conn
+	r         *bufio.Reader
+	client    bool
+	remaining uint64
+	masked    bool
+	mask      [4]byte
+	pos       int
+	writeLock sync.Mutex
+}
+
+// Read reads the payload of the incoming binary messages.
+func (c *webSocketConn) Read(p []byte) (int, error) {
+	for c.remaining == 0 {
+		if err := c.readFrameHeader(); err != nil {
+			return 0, err
+		}
+	}
+	if uint64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	if c.masked {
+		for i := 0; i < n; i++ {
+			p[i] ^= c.mask[c.pos&3]
+			c.pos++
+		}
+	}
+	c.remaining -= uint64(n)
+	return n, err
+}
+
+// readFrameHeader reads the header of the next data frame, handling any control frames.
+func (c *webSocketConn) readFrameHeader() error {
+	for {
+		var h [2]byte
+		if _, err := io.ReadFull(c.r, h[:]); err != nil {
+			return err
+		}
+		op := h[0] & 0x0F
+		c.masked = h[1]&0x80 != 0
+		length := uint64(h[1] & 0x7F)
+		switch length {
+		case 126:
+			var b [2]byte
+			if _, err := io.ReadFull(c.r, b[:]); err != nil {
+				return err
+			}
+			length = uint64(binary.BigEndian.Uint16(b[:]))
+		case 127:
+			var b [8]byte
+			if _, err := io.ReadFull(c.r, b[:]); err != nil {
+				return err
+			}
+			length = binary.BigEndian.Uint64(b[:])
+		}
+		if !c.client && !c.masked {
+			// frames sent by a client must be masked.
+			c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, closeProtocolError))
+			return BadTCPMessageTypeInvalid
+		}
+		if c.masked {
+			if _, err := io.ReadFull(c.r, c.mask[:]); err != nil {
+				return err
+			}
+		}
+		c.pos = 0
+		switch op {
+		case opBinary, opContinuation:
+			c.remaining = length
+			return nil
+		case opClose, opPing, opPong:
+			if length > 125 {
+				return BadTCPMessageTypeInvalid
+			}
+			payload := make([]byte, length)
+			if _, err := io.ReadFull(c.r, payload); err != nil {
+				return err
+			}
+			if c.masked {
+				for i := range payload {
+					payload[i] ^= c.mask[i&3]
+				}
+			}
+			switch op {
+			case opClose:
+				c.writeFrame(opClose, payload)
+				return io.EOF
+			case opPing:
+				if err := c.writeFrame(opPong, payload); err != nil {
+					return err
+				}
+			}
+		default:
+			// the opcua+uacp protocol only uses binary messages.
+			return BadTCPMessageTypeInvalid
+		}
+	}
+}
+
+// Write sends p as a single binary message.
+func (c *webSocketConn) Write(p []byte) (int, error) {
+	if err := c.writeFrame(opBinary, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeFrame sends a frame with the given opcode and payload. Frames sent by a client are masked.
+func (c *webSocketConn) writeFrame(op byte, p []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	frame := make([]byte, 0, len(p)+14)
+	frame = append(frame, 0x80|op)
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(p); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		for i, b := range p {
+			frame = append(frame, b^mask[i&3])
+		}
+	} else {
+		frame = append(frame, p...)
+	}
+	_, err := c.Conn.Write(frame)
+	return err
+}
+
+// Close sends a close frame and closes the connection.
+func (c *webSocketConn) Close() error {
+	c.writeFrame(opClose, nil)
+	return c.Conn.Close()
+}
+
+// DialWebSocket connects to the server at the opc.wss url and requests the opcua+uacp subprotocol.
+// The returned connection sends each Write as a single binary message.
+func DialWebSocket(endpointURL string, config *tls.Config, timeout time.Duration) (net.Conn, error) {
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return nil, BadTCPEndpointURLInvalid
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+	if config == nil {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" && !config.InsecureSkipVerify {
+		config = config.Clone()
+		config.ServerName = u.Hostname()
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", host, config)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+	path := u.RequestURI()
+	if _, err := fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Protocol: %s\r\n\r\n", path, u.Host, key, WebSocketProtocol); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	res, err := http.ReadResponse(r, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusSwitchingProtocols ||
+		!strings.EqualFold(res.Header.Get("Upgrade"), "websocket") ||
+		res.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) ||
+		res.Header.Get("Sec-WebSocket-Protocol") != WebSocketProtocol {
+		conn.Close()
+		return nil, BadConnectionRejected
+	}
+	conn.SetDeadline(time.Time{})
+	return &webSocketConn{Conn: conn, r: r, client: true}, nil
+}
+
+// ListenWebSocket listens on the address for clients requesting the opcua+uacp subprotocol.
+// If config is not nil, the listener uses TLS. The listener accepts connections for any path.
+func ListenWebSocket(addr string, config *tls.Config) (net.Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		ln = tls.NewListener(ln, config)
+	}
+	l := &webSocketListener{
+		ln:     ln,
+		conns:  make(chan net.Conn),
+		closed: make(chan struct{}),
+	}
+	l.srv = &http.Server{Handler: l, ReadHeaderTimeout: 10 * time.Second}
+	go l.srv.Serve(ln)
+	return l, nil
+}
+
+// webSocketListener is a net.Listener that accepts upgraded connections from an http server.
+type webSocketListener struct {
+	ln        net.Listener
+	srv       *http.Server
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// Accept waits for and returns the next connection.
+func (l *webSocketListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes the listener. Connections already accepted are not closed.
+func (l *webSocketListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		err = l.srv.Close()
+	})
+	return err
+}
+
+// Addr returns the listener's network address.
+func (l *webSocketListener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection.
+func (l *webSocketListener) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if req.Method != http.MethodGet ||
+		!strings.EqualFold(req.Header.Get("Upgrade"), "websocket") ||
+		req.Header.Get("Sec-WebSocket-Version") != "13" || key == "" {
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return
+	}
+	found := false
+	for _, h := range req.Header.Values("Sec-WebSocket-Protocol") {
+		for _, p := range strings.Split(h, ",") {
+			if strings.TrimSpace(p) == WebSocketProtocol {
+				found = true
+			}
+		}
+	}
+	if !found {
+		http.Error(w, "subprotocol "+WebSocketProtocol+" required", http.StatusBadRequest)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket upgrade not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	// clear the deadlines set by the http server.
+	conn.SetDeadline(time.Time{})
+	if _, err := fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\nSec-WebSocket-Protocol: %s\r\n\r\n", webSocketAccept(key), WebSocketProtocol); err != nil {
+		conn.Close()
+		return
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return
+	}
+	select {
+	case l.conns <- &webSocketConn{Conn: conn, r: rw.Reader}:
+	case <-l.closed:
+		conn.Close()
+	}
+}
+
+// webSocketAccept computes the accept key for the client key.
+func webSocketAccept(key string) string {
+	h := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
@@ -0,0 +1,62 @@
+package ua_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+)
+
+// TestWebSocketUnmaskedFrame tests that the server closes the connection with a protocol error when a client sends an unmasked frame.
+func TestWebSocketUnmaskedFrame(t *testing.T) {
+	ln, err := ua.ListenWebSocket("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(io.Discard, conn)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Protocol: %s\r\n\r\n", ln.Addr(), ua.WebSocketProtocol)
+	r := bufio.NewReader(conn)
+	res, err := http.ReadResponse(r, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected status %d, got %d", http.StatusSwitchingProtocols, res.StatusCode)
+	}
+
+	// send an unmasked binary frame.
+	if _, err := conn.Write([]byte{0x82, 0x04, 'H', 'E', 'L', 'F'}); err != nil {
+		t.Fatal(err)
+	}
+	var h [4]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		t.Fatal(err)
+	}
+	if h[0] != 0x88 || h[1] != 0x02 {
+		t.Fatalf("expected close frame, got % x", h[:2])
+	}
+	if status := binary.BigEndian.Uint16(h[2:]); status != 1002 {
+		t.Errorf("expected close status 1002, got %d", status)
+	}
+}
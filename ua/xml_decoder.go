@@ -0,0 +1,623 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// XMLDecoder decodes the UA XML encoding, described in Part 6 section 5.3.
+//
+// ExtensionObjects are decoded to the type registered for the xml encoding id, or to a Structure
+// if the definition of the DataType was registered. An ExtensionObject of an unknown type is a BadDecodingError.
+type XMLDecoder struct {
+	d     *xml.Decoder
+	ec    EncodingContext
+	nsMap func(ns uint16) uint16
+}
+
+// NewXMLDecoder returns a new decoder that reads from an io.Reader.
+func NewXMLDecoder(r io.Reader, ec EncodingContext) *XMLDecoder {
+	return &XMLDecoder{d: xml.NewDecoder(r), ec: ec}
+}
+
+// SetNamespaceMap sets a function that maps the namespace indexes read from the document, e.g. from the
+// NamespaceUris of a UANodeSet, to the namespace indexes of the values.
+func (dec *XMLDecoder) SetNamespaceMap(f func(ns uint16) uint16) {
+	dec.nsMap = f
+}
+
+// Decode reads the next element and stores it in the value pointed to by v.
+func (dec *XMLDecoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return BadDecodingError
+	}
+	n, err := dec.next()
+	if err != nil {
+		return BadDecodingError
+	}
+	if err := dec.decodeValue(n, rv.Elem()); err != nil {
+		return BadDecodingError
+	}
+	return nil
+}
+
+// ReadVariant reads the element of the value of a Variant, e.g. <Int32> or <ListOfInt32>,
+// as found in the Value of a Variant, or the Value of a variable of a UANodeSet.
+// A nil Variant has no element, so a document without an element is read as nil.
+func (dec *XMLDecoder) ReadVariant(value *Variant) error {
+	n, err := dec.next()
+	if err == io.EOF {
+		*value = nil
+		return nil
+	}
+	if err != nil {
+		return BadDecodingError
+	}
+	v, err := dec.decodeVariantValue(n)
+	if err != nil {
+		return BadDecodingError
+	}
+	*value = v
+	return nil
+}
+
+// ReadExtensionObject reads an <ExtensionObject> element.
+func (dec *XMLDecoder) ReadExtensionObject(value *ExtensionObject) error {
+	return dec.Decode(value)
+}
+
+// xmlNode is an element of the document.
+type xmlNode struct {
+	XMLName  xml.Name
+	Content  string    `xml:",chardata"`
+	InnerXML string    `xml:",innerxml"`
+	Nodes    []xmlNode `xml:",any"`
+}
+
+// child returns the child element with the given name, or nil if not found.
+func (n *xmlNode) child(name string) *xmlNode {
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == name {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// text returns the content of the element, without leading and trailing white space.
+func (n *xmlNode) text() string {
+	return strings.TrimSpace(n.Content)
+}
+
+// next reads the next element.
+func (dec *XMLDecoder) next() (*xmlNode, error) {
+	for {
+		tok, err := dec.d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			n := &xmlNode{}
+			if err := dec.d.DecodeElement(n, &start); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+	}
+}
+
+func (dec *XMLDecoder) decodeValue(n *xmlNode, rv reflect.Value) error {
+	switch rv.Type() {
+	case typeVariant:
+		v, err := dec.decodeVariant(n)
+		if err != nil {
+			return err
+		}
+		setInterface(rv, v)
+		return nil
+	case typeExtensionObject:
+		v, err := dec.decodeExtensionObject(n)
+		if err != nil {
+			return err
+		}
+		setInterface(rv, v)
+		return nil
+	case typeNodeID:
+		v, err := dec.decodeNodeID(n)
+		if err != nil {
+			return err
+		}
+		setInterface(rv, v)
+		return nil
+	case typeExpandedNodeID:
+		var id ExpandedNodeID
+		if c := n.child("Identifier"); c != nil && c.text() != "" {
+			if id = ParseExpandedNodeID(c.text()); id.NodeID == nil {
+				return BadDecodingError
+			}
+			if id.NamespaceURI == "" {
+				id.NodeID = dec.mapNodeID(id.NodeID)
+			}
+		}
+		rv.Set(reflect.ValueOf(id))
+		return nil
+	case typeDateTime:
+		var t time.Time
+		if s := n.text(); s != "" {
+			var err error
+			if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+				return BadDecodingError
+			}
+		}
+		rv.Set(reflect.ValueOf(t.UTC()))
+		return nil
+	case typeGUID:
+		var g uuid.UUID
+		if c := n.child("String"); c != nil {
+			var err error
+			if g, err = uuid.Parse(c.text()); err != nil {
+				return BadDecodingError
+			}
+		}
+		rv.Set(reflect.ValueOf(g))
+		return nil
+	case typeByteString, typeSliceOfByte:
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(n.Content), ""))
+		if err != nil {
+			return BadDecodingError
+		}
+		if rv.Type() == typeByteString {
+			rv.SetString(string(b))
+		} else if len(b) > 0 {
+			rv.SetBytes(b)
+		}
+		return nil
+	case typeXMLElement:
+		rv.SetString(n.InnerXML)
+		return nil
+	case typeStatusCode:
+		var code uint64
+		if c := n.child("Code"); c != nil {
+			var err error
+			if code, err = strconv.ParseUint(c.text(), 10, 32); err != nil {
+				return BadDecodingError
+			}
+		}
+		rv.SetUint(code)
+		return nil
+	case typeQualifiedName:
+		var qn QualifiedName
+		if c := n.child("NamespaceIndex"); c != nil {
+			ns, err := strconv.ParseUint(c.text(), 10, 16)
+			if err != nil {
+				return BadDecodingError
+			}
+			qn.NamespaceIndex = dec.mapNamespace(uint16(ns))
+		}
+		if c := n.child("Name"); c != nil {
+			qn.Name = c.Content
+		}
+		rv.Set(reflect.ValueOf(qn))
+		return nil
+	case typeLocalizedText:
+		var lt LocalizedText
+		if c := n.child("Locale"); c != nil {
+			lt.Locale = c.Content
+		}
+		if c := n.child("Text"); c != nil {
+			lt.Text = c.Content
+		}
+		rv.Set(reflect.ValueOf(lt))
+		return nil
+	case typeDataValue:
+		var dv DataValue
+		if err := dec.decodeFields(n, []xmlField{
+			{"Value", &dv.Value},
+			{"StatusCode", &dv.StatusCode},
+			{"SourceTimestamp", &dv.SourceTimestamp},
+			{"SourcePicoseconds", &dv.SourcePicoseconds},
+			{"ServerTimestamp", &dv.ServerTimestamp},
+			{"ServerPicoseconds", &dv.ServerPicoseconds},
+		}); err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(dv))
+		return nil
+	case typeDiagnosticInfo:
+		var info DiagnosticInfo
+		if err := dec.decodeFields(n, []xmlField{
+			{"SymbolicId", &info.SymbolicID},
+			{"NamespaceUri", &info.NamespaceURI},
+			{"Locale", &info.Locale},
+			{"LocalizedText", &info.LocalizedText},
+			{"AdditionalInfo", &info.AdditionalInfo},
+			{"InnerStatusCode", &info.InnerStatusCode},
+			{"InnerDiagnosticInfo", &info.InnerDiagnosticInfo},
+		}); err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(info))
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(n.text())
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.SetBool(b)
+		return nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// enumerations may be written as "Name_Value".
+		s := n.text()
+		if i := strings.LastIndexByte(s, '_'); i >= 0 {
+			s = s[i+1:]
+		}
+		i, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.SetInt(i)
+		return nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(n.text(), 10, rv.Type().Bits())
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(n.text(), rv.Type().Bits())
+		if err != nil {
+			return BadDecodingError
+		}
+		rv.SetFloat(f)
+		return nil
+	case reflect.String:
+		rv.SetString(n.Content)
+		return nil
+	case reflect.Slice:
+		s := reflect.MakeSlice(rv.Type(), len(n.Nodes), len(n.Nodes))
+		for i := range n.Nodes {
+			if err := dec.decodeValue(&n.Nodes[i], s.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+		return nil
+	case reflect.Ptr:
+		v := reflect.New(rv.Type().Elem())
+		if err := dec.decodeValue(n, v.Elem()); err != nil {
+			return err
+		}
+		rv.Set(v)
+		return nil
+	case reflect.Struct:
+		if rv.Type().Implements(typeNodeID) {
+			id, err := dec.decodeNodeID(n)
+			if err != nil {
+				return err
+			}
+			if id == nil || reflect.TypeOf(id) != rv.Type() {
+				return BadDecodingError
+			}
+			rv.Set(reflect.ValueOf(id))
+			return nil
+		}
+		return dec.decodeStruct(n, rv)
+	}
+	return BadDecodingError
+}
+
+// xmlField is a child element and the value it is decoded to.
+type xmlField struct {
+	name string
+	ptr  any
+}
+
+// decodeFields decodes the child elements that are found.
+func (dec *XMLDecoder) decodeFields(n *xmlNode, fields []xmlField) error {
+	for _, f := range fields {
+		if c := n.child(f.name); c != nil {
+			if err := dec.decodeValue(c, reflect.ValueOf(f.ptr).Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// decodeStruct decodes the fields of a struct from the child elements. Pointer fields are optional.
+func (dec *XMLDecoder) decodeStruct(n *xmlNode, rv reflect.Value) error {
+	typ := rv.Type()
+	if isUnion(typ) {
+		var sw int
+		if c := n.child("SwitchField"); c != nil {
+			var err error
+			if sw, err = strconv.Atoi(c.text()); err != nil {
+				return BadDecodingError
+			}
+		}
+		for i, pos := 0, 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Anonymous && field.Type == typeUnion {
+				continue
+			}
+			pos++
+			rv.Field(i).SetZero()
+			if pos == sw {
+				if c := n.child(fieldName(field)); c != nil {
+					if err := dec.decodeValue(c, rv.Field(i)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		c := n.child(fieldName(field))
+		if c == nil {
+			rv.Field(i).SetZero()
+			continue
+		}
+		if err := dec.decodeValue(c, rv.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeVariant decodes a Variant from its <Value> child element.
+func (dec *XMLDecoder) decodeVariant(n *xmlNode) (Variant, error) {
+	c := n.child("Value")
+	if c == nil || len(c.Nodes) == 0 {
+		return nil, nil
+	}
+	return dec.decodeVariantValue(&c.Nodes[0])
+}
+
+// decodeVariantValue decodes the element of the value of a Variant, e.g. <Int32>, <ListOfInt32> or <Matrix>.
+func (dec *XMLDecoder) decodeVariantValue(n *xmlNode) (Variant, error) {
+	name := n.XMLName.Local
+	switch {
+	case name == "Matrix":
+		c := n.child("Dimensions")
+		if c == nil {
+			return nil, BadDecodingError
+		}
+		var dims []int32
+		if err := dec.decodeValue(c, reflect.ValueOf(&dims).Elem()); err != nil {
+			return nil, err
+		}
+		elems := n.child("Elements")
+		length := 0
+		if elems != nil {
+			length = len(elems.Nodes)
+		}
+		if err := checkDimensions(dims, length); err != nil {
+			return nil, err
+		}
+		vt, ok := builtinTypeOf(elems.Nodes[0].XMLName.Local)
+		if !ok {
+			return nil, BadDecodingError
+		}
+		flat, err := dec.decodeArray(elems, vt)
+		if err != nil {
+			return nil, err
+		}
+		return reshapeArray(flat, dims)
+	case strings.HasPrefix(name, "ListOf"):
+		vt, ok := builtinTypeOf(strings.TrimPrefix(name, "ListOf"))
+		if !ok {
+			return nil, BadDecodingError
+		}
+		list, err := dec.decodeArray(n, vt)
+		if err != nil {
+			return nil, err
+		}
+		return list.Interface(), nil
+	default:
+		vt, ok := builtinTypeOf(name)
+		if !ok || vt == VariantTypeVariant {
+			return nil, BadDecodingError
+		}
+		if vt == VariantTypeExtensionObject {
+			return dec.decodeExtensionObject(n)
+		}
+		v := reflect.New(variantGoTypes[vt]).Elem()
+		if err := dec.decodeValue(n, v); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	}
+}
+
+// decodeArray decodes the child elements as a one-dimensional array of the builtin type.
+func (dec *XMLDecoder) decodeArray(n *xmlNode, vt byte) (reflect.Value, error) {
+	s := reflect.MakeSlice(reflect.SliceOf(variantGoTypes[vt]), len(n.Nodes), len(n.Nodes))
+	for i := range n.Nodes {
+		if err := dec.decodeValue(&n.Nodes[i], s.Index(i)); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return s, nil
+}
+
+// decodeExtensionObject decodes the body of the ExtensionObject to the type registered for the TypeId.
+// Returns BadDecodingError if the TypeId is of an unknown type.
+func (dec *XMLDecoder) decodeExtensionObject(n *xmlNode) (ExtensionObject, error) {
+	c := n.child("TypeId")
+	if c == nil {
+		return nil, nil
+	}
+	nodeID, err := dec.decodeNodeID(c)
+	if err != nil || nodeID == nil {
+		return nil, BadDecodingError
+	}
+	body := n.child("Body")
+	if body == nil || len(body.Nodes) == 0 {
+		return nil, nil
+	}
+	id := ToExpandedNodeID(nodeID, dec.ec.NamespaceURIs())
+	if typ, ok := FindTypeForXMLEncodingID(id); ok {
+		v := reflect.New(typ).Elem()
+		if err := dec.decodeValue(&body.Nodes[0], v); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	}
+	if st, ok := findStructureType(id); ok {
+		return dec.decodeStructure(&body.Nodes[0], st)
+	}
+	return nil, BadDecodingError
+}
+
+// decodeStructure decodes the fields of a Structure from the child elements, using the registered definition.
+func (dec *XMLDecoder) decodeStructure(n *xmlNode, st *structureType) (Structure, error) {
+	s := Structure{TypeID: st.dataTypeID, Fields: make([]StructureFieldValue, len(st.fields))}
+	for i, f := range st.fields {
+		s.Fields[i].Name = f.name
+	}
+	if st.structureType == StructureTypeUnion {
+		var sw int
+		if c := n.child("SwitchField"); c != nil {
+			var err error
+			if sw, err = strconv.Atoi(c.text()); err != nil {
+				return Structure{}, BadDecodingError
+			}
+		}
+		if sw < 0 || sw > len(st.fields) {
+			return Structure{}, BadDecodingError
+		}
+		if sw > 0 {
+			if c := n.child(st.fields[sw-1].name); c != nil {
+				v, err := dec.decodeFieldValue(c, st.fields[sw-1])
+				if err != nil {
+					return Structure{}, err
+				}
+				s.Fields[sw-1].Value = v
+			}
+		}
+		return s, nil
+	}
+	for i, f := range st.fields {
+		c := n.child(f.name)
+		if c == nil {
+			continue
+		}
+		v, err := dec.decodeFieldValue(c, f)
+		if err != nil {
+			return Structure{}, err
+		}
+		s.Fields[i].Value = v
+	}
+	return s, nil
+}
+
+// decodeFieldValue decodes the value of a field of a Structure.
+func (dec *XMLDecoder) decodeFieldValue(n *xmlNode, f structureFieldType) (Variant, error) {
+	ft, ok := findFieldType(f.dataTypeID)
+	if !ok {
+		return nil, BadDecodingError
+	}
+	elem := func(n *xmlNode) (Variant, error) {
+		switch {
+		case ft.structure != nil:
+			return dec.decodeStructure(n, ft.structure)
+		case ft.goType != nil:
+			v := reflect.New(ft.goType).Elem()
+			if err := dec.decodeValue(n, v); err != nil {
+				return nil, err
+			}
+			return v.Interface(), nil
+		case ft.builtin == VariantTypeVariant:
+			return dec.decodeVariant(n)
+		case ft.builtin == VariantTypeExtensionObject:
+			return dec.decodeExtensionObject(n)
+		default:
+			v := reflect.New(variantGoTypes[ft.builtin]).Elem()
+			if err := dec.decodeValue(n, v); err != nil {
+				return nil, err
+			}
+			return v.Interface(), nil
+		}
+	}
+	if f.valueRank == ValueRankScalar {
+		return elem(n)
+	}
+	if ft.builtin != 0 {
+		list, err := dec.decodeArray(n, ft.builtin)
+		if err != nil {
+			return nil, err
+		}
+		return list.Interface(), nil
+	}
+	list := make([]ExtensionObject, len(n.Nodes))
+	for i := range n.Nodes {
+		v, err := elem(&n.Nodes[i])
+		if err != nil {
+			return nil, err
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+// decodeNodeID decodes a NodeID from its <Identifier> child element. Identifiers of the form "nsu=uri;i=1"
+// are resolved using the NamespaceURIs of the EncodingContext.
+func (dec *XMLDecoder) decodeNodeID(n *xmlNode) (NodeID, error) {
+	c := n.child("Identifier")
+	if c == nil || c.text() == "" {
+		return nil, nil
+	}
+	s := c.text()
+	if strings.HasPrefix(s, "nsu=") {
+		id := ToNodeID(ParseExpandedNodeID(s), dec.ec.NamespaceURIs())
+		if id == nil {
+			return nil, BadDecodingError
+		}
+		return id, nil
+	}
+	id := ParseNodeID(s)
+	if id == nil {
+		return nil, BadDecodingError
+	}
+	return dec.mapNodeID(id), nil
+}
+
+// mapNamespace maps the namespace index using the namespace map, if set.
+func (dec *XMLDecoder) mapNamespace(ns uint16) uint16 {
+	if dec.nsMap == nil || ns == 0 {
+		return ns
+	}
+	return dec.nsMap(ns)
+}
+
+func (dec *XMLDecoder) mapNodeID(id NodeID) NodeID {
+	return withNamespaceIndex(id, dec.mapNamespace(namespaceIndexOf(id)))
+}
+
+// builtinTypeOf returns the builtin type given the name of its xml element.
+func builtinTypeOf(name string) (byte, bool) {
+	for vt, s := range builtinNames {
+		if s != "" && s == name {
+			return byte(vt), true
+		}
+	}
+	return 0, false
+}
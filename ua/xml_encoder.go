@@ -0,0 +1,738 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TypesNamespace is the xml namespace of the builtin types, described in Part 6 section 5.3.
+const TypesNamespace = "http://opcfoundation.org/UA/2008/02/Types.xsd"
+
+var (
+	// builtinNames maps the builtin type of a Variant to the name of its xml element.
+	builtinNames = [...]string{
+		VariantTypeBoolean:         "Boolean",
+		VariantTypeSByte:           "SByte",
+		VariantTypeByte:            "Byte",
+		VariantTypeInt16:           "Int16",
+		VariantTypeUInt16:          "UInt16",
+		VariantTypeInt32:           "Int32",
+		VariantTypeUInt32:          "UInt32",
+		VariantTypeInt64:           "Int64",
+		VariantTypeUInt64:          "UInt64",
+		VariantTypeFloat:           "Float",
+		VariantTypeDouble:          "Double",
+		VariantTypeString:          "String",
+		VariantTypeDateTime:        "DateTime",
+		VariantTypeGUID:            "Guid",
+		VariantTypeByteString:      "ByteString",
+		VariantTypeXMLElement:      "XmlElement",
+		VariantTypeNodeID:          "NodeId",
+		VariantTypeExpandedNodeID:  "ExpandedNodeId",
+		VariantTypeStatusCode:      "StatusCode",
+		VariantTypeQualifiedName:   "QualifiedName",
+		VariantTypeLocalizedText:   "LocalizedText",
+		VariantTypeExtensionObject: "ExtensionObject",
+		VariantTypeDataValue:       "DataValue",
+		VariantTypeVariant:         "Variant",
+		VariantTypeDiagnosticInfo:  "DiagnosticInfo",
+	}
+
+	// xmlCaseReplacer restores the names of the xml schema from the Go names, e.g. "NamespaceURI" to "NamespaceUri".
+	xmlCaseReplacer = strings.NewReplacer(
+		"GUID", "Guid",
+		"ID", "Id",
+		"JSON", "Json",
+		"QoS", "QualityOfService",
+		"TCP", "Tcp",
+		"UADP", "Uadp",
+		"URI", "Uri",
+		"URL", "Url",
+		"XML", "Xml",
+		"HTTPS", "Https",
+		"DNS", "Dns",
+	)
+)
+
+// XMLEncoder encodes the UA XML encoding, described in Part 6 section 5.3.
+//
+// ExtensionObjects are identified by the xml encoding id that was registered with
+//
+//	func RegisterXMLEncodingID(typ reflect.Type, id ExpandedNodeID)
+//
+// The elements of the fields of a struct are named by the `xml` tag of the field, or by the name of the field.
+type XMLEncoder struct {
+	w     io.Writer
+	e     *xml.Encoder
+	ec    EncodingContext
+	nsMap func(ns uint16) uint16
+	depth int
+}
+
+// NewXMLEncoder returns a new encoder that writes to an io.Writer.
+func NewXMLEncoder(w io.Writer, ec EncodingContext) *XMLEncoder {
+	return &XMLEncoder{w: w, e: xml.NewEncoder(w), ec: ec}
+}
+
+// SetNamespaceMap sets a function that maps the namespace indexes of the values to the namespace indexes
+// written to the document, e.g. to the NamespaceUris of a UANodeSet.
+func (enc *XMLEncoder) SetNamespaceMap(f func(ns uint16) uint16) {
+	enc.nsMap = f
+}
+
+// Encode writes the xml encoding of the value, as an element named for its type, e.g. <Int32>.
+// To encode a Variant or ExtensionObject, pass a pointer to the Variant or ExtensionObject.
+func (enc *XMLEncoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return BadEncodingError
+	}
+	name := elementName(rv.Type())
+	if rv.Type() == typeStructure {
+		// a Structure is named for its DataType.
+		st, ok := findStructureType(canonicalID(rv.Interface().(Structure).TypeID, enc.ec))
+		if !ok || st.name == "" {
+			return BadEncodingError
+		}
+		name = st.name
+	}
+	return enc.flush(enc.writeElement(name, rv))
+}
+
+// WriteVariant writes the element of the value of the Variant, e.g. <Int32> or <ListOfInt32>,
+// as found in the Value of a Variant, or the Value of a variable of a UANodeSet.
+func (enc *XMLEncoder) WriteVariant(value Variant) error {
+	return enc.flush(enc.writeVariantValue(value))
+}
+
+// WriteExtensionObject writes the ExtensionObject as an <ExtensionObject> element.
+func (enc *XMLEncoder) WriteExtensionObject(value ExtensionObject) error {
+	return enc.flush(enc.writeExtensionObject("ExtensionObject", value))
+}
+
+func (enc *XMLEncoder) flush(err error) error {
+	if err != nil {
+		return BadEncodingError
+	}
+	if err := enc.e.Flush(); err != nil {
+		return BadEncodingError
+	}
+	return nil
+}
+
+// start writes the start element. The outermost element declares the namespace of the builtin types.
+func (enc *XMLEncoder) start(name string) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if enc.depth == 0 {
+		start.Attr = []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: TypesNamespace}}
+	}
+	enc.depth++
+	return enc.e.EncodeToken(start)
+}
+
+func (enc *XMLEncoder) end(name string) error {
+	enc.depth--
+	return enc.e.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// writeText writes an element with the text as its content.
+func (enc *XMLEncoder) writeText(name, text string) error {
+	if err := enc.start(name); err != nil {
+		return err
+	}
+	if text != "" {
+		if err := enc.e.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+	return enc.end(name)
+}
+
+// writeElement writes the value as an element with the given name.
+func (enc *XMLEncoder) writeElement(name string, rv reflect.Value) error {
+	switch rv.Type() {
+	case typeVariant:
+		if err := enc.start(name); err != nil {
+			return err
+		}
+		if !rv.IsNil() {
+			if err := enc.start("Value"); err != nil {
+				return err
+			}
+			if err := enc.writeVariantValue(rv.Interface()); err != nil {
+				return err
+			}
+			if err := enc.end("Value"); err != nil {
+				return err
+			}
+		}
+		return enc.end(name)
+	case typeExtensionObject:
+		return enc.writeExtensionObject(name, rv.Interface())
+	case typeXMLElement:
+		// the content is written as is.
+		if err := enc.start(name); err != nil {
+			return err
+		}
+		if err := enc.e.Flush(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(enc.w, rv.String()); err != nil {
+			return err
+		}
+		return enc.end(name)
+	}
+	if text, ok := xmlText(rv); ok {
+		return enc.writeText(name, text)
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return enc.writeText(name, "")
+		}
+		return enc.writeElement(name, rv.Elem())
+	}
+	if err := enc.start(name); err != nil {
+		return err
+	}
+	if err := enc.writeContent(rv); err != nil {
+		return err
+	}
+	return enc.end(name)
+}
+
+// xmlText returns the content of a value that is encoded as text.
+func xmlText(rv reflect.Value) (string, bool) {
+	switch rv.Type() {
+	case typeDateTime:
+		return rv.Interface().(time.Time).UTC().Format(time.RFC3339Nano), true
+	case typeByteString:
+		return base64.StdEncoding.EncodeToString([]byte(rv.String())), true
+	case typeSliceOfByte:
+		return base64.StdEncoding.EncodeToString(rv.Bytes()), true
+	case typeStatusCode:
+		return "", false
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), true
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// enumerations are written as "Name_Value".
+		if s, ok := rv.Interface().(fmt.Stringer); ok && rv.Kind() == reflect.Int32 {
+			if name := s.String(); name != "" {
+				return name + "_" + strconv.FormatInt(rv.Int(), 10), true
+			}
+		}
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case reflect.Float32:
+		return formatXMLFloat(rv.Float(), 32), true
+	case reflect.Float64:
+		return formatXMLFloat(rv.Float(), 64), true
+	case reflect.String:
+		return rv.String(), true
+	}
+	return "", false
+}
+
+// writeContent writes the child elements of a value of a complex type.
+func (enc *XMLEncoder) writeContent(rv reflect.Value) error {
+	switch rv.Type() {
+	case typeExpandedNodeID:
+		id := rv.Interface().(ExpandedNodeID)
+		if id.NodeID == nil {
+			return nil
+		}
+		if id.NamespaceURI == "" {
+			id.NodeID = enc.mapNodeID(id.NodeID)
+		}
+		return enc.writeText("Identifier", id.String())
+	case typeGUID:
+		return enc.writeText("String", rv.Interface().(uuid.UUID).String())
+	case typeStatusCode:
+		return enc.writeText("Code", strconv.FormatUint(rv.Uint(), 10))
+	case typeQualifiedName:
+		qn := rv.Interface().(QualifiedName)
+		if err := enc.writeText("NamespaceIndex", strconv.FormatUint(uint64(enc.mapNamespace(qn.NamespaceIndex)), 10)); err != nil {
+			return err
+		}
+		return enc.writeText("Name", qn.Name)
+	case typeLocalizedText:
+		lt := rv.Interface().(LocalizedText)
+		if lt.Locale != "" {
+			if err := enc.writeText("Locale", lt.Locale); err != nil {
+				return err
+			}
+		}
+		return enc.writeText("Text", lt.Text)
+	case typeDataValue:
+		return enc.writeDataValue(rv.Interface().(DataValue))
+	case typeDiagnosticInfo:
+		return enc.writeDiagnosticInfo(rv.Interface().(DiagnosticInfo))
+	case typeStructure:
+		return enc.writeStructure(rv.Interface().(Structure))
+	}
+	switch rv.Kind() {
+	case reflect.Slice:
+		name := elementName(rv.Type().Elem())
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.writeElement(name, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		if id, ok := rv.Interface().(NodeID); ok {
+			return enc.writeText("Identifier", nodeIDString(enc.mapNodeID(id)))
+		}
+		return enc.writeStruct(rv)
+	}
+	return BadEncodingError
+}
+
+// writeStruct writes the fields of the struct as elements. Nil pointer fields are optional, and omitted.
+func (enc *XMLEncoder) writeStruct(rv reflect.Value) error {
+	typ := rv.Type()
+	if isUnion(typ) {
+		// the switch field is the position of the first field that is set.
+		for i, pos := 0, 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Anonymous && field.Type == typeUnion {
+				continue
+			}
+			pos++
+			if fv := rv.Field(i); !fv.IsNil() {
+				if err := enc.writeText("SwitchField", strconv.Itoa(pos)); err != nil {
+					return err
+				}
+				return enc.writeElement(fieldName(field), fv)
+			}
+		}
+		return enc.writeText("SwitchField", "0")
+	}
+	if hasOptionalFields(typ) {
+		var mask uint32
+		for i, bit := 0, uint32(1); i < typ.NumField(); i++ {
+			if typ.Field(i).Type.Kind() == reflect.Ptr {
+				if !rv.Field(i).IsNil() {
+					mask |= bit
+				}
+				bit <<= 1
+			}
+		}
+		if err := enc.writeText("EncodingMask", strconv.FormatUint(uint64(mask), 10)); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		if field.Type.Kind() == reflect.Ptr && fv.IsNil() {
+			continue
+		}
+		if err := enc.writeElement(fieldName(field), fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeStructure writes the fields of the Structure as elements, using the registered definition.
+func (enc *XMLEncoder) writeStructure(s Structure) error {
+	st, ok := findStructureType(canonicalID(s.TypeID, enc.ec))
+	if !ok {
+		return BadEncodingError
+	}
+	if st.structureType == StructureTypeUnion {
+		for i, f := range st.fields {
+			if v, _ := s.Field(f.name); v != nil {
+				if err := enc.writeText("SwitchField", strconv.Itoa(i+1)); err != nil {
+					return err
+				}
+				return enc.writeFieldValue(f, v)
+			}
+		}
+		return enc.writeText("SwitchField", "0")
+	}
+	if st.structureType == StructureTypeStructureWithOptionalFields {
+		var mask uint32
+		var bit uint32 = 1
+		for _, f := range st.fields {
+			if f.isOptional {
+				if v, _ := s.Field(f.name); v != nil {
+					mask |= bit
+				}
+				bit <<= 1
+			}
+		}
+		if err := enc.writeText("EncodingMask", strconv.FormatUint(uint64(mask), 10)); err != nil {
+			return err
+		}
+	}
+	for _, f := range st.fields {
+		v, _ := s.Field(f.name)
+		if f.isOptional && v == nil {
+			continue
+		}
+		if err := enc.writeFieldValue(f, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFieldValue writes the value of a field of a Structure. Fields of abstract data types, e.g. BaseDataType
+// or Structure, are written as Variant or ExtensionObject. Fields of structured data types are written inline.
+func (enc *XMLEncoder) writeFieldValue(f structureFieldType, v any) error {
+	ft, ok := findFieldType(f.dataTypeID)
+	if !ok {
+		return BadEncodingError
+	}
+	elem := func(name string, v any) error {
+		switch {
+		case ft.builtin == VariantTypeVariant:
+			vv := Variant(v)
+			return enc.writeElement(name, reflect.ValueOf(&vv).Elem())
+		case ft.builtin == VariantTypeExtensionObject:
+			return enc.writeExtensionObject(name, v)
+		case v == nil:
+			return enc.writeText(name, "")
+		default:
+			return enc.writeElement(name, reflect.ValueOf(v))
+		}
+	}
+	if f.valueRank == ValueRankScalar || v == nil {
+		return elem(f.name, v)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return BadEncodingError
+	}
+	var name string
+	switch {
+	case ft.builtin != 0:
+		name = builtinNames[ft.builtin]
+	case ft.structure != nil:
+		name = ft.structure.name
+	case ft.goType != nil:
+		name = elementName(ft.goType)
+	}
+	if err := enc.start(f.name); err != nil {
+		return err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if err := elem(name, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return enc.end(f.name)
+}
+
+// writeVariantValue writes the element of the value of the Variant. A multidimensional array is written as a Matrix.
+func (enc *XMLEncoder) writeVariantValue(value Variant) error {
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Slice {
+		// flatten a multidimensional array, and record the dimensions.
+		dims := []int32{int32(rv.Len())}
+		for rv.Type().Elem().Kind() == reflect.Slice {
+			var inner int32
+			if rv.Len() > 0 {
+				inner = int32(rv.Index(0).Len())
+			}
+			dims = append(dims, inner)
+			flat := reflect.MakeSlice(rv.Type().Elem(), 0, rv.Len()*int(inner))
+			for i := 0; i < rv.Len(); i++ {
+				flat = reflect.AppendSlice(flat, rv.Index(i))
+			}
+			rv = flat
+		}
+		if err := enc.start("Matrix"); err != nil {
+			return err
+		}
+		if err := enc.writeElement("Dimensions", reflect.ValueOf(dims)); err != nil {
+			return err
+		}
+		if err := enc.writeVariantArray("Elements", rv); err != nil {
+			return err
+		}
+		return enc.end("Matrix")
+	}
+	vt, ok := variantTypeOf(rv)
+	if !ok {
+		return BadEncodingError
+	}
+	if rv.Kind() == reflect.Slice {
+		return enc.writeVariantArray("ListOf"+builtinNames[vt], rv)
+	}
+	if vt == VariantTypeExtensionObject {
+		return enc.writeExtensionObject("ExtensionObject", value)
+	}
+	return enc.writeElement(builtinNames[vt], rv)
+}
+
+// writeVariantArray writes the elements of a one-dimensional array. Arrays of structs are arrays of ExtensionObjects.
+func (enc *XMLEncoder) writeVariantArray(name string, rv reflect.Value) error {
+	vt, ok := variantTypeOf(rv)
+	if !ok {
+		return BadEncodingError
+	}
+	if err := enc.start(name); err != nil {
+		return err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		var err error
+		if vt == VariantTypeExtensionObject {
+			err = enc.writeExtensionObject("ExtensionObject", rv.Index(i).Interface())
+		} else {
+			err = enc.writeElement(builtinNames[vt], rv.Index(i))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return enc.end(name)
+}
+
+// writeExtensionObject writes the TypeId and the Body of the ExtensionObject. The Body contains an element
+// named for the type of the structure.
+func (enc *XMLEncoder) writeExtensionObject(name string, value ExtensionObject) error {
+	if err := enc.start(name); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if value != nil && !(rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		var id ExpandedNodeID
+		var bodyName string
+		if s, ok := value.(Structure); ok {
+			st, ok := findStructureType(canonicalID(s.TypeID, enc.ec))
+			if !ok || st.xmlEncodingID.NodeID == nil {
+				return BadEncodingError
+			}
+			id, bodyName = st.xmlEncodingID, st.name
+		} else {
+			var ok bool
+			if id, ok = FindXMLEncodingIDForType(rv.Type()); !ok {
+				return BadEncodingError
+			}
+			bodyName = elementName(rv.Type())
+		}
+		typeID := ToNodeID(id, enc.ec.NamespaceURIs())
+		if typeID == nil {
+			return BadEncodingError
+		}
+		if err := enc.start("TypeId"); err != nil {
+			return err
+		}
+		if err := enc.writeText("Identifier", nodeIDString(enc.mapNodeID(typeID))); err != nil {
+			return err
+		}
+		if err := enc.end("TypeId"); err != nil {
+			return err
+		}
+		if err := enc.start("Body"); err != nil {
+			return err
+		}
+		if err := enc.start(bodyName); err != nil {
+			return err
+		}
+		if err := enc.writeContent(rv); err != nil {
+			return err
+		}
+		if err := enc.end(bodyName); err != nil {
+			return err
+		}
+		if err := enc.end("Body"); err != nil {
+			return err
+		}
+	}
+	return enc.end(name)
+}
+
+// writeDataValue writes the fields of the DataValue, omitting the fields with default values.
+func (enc *XMLEncoder) writeDataValue(dv DataValue) error {
+	if dv.Value != nil {
+		if err := enc.writeElement("Value", reflect.ValueOf(&dv.Value).Elem()); err != nil {
+			return err
+		}
+	}
+	if dv.StatusCode != Good {
+		if err := enc.writeElement("StatusCode", reflect.ValueOf(dv.StatusCode)); err != nil {
+			return err
+		}
+	}
+	if !dv.SourceTimestamp.IsZero() {
+		if err := enc.writeElement("SourceTimestamp", reflect.ValueOf(dv.SourceTimestamp)); err != nil {
+			return err
+		}
+	}
+	if dv.SourcePicoseconds != 0 {
+		if err := enc.writeElement("SourcePicoseconds", reflect.ValueOf(dv.SourcePicoseconds)); err != nil {
+			return err
+		}
+	}
+	if !dv.ServerTimestamp.IsZero() {
+		if err := enc.writeElement("ServerTimestamp", reflect.ValueOf(dv.ServerTimestamp)); err != nil {
+			return err
+		}
+	}
+	if dv.ServerPicoseconds != 0 {
+		if err := enc.writeElement("ServerPicoseconds", reflect.ValueOf(dv.ServerPicoseconds)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDiagnosticInfo writes the fields of the DiagnosticInfo, omitting the fields that are not set.
+func (enc *XMLEncoder) writeDiagnosticInfo(info DiagnosticInfo) error {
+	fields := []struct {
+		name  string
+		value any
+	}{
+		{"SymbolicId", info.SymbolicID},
+		{"NamespaceUri", info.NamespaceURI},
+		{"Locale", info.Locale},
+		{"LocalizedText", info.LocalizedText},
+		{"AdditionalInfo", info.AdditionalInfo},
+		{"InnerStatusCode", info.InnerStatusCode},
+		{"InnerDiagnosticInfo", info.InnerDiagnosticInfo},
+	}
+	for _, f := range fields {
+		if fv := reflect.ValueOf(f.value); !fv.IsNil() {
+			if err := enc.writeElement(f.name, fv.Elem()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mapNamespace maps the namespace index using the namespace map, if set.
+func (enc *XMLEncoder) mapNamespace(ns uint16) uint16 {
+	if enc.nsMap == nil || ns == 0 {
+		return ns
+	}
+	return enc.nsMap(ns)
+}
+
+func (enc *XMLEncoder) mapNodeID(id NodeID) NodeID {
+	return withNamespaceIndex(id, enc.mapNamespace(namespaceIndexOf(id)))
+}
+
+// elementName returns the name of the xml element of a value of the type.
+func elementName(typ reflect.Type) string {
+	if vt, ok := variantTypes[typ]; ok {
+		return builtinNames[vt]
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "Boolean"
+	case reflect.Int8:
+		return "SByte"
+	case reflect.Uint8:
+		return "Byte"
+	case reflect.Int16:
+		return "Int16"
+	case reflect.Uint16:
+		return "UInt16"
+	case reflect.Int32:
+		return "Int32"
+	case reflect.Uint32:
+		return "UInt32"
+	case reflect.Int64:
+		return "Int64"
+	case reflect.Uint64:
+		return "UInt64"
+	case reflect.Float32:
+		return "Float"
+	case reflect.Float64:
+		return "Double"
+	case reflect.String:
+		return "String"
+	case reflect.Ptr:
+		return elementName(typ.Elem())
+	case reflect.Slice:
+		if typ == typeSliceOfByte {
+			return "ByteString"
+		}
+		return "ListOf" + elementName(typ.Elem())
+	}
+	return xmlCaseReplacer.Replace(typ.Name())
+}
+
+// fieldName returns the name of the xml element of a field of a struct.
+func fieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("xml"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return xmlCaseReplacer.Replace(field.Name)
+}
+
+func namespaceIndexOf(id NodeID) uint16 {
+	switch id := id.(type) {
+	case NodeIDNumeric:
+		return id.NamespaceIndex
+	case NodeIDString:
+		return id.NamespaceIndex
+	case NodeIDGUID:
+		return id.NamespaceIndex
+	case NodeIDOpaque:
+		return id.NamespaceIndex
+	default:
+		return 0
+	}
+}
+
+func withNamespaceIndex(id NodeID, ns uint16) NodeID {
+	switch id := id.(type) {
+	case NodeIDNumeric:
+		return NodeIDNumeric{ns, id.ID}
+	case NodeIDString:
+		return NodeIDString{ns, id.ID}
+	case NodeIDGUID:
+		return NodeIDGUID{ns, id.ID}
+	case NodeIDOpaque:
+		return NodeIDOpaque{ns, id.ID}
+	default:
+		return id
+	}
+}
+
+// formatXMLFloat returns the xml schema form of the float, e.g. "INF" or "NaN".
+func formatXMLFloat(f float64, bitSize int) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "INF"
+	case math.IsInf(f, -1):
+		return "-INF"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, bitSize)
+}
@@ -0,0 +1,192 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcullen/opcua/ua"
+	"gotest.tools/assert"
+)
+
+func TestXML(t *testing.T) {
+	const nsu = "http://github.com/awcullen/opcua/ua/xml/"
+	ec := testEncodingContext{"http://opcfoundation.org/UA/", "urn:test:ns1", nsu}
+	ua.RegisterXMLEncodingID(reflect.TypeOf(testOptionalStruct{}), ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 1), NamespaceURI: nsu})
+	ua.RegisterStructureDefinition(ua.NewNodeIDNumeric(2, 2), ua.StructureDefinition{
+		DefaultEncodingID: ua.NewNodeIDNumeric(2, 3),
+		BaseDataType:      ua.DataTypeIDStructure,
+		StructureType:     ua.StructureTypeStructure,
+		Fields: []ua.StructureField{
+			{Name: "X", DataType: ua.DataTypeIDDouble, ValueRank: ua.ValueRankScalar},
+			{Name: "Tags", DataType: ua.DataTypeIDString, ValueRank: ua.ValueRankOneDimension},
+		},
+	}, ec)
+	ua.RegisterStructureXMLEncoding(ua.NewNodeIDNumeric(2, 2), "Point", ua.NewNodeIDNumeric(2, 4), ec)
+
+	b := 2.0
+	hi := "hi"
+	cases := []struct {
+		in  any
+		xml string
+	}{
+		{
+			ptr(ua.Variant(int32(5))),
+			`<Variant xmlns="T"><Value><Int32>5</Int32></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant([]string{"a", "b"})),
+			`<Variant xmlns="T"><Value><ListOfString><String>a</String><String>b</String></ListOfString></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant([][]int32{{1, 2}, {3, 4}})),
+			`<Variant xmlns="T"><Value><Matrix><Dimensions><Int32>2</Int32><Int32>2</Int32></Dimensions><Elements><Int32>1</Int32><Int32>2</Int32><Int32>3</Int32><Int32>4</Int32></Elements></Matrix></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant(ua.NewNodeIDString(2, "Demo"))),
+			`<Variant xmlns="T"><Value><NodeId><Identifier>ns=2;s=Demo</Identifier></NodeId></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant(ua.ByteString("abc"))),
+			`<Variant xmlns="T"><Value><ByteString>YWJj</ByteString></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant(ua.XMLElement(`<a b="c">d</a>`))),
+			`<Variant xmlns="T"><Value><XmlElement><a b="c">d</a></XmlElement></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant([]ua.Variant{int32(1), "a"})),
+			`<Variant xmlns="T"><Value><ListOfVariant><Variant><Value><Int32>1</Int32></Value></Variant><Variant><Value><String>a</String></Value></Variant></ListOfVariant></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant(ua.Range{Low: 0, High: 100})),
+			`<Variant xmlns="T"><Value><ExtensionObject><TypeId><Identifier>i=885</Identifier></TypeId><Body><Range><Low>0</Low><High>100</High></Range></Body></ExtensionObject></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant(testOptionalStruct{A: 1, B: &b})),
+			`<Variant xmlns="T"><Value><ExtensionObject><TypeId><Identifier>ns=2;i=1</Identifier></TypeId><Body><testOptionalStruct><EncodingMask>1</EncodingMask><A>1</A><B>2</B></testOptionalStruct></Body></ExtensionObject></Value></Variant>`,
+		},
+		{
+			ptr(ua.Variant(ua.Structure{
+				TypeID: ua.ExpandedNodeID{NodeID: ua.NewNodeIDNumeric(0, 2), NamespaceURI: nsu},
+				Fields: []ua.StructureFieldValue{{"X", 1.5}, {"Tags", []string{"x"}}},
+			})),
+			`<Variant xmlns="T"><Value><ExtensionObject><TypeId><Identifier>ns=2;i=4</Identifier></TypeId><Body><Point><X>1.5</X><Tags><String>x</String></Tags></Point></Body></ExtensionObject></Value></Variant>`,
+		},
+		{
+			ptr(ua.NewQualifiedName(2, "Demo")),
+			`<QualifiedName xmlns="T"><NamespaceIndex>2</NamespaceIndex><Name>Demo</Name></QualifiedName>`,
+		},
+		{
+			ptr(ua.NewLocalizedText("Hello", "en")),
+			`<LocalizedText xmlns="T"><Locale>en</Locale><Text>Hello</Text></LocalizedText>`,
+		},
+		{
+			ptr(ua.DataValue{Value: 1.5, StatusCode: ua.BadNodeIDUnknown, SourceTimestamp: time.Date(2021, time.January, 2, 3, 4, 5, 0, time.UTC)}),
+			`<DataValue xmlns="T"><Value><Value><Double>1.5</Double></Value></Value><StatusCode><Code>2150891520</Code></StatusCode><SourceTimestamp>2021-01-02T03:04:05Z</SourceTimestamp></DataValue>`,
+		},
+		{
+			ptr(ua.Argument{Name: "In", DataType: ua.DataTypeIDInt32, ValueRank: ua.ValueRankScalar, ArrayDimensions: []uint32{}, Description: ua.NewLocalizedText("x", "")}),
+			`<Argument xmlns="T"><Name>In</Name><DataType><Identifier>i=6</Identifier></DataType><ValueRank>-1</ValueRank><ArrayDimensions></ArrayDimensions><Description><Text>x</Text></Description></Argument>`,
+		},
+		{
+			ptr(ua.ApplicationDescription{ApplicationURI: "urn:a", ApplicationType: ua.ApplicationTypeServer, DiscoveryURLs: []string{}}),
+			`<ApplicationDescription xmlns="T"><ApplicationUri>urn:a</ApplicationUri><ProductUri></ProductUri><ApplicationName><Text></Text></ApplicationName><ApplicationType>Server_0</ApplicationType><GatewayServerUri></GatewayServerUri><DiscoveryProfileUri></DiscoveryProfileUri><DiscoveryUrls></DiscoveryUrls></ApplicationDescription>`,
+		},
+		{
+			ptr(testUnion{B: &hi}),
+			`<testUnion xmlns="T"><SwitchField>2</SwitchField><B>hi</B></testUnion>`,
+		},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		if err := ua.NewXMLEncoder(buf, ec).Encode(c.in); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, buf.String(), strings.Replace(c.xml, `xmlns="T"`, `xmlns="`+ua.TypesNamespace+`"`, 1))
+
+		out := reflect.New(reflect.TypeOf(c.in).Elem())
+		if err := ua.NewXMLDecoder(buf, ec).Decode(out.Interface()); err != nil {
+			t.Fatal(err)
+		}
+		assert.DeepEqual(t, out.Interface(), c.in)
+	}
+}
+
+func TestXMLNamespaceMap(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/", "urn:test:ns1", "urn:test:ns2"}
+	// the document has only the second namespace, at index 1.
+	toDoc := func(ns uint16) uint16 { return ns - 1 }
+	fromDoc := func(ns uint16) uint16 { return ns + 1 }
+	in := []ua.QualifiedName{ua.NewQualifiedName(0, "a"), ua.NewQualifiedName(2, "b")}
+
+	buf := &bytes.Buffer{}
+	enc := ua.NewXMLEncoder(buf, ec)
+	enc.SetNamespaceMap(toDoc)
+	if err := enc.WriteVariant(in); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, buf.String(), `<ListOfQualifiedName xmlns="`+ua.TypesNamespace+`"><QualifiedName><NamespaceIndex>0</NamespaceIndex><Name>a</Name></QualifiedName><QualifiedName><NamespaceIndex>1</NamespaceIndex><Name>b</Name></QualifiedName></ListOfQualifiedName>`)
+
+	dec := ua.NewXMLDecoder(buf, ec)
+	dec.SetNamespaceMap(fromDoc)
+	var out ua.Variant
+	if err := dec.ReadVariant(&out); err != nil {
+		t.Fatal(err)
+	}
+	assert.DeepEqual(t, out, ua.Variant(in))
+}
+
+func TestXMLInvalidDimensions(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/"}
+	for _, dims := range []string{
+		`<Int32>-1</Int32><Int32>-2</Int32>`,
+		`<Int32>0</Int32><Int32>2</Int32>`,
+		`<Int32>2</Int32><Int32>1</Int32><Int32>-1</Int32>`,
+		`<Int32>65536</Int32><Int32>65536</Int32><Int32>65536</Int32>`,
+	} {
+		in := `<Matrix xmlns="` + ua.TypesNamespace + `"><Dimensions>` + dims + `</Dimensions><Elements><Int32>1</Int32><Int32>2</Int32></Elements></Matrix>`
+		var out ua.Variant
+		if err := ua.NewXMLDecoder(strings.NewReader(in), ec).ReadVariant(&out); err != ua.BadDecodingError {
+			t.Errorf("ReadVariant(%s) = %v, want %v", in, err, ua.BadDecodingError)
+		}
+	}
+	for _, in := range []string{
+		// the dimensions are checked without elements, and a matrix must have dimensions.
+		`<Matrix xmlns="` + ua.TypesNamespace + `"><Dimensions><Int32>65536</Int32><Int32>65536</Int32><Int32>65536</Int32></Dimensions><Elements></Elements></Matrix>`,
+		`<Matrix xmlns="` + ua.TypesNamespace + `"><Elements><Int32>1</Int32><Int32>2</Int32></Elements></Matrix>`,
+	} {
+		var out ua.Variant
+		if err := ua.NewXMLDecoder(strings.NewReader(in), ec).ReadVariant(&out); err != ua.BadDecodingError {
+			t.Errorf("ReadVariant(%s) = %v, want %v", in, err, ua.BadDecodingError)
+		}
+	}
+}
+
+func TestXMLUnknownExtensionObject(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/"}
+	in := `<ExtensionObject xmlns="` + ua.TypesNamespace + `"><TypeId><Identifier>i=999999</Identifier></TypeId><Body><X>1</X></Body></ExtensionObject>`
+	var out ua.Variant
+	if err := ua.NewXMLDecoder(strings.NewReader(in), ec).ReadVariant(&out); err != ua.BadDecodingError {
+		t.Errorf("ReadVariant(%s) = %v, want %v", in, err, ua.BadDecodingError)
+	}
+}
+
+func TestXMLNilVariant(t *testing.T) {
+	ec := testEncodingContext{"http://opcfoundation.org/UA/"}
+	buf := &bytes.Buffer{}
+	if err := ua.NewXMLEncoder(buf, ec).WriteVariant(nil); err != nil {
+		t.Fatal(err)
+	}
+	var out ua.Variant = int32(1)
+	if err := ua.NewXMLDecoder(buf, ec).ReadVariant(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != nil {
+		t.Errorf("ReadVariant = %v, want nil", out)
+	}
+}
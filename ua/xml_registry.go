@@ -0,0 +1,39 @@
+// Copyright 2021 Converter Systems LLC. All rights reserved.
+
+package ua
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	xmlEncodingTypes sync.Map // map[ExpandedNodeID]reflect.Type
+	xmlEncodingIDs   sync.Map // map[reflect.Type]ExpandedNodeID
+)
+
+// RegisterXMLEncodingID registers the type and id with the XMLEncoder.
+func RegisterXMLEncodingID(typ reflect.Type, id ExpandedNodeID) {
+	xmlEncodingTypes.LoadOrStore(id, typ)
+	xmlEncodingIDs.LoadOrStore(typ, id)
+}
+
+// FindXMLEncodingIDForType finds the XMLEncodingID given the type.
+func FindXMLEncodingIDForType(typ reflect.Type) (ExpandedNodeID, bool) {
+	if val, ok := xmlEncodingIDs.Load(typ); ok {
+		if id, ok := val.(ExpandedNodeID); ok {
+			return id, ok
+		}
+	}
+	return NilExpandedNodeID, false
+}
+
+// FindTypeForXMLEncodingID finds the Type given the XMLEncodingID.
+func FindTypeForXMLEncodingID(id ExpandedNodeID) (reflect.Type, bool) {
+	if val, ok := xmlEncodingTypes.Load(id); ok {
+		if typ, ok := val.(reflect.Type); ok {
+			return typ, ok
+		}
+	}
+	return nil, false
+}